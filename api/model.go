@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -11,6 +12,13 @@ const (
 	CrashedState            = "CRASHED"
 	UnknownState            = "UNKNOWN"
 	InsufficientMemoryError = "Insufficient resources: memory"
+
+	ProtocolTCP = "tcp"
+	ProtocolUDP = "udp"
+
+	HealthCheckTypeHTTP    = "http"
+	HealthCheckTypePort    = "port"
+	HealthCheckTypeProcess = "process"
 )
 
 type LRPIdentifier struct {
@@ -36,10 +44,15 @@ type LRP struct {
 	Image                  string
 	Command                []string
 	Sidecars               []Sidecar
+	PlacementTags          []string
 	PrivateRegistry        *PrivateRegistry
 	Env                    map[string]string
 	Health                 Healthcheck
+	Readiness              Healthcheck
 	Ports                  []int32
+	PortProtocols          map[int32]string
+	Routes                 Routes
+	EgressRules            []json.RawMessage
 	TargetInstances        int
 	RunningInstances       int
 	MemoryMB               int64
@@ -51,11 +64,47 @@ type LRP struct {
 	UserDefinedAnnotations map[string]string
 }
 
+// PortProtocol returns the protocol of one of the LRP ports. Ports without
+// an explicit protocol are TCP ports.
+func (l *LRP) PortProtocol(port int32) string {
+	if protocol, ok := l.PortProtocols[port]; ok {
+		return protocol
+	}
+
+	return ProtocolTCP
+}
+
+// Sidecar is an additional container of an LRP instance. An empty Image
+// means the app image and a zero CPUWeight means the app CPU weight.
 type Sidecar struct {
-	Name     string
-	Command  []string
-	MemoryMB int64
-	Env      map[string]string
+	Name      string
+	Command   []string
+	Image     string
+	MemoryMB  int64
+	CPUWeight uint8
+	Env       map[string]string
+}
+
+// Routes describe how an LRP is reachable from outside the cluster. HTTP
+// routes are served through an Ingress, TCP routes through a LoadBalancer
+// Service.
+type Routes struct {
+	HTTP []HTTPRoute `json:"http,omitempty"`
+	TCP  []TCPRoute  `json:"tcp,omitempty"`
+}
+
+type HTTPRoute struct {
+	Hostname string `json:"hostname"`
+	Port     int32  `json:"port"`
+}
+
+type TCPRoute struct {
+	ExternalPort  int32 `json:"external_port"`
+	ContainerPort int32 `json:"container_port"`
+}
+
+func (r Routes) IsEmpty() bool {
+	return len(r.HTTP) == 0 && len(r.TCP) == 0
 }
 
 type PrivateRegistry struct {
@@ -77,10 +126,23 @@ type Instance struct {
 }
 
 type Healthcheck struct {
-	Type      string
-	Port      int32
-	Endpoint  string
-	TimeoutMs uint
+	Type                string
+	Port                int32
+	Endpoint            string
+	TimeoutMs           uint
+	StartTimeoutMs      uint
+	InvocationTimeoutMs uint
+	IntervalMs          uint
+}
+
+// HealthCheckPort returns the port chosen for a health check, defaulting to
+// the first app port when none was chosen.
+func HealthCheckPort(chosenPort, firstAppPort int32) int32 {
+	if chosenPort != 0 {
+		return chosenPort
+	}
+
+	return firstAppPort
 }
 
 // A Task is a one-off process that is run exactly once and returns a
@@ -93,6 +155,7 @@ type Task struct {
 	PrivateRegistry    *PrivateRegistry
 	Env                map[string]string
 	Command            []string
+	PlacementTags      []string
 	AppName            string
 	AppGUID            string
 	OrgName            string
@@ -102,4 +165,28 @@ type Task struct {
 	MemoryMB           int64
 	DiskMB             int64
 	CPUWeight          uint8
+	ResultFile         string
+}
+
+// Staging builds the droplet image of an app from its source package.
+type Staging struct {
+	GUID               string
+	CompletionCallback string
+	Env                map[string]string
+	PackageURL         string
+	DropletUploadURL   string
+	// Buildpacks is the JSON list of the buildpacks requested by the cloud
+	// controller, passed on to the builder as is.
+	Buildpacks string
+	// Image is where the built droplet image is pushed to.
+	Image     string
+	AppName   string
+	AppGUID   string
+	OrgName   string
+	OrgGUID   string
+	SpaceName string
+	SpaceGUID string
+	MemoryMB  int64
+	DiskMB    int64
+	CPUWeight uint8
 }
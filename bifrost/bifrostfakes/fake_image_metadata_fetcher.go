@@ -2,50 +2,56 @@
 package bifrostfakes
 
 import (
+	"context"
 	"sync"
 
 	"code.cloudfoundry.org/eirini/bifrost"
 	"github.com/containers/image/types"
+	digest "github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type FakeImageMetadataFetcher struct {
-	Stub        func(string, types.SystemContext) (*v1.ImageConfig, error)
+	Stub        func(context.Context, string, types.SystemContext) (*v1.ImageConfig, digest.Digest, error)
 	mutex       sync.RWMutex
 	argsForCall []struct {
-		arg1 string
-		arg2 types.SystemContext
+		arg1 context.Context
+		arg2 string
+		arg3 types.SystemContext
 	}
 	returns struct {
 		result1 *v1.ImageConfig
-		result2 error
+		result2 digest.Digest
+		result3 error
 	}
 	returnsOnCall map[int]struct {
 		result1 *v1.ImageConfig
-		result2 error
+		result2 digest.Digest
+		result3 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeImageMetadataFetcher) Spy(arg1 string, arg2 types.SystemContext) (*v1.ImageConfig, error) {
+func (fake *FakeImageMetadataFetcher) Spy(arg1 context.Context, arg2 string, arg3 types.SystemContext) (*v1.ImageConfig, digest.Digest, error) {
 	fake.mutex.Lock()
 	ret, specificReturn := fake.returnsOnCall[len(fake.argsForCall)]
 	fake.argsForCall = append(fake.argsForCall, struct {
-		arg1 string
-		arg2 types.SystemContext
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 types.SystemContext
+	}{arg1, arg2, arg3})
 	stub := fake.Stub
 	returns := fake.returns
-	fake.recordInvocation("ImageMetadataFetcher", []interface{}{arg1, arg2})
+	fake.recordInvocation("ImageMetadataFetcher", []interface{}{arg1, arg2, arg3})
 	fake.mutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return returns.result1, returns.result2
+	return returns.result1, returns.result2, returns.result3
 }
 
 func (fake *FakeImageMetadataFetcher) CallCount() int {
@@ -54,42 +60,45 @@ func (fake *FakeImageMetadataFetcher) CallCount() int {
 	return len(fake.argsForCall)
 }
 
-func (fake *FakeImageMetadataFetcher) Calls(stub func(string, types.SystemContext) (*v1.ImageConfig, error)) {
+func (fake *FakeImageMetadataFetcher) Calls(stub func(context.Context, string, types.SystemContext) (*v1.ImageConfig, digest.Digest, error)) {
 	fake.mutex.Lock()
 	defer fake.mutex.Unlock()
 	fake.Stub = stub
 }
 
-func (fake *FakeImageMetadataFetcher) ArgsForCall(i int) (string, types.SystemContext) {
+func (fake *FakeImageMetadataFetcher) ArgsForCall(i int) (context.Context, string, types.SystemContext) {
 	fake.mutex.RLock()
 	defer fake.mutex.RUnlock()
-	return fake.argsForCall[i].arg1, fake.argsForCall[i].arg2
+	return fake.argsForCall[i].arg1, fake.argsForCall[i].arg2, fake.argsForCall[i].arg3
 }
 
-func (fake *FakeImageMetadataFetcher) Returns(result1 *v1.ImageConfig, result2 error) {
+func (fake *FakeImageMetadataFetcher) Returns(result1 *v1.ImageConfig, result2 digest.Digest, result3 error) {
 	fake.mutex.Lock()
 	defer fake.mutex.Unlock()
 	fake.Stub = nil
 	fake.returns = struct {
 		result1 *v1.ImageConfig
-		result2 error
-	}{result1, result2}
+		result2 digest.Digest
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeImageMetadataFetcher) ReturnsOnCall(i int, result1 *v1.ImageConfig, result2 error) {
+func (fake *FakeImageMetadataFetcher) ReturnsOnCall(i int, result1 *v1.ImageConfig, result2 digest.Digest, result3 error) {
 	fake.mutex.Lock()
 	defer fake.mutex.Unlock()
 	fake.Stub = nil
 	if fake.returnsOnCall == nil {
 		fake.returnsOnCall = make(map[int]struct {
 			result1 *v1.ImageConfig
-			result2 error
+			result2 digest.Digest
+			result3 error
 		})
 	}
 	fake.returnsOnCall[i] = struct {
 		result1 *v1.ImageConfig
-		result2 error
-	}{result1, result2}
+		result2 digest.Digest
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *FakeImageMetadataFetcher) Invocations() map[string][][]interface{} {
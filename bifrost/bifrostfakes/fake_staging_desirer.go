@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package bifrostfakes
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/bifrost"
+)
+
+type FakeStagingDesirer struct {
+	DesireStub        func(context.Context, string, *api.Staging) error
+	desireMutex       sync.RWMutex
+	desireArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *api.Staging
+	}
+	desireReturns struct {
+		result1 error
+	}
+	desireReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStagingDesirer) Desire(arg1 context.Context, arg2 string, arg3 *api.Staging) error {
+	fake.desireMutex.Lock()
+	ret, specificReturn := fake.desireReturnsOnCall[len(fake.desireArgsForCall)]
+	fake.desireArgsForCall = append(fake.desireArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *api.Staging
+	}{arg1, arg2, arg3})
+	stub := fake.DesireStub
+	fakeReturns := fake.desireReturns
+	fake.recordInvocation("Desire", []interface{}{arg1, arg2, arg3})
+	fake.desireMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStagingDesirer) DesireCallCount() int {
+	fake.desireMutex.RLock()
+	defer fake.desireMutex.RUnlock()
+	return len(fake.desireArgsForCall)
+}
+
+func (fake *FakeStagingDesirer) DesireCalls(stub func(context.Context, string, *api.Staging) error) {
+	fake.desireMutex.Lock()
+	defer fake.desireMutex.Unlock()
+	fake.DesireStub = stub
+}
+
+func (fake *FakeStagingDesirer) DesireArgsForCall(i int) (context.Context, string, *api.Staging) {
+	fake.desireMutex.RLock()
+	defer fake.desireMutex.RUnlock()
+	argsForCall := fake.desireArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStagingDesirer) DesireReturns(result1 error) {
+	fake.desireMutex.Lock()
+	defer fake.desireMutex.Unlock()
+	fake.DesireStub = nil
+	fake.desireReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStagingDesirer) DesireReturnsOnCall(i int, result1 error) {
+	fake.desireMutex.Lock()
+	defer fake.desireMutex.Unlock()
+	fake.DesireStub = nil
+	if fake.desireReturnsOnCall == nil {
+		fake.desireReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.desireReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStagingDesirer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.desireMutex.RLock()
+	defer fake.desireMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStagingDesirer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ bifrost.StagingDesirer = new(FakeStagingDesirer)
@@ -0,0 +1,79 @@
+package bifrost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/lager"
+	"github.com/pkg/errors"
+)
+
+//counterfeiter:generate . StagingDesirer
+
+type StagingDesirer interface {
+	Desire(ctx context.Context, namespace string, staging *api.Staging) error
+}
+
+// BuildpackStaging stages buildpack apps in a job that pushes the droplet
+// image to the DropletRegistry. The task reporter reports the result of the
+// job to the cloud controller once it finishes.
+type BuildpackStaging struct {
+	Logger           lager.Logger
+	StagingDesirer   StagingDesirer
+	StagingCompleter StagingCompleter
+	Namespace        string
+	DropletRegistry  string
+}
+
+func (s BuildpackStaging) TransferStaging(ctx context.Context, stagingGUID string, request cf.StagingRequest) error {
+	logger := s.Logger.Session("transfer-staging", lager.Data{"staging-guid": stagingGUID})
+
+	lifecycle := request.Lifecycle.BuildpackLifecycle
+
+	buildpacks, err := json.Marshal(lifecycle.Buildpacks)
+	if err != nil {
+		logger.Error("failed-to-marshal-buildpacks", err)
+
+		return errors.Wrap(err, "failed to marshal buildpacks")
+	}
+
+	staging := &api.Staging{
+		GUID:               stagingGUID,
+		CompletionCallback: request.CompletionCallback,
+		Env:                mergeEnvs(request.Environment, nil),
+		PackageURL:         lifecycle.AppBitsDownloadURI,
+		DropletUploadURL:   lifecycle.DropletUploadURI,
+		Buildpacks:         string(buildpacks),
+		Image:              dropletImage(s.DropletRegistry, request.AppGUID, stagingGUID),
+		AppName:            request.AppName,
+		AppGUID:            request.AppGUID,
+		OrgName:            request.OrgName,
+		OrgGUID:            request.OrgGUID,
+		SpaceName:          request.SpaceName,
+		SpaceGUID:          request.SpaceGUID,
+		MemoryMB:           request.MemoryMB,
+		DiskMB:             request.DiskMB,
+		CPUWeight:          request.CPUWeight,
+	}
+
+	if err = s.StagingDesirer.Desire(ctx, s.Namespace, staging); err != nil {
+		logger.Error("failed-to-desire-staging", err)
+
+		return errors.Wrap(err, "failed to desire staging job")
+	}
+
+	return nil
+}
+
+func (s BuildpackStaging) CompleteStaging(ctx context.Context, taskCompletedRequest cf.StagingCompletedRequest) error {
+	return s.StagingCompleter.CompleteStaging(ctx, taskCompletedRequest)
+}
+
+// dropletImage is the image the droplet built by staging is pushed to. The
+// cloud controller uses the staging guid as the guid of the droplet.
+func dropletImage(registry, appGUID, dropletGUID string) string {
+	return fmt.Sprintf("%s/%s:%s", registry, appGUID, dropletGUID)
+}
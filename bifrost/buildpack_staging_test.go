@@ -0,0 +1,125 @@
+package bifrost_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/bifrost"
+	"code.cloudfoundry.org/eirini/bifrost/bifrostfakes"
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildpackStaging", func() {
+	var (
+		stager           bifrost.BuildpackStaging
+		stagingDesirer   *bifrostfakes.FakeStagingDesirer
+		stagingCompleter *bifrostfakes.FakeStagingCompleter
+	)
+
+	BeforeEach(func() {
+		stagingDesirer = new(bifrostfakes.FakeStagingDesirer)
+		stagingCompleter = new(bifrostfakes.FakeStagingCompleter)
+		stager = bifrost.BuildpackStaging{
+			Logger:           tests.NewTestLogger("buildpack-staging"),
+			StagingDesirer:   stagingDesirer,
+			StagingCompleter: stagingCompleter,
+			Namespace:        "the-namespace",
+			DropletRegistry:  "registry.example.com/droplets",
+		}
+	})
+
+	Describe("TransferStaging", func() {
+		var (
+			stagingRequest cf.StagingRequest
+			stagingErr     error
+		)
+
+		BeforeEach(func() {
+			stagingRequest = cf.StagingRequest{
+				AppGUID:            "app-guid",
+				AppName:            "app-name",
+				OrgName:            "org-name",
+				OrgGUID:            "org-guid",
+				SpaceName:          "space-name",
+				SpaceGUID:          "space-guid",
+				CompletionCallback: "example.com/call/me",
+				Environment:        []cf.EnvironmentVariable{{Name: "FOO", Value: "bar"}},
+				MemoryMB:           1024,
+				DiskMB:             2048,
+				CPUWeight:          50,
+				Lifecycle: cf.StagingLifecycle{
+					BuildpackLifecycle: &cf.StagingBuildpackLifecycle{
+						AppBitsDownloadURI: "example.com/download",
+						DropletUploadURI:   "example.com/upload",
+						Buildpacks:         []cf.Buildpack{{Name: "ruby", Key: "ruby-key", URL: "example.com/ruby"}},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			stagingErr = stager.TransferStaging(context.Background(), "staging-guid", stagingRequest)
+		})
+
+		It("succeeds", func() {
+			Expect(stagingErr).NotTo(HaveOccurred())
+		})
+
+		It("desires a staging job pushing to the droplet registry", func() {
+			Expect(stagingDesirer.DesireCallCount()).To(Equal(1))
+			_, namespace, staging := stagingDesirer.DesireArgsForCall(0)
+			Expect(namespace).To(Equal("the-namespace"))
+			Expect(staging).To(Equal(&api.Staging{
+				GUID:               "staging-guid",
+				CompletionCallback: "example.com/call/me",
+				Env:                map[string]string{"FOO": "bar"},
+				PackageURL:         "example.com/download",
+				DropletUploadURL:   "example.com/upload",
+				Buildpacks:         `[{"name":"ruby","key":"ruby-key","url":"example.com/ruby","skip_detect":false}]`,
+				Image:              "registry.example.com/droplets/app-guid:staging-guid",
+				AppName:            "app-name",
+				AppGUID:            "app-guid",
+				OrgName:            "org-name",
+				OrgGUID:            "org-guid",
+				SpaceName:          "space-name",
+				SpaceGUID:          "space-guid",
+				MemoryMB:           1024,
+				DiskMB:             2048,
+				CPUWeight:          50,
+			}))
+		})
+
+		It("does not complete the staging", func() {
+			Expect(stagingCompleter.CompleteStagingCallCount()).To(BeZero())
+		})
+
+		When("desiring the staging job fails", func() {
+			BeforeEach(func() {
+				stagingDesirer.DesireReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(stagingErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+
+	Describe("CompleteStaging", func() {
+		var completeErr error
+
+		JustBeforeEach(func() {
+			completeErr = stager.CompleteStaging(context.Background(), cf.StagingCompletedRequest{TaskGUID: "staging-guid"})
+		})
+
+		It("delegates to the staging completer", func() {
+			Expect(completeErr).NotTo(HaveOccurred())
+			Expect(stagingCompleter.CompleteStagingCallCount()).To(Equal(1))
+			_, request := stagingCompleter.CompleteStagingArgsForCall(0)
+			Expect(request.TaskGUID).To(Equal("staging-guid"))
+		})
+	})
+})
@@ -1,16 +1,32 @@
 package bifrost
 
 import (
+	"encoding/json"
 	"fmt"
+	"path"
 
 	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/netpol"
+	"code.cloudfoundry.org/eirini/k8s/shared"
 	"code.cloudfoundry.org/eirini/models/cf"
 	"code.cloudfoundry.org/eirini/util"
 	"code.cloudfoundry.org/lager"
 	"github.com/pkg/errors"
 )
 
+//counterfeiter:generate . ImagePolicy
+
+const (
+	HTTPRouterKey = "cf-router"
+	TCPRouterKey  = "tcp-router"
+
+	// cnbLauncher runs the processes of droplet images built by Cloud
+	// Native Buildpacks. Given a command, it runs the command in the
+	// environment set up by the buildpacks.
+	cnbLauncher = "/cnb/lifecycle/launcher"
+)
+
 type lifecycleOptions struct {
 	command         []string
 	env             map[string]string
@@ -18,13 +34,29 @@ type lifecycleOptions struct {
 	privateRegistry *api.PrivateRegistry
 }
 
+type ImagePolicy interface {
+	Check(image string) error
+}
+
 type APIConverter struct {
-	logger lager.Logger
+	logger          lager.Logger
+	imagePolicy     ImagePolicy
+	dropletRegistry string
+	imageRewrites   map[string]string
 }
 
-func NewAPIConverter(logger lager.Logger) *APIConverter {
+// NewAPIConverter returns a converter of CC requests. Buildpack apps and
+// tasks run the droplet images that buildpack staging pushed to
+// dropletRegistry; they are rejected if it is empty. The imageRewrites must
+// be the ones the workloads are created with, so that private registry
+// credentials are issued for the registry the images are actually pulled
+// from.
+func NewAPIConverter(logger lager.Logger, imagePolicy ImagePolicy, dropletRegistry string, imageRewrites map[string]string) *APIConverter {
 	return &APIConverter{
-		logger: logger,
+		logger:          logger,
+		imagePolicy:     imagePolicy,
+		dropletRegistry: dropletRegistry,
+		imageRewrites:   imageRewrites,
 	}
 }
 
@@ -33,19 +65,39 @@ func (c *APIConverter) ConvertLRP(request cf.DesireLRPRequest) (api.LRP, error)
 		"LANG": "en_US.UTF-8",
 	}
 
+	if err := validatePorts(request); err != nil {
+		return api.LRP{}, err
+	}
+
 	var port int32
 	if len(request.Ports) != 0 {
 		port = request.Ports[0]
 		env[eirini.EnvCFInstanceAddr] = fmt.Sprintf("0.0.0.0:%d", port)
 		env[eirini.EnvCFInstancePort] = fmt.Sprintf("%d", port)
-		env[eirini.EnvCFInstancePorts] = fmt.Sprintf(`[{"external":%d,"internal":%d}]`, port, port)
+		env[eirini.EnvCFInstancePorts] = instancePorts(request.Ports)
+	}
+
+	startTimeoutMs := request.StartTimeoutMs
+	if startTimeoutMs == 0 {
+		startTimeoutMs = request.HealthCheckTimeoutMs
 	}
 
 	healthcheck := api.Healthcheck{
-		Type:      request.HealthCheckType,
-		Endpoint:  request.HealthCheckHTTPEndpoint,
-		TimeoutMs: request.HealthCheckTimeoutMs,
-		Port:      port,
+		Type:                request.HealthCheckType,
+		Endpoint:            request.HealthCheckHTTPEndpoint,
+		TimeoutMs:           request.HealthCheckTimeoutMs,
+		StartTimeoutMs:      startTimeoutMs,
+		InvocationTimeoutMs: request.HealthCheckInvocationTimeoutMs,
+		IntervalMs:          request.HealthCheckIntervalMs,
+		Port:                api.HealthCheckPort(request.HealthCheckPort, port),
+	}
+
+	readiness := api.Healthcheck{
+		Type:                request.ReadinessHealthCheckType,
+		Endpoint:            request.ReadinessHealthCheckHTTPEndpoint,
+		InvocationTimeoutMs: request.ReadinessHealthCheckInvocationTimeoutMs,
+		IntervalMs:          request.ReadinessHealthCheckIntervalMs,
+		Port:                api.HealthCheckPort(request.ReadinessHealthCheckPort, port),
 	}
 
 	lrpLifecycleOptions, err := c.getLifecycleOptions(request)
@@ -63,6 +115,22 @@ func (c *APIConverter) ConvertLRP(request cf.DesireLRPRequest) (api.LRP, error)
 		return api.LRP{}, err
 	}
 
+	routes, err := ConvertRoutes(request.Routes)
+	if err != nil {
+		return api.LRP{}, err
+	}
+
+	if _, err = netpol.ParseEgressRules(request.EgressRules); err != nil {
+		return api.LRP{}, err
+	}
+
+	lrpEnv := mergeMaps(request.Environment, env, lrpLifecycleOptions.env)
+
+	sidecars, err := c.convertSidecars(request, lrpEnv)
+	if err != nil {
+		return api.LRP{}, err
+	}
+
 	return api.LRP{
 		AppName:                request.AppName,
 		AppGUID:                request.AppGUID,
@@ -73,12 +141,18 @@ func (c *APIConverter) ConvertLRP(request cf.DesireLRPRequest) (api.LRP, error)
 		SpaceGUID:              request.SpaceGUID,
 		LRPIdentifier:          identifier,
 		ProcessType:            request.ProcessType,
+		PlacementTags:          request.PlacementTags,
 		Image:                  lrpLifecycleOptions.image,
 		TargetInstances:        request.NumInstances,
 		Command:                lrpLifecycleOptions.command,
-		Env:                    mergeMaps(request.Environment, env, lrpLifecycleOptions.env),
+		Env:                    lrpEnv,
+		Sidecars:               sidecars,
 		Health:                 healthcheck,
+		Readiness:              readiness,
 		Ports:                  request.Ports,
+		PortProtocols:          request.PortProtocols,
+		Routes:                 routes,
+		EgressRules:            request.EgressRules,
 		MemoryMB:               request.MemoryMB,
 		DiskMB:                 request.DiskMB,
 		CPUWeight:              request.CPUWeight,
@@ -109,22 +183,44 @@ func (c *APIConverter) ConvertTask(taskGUID string, request cf.TaskRequest) (api
 		SpaceName:          request.SpaceName,
 		OrgGUID:            request.OrgGUID,
 		SpaceGUID:          request.SpaceGUID,
+		PlacementTags:      request.PlacementTags,
+		MemoryMB:           request.MemoryMB,
+		DiskMB:             request.DiskMB,
+		CPUWeight:          request.CPUWeight,
+		ResultFile:         request.ResultFile,
 	}
 
-	if request.Lifecycle.DockerLifecycle == nil {
-		return api.Task{}, errors.New("docker is the only supported lifecycle")
+	if err := validateTaskRequest(request); err != nil {
+		return api.Task{}, err
 	}
 
-	lifecycle := request.Lifecycle.DockerLifecycle
-	task.Command = lifecycle.Command
-	task.Image = lifecycle.Image
+	switch {
+	case request.Lifecycle.BuildpackLifecycle != nil:
+		options, err := c.getBuildpackLifecycleOptions(request.AppGUID, request.Lifecycle.BuildpackLifecycle)
+		if err != nil {
+			return api.Task{}, err
+		}
 
-	if lifecycle.RegistryUsername != "" || lifecycle.RegistryPassword != "" {
-		task.PrivateRegistry = &api.PrivateRegistry{
-			Server:   util.ParseImageRegistryHost(lifecycle.Image),
-			Username: lifecycle.RegistryUsername,
-			Password: lifecycle.RegistryPassword,
+		task.Command = options.command
+		task.Image = options.image
+	case request.Lifecycle.DockerLifecycle != nil:
+		lifecycle := request.Lifecycle.DockerLifecycle
+		if err := c.imagePolicy.Check(lifecycle.Image); err != nil {
+			return api.Task{}, err
 		}
+
+		task.Command = lifecycle.Command
+		task.Image = lifecycle.Image
+
+		if lifecycle.RegistryUsername != "" || lifecycle.RegistryPassword != "" {
+			task.PrivateRegistry = &api.PrivateRegistry{
+				Server:   c.registryHost(lifecycle.Image),
+				Username: lifecycle.RegistryUsername,
+				Password: lifecycle.RegistryPassword,
+			}
+		}
+	default:
+		return api.Task{}, errors.New("missing lifecycle data")
 	}
 
 	task.Env = mergeEnvs(request.Environment, env)
@@ -160,26 +256,28 @@ func mergeEnvs(requestEnv []cf.EnvironmentVariable, appliedEnv map[string]string
 func (c *APIConverter) getLifecycleOptions(request cf.DesireLRPRequest) (*lifecycleOptions, error) {
 	options := &lifecycleOptions{}
 
+	if request.Lifecycle.BuildpackLifecycle != nil {
+		return c.getBuildpackLifecycleOptions(request.AppGUID, request.Lifecycle.BuildpackLifecycle)
+	}
+
 	if request.Lifecycle.DockerLifecycle == nil {
 		return nil, fmt.Errorf("missing lifecycle data")
 	}
 
-	var err error
-
 	lifecycle := request.Lifecycle.DockerLifecycle
+	if err := c.imagePolicy.Check(lifecycle.Image); err != nil {
+		return nil, err
+	}
+
 	options.image = lifecycle.Image
 	options.command = lifecycle.Command
 
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to verify if docker image needs root user")
-	}
-
 	registryUsername := lifecycle.RegistryUsername
 	registryPassword := lifecycle.RegistryPassword
 
 	if registryUsername != "" || registryPassword != "" {
 		options.privateRegistry = &api.PrivateRegistry{
-			Server:   util.ParseImageRegistryHost(options.image),
+			Server:   c.registryHost(options.image),
 			Username: registryUsername,
 			Password: registryPassword,
 		}
@@ -188,6 +286,185 @@ func (c *APIConverter) getLifecycleOptions(request cf.DesireLRPRequest) (*lifecy
 	return options, nil
 }
 
+// registryHost returns the host of the registry the image is pulled from,
+// which is the one of the rewritten image if an image rewrite applies.
+func (c *APIConverter) registryHost(image string) string {
+	return util.ParseImageRegistryHost(shared.RewriteImage(image, c.imageRewrites))
+}
+
+// getBuildpackLifecycleOptions runs the droplet image pushed by buildpack
+// staging. The image policy does not apply to it, as eirini built it.
+func (c *APIConverter) getBuildpackLifecycleOptions(appGUID string, lifecycle *cf.BuildpackLifecycle) (*lifecycleOptions, error) {
+	if c.dropletRegistry == "" {
+		return nil, errors.New("buildpack lifecycle is not supported: no droplet registry is configured")
+	}
+
+	options := &lifecycleOptions{
+		image: dropletImage(c.dropletRegistry, appGUID, lifecycle.DropletGUID),
+	}
+
+	if lifecycle.StartCommand != "" {
+		options.command = []string{cnbLauncher, lifecycle.StartCommand}
+	}
+
+	return options, nil
+}
+
+// ConvertRoutes parses the router specific route payloads sent by Cloud
+// Controller. Routes for routers other than the HTTP and TCP ones are ignored.
+func ConvertRoutes(routes map[string]json.RawMessage) (api.Routes, error) {
+	result := api.Routes{}
+
+	if raw, ok := routes[HTTPRouterKey]; ok && raw != nil {
+		httpRoutes := []cf.Route{}
+		if err := json.Unmarshal(raw, &httpRoutes); err != nil {
+			return api.Routes{}, errors.Wrapf(err, "failed to parse %s routes", HTTPRouterKey)
+		}
+
+		for _, r := range httpRoutes {
+			result.HTTP = append(result.HTTP, api.HTTPRoute{Hostname: r.Hostname, Port: r.Port})
+		}
+	}
+
+	if raw, ok := routes[TCPRouterKey]; ok && raw != nil {
+		tcpRoutes := []cf.TCPRoute{}
+		if err := json.Unmarshal(raw, &tcpRoutes); err != nil {
+			return api.Routes{}, errors.Wrapf(err, "failed to parse %s routes", TCPRouterKey)
+		}
+
+		for _, r := range tcpRoutes {
+			result.TCP = append(result.TCP, api.TCPRoute{ExternalPort: r.ExternalPort, ContainerPort: r.ContainerPort})
+		}
+	}
+
+	return result, nil
+}
+
+// convertSidecars returns the sidecars of the process type of the request.
+// Sidecars share the app environment. Sidecar images are subject to the image
+// policy like app images are.
+func (c *APIConverter) convertSidecars(request cf.DesireLRPRequest, env map[string]string) ([]api.Sidecar, error) {
+	sidecars := []api.Sidecar{}
+
+	for _, s := range request.Sidecars {
+		if !runsWithProcessType(s, request.ProcessType) {
+			continue
+		}
+
+		if s.Image != "" {
+			if err := c.imagePolicy.Check(s.Image); err != nil {
+				return nil, errors.Wrapf(err, "sidecar %s", s.Name)
+			}
+		}
+
+		sidecars = append(sidecars, api.Sidecar{
+			Name:      s.Name,
+			Command:   s.Command,
+			Image:     s.Image,
+			MemoryMB:  s.MemoryMB,
+			CPUWeight: s.CPUWeight,
+			Env:       mergeMaps(env),
+		})
+	}
+
+	return sidecars, nil
+}
+
+func runsWithProcessType(sidecar cf.Sidecar, processType string) bool {
+	if len(sidecar.ProcessTypes) == 0 {
+		return true
+	}
+
+	for _, t := range sidecar.ProcessTypes {
+		if t == processType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// instancePorts lists all app ports in the CF_INSTANCE_PORTS format. Apps
+// are reachable on the same port inside and outside of their container.
+func instancePorts(ports []int32) string {
+	type instancePort struct {
+		External int32 `json:"external"`
+		Internal int32 `json:"internal"`
+	}
+
+	instancePorts := make([]instancePort, 0, len(ports))
+	for _, p := range ports {
+		instancePorts = append(instancePorts, instancePort{External: p, Internal: p})
+	}
+
+	instancePortsJSON, _ := json.Marshal(instancePorts) // marshalling a list of ints cannot fail
+
+	return string(instancePortsJSON)
+}
+
+func validatePorts(request cf.DesireLRPRequest) error {
+	isPort := map[int32]bool{}
+	for _, p := range request.Ports {
+		isPort[p] = true
+	}
+
+	for p, protocol := range request.PortProtocols {
+		if !isPort[p] {
+			return fmt.Errorf("protocol set for port %d which is not an app port", p)
+		}
+
+		if protocol != api.ProtocolTCP && protocol != api.ProtocolUDP {
+			return fmt.Errorf("unsupported protocol %q for port %d", protocol, p)
+		}
+	}
+
+	for _, p := range []int32{request.HealthCheckPort, request.ReadinessHealthCheckPort} {
+		if p == 0 {
+			continue
+		}
+
+		if !isPort[p] {
+			return fmt.Errorf("health check port %d is not an app port", p)
+		}
+
+		if protocol, ok := request.PortProtocols[p]; ok && protocol != api.ProtocolTCP {
+			return fmt.Errorf("health check port %d is not a tcp port", p)
+		}
+	}
+
+	return validateDefaultHealthCheckPort(request)
+}
+
+// validateDefaultHealthCheckPort rejects port and http health checks without
+// a chosen port when the first app port, which they then target, is not a
+// tcp port.
+func validateDefaultHealthCheckPort(request cf.DesireLRPRequest) error {
+	if len(request.Ports) == 0 {
+		return nil
+	}
+
+	defaultPort := request.Ports[0]
+	if protocol, ok := request.PortProtocols[defaultPort]; !ok || protocol == api.ProtocolTCP {
+		return nil
+	}
+
+	checks := []struct {
+		checkType string
+		port      int32
+	}{
+		{request.HealthCheckType, request.HealthCheckPort},
+		{request.ReadinessHealthCheckType, request.ReadinessHealthCheckPort},
+	}
+
+	for _, check := range checks {
+		if check.port == 0 && (check.checkType == api.HealthCheckTypePort || check.checkType == api.HealthCheckTypeHTTP) {
+			return fmt.Errorf("%s health check defaults to port %d, which is not a tcp port", check.checkType, defaultPort)
+		}
+	}
+
+	return nil
+}
+
 func convertVolumeMounts(request cf.DesireLRPRequest) []api.VolumeMount {
 	volumeMounts := []api.VolumeMount{}
 	for _, vm := range request.VolumeMounts {
@@ -207,3 +484,19 @@ func (c *APIConverter) validateRequest(request cf.DesireLRPRequest) error {
 
 	return nil
 }
+
+func validateTaskRequest(request cf.TaskRequest) error {
+	if request.MemoryMB == 0 {
+		return errors.Wrap(eirini.ErrInvalidTaskRequest, "MemoryMB cannot be 0")
+	}
+
+	if request.DiskMB == 0 {
+		return errors.Wrap(eirini.ErrInvalidTaskRequest, "DiskMB cannot be 0")
+	}
+
+	if request.ResultFile != "" && !path.IsAbs(request.ResultFile) {
+		return errors.Wrap(eirini.ErrInvalidTaskRequest, "ResultFile must be an absolute path")
+	}
+
+	return nil
+}
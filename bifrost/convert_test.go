@@ -6,26 +6,38 @@ import (
 	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/bifrost"
+	"code.cloudfoundry.org/eirini/bifrost/bifrostfakes"
 	"code.cloudfoundry.org/eirini/models/cf"
 	"code.cloudfoundry.org/eirini/tests"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/pkg/errors"
 )
 
 var _ = Describe("API Converter", func() {
 	var (
-		logger    *tests.TestLogger
-		err       error
-		converter *bifrost.APIConverter
+		logger          *tests.TestLogger
+		imagePolicy     *bifrostfakes.FakeImagePolicy
+		dropletRegistry string
+		imageRewrites   map[string]string
+		err             error
+		converter       *bifrost.APIConverter
 	)
 
 	BeforeEach(func() {
 		logger = tests.NewTestLogger("converter-test")
+		imagePolicy = new(bifrostfakes.FakeImagePolicy)
+		dropletRegistry = "registry.example.com/droplets"
+		imageRewrites = nil
 	})
 
 	JustBeforeEach(func() {
 		converter = bifrost.NewAPIConverter(
 			logger,
+			imagePolicy,
+			dropletRegistry,
+			imageRewrites,
 		)
 	})
 
@@ -56,6 +68,7 @@ var _ = Describe("API Converter", func() {
 				Version:          "capi-process-version-87d0124c433a",
 				ProcessGUID:      "capi-process-guid-69da097fc360-capi-process-version-87d0124c433a",
 				ProcessType:      "web",
+				PlacementTags:    []string{"isolated"},
 				LastUpdated:      "23534635232.3",
 				NumInstances:     3,
 				MemoryMB:         456,
@@ -69,12 +82,23 @@ var _ = Describe("API Converter", func() {
 				Environment: map[string]string{
 					"VAR_FROM_CC": "val from cc",
 				},
-				HealthCheckType:         "http",
-				HealthCheckHTTPEndpoint: "/heat",
-				HealthCheckTimeoutMs:    400,
-				Ports:                   []int32{8000, 8888},
+				HealthCheckType:                         "http",
+				HealthCheckHTTPEndpoint:                 "/heat",
+				HealthCheckTimeoutMs:                    400,
+				HealthCheckInvocationTimeoutMs:          2000,
+				HealthCheckIntervalMs:                   5000,
+				StartTimeoutMs:                          90000,
+				ReadinessHealthCheckType:                "http",
+				ReadinessHealthCheckHTTPEndpoint:        "/ready",
+				ReadinessHealthCheckInvocationTimeoutMs: 1000,
+				ReadinessHealthCheckIntervalMs:          3000,
+				Ports:                                   []int32{8000, 8888},
 				Routes: map[string]json.RawMessage{
-					"cf-router": rawJSON,
+					"cf-router":  rawJSON,
+					"tcp-router": json.RawMessage(`[{"external_port":61000,"container_port":8888,"router_group_guid":"rg"}]`),
+				},
+				EgressRules: []json.RawMessage{
+					json.RawMessage(`{"protocol":"tcp","destinations":["10.0.0.0/8"],"ports":[443]}`),
 				},
 				VolumeMounts: []cf.VolumeMount{
 					{
@@ -133,6 +157,10 @@ var _ = Describe("API Converter", func() {
 			Expect(lrp.ProcessType).To(Equal("web"))
 		})
 
+		It("should set the placement tags", func() {
+			Expect(lrp.PlacementTags).To(Equal([]string{"isolated"}))
+		})
+
 		It("should set the lrp memory", func() {
 			Expect(lrp.CPUWeight).To(Equal(uint8(50)))
 		})
@@ -164,7 +192,7 @@ var _ = Describe("API Converter", func() {
 		It("should set CF_INSTANCE_* env variables", func() {
 			Expect(lrp.Env).To(HaveKeyWithValue(eirini.EnvCFInstanceAddr, "0.0.0.0:8000"))
 			Expect(lrp.Env).To(HaveKeyWithValue(eirini.EnvCFInstancePort, "8000"))
-			Expect(lrp.Env).To(HaveKeyWithValue(eirini.EnvCFInstancePorts, MatchJSON(`[{"external": 8000, "internal": 8000}]`)))
+			Expect(lrp.Env).To(HaveKeyWithValue(eirini.EnvCFInstancePorts, MatchJSON(`[{"external": 8000, "internal": 8000}, {"external": 8888, "internal": 8888}]`)))
 		})
 
 		It("should set LANG env variable", func() {
@@ -175,6 +203,160 @@ var _ = Describe("API Converter", func() {
 			Expect(lrp.Ports).To(Equal([]int32{8000, 8888}))
 		})
 
+		Context("when port protocols are specified", func() {
+			BeforeEach(func() {
+				desireLRPRequest.PortProtocols = map[int32]string{8888: "udp"}
+			})
+
+			It("should set the port protocols", func() {
+				Expect(lrp.PortProtocols).To(Equal(map[int32]string{8888: "udp"}))
+				Expect(lrp.PortProtocol(8000)).To(Equal(api.ProtocolTCP))
+				Expect(lrp.PortProtocol(8888)).To(Equal(api.ProtocolUDP))
+			})
+
+			Context("and a protocol is not supported", func() {
+				BeforeEach(func() {
+					desireLRPRequest.PortProtocols = map[int32]string{8888: "sctp"}
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError(`unsupported protocol "sctp" for port 8888`))
+				})
+			})
+
+			Context("and a protocol is set for a port that is not an app port", func() {
+				BeforeEach(func() {
+					desireLRPRequest.PortProtocols = map[int32]string{9999: "udp"}
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError("protocol set for port 9999 which is not an app port"))
+				})
+			})
+
+			Context("and the health check targets a udp port", func() {
+				BeforeEach(func() {
+					desireLRPRequest.HealthCheckPort = 8888
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError("health check port 8888 is not a tcp port"))
+				})
+			})
+
+			Context("and the first port is a udp port", func() {
+				BeforeEach(func() {
+					desireLRPRequest.PortProtocols = map[int32]string{8000: "udp"}
+					desireLRPRequest.ReadinessHealthCheckType = "process"
+				})
+
+				Context("and the health check does not choose a port", func() {
+					BeforeEach(func() {
+						desireLRPRequest.HealthCheckType = "port"
+					})
+
+					It("fails", func() {
+						Expect(err).To(MatchError("port health check defaults to port 8000, which is not a tcp port"))
+					})
+
+					Context("but chooses a tcp port", func() {
+						BeforeEach(func() {
+							desireLRPRequest.HealthCheckPort = 8888
+						})
+
+						It("succeeds", func() {
+							Expect(err).NotTo(HaveOccurred())
+							Expect(lrp.Health.Port).To(Equal(int32(8888)))
+						})
+					})
+				})
+
+				Context("and the readiness health check does not choose a port", func() {
+					BeforeEach(func() {
+						desireLRPRequest.HealthCheckType = "process"
+						desireLRPRequest.ReadinessHealthCheckType = "http"
+					})
+
+					It("fails", func() {
+						Expect(err).To(MatchError("http health check defaults to port 8000, which is not a tcp port"))
+					})
+				})
+
+				Context("and the health checks do not probe a port", func() {
+					BeforeEach(func() {
+						desireLRPRequest.HealthCheckType = "process"
+					})
+
+					It("succeeds", func() {
+						Expect(err).NotTo(HaveOccurred())
+					})
+				})
+			})
+		})
+
+		Context("when the health checks target chosen ports", func() {
+			BeforeEach(func() {
+				desireLRPRequest.HealthCheckPort = 8888
+				desireLRPRequest.ReadinessHealthCheckPort = 8888
+			})
+
+			It("should use the chosen ports", func() {
+				Expect(lrp.Health.Port).To(Equal(int32(8888)))
+				Expect(lrp.Readiness.Port).To(Equal(int32(8888)))
+			})
+
+			It("should keep the first port as the instance port", func() {
+				Expect(lrp.Env).To(HaveKeyWithValue(eirini.EnvCFInstancePort, "8000"))
+			})
+
+			Context("and a chosen port is not an app port", func() {
+				BeforeEach(func() {
+					desireLRPRequest.ReadinessHealthCheckPort = 9999
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError("health check port 9999 is not an app port"))
+				})
+			})
+		})
+
+		It("should set the routes", func() {
+			Expect(lrp.Routes).To(Equal(api.Routes{
+				HTTP: []api.HTTPRoute{
+					{Hostname: "bumblebee.example.com", Port: 8000},
+					{Hostname: "transformers.example.com", Port: 7070},
+				},
+				TCP: []api.TCPRoute{
+					{ExternalPort: 61000, ContainerPort: 8888},
+				},
+			}))
+		})
+
+		It("should set the egress rules", func() {
+			Expect(lrp.EgressRules).To(HaveLen(1))
+			Expect(lrp.EgressRules[0]).To(MatchJSON(`{"protocol":"tcp","destinations":["10.0.0.0/8"],"ports":[443]}`))
+		})
+
+		Context("when the egress rules are invalid", func() {
+			BeforeEach(func() {
+				desireLRPRequest.EgressRules = []json.RawMessage{json.RawMessage(`{"protocol":"sctp","destinations":["10.0.0.0/8"]}`)}
+			})
+
+			It("fails", func() {
+				Expect(err).To(MatchError(ContainSubstring(`unsupported protocol "sctp"`)))
+			})
+		})
+
+		Context("when the routes are malformed", func() {
+			BeforeEach(func() {
+				desireLRPRequest.Routes["cf-router"] = json.RawMessage(`{"not": "a list"}`)
+			})
+
+			It("fails", func() {
+				Expect(err).To(MatchError(ContainSubstring("failed to parse cf-router routes")))
+			})
+		})
+
 		It("should set the volume mounts", func() {
 			volumes := lrp.VolumeMounts
 			Expect(len(volumes)).To(Equal(2))
@@ -216,6 +398,80 @@ var _ = Describe("API Converter", func() {
 			})
 		})
 
+		It("should not set any sidecars", func() {
+			Expect(lrp.Sidecars).To(BeEmpty())
+		})
+
+		Context("when the app has sidecars", func() {
+			BeforeEach(func() {
+				desireLRPRequest.Sidecars = []cf.Sidecar{
+					{
+						Name:     "apm-agent",
+						Command:  []string{"run", "agent"},
+						MemoryMB: 64,
+						Image:    "apm/agent",
+					},
+					{
+						Name:         "web-proxy",
+						Command:      []string{"run", "proxy"},
+						MemoryMB:     32,
+						CPUWeight:    5,
+						ProcessTypes: []string{"web", "worker"},
+					},
+					{
+						Name:         "worker-helper",
+						Command:      []string{"run", "helper"},
+						ProcessTypes: []string{"worker"},
+					},
+				}
+			})
+
+			It("should set the sidecars of the process type", func() {
+				Expect(lrp.Sidecars).To(HaveLen(2))
+				Expect(lrp.Sidecars[0]).To(MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal("apm-agent"),
+					"Command":   Equal([]string{"run", "agent"}),
+					"Image":     Equal("apm/agent"),
+					"MemoryMB":  Equal(int64(64)),
+					"CPUWeight": BeZero(),
+				}))
+				Expect(lrp.Sidecars[1]).To(MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal("web-proxy"),
+					"Command":   Equal([]string{"run", "proxy"}),
+					"Image":     BeEmpty(),
+					"MemoryMB":  Equal(int64(32)),
+					"CPUWeight": Equal(uint8(5)),
+				}))
+			})
+
+			It("should give the sidecars the app environment", func() {
+				Expect(lrp.Sidecars[0].Env).To(Equal(lrp.Env))
+				Expect(lrp.Sidecars[1].Env).To(Equal(lrp.Env))
+			})
+
+			It("should check the sidecar images against the image policy", func() {
+				Expect(imagePolicy.CheckCallCount()).To(Equal(2))
+				Expect(imagePolicy.CheckArgsForCall(1)).To(Equal("apm/agent"))
+			})
+
+			Context("when a sidecar image is not allowed", func() {
+				BeforeEach(func() {
+					imagePolicy.CheckStub = func(image string) error {
+						if image == "apm/agent" {
+							return errors.Wrap(eirini.ErrImageNotAllowed, "nope")
+						}
+
+						return nil
+					}
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+					Expect(err).To(MatchError(ContainSubstring("sidecar apm-agent")))
+				})
+			})
+		})
+
 		Context("when the disk quota is not provided", func() {
 			BeforeEach(func() {
 				desireLRPRequest.DiskMB = 0
@@ -226,6 +482,61 @@ var _ = Describe("API Converter", func() {
 			})
 		})
 
+		Context("When the app is using buildpack lifecycle", func() {
+			BeforeEach(func() {
+				desireLRPRequest.Lifecycle = cf.Lifecycle{
+					BuildpackLifecycle: &cf.BuildpackLifecycle{
+						DropletGUID:  "the-droplet-guid",
+						DropletHash:  "the-droplet-hash",
+						StartCommand: "bundle exec rackup",
+					},
+				}
+			})
+
+			It("should run the droplet image pushed by staging", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lrp.Image).To(Equal("registry.example.com/droplets/app-guid-69da097fc360:the-droplet-guid"))
+			})
+
+			It("should run the start command with the launcher", func() {
+				Expect(lrp.Command).To(Equal([]string{"/cnb/lifecycle/launcher", "bundle exec rackup"}))
+			})
+
+			It("should not check the image against the image policy", func() {
+				Expect(imagePolicy.CheckCallCount()).To(BeZero())
+			})
+
+			Context("when there is no start command", func() {
+				BeforeEach(func() {
+					desireLRPRequest.Lifecycle.BuildpackLifecycle.StartCommand = ""
+				})
+
+				It("should run the default process of the droplet", func() {
+					Expect(lrp.Command).To(BeEmpty())
+				})
+			})
+
+			Context("when no droplet registry is configured", func() {
+				BeforeEach(func() {
+					dropletRegistry = ""
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError(ContainSubstring("no droplet registry is configured")))
+				})
+			})
+		})
+
+		Context("when the app has no lifecycle", func() {
+			BeforeEach(func() {
+				desireLRPRequest.Lifecycle = cf.Lifecycle{}
+			})
+
+			It("fails", func() {
+				Expect(err).To(MatchError("missing lifecycle data"))
+			})
+		})
+
 		Context("When the app is using docker lifecycle", func() {
 			BeforeEach(func() {
 				desireLRPRequest.Lifecycle = cf.Lifecycle{
@@ -240,6 +551,21 @@ var _ = Describe("API Converter", func() {
 				Expect(lrp.Image).To(Equal("the-image-url"))
 			})
 
+			It("should check the image against the image policy", func() {
+				Expect(imagePolicy.CheckCallCount()).To(Equal(1))
+				Expect(imagePolicy.CheckArgsForCall(0)).To(Equal("the-image-url"))
+			})
+
+			Context("when the image is not allowed", func() {
+				BeforeEach(func() {
+					imagePolicy.CheckReturns(errors.Wrap(eirini.ErrImageNotAllowed, "nope"))
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+				})
+			})
+
 			It("should set command from docker lifecycle", func() {
 				Expect(lrp.Command).To(Equal([]string{"command-in-docker"}))
 			})
@@ -250,6 +576,29 @@ var _ = Describe("API Converter", func() {
 				Expect(health.Port).To(Equal(int32(8000)))
 				Expect(health.Endpoint).To(Equal("/heat"))
 				Expect(health.TimeoutMs).To(Equal(uint(400)))
+				Expect(health.StartTimeoutMs).To(Equal(uint(90000)))
+				Expect(health.InvocationTimeoutMs).To(Equal(uint(2000)))
+				Expect(health.IntervalMs).To(Equal(uint(5000)))
+			})
+
+			It("sets the readiness healthcheck information", func() {
+				Expect(lrp.Readiness).To(Equal(api.Healthcheck{
+					Type:                "http",
+					Port:                8000,
+					Endpoint:            "/ready",
+					InvocationTimeoutMs: 1000,
+					IntervalMs:          3000,
+				}))
+			})
+
+			Context("when the start timeout is not provided", func() {
+				BeforeEach(func() {
+					desireLRPRequest.StartTimeoutMs = 0
+				})
+
+				It("falls back to the healthcheck timeout", func() {
+					Expect(lrp.Health.StartTimeoutMs).To(Equal(uint(400)))
+				})
 			})
 
 			It("shouldn't set privateRegistry information", func() {
@@ -295,6 +644,22 @@ var _ = Describe("API Converter", func() {
 						Expect(lrp.PrivateRegistry.Server).To(Equal("index.docker.io/v1/"))
 					})
 				})
+
+				Context("and the image is rewritten to another registry", func() {
+					BeforeEach(func() {
+						imageRewrites = map[string]string{
+							"my-secret-docker-registry.docker.io:5000": "mirror.example.com",
+						}
+					})
+
+					It("keeps the original image, which the workload converters rewrite", func() {
+						Expect(lrp.Image).To(Equal("my-secret-docker-registry.docker.io:5000/repo/the-mighty-image:not-latest"))
+					})
+
+					It("provides the credentials for the registry the image is pulled from", func() {
+						Expect(lrp.PrivateRegistry.Server).To(Equal("mirror.example.com"))
+					})
+				})
 			})
 		})
 	})
@@ -316,6 +681,10 @@ var _ = Describe("API Converter", func() {
 					Name:               "task-name",
 					Environment:        []cf.EnvironmentVariable{{Name: "HOWARD", Value: "the alien"}},
 					CompletionCallback: "example.com/call/me/maybe",
+					PlacementTags:      []string{"isolated"},
+					MemoryMB:           256,
+					DiskMB:             512,
+					CPUWeight:          10,
 					Lifecycle: cf.Lifecycle{
 						DockerLifecycle: &cf.DockerLifecycle{
 							Image:   "some/image",
@@ -339,11 +708,74 @@ var _ = Describe("API Converter", func() {
 						"USER":   "vcap",
 						"TMPDIR": "/home/vcap/tmp",
 					},
-					Command: []string{"some", "command"},
-					Image:   "some/image",
+					Command:       []string{"some", "command"},
+					Image:         "some/image",
+					PlacementTags: []string{"isolated"},
+					MemoryMB:      256,
+					DiskMB:        512,
+					CPUWeight:     10,
 				}))
 			})
 
+			When("the memory is missing", func() {
+				BeforeEach(func() {
+					taskRequest.MemoryMB = 0
+				})
+
+				It("returns an invalid task request error", func() {
+					Expect(err).To(MatchError(ContainSubstring("MemoryMB cannot be 0")))
+					Expect(err).To(MatchError(eirini.ErrInvalidTaskRequest))
+				})
+			})
+
+			When("the disk is missing", func() {
+				BeforeEach(func() {
+					taskRequest.DiskMB = 0
+				})
+
+				It("returns an invalid task request error", func() {
+					Expect(err).To(MatchError(ContainSubstring("DiskMB cannot be 0")))
+					Expect(err).To(MatchError(eirini.ErrInvalidTaskRequest))
+				})
+			})
+
+			When("a result file is declared", func() {
+				BeforeEach(func() {
+					taskRequest.ResultFile = "/home/vcap/result.json"
+				})
+
+				It("sets the result file", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(task.ResultFile).To(Equal("/home/vcap/result.json"))
+				})
+
+				When("the result file path is relative", func() {
+					BeforeEach(func() {
+						taskRequest.ResultFile = "result.json"
+					})
+
+					It("returns an invalid task request error", func() {
+						Expect(err).To(MatchError(ContainSubstring("ResultFile must be an absolute path")))
+						Expect(err).To(MatchError(eirini.ErrInvalidTaskRequest))
+					})
+				})
+			})
+
+			It("checks the image against the image policy", func() {
+				Expect(imagePolicy.CheckCallCount()).To(Equal(1))
+				Expect(imagePolicy.CheckArgsForCall(0)).To(Equal("some/image"))
+			})
+
+			When("the image is not allowed", func() {
+				BeforeEach(func() {
+					imagePolicy.CheckReturns(errors.Wrap(eirini.ErrImageNotAllowed, "nope"))
+				})
+
+				It("returns an image not allowed error", func() {
+					Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+				})
+			})
+
 			When("the docker image is in a private registry", func() {
 				BeforeEach(func() {
 					taskRequest.Lifecycle.DockerLifecycle.Image = "private-registry/some/image"
@@ -358,22 +790,70 @@ var _ = Describe("API Converter", func() {
 					Expect(task.PrivateRegistry.Password).To(Equal("12345"))
 					Expect(task.PrivateRegistry.Server).To(Equal("private-registry"))
 				})
+
+				When("the image is rewritten to another registry", func() {
+					BeforeEach(func() {
+						imageRewrites = map[string]string{"docker.io/private-registry": "mirror.example.com/private-registry"}
+					})
+
+					It("provides the credentials for the registry the image is pulled from", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(task.PrivateRegistry.Server).To(Equal("mirror.example.com"))
+					})
+				})
+			})
+		})
+
+		When("the task has a buildpack lifecycle", func() {
+			BeforeEach(func() {
+				taskRequest = cf.TaskRequest{
+					AppGUID:            "our-app-id",
+					Name:               "task-name",
+					CompletionCallback: "example.com/call/me/maybe",
+					MemoryMB:           256,
+					DiskMB:             512,
+					Lifecycle: cf.Lifecycle{
+						BuildpackLifecycle: &cf.BuildpackLifecycle{
+							DropletGUID:  "the-droplet-guid",
+							StartCommand: "rake db:migrate",
+						},
+					},
+				}
+			})
+
+			It("should run the command in the droplet image", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(task.Image).To(Equal("registry.example.com/droplets/our-app-id:the-droplet-guid"))
+				Expect(task.Command).To(Equal([]string{"/cnb/lifecycle/launcher", "rake db:migrate"}))
+				Expect(task.PrivateRegistry).To(BeNil())
+			})
+
+			When("no droplet registry is configured", func() {
+				BeforeEach(func() {
+					dropletRegistry = ""
+				})
+
+				It("fails", func() {
+					Expect(err).To(MatchError(ContainSubstring("no droplet registry is configured")))
+				})
 			})
 		})
 
-		When("the task does not have any docker lifecycle information", func() {
+		When("the task does not have any lifecycle information", func() {
 			BeforeEach(func() {
 				taskRequest = cf.TaskRequest{
 					AppGUID:            "our-app-id",
 					Name:               "task-name",
 					Environment:        []cf.EnvironmentVariable{{Name: "HOWARD", Value: "the alien"}},
 					CompletionCallback: "example.com/call/me/maybe",
+					MemoryMB:           256,
+					DiskMB:             512,
 					Lifecycle:          cf.Lifecycle{},
 				}
 			})
 
 			It("fails with a useful message", func() {
-				Expect(err).To(MatchError("docker is the only supported lifecycle"))
+				Expect(err).To(MatchError("missing lifecycle data"))
 			})
 		})
 	})
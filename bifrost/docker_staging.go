@@ -4,10 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"code.cloudfoundry.org/eirini/k8s/shared"
 	"code.cloudfoundry.org/eirini/models/cf"
 	"code.cloudfoundry.org/lager"
 	"github.com/containers/image/types"
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
@@ -20,10 +26,10 @@ type StagingCompleter interface {
 	CompleteStaging(ctx context.Context, req cf.StagingCompletedRequest) error
 }
 
-type ImageMetadataFetcher func(string, types.SystemContext) (*v1.ImageConfig, error)
+type ImageMetadataFetcher func(context.Context, string, types.SystemContext) (*v1.ImageConfig, digest.Digest, error)
 
-func (f ImageMetadataFetcher) Fetch(dockerRef string, sysCtx types.SystemContext) (*v1.ImageConfig, error) {
-	return f(dockerRef, sysCtx)
+func (f ImageMetadataFetcher) Fetch(ctx context.Context, dockerRef string, sysCtx types.SystemContext) (*v1.ImageConfig, digest.Digest, error) {
+	return f(ctx, dockerRef, sysCtx)
 }
 
 type ImageRefParser func(string) (string, error)
@@ -37,6 +43,21 @@ type DockerStaging struct {
 	ImageMetadataFetcher ImageMetadataFetcher
 	ImageRefParser       ImageRefParser
 	StagingCompleter     StagingCompleter
+	ImagePolicy          ImagePolicy
+	AllowRunImageAsRoot  bool
+
+	// ImageOS and ImageArchitecture choose the image of multi-arch images.
+	// They default to the platform eirini runs on.
+	ImageOS           string
+	ImageArchitecture string
+
+	// FetchTimeout limits how long fetching the image metadata may take.
+	// Zero means no limit.
+	FetchTimeout time.Duration
+
+	// ImageRewrites are applied to the image before fetching its metadata,
+	// the same way they are applied to the images of the staged workloads.
+	ImageRewrites map[string]string
 }
 
 type StagingResult struct {
@@ -60,8 +81,11 @@ type port struct {
 }
 
 type executionMetadata struct {
-	Cmd   []string `json:"cmd"`
-	Ports []port   `json:"ports"`
+	Cmd        []string `json:"cmd"`
+	Entrypoint []string `json:"entrypoint,omitempty"`
+	Workdir    string   `json:"workdir,omitempty"`
+	Ports      []port   `json:"ports"`
+	User       string   `json:"user,omitempty"`
 }
 
 func (s DockerStaging) TransferStaging(ctx context.Context, stagingGUID string, request cf.StagingRequest) error {
@@ -72,7 +96,13 @@ func (s DockerStaging) TransferStaging(ctx context.Context, stagingGUID string,
 		Annotation: fmt.Sprintf(`{"completion_callback": "%s"}`, request.CompletionCallback),
 	}
 
-	imageConfig, err := s.getImageConfig(request.Lifecycle.DockerLifecycle)
+	if err := s.ImagePolicy.Check(request.Lifecycle.DockerLifecycle.Image); err != nil {
+		logger.Error("image-not-allowed", err)
+
+		return s.respondWithFailure(ctx, taskCallbackResponse, err)
+	}
+
+	imageConfig, imageDigest, err := s.getImageConfig(ctx, request.Lifecycle.DockerLifecycle)
 	if err != nil {
 		logger.Error("failed-to-get-image-config", err)
 
@@ -86,7 +116,22 @@ func (s DockerStaging) TransferStaging(ctx context.Context, stagingGUID string,
 		return s.respondWithFailure(ctx, taskCallbackResponse, errors.Wrap(err, "failed to parse exposed ports"))
 	}
 
-	stagingResult, err := buildStagingResult(request.Lifecycle.DockerLifecycle.Image, ports)
+	if !s.AllowRunImageAsRoot {
+		if err = verifyNonRootUser(imageConfig.User); err != nil {
+			logger.Error("image-runs-as-root", err)
+
+			return s.respondWithFailure(ctx, taskCallbackResponse, err)
+		}
+	}
+
+	image, err := pinToDigest(request.Lifecycle.DockerLifecycle.Image, imageDigest)
+	if err != nil {
+		logger.Error("failed-to-pin-image-to-digest", err)
+
+		return s.respondWithFailure(ctx, taskCallbackResponse, errors.Wrap(err, "failed to pin image to digest"))
+	}
+
+	stagingResult, err := buildStagingResult(image, imageConfig, ports)
 	if err != nil {
 		logger.Error("failed-to-build-staging-result", err)
 
@@ -109,23 +154,48 @@ func (s DockerStaging) CompleteStaging(ctx context.Context, taskCompletedRequest
 	return s.StagingCompleter.CompleteStaging(ctx, taskCompletedRequest)
 }
 
-func (s DockerStaging) getImageConfig(lifecycle *cf.StagingDockerLifecycle) (*v1.ImageConfig, error) {
-	dockerRef, err := s.ImageRefParser.Parse(lifecycle.Image)
+func (s DockerStaging) getImageConfig(ctx context.Context, lifecycle *cf.StagingDockerLifecycle) (*v1.ImageConfig, digest.Digest, error) {
+	dockerRef, err := s.ImageRefParser.Parse(shared.RewriteImage(lifecycle.Image, s.ImageRewrites))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse image ref")
+		return nil, "", errors.Wrap(err, "failed to parse image ref")
 	}
 
-	imgMetadata, err := s.ImageMetadataFetcher.Fetch(dockerRef, types.SystemContext{
+	if s.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.FetchTimeout)
+
+		defer cancel()
+	}
+
+	imgMetadata, imgDigest, err := s.ImageMetadataFetcher.Fetch(ctx, dockerRef, types.SystemContext{
 		DockerAuthConfig: &types.DockerAuthConfig{
 			Username: lifecycle.RegistryUsername,
 			Password: lifecycle.RegistryPassword,
 		},
+		OSChoice:           s.ImageOS,
+		ArchitectureChoice: s.ImageArchitecture,
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to fetch image metadata")
+		return nil, "", errors.Wrap(err, "failed to fetch image metadata")
+	}
+
+	return imgMetadata, imgDigest, nil
+}
+
+// pinToDigest replaces the tag or digest of image with imageDigest, so that
+// all instances of the app run the staged image even if the tag moves.
+func pinToDigest(image string, imageDigest digest.Digest) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse image")
+	}
+
+	digested, err := reference.WithDigest(reference.TrimNamed(named), imageDigest)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid image digest")
 	}
 
-	return imgMetadata, nil
+	return reference.FamiliarString(digested), nil
 }
 
 func parseExposedPorts(imageConfig *v1.ImageConfig) ([]port, error) {
@@ -151,10 +221,42 @@ func parseExposedPorts(imageConfig *v1.ImageConfig) ([]port, error) {
 	return ports, nil
 }
 
-func buildStagingResult(image string, ports []port) (string, error) {
+// verifyNonRootUser fails for image users that Kubernetes refuses to run
+// when pods must not run as root. The user can be a name or a UID,
+// optionally followed by a group.
+func verifyNonRootUser(imageUser string) error {
+	user := strings.Split(imageUser, ":")[0]
+
+	if user == "" || user == "root" {
+		return errors.New("image runs as root, which is not allowed")
+	}
+
+	uid, err := strconv.ParseUint(user, 10, 32) // nolint:gomnd
+	if err != nil {
+		return errors.Errorf("image runs as non-numeric user %q, which cannot be verified as non-root", user)
+	}
+
+	if uid == 0 {
+		return errors.New("image runs as root, which is not allowed")
+	}
+
+	return nil
+}
+
+func buildStagingResult(image string, imageConfig *v1.ImageConfig, ports []port) (string, error) {
+	cmd := imageConfig.Cmd
+	if cmd == nil {
+		cmd = []string{}
+	}
+
+	startCommand := append(append([]string{}, imageConfig.Entrypoint...), cmd...)
+
 	executionMetadataJSON, err := json.Marshal(executionMetadata{
-		Cmd:   []string{},
-		Ports: ports,
+		Cmd:        cmd,
+		Entrypoint: imageConfig.Entrypoint,
+		Workdir:    imageConfig.WorkingDir,
+		Ports:      ports,
+		User:       imageConfig.User,
 	})
 	if err != nil {
 		return "", errors.Wrap(err, "failed to parse execution metadata")
@@ -165,7 +267,7 @@ func buildStagingResult(image string, ports []port) (string, error) {
 		LifecycleMetadata: LifecycleMetadata{
 			DockerImage: image,
 		},
-		ProcessTypes:      ProcessTypes{Web: ""},
+		ProcessTypes:      ProcessTypes{Web: strings.Join(startCommand, " ")},
 		ExecutionMetadata: string(executionMetadataJSON),
 	}
 
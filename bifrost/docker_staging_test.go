@@ -4,34 +4,47 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
+	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/bifrost"
 	"code.cloudfoundry.org/eirini/bifrost/bifrostfakes"
 	"code.cloudfoundry.org/eirini/models/cf"
 	"code.cloudfoundry.org/eirini/tests"
+	"github.com/containers/image/types"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
+const imageDigest = "sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1"
+
 var _ = Describe("DockerStager", func() {
 	var (
 		stager           bifrost.DockerStaging
 		fetcher          *bifrostfakes.FakeImageMetadataFetcher
 		parser           *bifrostfakes.FakeImageRefParser
 		stagingCompleter *bifrostfakes.FakeStagingCompleter
+		imagePolicy      *bifrostfakes.FakeImagePolicy
 	)
 
 	Context("Stage a docker image", func() {
 		var (
 			stagingErr     error
 			stagingRequest cf.StagingRequest
+			imageConfig    *v1.ImageConfig
+			allowRunAsRoot bool
+			fetchTimeout   time.Duration
+			imageRewrites  map[string]string
 		)
 
 		BeforeEach(func() {
 			fetcher = new(bifrostfakes.FakeImageMetadataFetcher)
 			parser = new(bifrostfakes.FakeImageRefParser)
 			stagingCompleter = new(bifrostfakes.FakeStagingCompleter)
+			imagePolicy = new(bifrostfakes.FakeImagePolicy)
 			stagingRequest = cf.StagingRequest{
 				CompletionCallback: "the-completion-callback/call/me",
 				Lifecycle: cf.StagingLifecycle{
@@ -41,11 +54,19 @@ var _ = Describe("DockerStager", func() {
 				},
 			}
 
-			fetcher.Returns(&v1.ImageConfig{
+			imageConfig = &v1.ImageConfig{
 				ExposedPorts: map[string]struct{}{
 					"8888/tcp": {},
 				},
-			}, nil)
+				Entrypoint: []string{"/bin/app"},
+				Cmd:        []string{"--port", "8888"},
+				WorkingDir: "/home/app",
+				User:       "1000:1000",
+			}
+			fetcher.Returns(imageConfig, imageDigest, nil)
+			allowRunAsRoot = false
+			fetchTimeout = 0
+			imageRewrites = nil
 
 			parser.Returns("//some-valid-docker-ref", nil)
 		})
@@ -56,6 +77,12 @@ var _ = Describe("DockerStager", func() {
 				ImageMetadataFetcher: fetcher.Spy,
 				ImageRefParser:       parser.Spy,
 				StagingCompleter:     stagingCompleter,
+				ImagePolicy:          imagePolicy,
+				AllowRunImageAsRoot:  allowRunAsRoot,
+				ImageOS:              "linux",
+				ImageArchitecture:    "arm64",
+				FetchTimeout:         fetchTimeout,
+				ImageRewrites:        imageRewrites,
 			}
 
 			stagingErr = stager.TransferStaging(context.Background(), "stg-guid", stagingRequest)
@@ -65,18 +92,68 @@ var _ = Describe("DockerStager", func() {
 			Expect(stagingErr).ToNot(HaveOccurred())
 		})
 
+		It("should check the image against the image policy", func() {
+			Expect(imagePolicy.CheckCallCount()).To(Equal(1))
+			Expect(imagePolicy.CheckArgsForCall(0)).To(Equal("eirini/some-app:some-tag"))
+		})
+
+		Context("when the image is not allowed", func() {
+			BeforeEach(func() {
+				imagePolicy.CheckReturns(fmt.Errorf(`image "eirini/some-app:some-tag": tag "some-tag" is banned: %w`, eirini.ErrImageNotAllowed))
+			})
+
+			It("should not fetch the image metadata", func() {
+				Expect(fetcher.CallCount()).To(Equal(0))
+			})
+
+			It("should fail staging with the policy violation", func() {
+				Expect(stagingErr).ToNot(HaveOccurred())
+
+				_, taskCallbackResponse := stagingCompleter.CompleteStagingArgsForCall(0)
+				Expect(taskCallbackResponse.Failed).To(BeTrue())
+				Expect(taskCallbackResponse.FailureReason).To(ContainSubstring(`tag "some-tag" is banned`))
+			})
+		})
+
 		It("should parse the docker image ref", func() {
 			Expect(parser.CallCount()).To(Equal(1))
 			img := parser.ArgsForCall(0)
 			Expect(img).To(Equal("eirini/some-app:some-tag"))
 		})
 
+		Context("when the image matches an image rewrite", func() {
+			BeforeEach(func() {
+				imageRewrites = map[string]string{"docker.io/eirini": "mirror.example.com/eirini"}
+			})
+
+			It("should parse the rewritten image ref", func() {
+				Expect(parser.CallCount()).To(Equal(1))
+				img := parser.ArgsForCall(0)
+				Expect(img).To(Equal("mirror.example.com/eirini/some-app:some-tag"))
+			})
+
+			It("should report the original image pinned to its digest", func() {
+				_, taskCompletedRequest := stagingCompleter.CompleteStagingArgsForCall(0)
+
+				var payload bifrost.StagingResult
+				Expect(json.Unmarshal([]byte(taskCompletedRequest.Result), &payload)).To(Succeed())
+				Expect(payload.LifecycleMetadata.DockerImage).To(Equal("eirini/some-app@" + imageDigest))
+			})
+		})
+
 		It("should use the parsed docker image ref", func() {
 			Expect(fetcher.CallCount()).To(Equal(1))
-			ref, _ := fetcher.ArgsForCall(0)
+			_, ref, _ := fetcher.ArgsForCall(0)
 			Expect(ref).To(Equal("//some-valid-docker-ref"))
 		})
 
+		It("should fetch the image for the configured platform", func() {
+			Expect(fetcher.CallCount()).To(Equal(1))
+			_, _, sysCtx := fetcher.ArgsForCall(0)
+			Expect(sysCtx.OSChoice).To(Equal("linux"))
+			Expect(sysCtx.ArchitectureChoice).To(Equal("arm64"))
+		})
+
 		It("should complete staging with correct parameters", func() {
 			Expect(stagingCompleter.CompleteStagingCallCount()).To(Equal(1))
 			_, taskCompletedRequest := stagingCompleter.CompleteStagingArgsForCall(0)
@@ -89,9 +166,108 @@ var _ = Describe("DockerStager", func() {
 			Expect(json.Unmarshal([]byte(taskCompletedRequest.Result), &payload)).To(Succeed())
 
 			Expect(payload.LifecycleType).To(Equal("docker"))
-			Expect(payload.LifecycleMetadata.DockerImage).To(Equal("eirini/some-app:some-tag"))
-			Expect(payload.ProcessTypes.Web).To(BeEmpty())
-			Expect(payload.ExecutionMetadata).To(Equal(`{"cmd":[],"ports":[{"Port":8888,"Protocol":"tcp"}]}`))
+			Expect(payload.LifecycleMetadata.DockerImage).To(Equal("eirini/some-app@" + imageDigest))
+			Expect(payload.ProcessTypes.Web).To(Equal("/bin/app --port 8888"))
+			Expect(payload.ExecutionMetadata).To(MatchJSON(`{
+				"cmd": ["--port", "8888"],
+				"entrypoint": ["/bin/app"],
+				"workdir": "/home/app",
+				"ports": [{"Port": 8888, "Protocol": "tcp"}],
+				"user": "1000:1000"
+			}`))
+		})
+
+		Context("when the image has no entrypoint, command, user or working dir", func() {
+			BeforeEach(func() {
+				imageConfig.Entrypoint = nil
+				imageConfig.Cmd = nil
+				imageConfig.WorkingDir = ""
+				imageConfig.User = ""
+				allowRunAsRoot = true
+			})
+
+			It("sends empty execution metadata", func() {
+				_, taskCompletedRequest := stagingCompleter.CompleteStagingArgsForCall(0)
+
+				var payload bifrost.StagingResult
+				Expect(json.Unmarshal([]byte(taskCompletedRequest.Result), &payload)).To(Succeed())
+
+				Expect(payload.ProcessTypes.Web).To(BeEmpty())
+				Expect(payload.ExecutionMetadata).To(Equal(`{"cmd":[],"ports":[{"Port":8888,"Protocol":"tcp"}]}`))
+			})
+		})
+
+		DescribeTable("images running as root",
+			func(user string) {
+				imageConfig.User = user
+
+				stagingErr = stager.TransferStaging(context.Background(), "stg-guid", stagingRequest)
+				Expect(stagingErr).ToNot(HaveOccurred())
+
+				Expect(stagingCompleter.CompleteStagingCallCount()).To(Equal(2))
+				_, taskCallbackResponse := stagingCompleter.CompleteStagingArgsForCall(1)
+				Expect(taskCallbackResponse.Failed).To(BeTrue())
+				Expect(taskCallbackResponse.FailureReason).To(ContainSubstring("image runs as root"))
+			},
+			Entry("no user", ""),
+			Entry("root user", "root"),
+			Entry("root user and group", "root:root"),
+			Entry("root uid", "0"),
+			Entry("root uid and group", "0:1000"),
+		)
+
+		Context("when the image runs as a non-numeric user", func() {
+			BeforeEach(func() {
+				imageConfig.User = "vcap"
+			})
+
+			It("fails staging as the user cannot be verified as non-root", func() {
+				Expect(stagingErr).ToNot(HaveOccurred())
+
+				_, taskCallbackResponse := stagingCompleter.CompleteStagingArgsForCall(0)
+				Expect(taskCallbackResponse.Failed).To(BeTrue())
+				Expect(taskCallbackResponse.FailureReason).To(ContainSubstring(`non-numeric user "vcap"`))
+			})
+		})
+
+		Context("when running images as root is allowed", func() {
+			BeforeEach(func() {
+				imageConfig.User = "root"
+				allowRunAsRoot = true
+			})
+
+			It("stages the image", func() {
+				_, taskCallbackResponse := stagingCompleter.CompleteStagingArgsForCall(0)
+				Expect(taskCallbackResponse.Failed).To(BeFalse())
+			})
+		})
+
+		Context("when the image is already pinned to a digest", func() {
+			BeforeEach(func() {
+				stagingRequest.Lifecycle.DockerLifecycle.Image = "eirini/some-app@sha256:8e8f4d8c1d1b9a5a0ff7b1a6b1c6b8a7f5b2e0e9d9c4c1f3b2a1e0d9c8b7a6f5"
+			})
+
+			It("pins the image to the resolved digest", func() {
+				_, taskCompletedRequest := stagingCompleter.CompleteStagingArgsForCall(0)
+
+				var payload bifrost.StagingResult
+				Expect(json.Unmarshal([]byte(taskCompletedRequest.Result), &payload)).To(Succeed())
+				Expect(payload.LifecycleMetadata.DockerImage).To(Equal("eirini/some-app@" + imageDigest))
+			})
+		})
+
+		Context("when the resolved digest is invalid", func() {
+			BeforeEach(func() {
+				fetcher.Returns(imageConfig, "not-a-digest", nil)
+			})
+
+			It("should respond to the callback url with failure", func() {
+				Expect(stagingErr).ToNot(HaveOccurred())
+
+				_, taskCallbackResponse := stagingCompleter.CompleteStagingArgsForCall(0)
+				Expect(taskCallbackResponse.Failed).To(BeTrue())
+				Expect(taskCallbackResponse.FailureReason).To(ContainSubstring("failed to pin image to digest"))
+			})
 		})
 
 		Context("when the image is from a private registry", func() {
@@ -107,7 +283,7 @@ var _ = Describe("DockerStager", func() {
 
 			It("should provide the correct credentials", func() {
 				Expect(fetcher.CallCount()).To(Equal(1))
-				_, ctx := fetcher.ArgsForCall(0)
+				_, _, ctx := fetcher.ArgsForCall(0)
 				Expect(ctx.DockerAuthConfig.Username).To(Equal("some-user"))
 				Expect(ctx.DockerAuthConfig.Password).To(Equal("thepasswrd"))
 			})
@@ -148,9 +324,30 @@ var _ = Describe("DockerStager", func() {
 			})
 		})
 
+		Context("when fetching the image metadata takes too long", func() {
+			BeforeEach(func() {
+				fetchTimeout = 10 * time.Millisecond
+				fetcher.Stub = func(ctx context.Context, _ string, _ types.SystemContext) (*v1.ImageConfig, digest.Digest, error) {
+					<-ctx.Done()
+
+					return nil, "", ctx.Err()
+				}
+			})
+
+			It("should respond to the callback url with failure", func() {
+				Expect(stagingErr).ToNot(HaveOccurred())
+				Expect(stagingCompleter.CompleteStagingCallCount()).To(Equal(1))
+
+				ctx, taskCallbackResponse := stagingCompleter.CompleteStagingArgsForCall(0)
+				Expect(ctx.Err()).NotTo(HaveOccurred())
+				Expect(taskCallbackResponse.Failed).To(BeTrue())
+				Expect(taskCallbackResponse.FailureReason).To(ContainSubstring("context deadline exceeded"))
+			})
+		})
+
 		Context("when metadata fetching fails", func() {
 			BeforeEach(func() {
-				fetcher.Returns(nil, errors.New("boom"))
+				fetcher.Returns(nil, "", errors.New("boom"))
 			})
 
 			It("should fail with the right error", func() {
@@ -179,7 +376,7 @@ var _ = Describe("DockerStager", func() {
 					ExposedPorts: map[string]struct{}{
 						"invalid-port-spec": {},
 					},
-				}, nil)
+				}, imageDigest, nil)
 			})
 
 			It("should respond to the callback url with failure", func() {
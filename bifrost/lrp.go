@@ -2,6 +2,8 @@ package bifrost
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
 
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s/shared"
@@ -12,6 +14,7 @@ import (
 //counterfeiter:generate . LRPConverter
 //counterfeiter:generate . LRPClient
 //counterfeiter:generate . LRPNamespacer
+//counterfeiter:generate . LRPGetter
 
 type LRPConverter interface {
 	ConvertLRP(request cf.DesireLRPRequest) (api.LRP, error)
@@ -31,10 +34,20 @@ type LRPNamespacer interface {
 	GetNamespace(requestedNamespace string) string
 }
 
+type LRPGetter interface {
+	Get(ctx context.Context, identifier api.LRPIdentifier) (*api.LRP, error)
+}
+
 type LRP struct {
-	Converter  LRPConverter
-	LRPClient  LRPClient
-	Namespacer LRPNamespacer
+	Converter   LRPConverter
+	LRPClient   LRPClient
+	Namespacer  LRPNamespacer
+	ImagePolicy ImagePolicy
+
+	// LiveGetter, if set, reads the LRP an update is applied to instead of
+	// LRPClient. Updates are merged into the stored desire request, so they
+	// must not be based on a cached LRP that may miss a previous update.
+	LiveGetter LRPGetter
 }
 
 func (l *LRP) Transfer(ctx context.Context, request cf.DesireLRPRequest) error {
@@ -78,19 +91,155 @@ func (l *LRP) Update(ctx context.Context, request cf.UpdateDesiredLRPRequest) er
 		Version: request.Version,
 	}
 
-	lrp, err := l.LRPClient.Get(ctx, identifier)
+	lrp, err := l.liveGetter().Get(ctx, identifier)
 	if err != nil {
 		return errors.Wrap(err, "failed to get app")
 	}
 
+	if isJSONObject(lrp.LRP) {
+		lrp, err = l.updateOriginalRequest(lrp, request.Update)
+		if err != nil {
+			return err
+		}
+
+		return errors.Wrap(l.LRPClient.Update(ctx, lrp), "failed to update")
+	}
+
+	if changesSpec(request.Update) {
+		return errors.New("cannot change the spec of an app without an original desire request")
+	}
+
+	if request.Update.Image != "" {
+		if err = l.ImagePolicy.Check(request.Update.Image); err != nil {
+			return err
+		}
+	}
+
 	lrp.TargetInstances = request.Update.Instances
 	lrp.LastUpdated = request.Update.Annotation
 
 	lrp.Image = request.Update.Image
 
+	if request.Update.Routes != nil {
+		routes, err := ConvertRoutes(request.Update.Routes)
+		if err != nil {
+			return errors.Wrap(err, "failed to convert routes")
+		}
+
+		lrp.Routes = routes
+	}
+
 	return errors.Wrap(l.LRPClient.Update(ctx, lrp), "failed to update")
 }
 
+func (l *LRP) liveGetter() LRPGetter {
+	if l.LiveGetter != nil {
+		return l.LiveGetter
+	}
+
+	return l.LRPClient
+}
+
+// updateOriginalRequest applies the update to the request the LRP was
+// desired with and converts the result, so that the whole LRP spec can
+// change without a new version. Fields of the original request that eirini
+// does not know about are preserved.
+func (l *LRP) updateOriginalRequest(lrp *api.LRP, update cf.DesiredLRPUpdate) (*api.LRP, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(lrp.LRP), &fields); err != nil {
+		return nil, errors.Wrap(err, "failed to parse original request")
+	}
+
+	// the spec fields of an update are named after the desire request fields
+	patch, err := toFields(update)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal update")
+	}
+
+	delete(patch, "annotation")
+	delete(patch, "image")
+
+	if patch["last_updated"], err = json.Marshal(update.Annotation); err != nil {
+		return nil, errors.Wrap(err, "failed to marshal update")
+	}
+
+	if update.Image != "" {
+		lifecycle := cf.Lifecycle{}
+		if err = json.Unmarshal(fields["lifecycle"], &lifecycle); err != nil || lifecycle.DockerLifecycle == nil {
+			return nil, errors.New("cannot change the image of an app without a docker lifecycle")
+		}
+
+		lifecycle.DockerLifecycle.Image = update.Image
+
+		if patch["lifecycle"], err = json.Marshal(lifecycle); err != nil {
+			return nil, errors.Wrap(err, "failed to marshal lifecycle")
+		}
+	}
+
+	for key, value := range patch {
+		fields[key] = value
+	}
+
+	updatedRequestJSON, err := json.Marshal(fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal updated request")
+	}
+
+	var updatedRequest cf.DesireLRPRequest
+	if err = json.Unmarshal(updatedRequestJSON, &updatedRequest); err != nil {
+		return nil, errors.Wrap(err, "failed to parse updated request")
+	}
+
+	updatedRequest.LRP = string(updatedRequestJSON)
+
+	updatedLRP, err := l.Converter.ConvertLRP(updatedRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert request")
+	}
+
+	return &updatedLRP, nil
+}
+
+func toFields(v interface{}) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(data, &fields)
+
+	return fields, err
+}
+
+func changesSpec(update cf.DesiredLRPUpdate) bool {
+	return update.Environment != nil ||
+		update.MemoryMB != nil ||
+		update.DiskMB != nil ||
+		update.CPUWeight != nil ||
+		update.Ports != nil ||
+		update.PortProtocols != nil ||
+		update.HealthCheckType != nil ||
+		update.HealthCheckHTTPEndpoint != nil ||
+		update.HealthCheckTimeoutMs != nil ||
+		update.HealthCheckInvocationTimeoutMs != nil ||
+		update.HealthCheckIntervalMs != nil ||
+		update.HealthCheckPort != nil ||
+		update.ReadinessHealthCheckType != nil ||
+		update.ReadinessHealthCheckHTTPEndpoint != nil ||
+		update.ReadinessHealthCheckInvocationTimeoutMs != nil ||
+		update.ReadinessHealthCheckIntervalMs != nil ||
+		update.ReadinessHealthCheckPort != nil ||
+		update.StartTimeoutMs != nil ||
+		update.UserDefinedAnnotations != nil
+}
+
+func isJSONObject(s string) bool {
+	fields := map[string]json.RawMessage{}
+
+	return json.Unmarshal([]byte(s), &fields) == nil
+}
+
 func (l *LRP) GetApp(ctx context.Context, identifier api.LRPIdentifier) (cf.DesiredLRP, error) {
 	lrp, err := l.LRPClient.Get(ctx, identifier)
 	if err != nil {
@@ -98,15 +247,112 @@ func (l *LRP) GetApp(ctx context.Context, identifier api.LRPIdentifier) (cf.Desi
 	}
 
 	desiredLRP := cf.DesiredLRP{
-		ProcessGUID: identifier.ProcessGUID(),
-		Instances:   int32(lrp.TargetInstances),
-		Annotation:  lrp.LastUpdated,
-		Image:       lrp.Image,
+		ProcessGUID:          identifier.ProcessGUID(),
+		Instances:            int32(lrp.TargetInstances),
+		Annotation:           lrp.LastUpdated,
+		Image:                lrp.Image,
+		EnvironmentVariables: toEnvironmentVariables(lrp.Env),
+		Ports:                lrp.Ports,
+		MemoryMB:             lrp.MemoryMB,
+		DiskMB:               lrp.DiskMB,
+		CPUWeight:            lrp.CPUWeight,
+		StartTimeoutMs:       lrp.Health.StartTimeoutMs,
+		CheckDefinition:      toCheckDefinition(lrp.Health, lrp.Readiness),
+		PlacementTags:        lrp.PlacementTags,
+		EgressRules:          lrp.EgressRules,
+	}
+
+	routes, err := toCFRoutes(lrp.Routes)
+	if err != nil {
+		return cf.DesiredLRP{}, errors.Wrap(err, "failed to convert routes")
 	}
 
+	desiredLRP.Routes = routes
+
 	return desiredLRP, nil
 }
 
+func toEnvironmentVariables(env map[string]string) []cf.EnvironmentVariable {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	vars := make([]cf.EnvironmentVariable, 0, len(env))
+	for _, name := range names {
+		vars = append(vars, cf.EnvironmentVariable{Name: name, Value: env[name]})
+	}
+
+	return vars
+}
+
+func toCheckDefinition(health, readiness api.Healthcheck) *cf.CheckDefinition {
+	checks := toChecks(health)
+	readinessChecks := toChecks(readiness)
+
+	if len(checks) == 0 && len(readinessChecks) == 0 {
+		return nil
+	}
+
+	return &cf.CheckDefinition{
+		Checks:          checks,
+		ReadinessChecks: readinessChecks,
+	}
+}
+
+func toChecks(healthcheck api.Healthcheck) []cf.Check {
+	switch healthcheck.Type {
+	case api.HealthCheckTypeHTTP:
+		return []cf.Check{{HTTPCheck: &cf.HTTPCheck{
+			Port:             healthcheck.Port,
+			RequestTimeoutMs: healthcheck.InvocationTimeoutMs,
+			Path:             healthcheck.Endpoint,
+			IntervalMs:       healthcheck.IntervalMs,
+		}}}
+	case api.HealthCheckTypePort:
+		return []cf.Check{{TCPCheck: &cf.TCPCheck{
+			Port:             healthcheck.Port,
+			ConnectTimeoutMs: healthcheck.InvocationTimeoutMs,
+			IntervalMs:       healthcheck.IntervalMs,
+		}}}
+	default:
+		return []cf.Check{}
+	}
+}
+
+func toCFRoutes(routes api.Routes) (map[string]json.RawMessage, error) {
+	if routes.IsEmpty() {
+		return nil, nil
+	}
+
+	httpRoutes := []cf.Route{}
+	for _, r := range routes.HTTP {
+		httpRoutes = append(httpRoutes, cf.Route{Hostname: r.Hostname, Port: r.Port})
+	}
+
+	tcpRoutes := []cf.TCPRoute{}
+	for _, r := range routes.TCP {
+		tcpRoutes = append(tcpRoutes, cf.TCPRoute{ExternalPort: r.ExternalPort, ContainerPort: r.ContainerPort})
+	}
+
+	httpBytes, err := json.Marshal(httpRoutes)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpBytes, err := json.Marshal(tcpRoutes)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]json.RawMessage{
+		HTTPRouterKey: httpBytes,
+		TCPRouterKey:  tcpBytes,
+	}, nil
+}
+
 func (l *LRP) Stop(ctx context.Context, identifier api.LRPIdentifier) error {
 	return errors.Wrap(l.LRPClient.Stop(ctx, identifier), "failed to stop app")
 }
@@ -2,8 +2,10 @@ package bifrost_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
+	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/bifrost"
 	"code.cloudfoundry.org/eirini/bifrost/bifrostfakes"
@@ -20,6 +22,8 @@ var _ = Describe("Bifrost LRP", func() {
 		lrpConverter  *bifrostfakes.FakeLRPConverter
 		lrpClient     *bifrostfakes.FakeLRPClient
 		lrpNamespacer *bifrostfakes.FakeLRPNamespacer
+		imagePolicy   *bifrostfakes.FakeImagePolicy
+		liveGetter    bifrost.LRPGetter
 	)
 
 	BeforeEach(func() {
@@ -27,6 +31,8 @@ var _ = Describe("Bifrost LRP", func() {
 		lrpClient = new(bifrostfakes.FakeLRPClient)
 		lrpNamespacer = new(bifrostfakes.FakeLRPNamespacer)
 		lrpNamespacer.GetNamespaceReturns("my-namespace")
+		imagePolicy = new(bifrostfakes.FakeImagePolicy)
+		liveGetter = nil
 
 		request = cf.DesireLRPRequest{
 			GUID:      "my-guid",
@@ -36,9 +42,11 @@ var _ = Describe("Bifrost LRP", func() {
 
 	JustBeforeEach(func() {
 		lrpBifrost = &bifrost.LRP{
-			Converter:  lrpConverter,
-			LRPClient:  lrpClient,
-			Namespacer: lrpNamespacer,
+			Converter:   lrpConverter,
+			LRPClient:   lrpClient,
+			Namespacer:  lrpNamespacer,
+			ImagePolicy: imagePolicy,
+			LiveGetter:  liveGetter,
 		}
 	})
 
@@ -191,6 +199,9 @@ var _ = Describe("Bifrost LRP", func() {
 			lrpClient.GetReturns(&api.LRP{
 				TargetInstances: 2,
 				LastUpdated:     "whenever",
+				Routes: api.Routes{
+					HTTP: []api.HTTPRoute{{Hostname: "old.example.com", Port: 8080}},
+				},
 			}, nil)
 
 			lrpClient.UpdateReturns(nil)
@@ -211,6 +222,24 @@ var _ = Describe("Bifrost LRP", func() {
 			Expect(identifier.Version).To(Equal("version_1234"))
 		})
 
+		When("a live getter is set", func() {
+			var fakeLiveGetter *bifrostfakes.FakeLRPGetter
+
+			BeforeEach(func() {
+				fakeLiveGetter = new(bifrostfakes.FakeLRPGetter)
+				fakeLiveGetter.GetReturns(&api.LRP{TargetInstances: 2}, nil)
+				liveGetter = fakeLiveGetter
+			})
+
+			It("gets the existing LRP through the live getter", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeLiveGetter.GetCallCount()).To(Equal(1))
+				_, identifier := fakeLiveGetter.GetArgsForCall(0)
+				Expect(identifier.GUID).To(Equal("guid_1234"))
+				Expect(lrpClient.GetCallCount()).To(BeZero())
+			})
+		})
+
 		It("should submit the updated LRP", func() {
 			Expect(lrpClient.UpdateCallCount()).To(Equal(1))
 			_, lrp := lrpClient.UpdateArgsForCall(0)
@@ -219,6 +248,72 @@ var _ = Describe("Bifrost LRP", func() {
 			Expect(lrp.Image).To(Equal("the/image"))
 		})
 
+		It("should keep the existing routes", func() {
+			_, lrp := lrpClient.UpdateArgsForCall(0)
+			Expect(lrp.Routes.HTTP).To(ConsistOf(api.HTTPRoute{Hostname: "old.example.com", Port: 8080}))
+		})
+
+		It("should check the new image against the image policy", func() {
+			Expect(imagePolicy.CheckCallCount()).To(Equal(1))
+			Expect(imagePolicy.CheckArgsForCall(0)).To(Equal("the/image"))
+		})
+
+		Context("when the new image is not allowed", func() {
+			BeforeEach(func() {
+				imagePolicy.CheckReturns(eirini.ErrImageNotAllowed)
+			})
+
+			It("should not submit anything to be updated", func() {
+				Expect(lrpClient.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("should return an image not allowed error", func() {
+				Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+			})
+		})
+
+		Context("when the update does not change the image", func() {
+			BeforeEach(func() {
+				updateRequest.Update.Image = ""
+			})
+
+			It("should not check the image policy", func() {
+				Expect(imagePolicy.CheckCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the update contains routes", func() {
+			BeforeEach(func() {
+				updateRequest.Update.Routes = map[string]json.RawMessage{
+					"cf-router":  json.RawMessage(`[{"hostname":"new.example.com","port":9090}]`),
+					"tcp-router": json.RawMessage(`[]`),
+				}
+			})
+
+			It("should replace the routes", func() {
+				_, lrp := lrpClient.UpdateArgsForCall(0)
+				Expect(lrp.Routes).To(Equal(api.Routes{
+					HTTP: []api.HTTPRoute{{Hostname: "new.example.com", Port: 9090}},
+				}))
+			})
+		})
+
+		Context("when the update contains malformed routes", func() {
+			BeforeEach(func() {
+				updateRequest.Update.Routes = map[string]json.RawMessage{
+					"cf-router": json.RawMessage(`"nope"`),
+				}
+			})
+
+			It("should not submit anything to be updated", func() {
+				Expect(lrpClient.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("should propagate the error", func() {
+				Expect(err).To(MatchError(ContainSubstring("failed to convert routes")))
+			})
+		})
+
 		Context("when the update fails", func() {
 			BeforeEach(func() {
 				lrpClient.UpdateReturns(errors.New("your app is bad"))
@@ -229,6 +324,153 @@ var _ = Describe("Bifrost LRP", func() {
 			})
 		})
 
+		Context("when the update changes the spec", func() {
+			BeforeEach(func() {
+				memory := int64(512)
+				updateRequest.Update.MemoryMB = &memory
+			})
+
+			It("should not submit anything to be updated", func() {
+				Expect(lrpClient.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("without an original desire request")))
+			})
+		})
+
+		Context("when the update changes the readiness health check", func() {
+			BeforeEach(func() {
+				readinessPort := int32(9000)
+				updateRequest.Update.ReadinessHealthCheckPort = &readinessPort
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("without an original desire request")))
+				Expect(lrpClient.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the app was desired with an original request", func() {
+			var convertedLRP api.LRP
+
+			BeforeEach(func() {
+				lrpClient.GetReturns(&api.LRP{
+					TargetInstances: 2,
+					LRP: `{
+						"guid": "guid_1234",
+						"instances": 2,
+						"memory_mb": 256,
+						"environment": {"FOO": "old"},
+						"lifecycle": {"docker_lifecycle": {"image": "old/image", "command": ["run"]}},
+						"unknown_field": "keep me"
+					}`,
+				}, nil)
+
+				convertedLRP = api.LRP{LRPIdentifier: api.LRPIdentifier{GUID: "guid_1234"}, MemoryMB: 1024}
+				lrpConverter.ConvertLRPReturns(convertedLRP, nil)
+
+				memory := int64(1024)
+				healthCheckType := "http"
+				updateRequest.Update.MemoryMB = &memory
+				updateRequest.Update.HealthCheckType = &healthCheckType
+				updateRequest.Update.Environment = map[string]string{"FOO": "new"}
+				updateRequest.Update.Ports = []int32{9000}
+				updateRequest.Update.PortProtocols = map[int32]string{9000: "udp"}
+			})
+
+			It("should convert the updated original request", func() {
+				Expect(lrpConverter.ConvertLRPCallCount()).To(Equal(1))
+				desireRequest := lrpConverter.ConvertLRPArgsForCall(0)
+				Expect(desireRequest.GUID).To(Equal("guid_1234"))
+				Expect(desireRequest.NumInstances).To(Equal(5))
+				Expect(desireRequest.LastUpdated).To(Equal("21421321.3"))
+				Expect(desireRequest.MemoryMB).To(Equal(int64(1024)))
+				Expect(desireRequest.HealthCheckType).To(Equal("http"))
+				Expect(desireRequest.Environment).To(Equal(map[string]string{"FOO": "new"}))
+				Expect(desireRequest.Ports).To(Equal([]int32{9000}))
+				Expect(desireRequest.PortProtocols).To(Equal(map[int32]string{9000: "udp"}))
+				Expect(desireRequest.Lifecycle.DockerLifecycle.Image).To(Equal("the/image"))
+				Expect(desireRequest.Lifecycle.DockerLifecycle.Command).To(Equal([]string{"run"}))
+			})
+
+			It("should keep the updated original request on the LRP", func() {
+				desireRequest := lrpConverter.ConvertLRPArgsForCall(0)
+
+				var fields map[string]interface{}
+				Expect(json.Unmarshal([]byte(desireRequest.LRP), &fields)).To(Succeed())
+				Expect(fields).To(HaveKeyWithValue("memory_mb", BeNumerically("==", 1024)))
+				Expect(fields).To(HaveKeyWithValue("unknown_field", "keep me"))
+				Expect(fields).NotTo(HaveKey("annotation"))
+			})
+
+			It("should submit the converted LRP", func() {
+				Expect(lrpClient.UpdateCallCount()).To(Equal(1))
+				_, lrp := lrpClient.UpdateArgsForCall(0)
+				Expect(*lrp).To(Equal(convertedLRP))
+			})
+
+			Context("when the update changes the readiness health check and the health check ports", func() {
+				BeforeEach(func() {
+					readinessType := "http"
+					readinessEndpoint := "/ready"
+					readinessTimeout := uint(2000)
+					readinessInterval := uint(3000)
+					healthCheckPort := int32(9001)
+					readinessPort := int32(9002)
+
+					updateRequest.Update = cf.DesiredLRPUpdate{
+						Instances:                               3,
+						Annotation:                              "later",
+						HealthCheckPort:                         &healthCheckPort,
+						ReadinessHealthCheckType:                &readinessType,
+						ReadinessHealthCheckHTTPEndpoint:        &readinessEndpoint,
+						ReadinessHealthCheckInvocationTimeoutMs: &readinessTimeout,
+						ReadinessHealthCheckIntervalMs:          &readinessInterval,
+						ReadinessHealthCheckPort:                &readinessPort,
+					}
+				})
+
+				It("should apply them to the original request", func() {
+					desireRequest := lrpConverter.ConvertLRPArgsForCall(0)
+					Expect(desireRequest.HealthCheckPort).To(Equal(int32(9001)))
+					Expect(desireRequest.ReadinessHealthCheckType).To(Equal("http"))
+					Expect(desireRequest.ReadinessHealthCheckHTTPEndpoint).To(Equal("/ready"))
+					Expect(desireRequest.ReadinessHealthCheckInvocationTimeoutMs).To(Equal(uint(2000)))
+					Expect(desireRequest.ReadinessHealthCheckIntervalMs).To(Equal(uint(3000)))
+					Expect(desireRequest.ReadinessHealthCheckPort).To(Equal(int32(9002)))
+				})
+			})
+
+			Context("when the update leaves fields out", func() {
+				BeforeEach(func() {
+					updateRequest.Update = cf.DesiredLRPUpdate{Instances: 3, Annotation: "later"}
+				})
+
+				It("should keep their original values", func() {
+					desireRequest := lrpConverter.ConvertLRPArgsForCall(0)
+					Expect(desireRequest.NumInstances).To(Equal(3))
+					Expect(desireRequest.MemoryMB).To(Equal(int64(256)))
+					Expect(desireRequest.Environment).To(Equal(map[string]string{"FOO": "old"}))
+					Expect(desireRequest.Lifecycle.DockerLifecycle.Image).To(Equal("old/image"))
+				})
+			})
+
+			Context("when converting the updated request fails", func() {
+				BeforeEach(func() {
+					lrpConverter.ConvertLRPReturns(api.LRP{}, errors.New("boom"))
+				})
+
+				It("should not submit anything to be updated", func() {
+					Expect(lrpClient.UpdateCallCount()).To(Equal(0))
+				})
+
+				It("should propagate the error", func() {
+					Expect(err).To(MatchError(ContainSubstring("failed to convert request")))
+				})
+			})
+		})
+
 		Context("when the app does not exist", func() {
 			BeforeEach(func() {
 				lrpClient.GetReturns(nil, errors.New("app does not exist"))
@@ -263,6 +505,28 @@ var _ = Describe("Bifrost LRP", func() {
 					TargetInstances: 5,
 					LastUpdated:     "1234.5",
 					Image:           "the/image",
+					Routes: api.Routes{
+						HTTP: []api.HTTPRoute{{Hostname: "app.example.com", Port: 8080}},
+					},
+					Env:           map[string]string{"B": "2", "A": "1"},
+					Ports:         []int32{8080},
+					MemoryMB:      256,
+					DiskMB:        512,
+					CPUWeight:     10,
+					PlacementTags: []string{"isolated"},
+					EgressRules:   []json.RawMessage{json.RawMessage(`{"protocol":"all"}`)},
+					Health: api.Healthcheck{
+						Type:                "http",
+						Port:                8080,
+						Endpoint:            "/healthz",
+						StartTimeoutMs:      60000,
+						InvocationTimeoutMs: 1000,
+						IntervalMs:          3000,
+					},
+					Readiness: api.Healthcheck{
+						Type: "port",
+						Port: 8080,
+					},
 				}
 
 				lrpClient.GetReturns(lrp, nil)
@@ -283,6 +547,47 @@ var _ = Describe("Bifrost LRP", func() {
 				Expect(desiredLRP.Instances).To(Equal(int32(5)))
 				Expect(desiredLRP.Annotation).To(Equal("1234.5"))
 				Expect(desiredLRP.Image).To(Equal("the/image"))
+				Expect(desiredLRP.Routes).To(HaveKeyWithValue("cf-router", MatchJSON(`[{"hostname":"app.example.com","port":8080}]`)))
+				Expect(desiredLRP.Routes).To(HaveKeyWithValue("tcp-router", MatchJSON(`[]`)))
+			})
+
+			It("should return the resources, ports and placement of the DesiredLRP", func() {
+				desiredLRP, _ := lrpBifrost.GetApp(context.Background(), identifier)
+				Expect(desiredLRP.EnvironmentVariables).To(Equal([]cf.EnvironmentVariable{
+					{Name: "A", Value: "1"},
+					{Name: "B", Value: "2"},
+				}))
+				Expect(desiredLRP.Ports).To(Equal([]int32{8080}))
+				Expect(desiredLRP.MemoryMB).To(Equal(int64(256)))
+				Expect(desiredLRP.DiskMB).To(Equal(int64(512)))
+				Expect(desiredLRP.CPUWeight).To(Equal(uint8(10)))
+				Expect(desiredLRP.PlacementTags).To(ConsistOf("isolated"))
+				Expect(desiredLRP.EgressRules).To(ConsistOf(MatchJSON(`{"protocol":"all"}`)))
+			})
+
+			It("should return the health checks of the DesiredLRP", func() {
+				desiredLRP, _ := lrpBifrost.GetApp(context.Background(), identifier)
+				Expect(desiredLRP.StartTimeoutMs).To(Equal(uint(60000)))
+				Expect(desiredLRP.CheckDefinition).To(Equal(&cf.CheckDefinition{
+					Checks: []cf.Check{
+						{HTTPCheck: &cf.HTTPCheck{Port: 8080, RequestTimeoutMs: 1000, Path: "/healthz", IntervalMs: 3000}},
+					},
+					ReadinessChecks: []cf.Check{
+						{TCPCheck: &cf.TCPCheck{Port: 8080}},
+					},
+				}))
+			})
+
+			Context("when the app uses a process health check", func() {
+				BeforeEach(func() {
+					lrp.Health = api.Healthcheck{Type: "process"}
+					lrp.Readiness = api.Healthcheck{}
+				})
+
+				It("should not return a check definition", func() {
+					desiredLRP, _ := lrpBifrost.GetApp(context.Background(), identifier)
+					Expect(desiredLRP.CheckDefinition).To(BeNil())
+				})
 			})
 		})
 
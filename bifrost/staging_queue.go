@@ -0,0 +1,72 @@
+package bifrost
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/lager"
+	"github.com/pkg/errors"
+)
+
+//counterfeiter:generate . StagingBifrost
+
+type StagingBifrost interface {
+	TransferStaging(ctx context.Context, stagingGUID string, request cf.StagingRequest) error
+	CompleteStaging(ctx context.Context, request cf.StagingCompletedRequest) error
+}
+
+type stagingJob struct {
+	stagingGUID string
+	request     cf.StagingRequest
+}
+
+// StagingQueue accepts staging requests without waiting for them to be
+// staged. The requests are staged by a fixed number of workers. When the
+// queue is full, new requests are rejected with eirini.ErrStagingQueueFull.
+type StagingQueue struct {
+	StagingBifrost
+	logger  lager.Logger
+	workers int
+	jobs    chan stagingJob
+}
+
+func NewStagingQueue(logger lager.Logger, stagingBifrost StagingBifrost, workers, queueSize int) *StagingQueue {
+	return &StagingQueue{
+		StagingBifrost: stagingBifrost,
+		logger:         logger.Session("staging-queue"),
+		workers:        workers,
+		jobs:           make(chan stagingJob, queueSize),
+	}
+}
+
+// Start starts the workers, which stop when ctx is done.
+func (q *StagingQueue) Start(ctx context.Context) {
+	for i := 0; i < q.workers; i++ {
+		go q.work(ctx)
+	}
+}
+
+func (q *StagingQueue) TransferStaging(_ context.Context, stagingGUID string, request cf.StagingRequest) error {
+	select {
+	case q.jobs <- stagingJob{stagingGUID: stagingGUID, request: request}:
+		return nil
+	default:
+		return errors.Wrapf(eirini.ErrStagingQueueFull, "cannot accept staging %q", stagingGUID)
+	}
+}
+
+func (q *StagingQueue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-q.jobs:
+			// The request context is gone by now, so the staging gets the
+			// context of the queue.
+			if err := q.StagingBifrost.TransferStaging(ctx, job.stagingGUID, job.request); err != nil {
+				q.logger.Error("staging-failed", err, lager.Data{"staging-guid": job.stagingGUID})
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package bifrost_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/bifrost"
+	"code.cloudfoundry.org/eirini/bifrost/bifrostfakes"
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StagingQueue", func() {
+	var (
+		stagingBifrost *bifrostfakes.FakeStagingBifrost
+		queue          *bifrost.StagingQueue
+		cancel         context.CancelFunc
+		release        chan struct{}
+		transferErr    error
+	)
+
+	BeforeEach(func() {
+		stagingBifrost = new(bifrostfakes.FakeStagingBifrost)
+		release = make(chan struct{})
+		stagingRelease := release
+		stagingBifrost.TransferStagingStub = func(context.Context, string, cf.StagingRequest) error {
+			<-stagingRelease
+
+			return nil
+		}
+
+		queue = bifrost.NewStagingQueue(tests.NewTestLogger("staging-queue"), stagingBifrost, 1, 1)
+
+		var queueCtx context.Context
+		queueCtx, cancel = context.WithCancel(context.Background())
+		queue.Start(queueCtx)
+	})
+
+	AfterEach(func() {
+		cancel()
+		close(release)
+	})
+
+	JustBeforeEach(func() {
+		transferErr = queue.TransferStaging(ctx, "the-guid", cf.StagingRequest{AppGUID: "the-app"})
+	})
+
+	It("accepts the staging", func() {
+		Expect(transferErr).NotTo(HaveOccurred())
+	})
+
+	It("stages in the background", func() {
+		Eventually(stagingBifrost.TransferStagingCallCount).Should(Equal(1))
+		_, guid, request := stagingBifrost.TransferStagingArgsForCall(0)
+		Expect(guid).To(Equal("the-guid"))
+		Expect(request.AppGUID).To(Equal("the-app"))
+	})
+
+	It("does not stage with the request context", func() {
+		Eventually(stagingBifrost.TransferStagingCallCount).Should(Equal(1))
+		stagingCtx, _, _ := stagingBifrost.TransferStagingArgsForCall(0)
+		Expect(stagingCtx).NotTo(Equal(ctx))
+	})
+
+	When("the workers are busy", func() {
+		JustBeforeEach(func() {
+			Eventually(stagingBifrost.TransferStagingCallCount).Should(Equal(1))
+		})
+
+		It("queues the staging", func() {
+			Expect(queue.TransferStaging(ctx, "another-guid", cf.StagingRequest{})).To(Succeed())
+		})
+
+		When("the queue is full", func() {
+			JustBeforeEach(func() {
+				Expect(queue.TransferStaging(ctx, "another-guid", cf.StagingRequest{})).To(Succeed())
+			})
+
+			It("rejects the staging", func() {
+				err := queue.TransferStaging(ctx, "yet-another-guid", cf.StagingRequest{})
+				Expect(errors.Is(err, eirini.ErrStagingQueueFull)).To(BeTrue())
+			})
+
+			It("stages the queued staging once a worker is free", func() {
+				release <- struct{}{}
+				Eventually(stagingBifrost.TransferStagingCallCount).Should(Equal(2))
+				_, guid, _ := stagingBifrost.TransferStagingArgsForCall(1)
+				Expect(guid).To(Equal("another-guid"))
+			})
+		})
+	})
+
+	Describe("CompleteStaging", func() {
+		It("delegates to the staging bifrost", func() {
+			Expect(queue.CompleteStaging(ctx, cf.StagingCompletedRequest{TaskGUID: "the-guid"})).To(Succeed())
+			Expect(stagingBifrost.CompleteStagingCallCount()).To(Equal(1))
+		})
+	})
+})
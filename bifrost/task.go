@@ -84,15 +84,9 @@ func (t *Task) CancelTask(ctx context.Context, taskGUID string) error {
 		return nil
 	}
 
-	go func() {
-		// We need to pass context.Background() here as the request context
-		// will be cancelled as soon as the HTTP response is returned.
-		_ = t.JSONClient.Post(context.Background(), callbackURL, cf.TaskCompletedRequest{
-			TaskGUID:      taskGUID,
-			Failed:        true,
-			FailureReason: "task was cancelled",
-		})
-	}()
-
-	return nil
+	return errors.Wrap(t.JSONClient.Post(ctx, callbackURL, cf.TaskCompletedRequest{
+		TaskGUID:      taskGUID,
+		Failed:        true,
+		FailureReason: "task was cancelled",
+	}), "failed to send task cancelled callback")
 }
@@ -216,7 +216,7 @@ var _ = Describe("Task", func() {
 		})
 
 		It("notifies the cloud controller", func() {
-			Eventually(jsonClient.PostCallCount).Should(Equal(1))
+			Expect(jsonClient.PostCallCount()).To(Equal(1))
 
 			_, url, data := jsonClient.PostArgsForCall(0)
 			Expect(url).To(Equal("the/callback/url"))
@@ -232,8 +232,8 @@ var _ = Describe("Task", func() {
 				jsonClient.PostReturns(errors.New("cc-error"))
 			})
 
-			It("still succeeds", func() {
-				Expect(err).NotTo(HaveOccurred())
+			It("returns the error", func() {
+				Expect(err).To(MatchError(ContainSubstring("cc-error")))
 			})
 		})
 
@@ -247,32 +247,7 @@ var _ = Describe("Task", func() {
 			})
 
 			It("does not notify the cloud controller", func() {
-				Consistently(jsonClient.PostCallCount).Should(BeZero())
-			})
-		})
-
-		When("cloud controller notification takes forever", func() {
-			It("still succeeds", func() {
-				done := make(chan interface{})
-				post := make(chan interface{})
-				defer close(post)
-
-				go func() {
-					defer GinkgoRecover()
-
-					jsonClient.PostStub = func(context.Context, string, interface{}) error {
-						<-post // block forever
-
-						return nil
-					}
-
-					err = taskBifrost.CancelTask(ctx, taskGUID)
-
-					Expect(err).NotTo(HaveOccurred())
-					close(done)
-				}()
-
-				Eventually(done).Should(BeClosed())
+				Expect(jsonClient.PostCallCount()).To(BeZero())
 			})
 		})
 	})
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,21 +11,35 @@ import (
 	"code.cloudfoundry.org/eirini/bifrost"
 	cmdcommons "code.cloudfoundry.org/eirini/cmd"
 	"code.cloudfoundry.org/eirini/handler"
+	"code.cloudfoundry.org/eirini/imagepolicy"
 	"code.cloudfoundry.org/eirini/k8s"
 	"code.cloudfoundry.org/eirini/k8s/client"
 	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/k8s/netpol"
+	"code.cloudfoundry.org/eirini/k8s/outbox"
 	"code.cloudfoundry.org/eirini/k8s/pdb"
+	"code.cloudfoundry.org/eirini/k8s/route"
 	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/prometheus"
 	"code.cloudfoundry.org/eirini/stager"
 	"code.cloudfoundry.org/eirini/stager/docker"
 	"code.cloudfoundry.org/eirini/util"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/tlsconfig"
 	"github.com/jessevdk/go-flags"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
 )
 
-const readHaderTimeout = 30 * time.Second
+const (
+	readHaderTimeout = 30 * time.Second
+
+	defaultStagingWorkers        = 10
+	defaultStagingQueueSize      = 100
+	defaultStagingTimeoutSeconds = 60
+)
 
 type options struct {
 	ConfigFile string `short:"c" long:"config" description:"Config for running the eirini api"`
@@ -39,19 +54,37 @@ func main() {
 	err = cmdcommons.ReadConfigFile(opts.ConfigFile, &cfg)
 	cmdcommons.ExitfIfError(err, "Failed to read config file")
 
+	if cfg.CallbackOutboxEnabled && cfg.DefaultWorkloadsNamespace == "" {
+		cmdcommons.Exitf("The callback outbox requires a default workloads namespace")
+	}
+
 	clientset := cmdcommons.CreateKubeClient(cfg.ConfigPath)
 
 	latestMigrationIndex := cmdcommons.GetLatestMigrationIndex()
 
-	dockerStagingBifrost := initDockerStagingBifrost(cfg)
-	taskBifrost := initTaskBifrost(cfg, clientset, latestMigrationIndex)
-	bifrost := initLRPBifrost(clientset, cfg, latestMigrationIndex)
-
 	handlerLogger := lager.NewLogger("handler")
 	handlerLogger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
-	handler := handler.New(bifrost, dockerStagingBifrost, taskBifrost, handlerLogger)
+
+	var informerCache *client.Cache
+	if cfg.InformerCacheEnabled {
+		informerCache = startInformerCache(cfg, clientset, handlerLogger)
+	}
+
+	stagingBifrost := initStagingBifrost(cfg, clientset)
+	buildpackStagingBifrost := initBuildpackStagingBifrost(cfg, clientset, latestMigrationIndex)
+	taskBifrost := initTaskBifrost(cfg, clientset, informerCache, latestMigrationIndex)
+	bifrost := initLRPBifrost(clientset, informerCache, cfg, latestMigrationIndex)
+
+	httpMetrics, err := prometheus.NewHTTPMetrics(prometheus_api.DefaultRegisterer, clock.RealClock{})
+	cmdcommons.ExitfIfError(err, "Failed to create http metrics")
+
+	handler := handler.New(bifrost, stagingBifrost, buildpackStagingBifrost, taskBifrost, handlerLogger, httpMetrics.Instrument)
 	handlerLogger.Info("api-connected")
 
+	if cfg.PrometheusPort != 0 {
+		go serveMetrics(cfg, informerCache, handlerLogger)
+	}
+
 	if cfg.ServePlaintext {
 		servePlaintext(cfg, handler, handlerLogger)
 	}
@@ -90,7 +123,46 @@ func servePlaintext(cfg eirini.APIConfig, handler http.Handler, logger lager.Log
 	logger.Fatal("api-crashed", server.ListenAndServe())
 }
 
-func initRetryableJSONClient(cfg eirini.APIConfig) *util.RetryableJSONClient {
+func serveMetrics(cfg eirini.APIConfig, informerCache *client.Cache, logger lager.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if informerCache != nil && !informerCache.HasSynced() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf("0.0.0.0:%d", cfg.PrometheusPort),
+		Handler:           mux,
+		ReadHeaderTimeout: readHaderTimeout,
+	}
+	logger.Fatal("metrics-server-crashed", server.ListenAndServe())
+}
+
+func startInformerCache(cfg eirini.APIConfig, clientset kubernetes.Interface, logger lager.Logger) *client.Cache {
+	resyncPeriod := time.Duration(cfg.InformerCacheResyncSeconds) * time.Second
+	informerCache := client.NewCache(clientset, cfg.WorkloadsNamespace, resyncPeriod)
+	informerCache.Start(context.Background())
+
+	go func() {
+		if err := informerCache.WaitForSync(context.Background()); err != nil {
+			logger.Error("informer-cache-sync-failed", err)
+
+			return
+		}
+
+		logger.Info("informer-cache-synced")
+	}()
+
+	return informerCache
+}
+
+func initRetryableJSONClient(cfg eirini.APIConfig) *prometheus.CallbackClientDecorator {
 	httpClient := http.DefaultClient
 
 	if !cfg.CCTLSDisabled {
@@ -112,16 +184,38 @@ func initRetryableJSONClient(cfg eirini.APIConfig) *util.RetryableJSONClient {
 		}
 	}
 
-	return util.NewRetryableJSONClient(httpClient)
+	callbackClient, err := prometheus.NewCallbackClientDecorator(util.NewRetryableJSONClient(httpClient), prometheus_api.DefaultRegisterer)
+	cmdcommons.ExitfIfError(err, "Failed to create callback client metrics")
+
+	return callbackClient
 }
 
-func initStagingCompleter(cfg eirini.APIConfig, logger lager.Logger) *stager.CallbackStagingCompleter {
-	retryableJSONClient := initRetryableJSONClient(cfg)
+// initStagingCallbackClient returns the client used to report staging
+// results to the cloud controller. With the outbox enabled the callbacks
+// survive API restarts, as they are delivered by the task reporter.
+func initStagingCallbackClient(cfg eirini.APIConfig, clientset kubernetes.Interface) stager.CallbackClient {
+	if cfg.CallbackOutboxEnabled {
+		return outbox.NewOutbox(client.NewConfigMap(clientset), cfg.DefaultWorkloadsNamespace)
+	}
 
-	return stager.NewCallbackStagingCompleter(logger, retryableJSONClient)
+	return initRetryableJSONClient(cfg)
 }
 
-func initTaskClient(cfg eirini.APIConfig, clientset kubernetes.Interface, latestMigrationIndex int) *k8s.TaskClient {
+// initTaskCallbackClient returns the client used to report cancelled tasks
+// to the cloud controller. Without the outbox the callback is posted in the
+// background, so that cancelling does not wait for the cloud controller.
+func initTaskCallbackClient(cfg eirini.APIConfig, clientset kubernetes.Interface) bifrost.JSONClient {
+	if cfg.CallbackOutboxEnabled {
+		return outbox.NewOutbox(client.NewConfigMap(clientset), cfg.DefaultWorkloadsNamespace)
+	}
+
+	logger := lager.NewLogger("task-callback-client")
+	logger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
+
+	return util.NewAsyncJSONClient(logger, initRetryableJSONClient(cfg))
+}
+
+func initTaskClient(cfg eirini.APIConfig, clientset kubernetes.Interface, informerCache *client.Cache, latestMigrationIndex int) *prometheus.TaskClientDecorator {
 	logger := lager.NewLogger("task-desirer")
 	logger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
 
@@ -129,75 +223,198 @@ func initTaskClient(cfg eirini.APIConfig, clientset kubernetes.Interface, latest
 		cfg.ApplicationServiceAccount,
 		cfg.RegistrySecretName,
 		cfg.UnsafeAllowAutomountServiceAccountToken,
+		cfg.PlacementTagNodeSelectors,
+		cfg.ImageRewrites,
 		latestMigrationIndex,
 	)
 
-	return k8s.NewTaskClient(
+	jobClient := client.NewJob(clientset, cfg.WorkloadsNamespace)
+	taskClient := k8s.NewTaskClient(
 		logger,
-		client.NewJob(clientset, cfg.WorkloadsNamespace),
+		jobClient,
 		client.NewSecret(clientset),
 		taskToJobConverter,
 	)
+
+	if informerCache != nil {
+		cachedJobClient := client.NewCachedJob(jobClient, informerCache)
+		taskClient.Getter = jobs.NewGetter(cachedJobClient)
+		taskClient.Lister = jobs.NewLister(cachedJobClient)
+	}
+
+	decoratedTaskClient, err := prometheus.NewTaskClientDecorator(logger, taskClient, prometheus_api.DefaultRegisterer, clock.RealClock{})
+	cmdcommons.ExitfIfError(err, "Failed to create task client metrics")
+
+	return decoratedTaskClient
 }
 
-func initDockerStagingBifrost(cfg eirini.APIConfig) *bifrost.DockerStaging {
+func initStagingBifrost(cfg eirini.APIConfig, clientset kubernetes.Interface) *bifrost.StagingQueue {
 	logger := lager.NewLogger("docker-staging-bifrost")
 	logger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
-	stagingCompleter := initStagingCompleter(cfg, logger)
+	stagingCompleter := stager.NewCallbackStagingCompleter(logger, initStagingCallbackClient(cfg, clientset))
+
+	timeoutSeconds := cfg.StagingTimeoutSeconds
+	if timeoutSeconds == 0 {
+		timeoutSeconds = defaultStagingTimeoutSeconds
+	}
+
+	// staging pins the images it stages to their digest, so only the images
+	// of apps and tasks need to be pinned already
+	stagingImagePolicy := cfg.ImagePolicy
+	stagingImagePolicy.RequireDigest = false
 
-	return &bifrost.DockerStaging{
+	registries, err := docker.NewRegistries(cfg.RegistryCAFiles, cfg.InsecureRegistries)
+	cmdcommons.ExitfIfError(err, "Failed to configure docker registries")
+
+	dockerStaging := &bifrost.DockerStaging{
 		Logger:               logger,
-		ImageMetadataFetcher: docker.Fetch,
+		ImageMetadataFetcher: registries.Fetch,
 		ImageRefParser:       docker.Parse,
 		StagingCompleter:     stagingCompleter,
+		ImagePolicy:          imagepolicy.New(stagingImagePolicy),
+		AllowRunImageAsRoot:  cfg.AllowRunImageAsRoot,
+		ImageOS:              cfg.ImageOS,
+		ImageArchitecture:    cfg.ImageArchitecture,
+		FetchTimeout:         time.Duration(timeoutSeconds) * time.Second,
+		ImageRewrites:        cfg.ImageRewrites,
 	}
+
+	decoratedStaging, err := prometheus.NewStagingBifrostDecorator(dockerStaging, prometheus_api.DefaultRegisterer, clock.RealClock{})
+	cmdcommons.ExitfIfError(err, "Failed to create staging metrics")
+
+	workers := cfg.StagingWorkers
+	if workers == 0 {
+		workers = defaultStagingWorkers
+	}
+
+	queueSize := cfg.StagingQueueSize
+	if queueSize == 0 {
+		queueSize = defaultStagingQueueSize
+	}
+
+	stagingQueue := bifrost.NewStagingQueue(logger, decoratedStaging, workers, queueSize)
+	stagingQueue.Start(context.Background())
+
+	return stagingQueue
 }
 
-func initTaskBifrost(cfg eirini.APIConfig, clientset kubernetes.Interface, latestMigrationIndex int) *bifrost.Task {
+// initBuildpackStagingBifrost returns nil unless buildpack staging is
+// enabled by setting its builder image.
+func initBuildpackStagingBifrost(cfg eirini.APIConfig, clientset kubernetes.Interface, latestMigrationIndex int) handler.StagingBifrost {
+	stagingCfg := cfg.BuildpackStaging
+	if stagingCfg.BuilderImage == "" {
+		return nil
+	}
+
+	if stagingCfg.DownloaderImage == "" || cfg.DropletRegistry == "" {
+		cmdcommons.Exitf("Buildpack staging requires a downloader image and a droplet registry")
+	}
+
+	logger := lager.NewLogger("buildpack-staging-bifrost")
+	logger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
+
+	registrySecretName := stagingCfg.RegistrySecretName
+	if registrySecretName == "" {
+		registrySecretName = cfg.RegistrySecretName
+	}
+
+	stagingToJobConverter := jobs.NewStagingToJobConverter(
+		cfg.ApplicationServiceAccount,
+		registrySecretName,
+		cfg.UnsafeAllowAutomountServiceAccountToken,
+		stagingCfg.DownloaderImage,
+		stagingCfg.BuilderImage,
+		latestMigrationIndex,
+	)
+	stagingDesirer := jobs.NewStagingDesirer(logger, stagingToJobConverter, client.NewJob(clientset, cfg.WorkloadsNamespace))
+
+	return &bifrost.BuildpackStaging{
+		Logger:           logger,
+		StagingDesirer:   &stagingDesirer,
+		StagingCompleter: stager.NewCallbackStagingCompleter(logger, initStagingCallbackClient(cfg, clientset)),
+		Namespace:        cfg.DefaultWorkloadsNamespace,
+		DropletRegistry:  cfg.DropletRegistry,
+	}
+}
+
+func initTaskBifrost(cfg eirini.APIConfig, clientset kubernetes.Interface, informerCache *client.Cache, latestMigrationIndex int) *bifrost.Task {
 	converter := initConverter(cfg)
-	taskClient := initTaskClient(cfg, clientset, latestMigrationIndex)
-	retryableJSONClient := initRetryableJSONClient(cfg)
+	taskClient := initTaskClient(cfg, clientset, informerCache, latestMigrationIndex)
 	namespacer := bifrost.NewNamespacer(cfg.DefaultWorkloadsNamespace)
 
 	return &bifrost.Task{
 		Converter:  converter,
 		TaskClient: taskClient,
-		JSONClient: retryableJSONClient,
+		JSONClient: initTaskCallbackClient(cfg, clientset),
 		Namespacer: namespacer,
 	}
 }
 
-func initLRPBifrost(clientset kubernetes.Interface, cfg eirini.APIConfig, latestMigration int) *bifrost.LRP {
+func initLRPBifrost(clientset kubernetes.Interface, informerCache *client.Cache, cfg eirini.APIConfig, latestMigration int) *bifrost.LRP {
 	desireLogger := lager.NewLogger("desirer")
 	desireLogger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
 
+	cmdcommons.ExitfIfError(stset.ValidateSchedulingConfig(cfg.Scheduling), "Invalid scheduling config")
+	cmdcommons.ExitfIfError(pdb.ValidateMinAvailable(cfg.DefaultMinAvailableInstances), "Invalid default min available instances")
+
 	lrpToStatefulSetConverter := stset.NewLRPToStatefulSetConverter(
 		cfg.ApplicationServiceAccount,
 		cfg.RegistrySecretName,
 		cfg.UnsafeAllowAutomountServiceAccountToken,
 		cfg.AllowRunImageAsRoot,
+		cfg.PlacementTagNodeSelectors,
+		cfg.ImageRewrites,
+		cfg.Scheduling,
 		latestMigration,
 		k8s.CreateLivenessProbe,
 		k8s.CreateReadinessProbe,
+		k8s.CreateStartupProbe,
 	)
+	statefulSetClient := client.NewStatefulSet(clientset, cfg.WorkloadsNamespace)
+	podClient := client.NewPod(clientset, cfg.WorkloadsNamespace)
+	eventClient := client.NewEvent(clientset)
+	statefulSetToLRPConverter := stset.NewStatefulSetToLRPConverter()
+
 	lrpClient := k8s.NewLRPClient(
 		desireLogger,
 		client.NewSecret(clientset),
-		client.NewStatefulSet(clientset, cfg.WorkloadsNamespace),
-		client.NewPod(clientset, cfg.WorkloadsNamespace),
-		pdb.NewUpdater(client.NewPodDisruptionBudget(clientset)),
-		client.NewEvent(clientset),
+		statefulSetClient,
+		podClient,
+		pdb.NewUpdater(client.NewPodDisruptionBudget(clientset), cfg.DefaultMinAvailableInstances),
+		route.NewUpdater(client.NewService(clientset), client.NewIngress(clientset), cfg.IngressClassName),
+		netpol.NewUpdater(desireLogger, client.NewNetworkPolicy(clientset)),
+		eventClient,
 		lrpToStatefulSetConverter,
-		stset.NewStatefulSetToLRPConverter(),
+		statefulSetToLRPConverter,
+		cfg.ImageRewrites,
 	)
 
+	// Only reads are served from the cache, writes keep using the live
+	// clients to avoid conflicts caused by stale objects. Updates also read
+	// the LRP they change through the live client.
+	liveGetter := lrpClient.Getter
+
+	if informerCache != nil {
+		cachedStatefulSetClient := client.NewCachedStatefulSet(statefulSetClient, informerCache)
+		cachedPodClient := client.NewCachedPod(podClient, informerCache)
+		cachedEventClient := client.NewCachedEvent(eventClient, informerCache)
+
+		lrpClient.Lister = stset.NewLister(desireLogger, cachedStatefulSetClient, statefulSetToLRPConverter)
+		lrpClient.Getter = stset.NewGetter(desireLogger, cachedStatefulSetClient, cachedPodClient, cachedEventClient, client.NewSecret(clientset), statefulSetToLRPConverter)
+	}
+
+	decoratedLRPClient, err := prometheus.NewLRPClientDecorator(desireLogger, lrpClient, prometheus_api.DefaultRegisterer, clock.RealClock{})
+	cmdcommons.ExitfIfError(err, "Failed to create lrp client metrics")
+
 	converter := initConverter(cfg)
 	namespacer := bifrost.NewNamespacer(cfg.DefaultWorkloadsNamespace)
 
 	return &bifrost.LRP{
-		Converter:  converter,
-		LRPClient:  lrpClient,
-		Namespacer: namespacer,
+		Converter:   converter,
+		LRPClient:   decoratedLRPClient,
+		Namespacer:  namespacer,
+		ImagePolicy: imagepolicy.New(cfg.ImagePolicy),
+		LiveGetter:  &liveGetter,
 	}
 }
 
@@ -207,5 +424,8 @@ func initConverter(cfg eirini.APIConfig) *bifrost.APIConverter {
 
 	return bifrost.NewAPIConverter(
 		convertLogger,
+		imagepolicy.New(cfg.ImagePolicy),
+		cfg.DropletRegistry,
+		cfg.ImageRewrites,
 	)
 }
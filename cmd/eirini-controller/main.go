@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"code.cloudfoundry.org/eirini"
+	cmdcommons "code.cloudfoundry.org/eirini/cmd"
+	"code.cloudfoundry.org/eirini/k8s"
+	"code.cloudfoundry.org/eirini/k8s/client"
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/k8s/netpol"
+	"code.cloudfoundry.org/eirini/k8s/pdb"
+	"code.cloudfoundry.org/eirini/k8s/reconciler"
+	"code.cloudfoundry.org/eirini/k8s/route"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	eiriniv1 "code.cloudfoundry.org/eirini/pkg/apis/eirini/v1"
+	"code.cloudfoundry.org/eirini/util"
+	"code.cloudfoundry.org/lager"
+	"github.com/jessevdk/go-flags"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+type options struct {
+	ConfigFile string `short:"c" long:"config" description:"Config for running eirini-controller"`
+}
+
+func main() {
+	var opts options
+	_, err := flags.ParseArgs(&opts, os.Args)
+	cmdcommons.ExitfIfError(err, "Failed to parse args")
+
+	var cfg eirini.ControllerConfig
+	err = cmdcommons.ReadConfigFile(opts.ConfigFile, &cfg)
+	cmdcommons.ExitfIfError(err, "Failed to read config file")
+
+	clientset := cmdcommons.CreateKubeClient(cfg.ConfigPath)
+
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", cfg.ConfigPath)
+	cmdcommons.ExitfIfError(err, "Failed to build kubeconfig")
+
+	logger := lager.NewLogger("eirini-controller")
+	logger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
+
+	scheme := runtime.NewScheme()
+	err = kscheme.AddToScheme(scheme)
+	cmdcommons.ExitfIfError(err, "Failed to add the k8s scheme")
+
+	err = eiriniv1.AddToScheme(scheme)
+	cmdcommons.ExitfIfError(err, "Failed to add the eirini scheme")
+
+	// do not serve prometheus metrics unless asked to; port clashes during integration tests
+	metricsBindAddress := "0"
+	if cfg.PrometheusPort != 0 {
+		metricsBindAddress = fmt.Sprintf(":%d", cfg.PrometheusPort)
+	}
+
+	managerOptions := manager.Options{
+		MetricsBindAddress: metricsBindAddress,
+		Namespace:          cfg.WorkloadsNamespace,
+		Scheme:             scheme,
+		Logger:             util.NewLagerLogr(logger),
+		LeaderElection:     true,
+		LeaderElectionID:   "eirini-controller-leader",
+	}
+
+	if cfg.LeaderElectionID != "" {
+		managerOptions.LeaderElectionNamespace = cfg.LeaderElectionNamespace
+		managerOptions.LeaderElectionID = cfg.LeaderElectionID
+	}
+
+	mgr, err := manager.New(kubeConfig, managerOptions)
+	cmdcommons.ExitfIfError(err, "Failed to create k8s controller runtime manager")
+
+	latestMigrationIndex := cmdcommons.GetLatestMigrationIndex()
+
+	lrpReconciler := reconciler.NewLRP(
+		logger.Session("lrp-reconciler"),
+		mgr.GetClient(),
+		createLRPClient(logger, clientset, cfg, latestMigrationIndex),
+		scheme,
+	)
+
+	err = builder.
+		ControllerManagedBy(mgr).
+		For(&eiriniv1.LRP{}).
+		Owns(&appsv1.StatefulSet{}).
+		Complete(lrpReconciler)
+	cmdcommons.ExitfIfError(err, "Failed to build LRP reconciler")
+
+	taskReconciler := reconciler.NewTask(
+		logger.Session("task-reconciler"),
+		mgr.GetClient(),
+		createTaskClient(logger, clientset, cfg, latestMigrationIndex),
+		scheme,
+		cfg.TaskTTLSeconds,
+	)
+
+	err = builder.
+		ControllerManagedBy(mgr).
+		For(&eiriniv1.Task{}).
+		Owns(&batchv1.Job{}).
+		Complete(taskReconciler)
+	cmdcommons.ExitfIfError(err, "Failed to build Task reconciler")
+
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	cmdcommons.ExitfIfError(err, "Failed to start manager")
+}
+
+func createLRPClient(logger lager.Logger, clientset kubernetes.Interface, cfg eirini.ControllerConfig, latestMigrationIndex int) *k8s.LRPClient {
+	desireLogger := logger.Session("lrp-desirer")
+
+	cmdcommons.ExitfIfError(stset.ValidateSchedulingConfig(cfg.Scheduling), "Invalid scheduling config")
+	cmdcommons.ExitfIfError(pdb.ValidateMinAvailable(cfg.DefaultMinAvailableInstances), "Invalid default min available instances")
+
+	lrpToStatefulSetConverter := stset.NewLRPToStatefulSetConverter(
+		cfg.ApplicationServiceAccount,
+		cfg.RegistrySecretName,
+		cfg.UnsafeAllowAutomountServiceAccountToken,
+		cfg.AllowRunImageAsRoot,
+		cfg.PlacementTagNodeSelectors,
+		cfg.ImageRewrites,
+		cfg.Scheduling,
+		latestMigrationIndex,
+		k8s.CreateLivenessProbe,
+		k8s.CreateReadinessProbe,
+		k8s.CreateStartupProbe,
+	)
+
+	return k8s.NewLRPClient(
+		desireLogger,
+		client.NewSecret(clientset),
+		client.NewStatefulSet(clientset, cfg.WorkloadsNamespace),
+		client.NewPod(clientset, cfg.WorkloadsNamespace),
+		pdb.NewUpdater(client.NewPodDisruptionBudget(clientset), cfg.DefaultMinAvailableInstances),
+		route.NewUpdater(client.NewService(clientset), client.NewIngress(clientset), cfg.IngressClassName),
+		netpol.NewUpdater(desireLogger, client.NewNetworkPolicy(clientset)),
+		client.NewEvent(clientset),
+		lrpToStatefulSetConverter,
+		stset.NewStatefulSetToLRPConverter(),
+		cfg.ImageRewrites,
+	)
+}
+
+func createTaskClient(logger lager.Logger, clientset kubernetes.Interface, cfg eirini.ControllerConfig, latestMigrationIndex int) *k8s.TaskClient {
+	taskToJobConverter := jobs.NewTaskToJobConverter(
+		cfg.ApplicationServiceAccount,
+		cfg.RegistrySecretName,
+		cfg.UnsafeAllowAutomountServiceAccountToken,
+		cfg.PlacementTagNodeSelectors,
+		cfg.ImageRewrites,
+		latestMigrationIndex,
+	)
+
+	return k8s.NewTaskClient(
+		logger.Session("task-desirer"),
+		client.NewJob(clientset, cfg.WorkloadsNamespace),
+		client.NewSecret(clientset),
+		taskToJobConverter,
+	)
+}
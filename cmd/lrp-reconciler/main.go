@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/bifrost"
+	cmdcommons "code.cloudfoundry.org/eirini/cmd"
+	"code.cloudfoundry.org/eirini/imagepolicy"
+	"code.cloudfoundry.org/eirini/k8s"
+	"code.cloudfoundry.org/eirini/k8s/client"
+	"code.cloudfoundry.org/eirini/k8s/informers/lrp"
+	"code.cloudfoundry.org/eirini/k8s/pdb"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/util"
+	"code.cloudfoundry.org/lager"
+	"github.com/jessevdk/go-flags"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+type options struct {
+	ConfigFile string `short:"c" long:"config" description:"Config for running lrp-reconciler"`
+}
+
+const defaultResyncPeriod = 10 * time.Minute
+
+func main() {
+	var opts options
+	_, err := flags.ParseArgs(&opts, os.Args)
+	cmdcommons.ExitfIfError(err, "Failed to parse args")
+
+	var cfg eirini.LRPReconcilerConfig
+	err = cmdcommons.ReadConfigFile(opts.ConfigFile, &cfg)
+	cmdcommons.ExitfIfError(err, "Failed to read config file")
+
+	clientset := cmdcommons.CreateKubeClient(cfg.ConfigPath)
+
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", cfg.ConfigPath)
+	cmdcommons.ExitfIfError(err, "Failed to build kubeconfig")
+
+	logger := lager.NewLogger("lrp-reconciler")
+	logger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
+
+	resyncPeriod := defaultResyncPeriod
+	if cfg.ResyncPeriodSeconds != 0 {
+		resyncPeriod = time.Duration(cfg.ResyncPeriodSeconds) * time.Second
+	}
+
+	// do not serve prometheus metrics unless asked to; port clashes during integration tests
+	metricsBindAddress := "0"
+	if cfg.PrometheusPort != 0 {
+		metricsBindAddress = fmt.Sprintf(":%d", cfg.PrometheusPort)
+	}
+
+	managerOptions := manager.Options{
+		MetricsBindAddress: metricsBindAddress,
+		Namespace:          cfg.WorkloadsNamespace,
+		Scheme:             kscheme.Scheme,
+		Logger:             util.NewLagerLogr(logger),
+		SyncPeriod:         &resyncPeriod,
+		LeaderElection:     true,
+		LeaderElectionID:   "lrp-reconciler-leader",
+	}
+
+	if cfg.LeaderElectionID != "" {
+		managerOptions.LeaderElectionNamespace = cfg.LeaderElectionNamespace
+		managerOptions.LeaderElectionID = cfg.LeaderElectionID
+	}
+
+	mgr, err := manager.New(kubeConfig, managerOptions)
+	cmdcommons.ExitfIfError(err, "Failed to create k8s controller runtime manager")
+
+	cmdcommons.ExitfIfError(stset.ValidateSchedulingConfig(cfg.Scheduling), "Invalid scheduling config")
+	cmdcommons.ExitfIfError(pdb.ValidateMinAvailable(cfg.DefaultMinAvailableInstances), "Invalid default min available instances")
+
+	lrpToStatefulSetConverter := stset.NewLRPToStatefulSetConverter(
+		cfg.ApplicationServiceAccount,
+		cfg.RegistrySecretName,
+		cfg.UnsafeAllowAutomountServiceAccountToken,
+		cfg.AllowRunImageAsRoot,
+		cfg.PlacementTagNodeSelectors,
+		cfg.ImageRewrites,
+		cfg.Scheduling,
+		cmdcommons.GetLatestMigrationIndex(),
+		k8s.CreateLivenessProbe,
+		k8s.CreateReadinessProbe,
+		k8s.CreateStartupProbe,
+	)
+
+	lrpReconciler, err := lrp.NewReconciler(
+		logger,
+		mgr.GetClient(),
+		// apps that are already running are not subject to the image policy
+		bifrost.NewAPIConverter(logger, imagepolicy.All{}, cfg.DropletRegistry, cfg.ImageRewrites),
+		lrpToStatefulSetConverter,
+		pdb.NewUpdater(client.NewPodDisruptionBudget(clientset), cfg.DefaultMinAvailableInstances),
+		mgr.GetEventRecorderFor("lrp-reconciler"),
+		metrics.Registry,
+	)
+	cmdcommons.ExitfIfError(err, "Failed to create lrp reconciler")
+
+	isApp := predicate.NewPredicateFuncs(func(obj runtimeclient.Object) bool {
+		return obj.GetLabels()[stset.LabelSourceType] == stset.AppSourceType
+	})
+
+	servesPolicyV1, err := client.ServesPolicyV1(clientset.Discovery())
+	cmdcommons.ExitfIfError(err, "Failed to discover the pod disruption budget API")
+
+	var pdbType runtimeclient.Object = &policyv1.PodDisruptionBudget{}
+	if !servesPolicyV1 {
+		pdbType = &policyv1beta1.PodDisruptionBudget{}
+	}
+
+	err = builder.
+		ControllerManagedBy(mgr).
+		For(&appsv1.StatefulSet{}, builder.WithPredicates(isApp)).
+		Owns(pdbType).
+		Owns(&corev1.Secret{}).
+		Complete(lrpReconciler)
+	cmdcommons.ExitfIfError(err, "Failed to build lrp reconciler")
+
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	cmdcommons.ExitfIfError(err, "Failed to start manager")
+}
@@ -1,25 +1,35 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 
 	"code.cloudfoundry.org/eirini"
 	cmdcommons "code.cloudfoundry.org/eirini/cmd"
 	"code.cloudfoundry.org/eirini/k8s/client"
+	"code.cloudfoundry.org/eirini/k8s/informers/callback"
 	k8stask "code.cloudfoundry.org/eirini/k8s/informers/task"
 	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/k8s/outbox"
 	"code.cloudfoundry.org/eirini/k8s/reconciler"
+	"code.cloudfoundry.org/eirini/stager"
 	"code.cloudfoundry.org/eirini/util"
 	"code.cloudfoundry.org/lager"
 	"github.com/jessevdk/go-flags"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
@@ -27,7 +37,10 @@ type options struct {
 	ConfigFile string `short:"c" long:"config" description:"Config for running task-reporter"`
 }
 
-const defaultCompletionCallbackRetryLimit = 10
+const (
+	defaultCompletionCallbackRetryLimit = 10
+	defaultCallbackOutboxRetryLimit     = 10
+)
 
 func main() {
 	var opts options
@@ -38,6 +51,10 @@ func main() {
 	err = cmdcommons.ReadConfigFile(opts.ConfigFile, &cfg)
 	cmdcommons.ExitfIfError(err, "Failed to read config file")
 
+	if cfg.CallbackOutboxEnabled && cfg.WorkloadsNamespace == "" {
+		cmdcommons.Exitf("The callback outbox requires a workloads namespace")
+	}
+
 	clientset := cmdcommons.CreateKubeClient(cfg.ConfigPath)
 
 	kubeConfig, err := clientcmd.BuildConfigFromFlags("", cfg.ConfigPath)
@@ -62,14 +79,32 @@ func main() {
 		completionCallbackRetryLimit = defaultCompletionCallbackRetryLimit
 	}
 
+	callbackOutboxRetryLimit := cfg.CallbackOutboxRetryLimit
+	if callbackOutboxRetryLimit == 0 {
+		callbackOutboxRetryLimit = defaultCallbackOutboxRetryLimit
+	}
+
+	// do not serve prometheus metrics unless asked to; port clashes during integration tests
+	metricsBindAddress := "0"
+	if cfg.PrometheusPort != 0 {
+		metricsBindAddress = fmt.Sprintf(":%d", cfg.PrometheusPort)
+	}
+
 	mgrOptions := manager.Options{
-		// do not serve prometheus metrics; disabled because port clashes during integration tests
-		MetricsBindAddress: "0",
+		MetricsBindAddress: metricsBindAddress,
 		Scheme:             kscheme.Scheme,
 		Logger:             util.NewLagerLogr(taskLogger),
 		Namespace:          cfg.WorkloadsNamespace,
 		LeaderElection:     true,
 		LeaderElectionID:   "task-reporter-leader",
+		// only the outbox config maps are watched, not all of the namespace
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1.ConfigMap{}: {
+					Label: labels.SelectorFromSet(labels.Set{outbox.LabelSourceType: outbox.SourceType}),
+				},
+			},
+		}),
 	}
 
 	if cfg.LeaderElectionID != "" {
@@ -97,10 +132,70 @@ func main() {
 		Complete(taskReconciler)
 	cmdcommons.ExitfIfError(err, "Failed to build task reporter reconciler")
 
+	stagingLogger := lager.NewLogger("staging-informer")
+	stagingLogger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
+
+	// staging jobs run like tasks, but report their result to the staging
+	// completion callback
+	stagingReconciler := k8stask.NewReconciler(stagingLogger,
+		mgr.GetClient(),
+		jobsClient,
+		podUpdater,
+		k8stask.StagingReporter{
+			StagingCompleter: stager.NewCallbackStagingCompleter(stagingLogger, initStagingCallbackClient(cfg, clientset, httpClient)),
+			Logger:           stagingLogger,
+		},
+		initTaskDeleter(clientset, cfg.WorkloadsNamespace),
+		completionCallbackRetryLimit,
+		cfg.TTLSeconds,
+	)
+
+	err = builder.
+		ControllerManagedBy(mgr).
+		Named("staging-reporter").
+		For(&corev1.Pod{}, builder.WithPredicates(reconciler.NewSourceTypeUpdatePredicate(jobs.StagingSourceType))).
+		Complete(stagingReconciler)
+	cmdcommons.ExitfIfError(err, "Failed to build staging reporter reconciler")
+
+	callbackLogger := lager.NewLogger("callback-outbox")
+	callbackLogger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
+
+	// the reconciler retries with its own backoff, so every delivery is a single attempt
+	callbackReconciler, err := callback.NewReconciler(callbackLogger,
+		mgr.GetClient(),
+		util.NewRetryableJSONClientWithConfig(httpClient, 0, 0, io.Discard),
+		callbackOutboxRetryLimit,
+		clock.RealClock{},
+		metrics.Registry,
+	)
+	cmdcommons.ExitfIfError(err, "Failed to create callback outbox reconciler")
+
+	err = builder.
+		ControllerManagedBy(mgr).
+		Named("callback-outbox").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(isOutboxCallback))).
+		Complete(callbackReconciler)
+	cmdcommons.ExitfIfError(err, "Failed to build callback outbox reconciler")
+
 	err = mgr.Start(ctrl.SetupSignalHandler())
 	cmdcommons.ExitfIfError(err, "Failed to start manager")
 }
 
+func isOutboxCallback(obj runtimeclient.Object) bool {
+	return obj.GetLabels()[outbox.LabelSourceType] == outbox.SourceType
+}
+
+// initStagingCallbackClient returns the client used to report staging
+// results. With the outbox enabled they are delivered, and retried, by the
+// callback outbox reconciler.
+func initStagingCallbackClient(cfg eirini.TaskReporterConfig, clientset kubernetes.Interface, httpClient *http.Client) stager.CallbackClient {
+	if cfg.CallbackOutboxEnabled {
+		return outbox.NewOutbox(client.NewConfigMap(clientset), cfg.WorkloadsNamespace)
+	}
+
+	return util.NewRetryableJSONClientWithConfig(httpClient, 0, 0, io.Discard)
+}
+
 func initTaskDeleter(clientset kubernetes.Interface, workloadsNamespace string) k8stask.Deleter {
 	logger := lager.NewLogger("task-deleter")
 	logger.RegisterSink(lager.NewPrettySink(os.Stdout, lager.DEBUG))
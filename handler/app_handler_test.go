@@ -30,7 +30,7 @@ var _ = Describe("AppHandler", func() {
 	BeforeEach(func() {
 		lrpBifrost = new(handlerfakes.FakeLRPBifrost)
 		lager = tests.NewTestLogger("app-handler-test")
-		ts = httptest.NewServer(New(lrpBifrost, nil, nil, lager))
+		ts = httptest.NewServer(New(lrpBifrost, nil, nil, nil, lager))
 	})
 
 	AfterEach(func() {
@@ -170,6 +170,22 @@ var _ = Describe("AppHandler", func() {
 			It("should provide a helpful log message", findLog("app-handler-test.desire-app.bifrost-failed", "myguid"))
 		})
 
+		Context("When the image is not allowed", func() {
+			BeforeEach(func() {
+				lrpBifrost.TransferReturns(errors.Wrap(eirini.ErrImageNotAllowed, `image "busybox": tag "latest" is banned`))
+			})
+
+			It("should return UnprocessableEntity status", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			})
+
+			It("should explain why the image is not allowed", func() {
+				var errorResponse cf.Error
+				Expect(json.NewDecoder(response.Body).Decode(&errorResponse)).To(Succeed())
+				Expect(errorResponse.Message).To(ContainSubstring(`tag "latest" is banned`))
+			})
+		})
+
 		Context("when the body is empty", func() {
 			BeforeEach(func() {
 				body = ""
@@ -531,6 +547,20 @@ var _ = Describe("AppHandler", func() {
 				verifyResponseObject()
 			})
 		})
+
+		Context("when the new image is not allowed", func() {
+			BeforeEach(func() {
+				lrpBifrost.UpdateReturns(errors.Wrap(eirini.ErrImageNotAllowed, `image "busybox": tag "latest" is banned`))
+			})
+
+			It("should return a 422 HTTP status code", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			})
+
+			It("shoud return a response object containing the error", func() {
+				verifyResponseObject()
+			})
+		})
 	})
 
 	Context("Stop an app", func() {
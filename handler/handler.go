@@ -36,15 +36,26 @@ type StagingBifrost interface {
 	CompleteStaging(ctx context.Context, request cf.StagingCompletedRequest) error
 }
 
+// Middleware wraps the handle registered for a route. The route is the path
+// pattern the handle is registered for, e.g. /apps/:process_guid.
+type Middleware func(method, route string, handle httprouter.Handle) httprouter.Handle
+
+// New returns the handler of the eirini API. buildpackStagingBifrost is nil
+// if buildpack staging is not enabled.
 func New(lrpBifrost LRPBifrost,
 	dockerStagingBifrost StagingBifrost,
+	buildpackStagingBifrost StagingBifrost,
 	taskBifrost TaskBifrost,
 	lager lager.Logger,
+	middlewares ...Middleware,
 ) http.Handler {
-	handler := httprouter.New()
+	handler := router{
+		Router:      httprouter.New(),
+		middlewares: middlewares,
+	}
 
 	appHandler := NewAppHandler(lrpBifrost, lager)
-	stageHandler := NewStageHandler(dockerStagingBifrost, lager)
+	stageHandler := NewStageHandler(dockerStagingBifrost, buildpackStagingBifrost, lager)
 	taskHandler := NewTaskHandler(lager, taskBifrost)
 
 	registerAppsEndpoints(handler, appHandler)
@@ -54,23 +65,36 @@ func New(lrpBifrost LRPBifrost,
 	return handler
 }
 
-func registerAppsEndpoints(handler *httprouter.Router, appHandler *App) {
-	handler.GET("/apps", appHandler.List)
-	handler.PUT("/apps/:process_guid", appHandler.Desire)
-	handler.POST("/apps/:process_guid", appHandler.Update)
-	handler.PUT("/apps/:process_guid/:version_guid/stop", appHandler.Stop)
-	handler.PUT("/apps/:process_guid/:version_guid/stop/:instance", appHandler.StopInstance)
-	handler.GET("/apps/:process_guid/:version_guid/instances", appHandler.GetInstances)
-	handler.GET("/apps/:process_guid/:version_guid", appHandler.Get)
+type router struct {
+	*httprouter.Router
+	middlewares []Middleware
+}
+
+func (r router) Handle(method, route string, handle httprouter.Handle) {
+	for _, middleware := range r.middlewares {
+		handle = middleware(method, route, handle)
+	}
+
+	r.Router.Handle(method, route, handle)
+}
+
+func registerAppsEndpoints(handler router, appHandler *App) {
+	handler.Handle(http.MethodGet, "/apps", appHandler.List)
+	handler.Handle(http.MethodPut, "/apps/:process_guid", appHandler.Desire)
+	handler.Handle(http.MethodPost, "/apps/:process_guid", appHandler.Update)
+	handler.Handle(http.MethodPut, "/apps/:process_guid/:version_guid/stop", appHandler.Stop)
+	handler.Handle(http.MethodPut, "/apps/:process_guid/:version_guid/stop/:instance", appHandler.StopInstance)
+	handler.Handle(http.MethodGet, "/apps/:process_guid/:version_guid/instances", appHandler.GetInstances)
+	handler.Handle(http.MethodGet, "/apps/:process_guid/:version_guid", appHandler.Get)
 }
 
-func registerStageEndpoint(handler *httprouter.Router, stageHandler *Stage) {
-	handler.POST("/stage/:staging_guid", stageHandler.Run)
+func registerStageEndpoint(handler router, stageHandler *Stage) {
+	handler.Handle(http.MethodPost, "/stage/:staging_guid", stageHandler.Run)
 }
 
-func registerTaskEndpoints(handler *httprouter.Router, taskHandler *Task) {
-	handler.GET("/tasks", taskHandler.List)
-	handler.GET("/tasks/:task_guid", taskHandler.Get)
-	handler.POST("/tasks/:task_guid", taskHandler.Run)
-	handler.DELETE("/tasks/:task_guid", taskHandler.Cancel)
+func registerTaskEndpoints(handler router, taskHandler *Task) {
+	handler.Handle(http.MethodGet, "/tasks", taskHandler.List)
+	handler.Handle(http.MethodGet, "/tasks/:task_guid", taskHandler.Get)
+	handler.Handle(http.MethodPost, "/tasks/:task_guid", taskHandler.Run)
+	handler.Handle(http.MethodDelete, "/tasks/:task_guid", taskHandler.Cancel)
 }
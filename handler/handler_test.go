@@ -9,6 +9,7 @@ import (
 	"code.cloudfoundry.org/eirini/handler/handlerfakes"
 	"code.cloudfoundry.org/eirini/models/cf"
 	"code.cloudfoundry.org/eirini/tests"
+	"github.com/julienschmidt/httprouter"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -30,7 +31,7 @@ var _ = Describe("Handler", func() {
 		taskBifrost = new(handlerfakes.FakeTaskBifrost)
 
 		lager := tests.NewTestLogger("handler-test")
-		handlerClient = New(lrpBifrost, dockerStagingBifrost, taskBifrost, lager)
+		handlerClient = New(lrpBifrost, dockerStagingBifrost, nil, taskBifrost, lager)
 	})
 
 	JustBeforeEach(func() {
@@ -185,4 +186,29 @@ var _ = Describe("Handler", func() {
 			})
 		})
 	})
+
+	Context("Middlewares", func() {
+		var routes []string
+
+		BeforeEach(func() {
+			routes = []string{}
+			middleware := func(method, route string, handle httprouter.Handle) httprouter.Handle {
+				return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+					routes = append(routes, method+" "+route)
+					w.Header().Set("X-Middleware", "called")
+					handle(w, r, ps)
+				}
+			}
+
+			handlerClient = New(lrpBifrost, dockerStagingBifrost, nil, taskBifrost, tests.NewTestLogger("handler-test"), middleware)
+		})
+
+		It("wraps the handles with the route they are registered for", func() {
+			res, err := client.Get(ts.URL + "/tasks/task_123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("X-Middleware")).To(Equal("called"))
+			Expect(routes).To(ConsistOf("GET /tasks/:task_guid"))
+		})
+	})
 })
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/models/cf"
 	"code.cloudfoundry.org/lager"
 	"github.com/julienschmidt/httprouter"
@@ -13,16 +14,20 @@ import (
 )
 
 type Stage struct {
-	dockerStagingBifrost StagingBifrost
-	logger               lager.Logger
+	dockerStagingBifrost    StagingBifrost
+	buildpackStagingBifrost StagingBifrost
+	logger                  lager.Logger
 }
 
-func NewStageHandler(dockerStagingBifrost StagingBifrost, logger lager.Logger) *Stage {
+// NewStageHandler returns the staging handler. buildpackStagingBifrost is
+// nil if buildpack staging is not enabled.
+func NewStageHandler(dockerStagingBifrost, buildpackStagingBifrost StagingBifrost, logger lager.Logger) *Stage {
 	logger = logger.Session("staging-handler")
 
 	return &Stage{
-		dockerStagingBifrost: dockerStagingBifrost,
-		logger:               logger,
+		dockerStagingBifrost:    dockerStagingBifrost,
+		buildpackStagingBifrost: buildpackStagingBifrost,
+		logger:                  logger,
 	}
 }
 
@@ -38,15 +43,22 @@ func (s *Stage) Run(resp http.ResponseWriter, req *http.Request, ps httprouter.P
 		return
 	}
 
-	if stagingRequest.Lifecycle.DockerLifecycle == nil {
-		err := errors.New("docker is the only supported lifecycle")
+	stagingBifrost, err := s.stagingBifrost(stagingRequest.Lifecycle)
+	if err != nil {
 		logger.Error("staging-failed", err)
 		writeErrorResponse(logger, resp, http.StatusBadRequest, err)
 
 		return
 	}
 
-	if err := s.stage(stagingGUID, stagingRequest); err != nil {
+	if err = stagingBifrost.TransferStaging(context.Background(), stagingGUID, stagingRequest); err != nil {
+		if errors.Is(err, eirini.ErrStagingQueueFull) {
+			logger.Info("staging-rejected", lager.Data{"reason": err.Error()})
+			writeErrorResponse(logger, resp, http.StatusServiceUnavailable, err)
+
+			return
+		}
+
 		reason := fmt.Sprintf("failed to stage task with guid %q", stagingGUID)
 		logger.Error("staging-failed", errors.Wrap(err, reason))
 		writeErrorResponse(logger, resp, http.StatusInternalServerError, errors.Wrap(err, reason))
@@ -57,12 +69,17 @@ func (s *Stage) Run(resp http.ResponseWriter, req *http.Request, ps httprouter.P
 	resp.WriteHeader(http.StatusAccepted)
 }
 
-func (s *Stage) stage(stagingGUID string, stagingRequest cf.StagingRequest) error {
-	return s.dockerStagingBifrost.TransferStaging(
-		context.Background(),
-		stagingGUID,
-		stagingRequest,
-	)
+func (s *Stage) stagingBifrost(lifecycle cf.StagingLifecycle) (StagingBifrost, error) {
+	switch {
+	case lifecycle.DockerLifecycle != nil:
+		return s.dockerStagingBifrost, nil
+	case lifecycle.BuildpackLifecycle != nil && s.buildpackStagingBifrost != nil:
+		return s.buildpackStagingBifrost, nil
+	case lifecycle.BuildpackLifecycle != nil:
+		return nil, errors.New("buildpack staging is not enabled")
+	default:
+		return nil, errors.New("missing lifecycle data")
+	}
 }
 
 func writeErrorResponse(logger lager.Logger, resp http.ResponseWriter, status int, err error) {
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 
+	"code.cloudfoundry.org/eirini"
 	. "code.cloudfoundry.org/eirini/handler"
 	"code.cloudfoundry.org/eirini/handler/handlerfakes"
 	"code.cloudfoundry.org/eirini/models/cf"
@@ -21,22 +23,26 @@ var _ = Describe("StageHandler", func() {
 		ts     *httptest.Server
 		logger *tests.TestLogger
 
-		dockerStagingClient *handlerfakes.FakeStagingBifrost
-		bifrostTaskClient   *handlerfakes.FakeTaskBifrost
-		response            *http.Response
-		body                string
-		path                string
-		method              string
+		dockerStagingClient    *handlerfakes.FakeStagingBifrost
+		buildpackStagingClient *handlerfakes.FakeStagingBifrost
+		buildpackStaging       StagingBifrost
+		bifrostTaskClient      *handlerfakes.FakeTaskBifrost
+		response               *http.Response
+		body                   string
+		path                   string
+		method                 string
 	)
 
 	BeforeEach(func() {
 		logger = tests.NewTestLogger("test")
 		dockerStagingClient = new(handlerfakes.FakeStagingBifrost)
+		buildpackStagingClient = new(handlerfakes.FakeStagingBifrost)
+		buildpackStaging = buildpackStagingClient
 		bifrostTaskClient = new(handlerfakes.FakeTaskBifrost)
 	})
 
 	JustBeforeEach(func() {
-		handler := New(nil, dockerStagingClient, bifrostTaskClient, logger)
+		handler := New(nil, dockerStagingClient, buildpackStaging, bifrostTaskClient, logger)
 		ts = httptest.NewServer(handler)
 		req, err := http.NewRequest(method, ts.URL+path, bytes.NewReader([]byte(body)))
 		Expect(err).NotTo(HaveOccurred())
@@ -89,7 +95,11 @@ var _ = Describe("StageHandler", func() {
 			}))
 		})
 
-		Context("and the lifecycle type is unsupported", func() {
+		It("should not use the buildpack staging client", func() {
+			Expect(buildpackStagingClient.TransferStagingCallCount()).To(Equal(0))
+		})
+
+		Context("and the lifecycle is buildpack", func() {
 			BeforeEach(func() {
 				body = `{
 				"app_guid": "our-app-id",
@@ -97,13 +107,63 @@ var _ = Describe("StageHandler", func() {
 				"lifecycle": {
 					"buildpack_lifecycle": {
 						"app_bits_download_uri": "example.com/download",
-						"droplet_upload_uri": "example.com/upload"
+						"droplet_upload_uri": "example.com/upload",
+						"buildpacks": [{"name": "ruby", "key": "ruby-key", "url": "example.com/ruby"}]
 					}
 				},
 				"completion_callback": "example.com/call/me/maybe"
 			}`
 			})
 
+			It("should return 202 Accepted code", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusAccepted))
+			})
+
+			It("should stage it using the buildpack staging client", func() {
+				Expect(dockerStagingClient.TransferStagingCallCount()).To(Equal(0))
+				Expect(buildpackStagingClient.TransferStagingCallCount()).To(Equal(1))
+				_, stagingGUID, stagingRequest := buildpackStagingClient.TransferStagingArgsForCall(0)
+
+				Expect(stagingGUID).To(Equal("guid_1234"))
+				Expect(stagingRequest.Lifecycle.BuildpackLifecycle).To(Equal(&cf.StagingBuildpackLifecycle{
+					AppBitsDownloadURI: "example.com/download",
+					DropletUploadURI:   "example.com/upload",
+					Buildpacks:         []cf.Buildpack{{Name: "ruby", Key: "ruby-key", URL: "example.com/ruby"}},
+				}))
+			})
+
+			When("buildpack staging is not enabled", func() {
+				BeforeEach(func() {
+					buildpackStaging = nil
+				})
+
+				It("should return a 400 Bad Request status code", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("should return the error in the response body", func() {
+					bytes, _ := io.ReadAll(response.Body)
+					stagingError := cf.Error{}
+					err := json.Unmarshal(bytes, &stagingError)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(stagingError.Message).To(ContainSubstring("buildpack staging is not enabled"))
+				})
+
+				It("should not stage it", func() {
+					Expect(dockerStagingClient.TransferStagingCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("and the lifecycle is missing", func() {
+			BeforeEach(func() {
+				body = `{
+				"app_guid": "our-app-id",
+				"lifecycle": {},
+				"completion_callback": "example.com/call/me/maybe"
+			}`
+			})
+
 			It("should return a 400 Bad Request status code", func() {
 				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
 			})
@@ -113,11 +173,12 @@ var _ = Describe("StageHandler", func() {
 				stagingError := cf.Error{}
 				err := json.Unmarshal(bytes, &stagingError)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(stagingError.Message).To(ContainSubstring("docker is the only supported lifecycle"))
+				Expect(stagingError.Message).To(ContainSubstring("missing lifecycle data"))
 			})
 
-			It("should not desire a task", func() {
+			It("should not stage it", func() {
 				Expect(dockerStagingClient.TransferStagingCallCount()).To(Equal(0))
+				Expect(buildpackStagingClient.TransferStagingCallCount()).To(Equal(0))
 			})
 		})
 
@@ -145,6 +206,24 @@ var _ = Describe("StageHandler", func() {
 			})
 		})
 
+		Context("and the staging queue is full", func() {
+			BeforeEach(func() {
+				dockerStagingClient.TransferStagingReturns(fmt.Errorf("cannot accept staging: %w", eirini.ErrStagingQueueFull))
+			})
+
+			It("should return a 503 Service Unavailable", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			})
+
+			It("should return the error in the response body", func() {
+				bytes, _ := io.ReadAll(response.Body)
+				stagingError := cf.Error{}
+				err := json.Unmarshal(bytes, &stagingError)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stagingError.Message).To(ContainSubstring("staging queue is full"))
+			})
+		})
+
 		Context("and the body is invalid", func() {
 			BeforeEach(func() {
 				body = "{ this json is invalid"
@@ -64,7 +64,17 @@ func (t *Task) Run(resp http.ResponseWriter, req *http.Request, ps httprouter.Pa
 
 	if err := t.taskBifrost.TransferTask(req.Context(), taskGUID, taskRequest); err != nil {
 		logger.Error("task-request-task-create-failed", err)
-		writeErrorResponse(logger, resp, http.StatusInternalServerError, err)
+
+		statusCode := http.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, eirini.ErrInvalidTaskRequest):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, eirini.ErrImageNotAllowed):
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		writeErrorResponse(logger, resp, statusCode, err)
 
 		return
 	}
@@ -39,7 +39,7 @@ var _ = Describe("TaskHandler", func() {
 
 	JustBeforeEach(func() {
 		logger = tests.NewTestLogger("test")
-		handler := New(nil, nil, taskBifrost, logger)
+		handler := New(nil, nil, nil, taskBifrost, logger)
 		ts = httptest.NewServer(handler)
 		req, err := http.NewRequest(method, ts.URL+path, bytes.NewReader([]byte(body)))
 		Expect(err).NotTo(HaveOccurred())
@@ -117,6 +117,26 @@ var _ = Describe("TaskHandler", func() {
 			})
 		})
 
+		When("the task request is invalid", func() {
+			BeforeEach(func() {
+				taskBifrost.TransferTaskReturns(errors.Wrap(eirini.ErrInvalidTaskRequest, "MemoryMB cannot be 0"))
+			})
+
+			It("should return 400 Bad Request code", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("the task image is not allowed", func() {
+			BeforeEach(func() {
+				taskBifrost.TransferTaskReturns(errors.Wrap(eirini.ErrImageNotAllowed, `image "busybox": tag "latest" is banned`))
+			})
+
+			It("should return 422 Unprocessable Entity code", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+
 		Context("when the request body cannot be unmarshalled", func() {
 			BeforeEach(func() {
 				body = "random stuff"
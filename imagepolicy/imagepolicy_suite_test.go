@@ -0,0 +1,13 @@
+package imagepolicy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestImagePolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ImagePolicy Suite")
+}
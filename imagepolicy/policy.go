@@ -0,0 +1,76 @@
+package imagepolicy
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/eirini"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+)
+
+// Policy decides whether a workload may use an image. Images that are not
+// allowed are reported with an error wrapping eirini.ErrImageNotAllowed.
+type Policy interface {
+	Check(image string) error
+}
+
+// All is a Policy that allows an image only if all of its policies do. An
+// empty All allows every image.
+type All []Policy
+
+func (a All) Check(image string) error {
+	for _, policy := range a {
+		if err := policy.Check(image); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// New returns the policy configured in cfg. Settings that are not
+// configured do not restrict images.
+func New(cfg eirini.ImagePolicyConfig) All {
+	policies := All{}
+
+	if len(cfg.AllowedRegistries) > 0 {
+		policies = append(policies, NewRegistryAllowlist(cfg.AllowedRegistries))
+	}
+
+	if len(cfg.DeniedRegistries) > 0 {
+		policies = append(policies, NewRegistryDenylist(cfg.DeniedRegistries))
+	}
+
+	if cfg.RequireDigest {
+		policies = append(policies, RequireDigest{})
+	}
+
+	if len(cfg.BannedTags) > 0 {
+		policies = append(policies, NewBannedTags(cfg.BannedTags))
+	}
+
+	return policies
+}
+
+func parse(image string) (reference.Named, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, violation(image, "invalid image reference")
+	}
+
+	return ref, nil
+}
+
+func violation(image, format string, args ...interface{}) error {
+	return errors.Wrapf(eirini.ErrImageNotAllowed, "image %q: %s", image, fmt.Sprintf(format, args...))
+}
+
+func toSet(values []string) map[string]bool {
+	set := map[string]bool{}
+
+	for _, v := range values {
+		set[v] = true
+	}
+
+	return set
+}
@@ -0,0 +1,64 @@
+package imagepolicy_test
+
+import (
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/imagepolicy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy", func() {
+	var (
+		cfg    eirini.ImagePolicyConfig
+		policy imagepolicy.Policy
+	)
+
+	BeforeEach(func() {
+		cfg = eirini.ImagePolicyConfig{}
+	})
+
+	JustBeforeEach(func() {
+		policy = imagepolicy.New(cfg)
+	})
+
+	It("allows all images when nothing is configured", func() {
+		Expect(policy.Check("busybox")).To(Succeed())
+		Expect(policy.Check("quay.io/some/image:latest")).To(Succeed())
+	})
+
+	When("all settings are configured", func() {
+		BeforeEach(func() {
+			cfg = eirini.ImagePolicyConfig{
+				AllowedRegistries: []string{"docker.io", "registry.example.com"},
+				DeniedRegistries:  []string{"registry.example.com"},
+				RequireDigest:     true,
+				BannedTags:        []string{"latest"},
+			}
+		})
+
+		It("allows images satisfying all of them", func() {
+			Expect(policy.Check("busybox@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1")).To(Succeed())
+		})
+
+		It("rejects images from registries that are not allowed", func() {
+			err := policy.Check("quay.io/some/image@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1")
+			Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+			Expect(err).To(MatchError(ContainSubstring(`registry "quay.io" is not allowed`)))
+		})
+
+		It("rejects images from denied registries", func() {
+			err := policy.Check("registry.example.com/image@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1")
+			Expect(err).To(MatchError(ContainSubstring(`registry "registry.example.com" is denied`)))
+		})
+
+		It("rejects images that are not pinned to a digest", func() {
+			err := policy.Check("busybox:1.35")
+			Expect(err).To(MatchError(ContainSubstring("image is not pinned to a digest")))
+		})
+	})
+
+	It("reports the first violation", func() {
+		Expect(imagepolicy.All{imagepolicy.RequireDigest{}, imagepolicy.NewBannedTags([]string{"latest"})}.Check("busybox")).
+			To(MatchError(ContainSubstring("not pinned to a digest")))
+	})
+})
@@ -0,0 +1,49 @@
+package imagepolicy
+
+import "github.com/docker/distribution/reference"
+
+// RegistryAllowlist only allows images from the listed registries
+// (host[:port]). Docker Hub images are in the docker.io registry.
+type RegistryAllowlist struct {
+	registries map[string]bool
+}
+
+func NewRegistryAllowlist(registries []string) RegistryAllowlist {
+	return RegistryAllowlist{registries: toSet(registries)}
+}
+
+func (p RegistryAllowlist) Check(image string) error {
+	ref, err := parse(image)
+	if err != nil {
+		return err
+	}
+
+	if registry := reference.Domain(ref); !p.registries[registry] {
+		return violation(image, "registry %q is not allowed", registry)
+	}
+
+	return nil
+}
+
+// RegistryDenylist allows images from all registries but the listed ones
+// (host[:port]).
+type RegistryDenylist struct {
+	registries map[string]bool
+}
+
+func NewRegistryDenylist(registries []string) RegistryDenylist {
+	return RegistryDenylist{registries: toSet(registries)}
+}
+
+func (p RegistryDenylist) Check(image string) error {
+	ref, err := parse(image)
+	if err != nil {
+		return err
+	}
+
+	if registry := reference.Domain(ref); p.registries[registry] {
+		return violation(image, "registry %q is denied", registry)
+	}
+
+	return nil
+}
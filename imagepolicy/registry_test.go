@@ -0,0 +1,40 @@
+package imagepolicy_test
+
+import (
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/imagepolicy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("RegistryAllowlist",
+	func(image string, allowed bool) {
+		err := imagepolicy.NewRegistryAllowlist([]string{"docker.io", "registry.example.com:5000"}).Check(image)
+		if allowed {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+		}
+	},
+	Entry("official docker hub image", "busybox", true),
+	Entry("docker hub image", "eirini/some-app:tag", true),
+	Entry("image from an allowed registry", "registry.example.com:5000/some-app", true),
+	Entry("image from the same host on another port", "registry.example.com/some-app", false),
+	Entry("image from another registry", "quay.io/some-app", false),
+	Entry("invalid image", "this is invalid", false),
+)
+
+var _ = DescribeTable("RegistryDenylist",
+	func(image string, allowed bool) {
+		err := imagepolicy.NewRegistryDenylist([]string{"docker.io"}).Check(image)
+		if allowed {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+		}
+	},
+	Entry("official docker hub image", "busybox", false),
+	Entry("docker hub image referenced by its legacy registry", "index.docker.io/eirini/some-app", false),
+	Entry("image from another registry", "quay.io/some-app", true),
+	Entry("invalid image", "this is invalid", false),
+)
@@ -0,0 +1,59 @@
+package imagepolicy
+
+import "github.com/docker/distribution/reference"
+
+const defaultTag = "latest"
+
+// RequireDigest only allows images pinned to a digest, so that a workload
+// always runs the image it was desired with.
+type RequireDigest struct{}
+
+func (RequireDigest) Check(image string) error {
+	ref, err := parse(image)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := ref.(reference.Digested); !ok {
+		return violation(image, "image is not pinned to a digest")
+	}
+
+	return nil
+}
+
+// BannedTags rejects images referenced by one of the listed tags. Images
+// without a tag or digest are referenced by the latest tag. Images pinned to
+// a digest are allowed whatever their tag, as the tag is not used to pull
+// them.
+type BannedTags struct {
+	tags map[string]bool
+}
+
+func NewBannedTags(tags []string) BannedTags {
+	return BannedTags{tags: toSet(tags)}
+}
+
+func (p BannedTags) Check(image string) error {
+	ref, err := parse(image)
+	if err != nil {
+		return err
+	}
+
+	if tag := imageTag(ref); p.tags[tag] {
+		return violation(image, "tag %q is banned", tag)
+	}
+
+	return nil
+}
+
+func imageTag(ref reference.Named) string {
+	if _, ok := ref.(reference.Digested); ok {
+		return ""
+	}
+
+	if tagged, ok := ref.(reference.Tagged); ok {
+		return tagged.Tag()
+	}
+
+	return defaultTag
+}
@@ -0,0 +1,42 @@
+package imagepolicy_test
+
+import (
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/imagepolicy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const imageDigest = "sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1"
+
+var _ = DescribeTable("RequireDigest",
+	func(image string, allowed bool) {
+		err := imagepolicy.RequireDigest{}.Check(image)
+		if allowed {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+		}
+	},
+	Entry("untagged image", "busybox", false),
+	Entry("tagged image", "busybox:1.35", false),
+	Entry("image pinned to a digest", "busybox@"+imageDigest, true),
+	Entry("tagged image pinned to a digest", "busybox:1.35@"+imageDigest, true),
+)
+
+var _ = DescribeTable("BannedTags",
+	func(image string, allowed bool) {
+		err := imagepolicy.NewBannedTags([]string{"latest", "dev"}).Check(image)
+		if allowed {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(eirini.ErrImageNotAllowed))
+		}
+	},
+	Entry("untagged image", "busybox", false),
+	Entry("image with a banned tag", "registry.example.com:5000/some-app:dev", false),
+	Entry("image with another tag", "busybox:1.35", true),
+	Entry("image pinned to a digest", "busybox@"+imageDigest, true),
+	Entry("image with a banned tag pinned to a digest", "busybox:latest@"+imageDigest, true),
+	Entry("invalid image", "this is invalid", false),
+)
@@ -0,0 +1,378 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	IndexLRPIdentifier = "lrp-identifier"
+	IndexSourceType    = "source-type"
+	IndexGUID          = "guid"
+	IndexInvolvedPod   = "involved-pod"
+)
+
+// Cache keeps shared informers for the resources read by the Eirini API, so
+// that listing apps, instances and tasks does not hit the API server.
+type Cache struct {
+	statefulSets cache.SharedIndexInformer
+	pods         cache.SharedIndexInformer
+	jobs         cache.SharedIndexInformer
+	events       cache.SharedIndexInformer
+}
+
+func NewCache(clientSet kubernetes.Interface, workloadsNamespace string, resyncPeriod time.Duration) *Cache {
+	return &Cache{
+		statefulSets: cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+					return clientSet.AppsV1().StatefulSets(workloadsNamespace).List(context.Background(), opts)
+				},
+				WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+					return clientSet.AppsV1().StatefulSets(workloadsNamespace).Watch(context.Background(), opts)
+				},
+			},
+			&appsv1.StatefulSet{},
+			resyncPeriod,
+			cache.Indexers{
+				IndexLRPIdentifier: indexByLabels(stset.LabelGUID, stset.LabelVersion),
+				IndexSourceType:    indexByLabels(stset.LabelSourceType),
+			},
+		),
+		pods: cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+					return clientSet.CoreV1().Pods(workloadsNamespace).List(context.Background(), opts)
+				},
+				WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+					return clientSet.CoreV1().Pods(workloadsNamespace).Watch(context.Background(), opts)
+				},
+			},
+			&corev1.Pod{},
+			resyncPeriod,
+			cache.Indexers{
+				IndexLRPIdentifier: indexByLabels(stset.LabelGUID, stset.LabelVersion),
+				IndexSourceType:    indexByLabels(stset.LabelSourceType),
+			},
+		),
+		jobs: cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+					return clientSet.BatchV1().Jobs(workloadsNamespace).List(context.Background(), opts)
+				},
+				WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+					return clientSet.BatchV1().Jobs(workloadsNamespace).Watch(context.Background(), opts)
+				},
+			},
+			&batchv1.Job{},
+			resyncPeriod,
+			cache.Indexers{
+				IndexGUID:       indexByLabels(jobs.LabelGUID),
+				IndexSourceType: indexByLabels(jobs.LabelSourceType),
+			},
+		),
+		events: cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+					return clientSet.CoreV1().Events(workloadsNamespace).List(context.Background(), opts)
+				},
+				WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+					return clientSet.CoreV1().Events(workloadsNamespace).Watch(context.Background(), opts)
+				},
+			},
+			&corev1.Event{},
+			resyncPeriod,
+			cache.Indexers{
+				IndexInvolvedPod: indexEventByInvolvedObject,
+			},
+		),
+	}
+}
+
+// Start runs the informers until ctx is done. It does not block.
+func (c *Cache) Start(ctx context.Context) {
+	for _, informer := range c.informers() {
+		go informer.Run(ctx.Done())
+	}
+}
+
+// WaitForSync blocks until all informers have synced or ctx is done.
+func (c *Cache) WaitForSync(ctx context.Context) error {
+	synced := []cache.InformerSynced{}
+	for _, informer := range c.informers() {
+		synced = append(synced, informer.HasSynced)
+	}
+
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return errors.New("timed out waiting for informer caches to sync")
+	}
+
+	return nil
+}
+
+// HasSynced reports whether all informers have completed their initial list.
+func (c *Cache) HasSynced() bool {
+	for _, informer := range c.informers() {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Cache) informers() []cache.SharedIndexInformer {
+	return []cache.SharedIndexInformer{c.statefulSets, c.pods, c.jobs, c.events}
+}
+
+// CachedStatefulSet serves statefulset reads from the cache once it has
+// synced and delegates everything else to the wrapped client.
+type CachedStatefulSet struct {
+	*StatefulSet
+	cache *Cache
+}
+
+func NewCachedStatefulSet(statefulSetClient *StatefulSet, c *Cache) *CachedStatefulSet {
+	return &CachedStatefulSet{
+		StatefulSet: statefulSetClient,
+		cache:       c,
+	}
+}
+
+func (c *CachedStatefulSet) GetBySourceType(ctx context.Context, sourceType string) ([]appsv1.StatefulSet, error) {
+	if !c.cache.statefulSets.HasSynced() {
+		return c.StatefulSet.GetBySourceType(ctx, sourceType)
+	}
+
+	objs, err := c.cache.statefulSets.GetIndexer().ByIndex(IndexSourceType, sourceType)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cached statefulsets by resource type")
+	}
+
+	return toStatefulSets(objs), nil
+}
+
+func (c *CachedStatefulSet) GetByLRPIdentifier(ctx context.Context, id api.LRPIdentifier) ([]appsv1.StatefulSet, error) {
+	if !c.cache.statefulSets.HasSynced() {
+		return c.StatefulSet.GetByLRPIdentifier(ctx, id)
+	}
+
+	objs, err := c.cache.statefulSets.GetIndexer().ByIndex(IndexLRPIdentifier, indexKey(id.GUID, id.Version))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cached statefulsets by lrp identifier")
+	}
+
+	return toStatefulSets(objs), nil
+}
+
+// CachedPod serves pod reads from the cache once it has synced and delegates
+// everything else to the wrapped client.
+type CachedPod struct {
+	*Pod
+	cache *Cache
+}
+
+func NewCachedPod(podClient *Pod, c *Cache) *CachedPod {
+	return &CachedPod{
+		Pod:   podClient,
+		cache: c,
+	}
+}
+
+func (c *CachedPod) GetAll(ctx context.Context) ([]corev1.Pod, error) {
+	if !c.cache.pods.HasSynced() {
+		return c.Pod.GetAll(ctx)
+	}
+
+	pods := []corev1.Pod{}
+
+	for _, sourceType := range []string{stset.AppSourceType, jobs.TaskSourceType} {
+		objs, err := c.cache.pods.GetIndexer().ByIndex(IndexSourceType, sourceType)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list cached pods")
+		}
+
+		pods = append(pods, toPods(objs)...)
+	}
+
+	return pods, nil
+}
+
+func (c *CachedPod) GetByLRPIdentifier(ctx context.Context, id api.LRPIdentifier) ([]corev1.Pod, error) {
+	if !c.cache.pods.HasSynced() {
+		return c.Pod.GetByLRPIdentifier(ctx, id)
+	}
+
+	objs, err := c.cache.pods.GetIndexer().ByIndex(IndexLRPIdentifier, indexKey(id.GUID, id.Version))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cached pods by lrp identifier")
+	}
+
+	return toPods(objs), nil
+}
+
+// CachedJob serves job reads from the cache once it has synced and delegates
+// everything else to the wrapped client.
+type CachedJob struct {
+	*Job
+	cache *Cache
+}
+
+func NewCachedJob(jobClient *Job, c *Cache) *CachedJob {
+	return &CachedJob{
+		Job:   jobClient,
+		cache: c,
+	}
+}
+
+func (c *CachedJob) GetByGUID(ctx context.Context, guid string, includeCompleted bool) ([]batchv1.Job, error) {
+	if !c.cache.jobs.HasSynced() {
+		return c.Job.GetByGUID(ctx, guid, includeCompleted)
+	}
+
+	objs, err := c.cache.jobs.GetIndexer().ByIndex(IndexGUID, guid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cached jobs by guid")
+	}
+
+	return toJobs(objs, includeCompleted), nil
+}
+
+func (c *CachedJob) List(ctx context.Context, includeCompleted bool) ([]batchv1.Job, error) {
+	if !c.cache.jobs.HasSynced() {
+		return c.Job.List(ctx, includeCompleted)
+	}
+
+	objs, err := c.cache.jobs.GetIndexer().ByIndex(IndexSourceType, c.jobType)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cached jobs")
+	}
+
+	return toJobs(objs, includeCompleted), nil
+}
+
+// CachedEvent serves event reads from the cache once it has synced and
+// delegates everything else to the wrapped client.
+type CachedEvent struct {
+	*Event
+	cache *Cache
+}
+
+func NewCachedEvent(eventClient *Event, c *Cache) *CachedEvent {
+	return &CachedEvent{
+		Event: eventClient,
+		cache: c,
+	}
+}
+
+func (c *CachedEvent) GetByPod(ctx context.Context, pod corev1.Pod) ([]corev1.Event, error) {
+	if !c.cache.events.HasSynced() {
+		return c.Event.GetByPod(ctx, pod)
+	}
+
+	objs, err := c.cache.events.GetIndexer().ByIndex(IndexInvolvedPod, indexKey(pod.Namespace, string(pod.UID), pod.Name))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cached pod events")
+	}
+
+	events := make([]corev1.Event, 0, len(objs))
+	for _, obj := range objs {
+		events = append(events, *obj.(*corev1.Event).DeepCopy())
+	}
+
+	return events, nil
+}
+
+func indexByLabels(labels ...string) cache.IndexFunc {
+	return func(obj interface{}) ([]string, error) {
+		meta, err := metaAccessor(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0, len(labels))
+
+		for _, label := range labels {
+			value, ok := meta.GetLabels()[label]
+			if !ok {
+				return []string{}, nil
+			}
+
+			values = append(values, value)
+		}
+
+		return []string{indexKey(values...)}, nil
+	}
+}
+
+func indexEventByInvolvedObject(obj interface{}) ([]string, error) {
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		return nil, fmt.Errorf("expected an event, got %T", obj)
+	}
+
+	involved := event.InvolvedObject
+
+	return []string{indexKey(involved.Namespace, string(involved.UID), involved.Name)}, nil
+}
+
+func metaAccessor(obj interface{}) (metav1.Object, error) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, fmt.Errorf("object of type %T has no metadata", obj)
+	}
+
+	return meta, nil
+}
+
+func indexKey(values ...string) string {
+	return strings.Join(values, "/")
+}
+
+func toStatefulSets(objs []interface{}) []appsv1.StatefulSet {
+	statefulSets := make([]appsv1.StatefulSet, 0, len(objs))
+	for _, obj := range objs {
+		statefulSets = append(statefulSets, *obj.(*appsv1.StatefulSet).DeepCopy())
+	}
+
+	return statefulSets
+}
+
+func toPods(objs []interface{}) []corev1.Pod {
+	pods := make([]corev1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		pods = append(pods, *obj.(*corev1.Pod).DeepCopy())
+	}
+
+	return pods
+}
+
+func toJobs(objs []interface{}, includeCompleted bool) []batchv1.Job {
+	result := make([]batchv1.Job, 0, len(objs))
+
+	for _, obj := range objs {
+		job := obj.(*batchv1.Job)
+		if !includeCompleted && job.Labels[jobs.LabelTaskCompleted] == jobs.TaskCompletedTrue {
+			continue
+		}
+
+		result = append(result, *job.DeepCopy())
+	}
+
+	return result
+}
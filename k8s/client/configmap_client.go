@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ConfigMap struct {
+	clientSet kubernetes.Interface
+}
+
+func NewConfigMap(clientSet kubernetes.Interface) *ConfigMap {
+	return &ConfigMap{clientSet: clientSet}
+}
+
+func (c *ConfigMap) Create(ctx context.Context, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+}
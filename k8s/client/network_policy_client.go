@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type NetworkPolicy struct {
+	clientSet kubernetes.Interface
+}
+
+func NewNetworkPolicy(clientSet kubernetes.Interface) *NetworkPolicy {
+	return &NetworkPolicy{clientSet: clientSet}
+}
+
+func (c *NetworkPolicy) Get(ctx context.Context, namespace, name string) (*networkingv1.NetworkPolicy, error) {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.NetworkingV1().NetworkPolicies(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *NetworkPolicy) Create(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error) {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.NetworkingV1().NetworkPolicies(namespace).Create(ctx, networkPolicy, metav1.CreateOptions{})
+}
+
+func (c *NetworkPolicy) Update(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error) {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.NetworkingV1().NetworkPolicies(namespace).Update(ctx, networkPolicy, metav1.UpdateOptions{})
+}
+
+func (c *NetworkPolicy) Delete(ctx context.Context, namespace string, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
@@ -2,47 +2,107 @@ package client
 
 import (
 	"context"
+	"sync"
 
 	"code.cloudfoundry.org/eirini/k8s/patching"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// PodDisruptionBudget manages pod disruption budgets through the policy/v1
+// API. On clusters that do not serve policy/v1 yet it falls back to
+// policy/v1beta1, converting the objects to and from policy/v1.
 type PodDisruptionBudget struct {
 	clientSet kubernetes.Interface
+
+	discoverOnce sync.Once
+	useV1beta1   bool
 }
 
 func NewPodDisruptionBudget(clientSet kubernetes.Interface) *PodDisruptionBudget {
 	return &PodDisruptionBudget{clientSet: clientSet}
 }
 
-func (c *PodDisruptionBudget) Get(ctx context.Context, namespace, name string) (*policyv1beta1.PodDisruptionBudget, error) {
+// ServesPolicyV1 reports whether the cluster serves pod disruption budgets
+// through the policy/v1 API.
+func ServesPolicyV1(discoveryClient discovery.DiscoveryInterface) (bool, error) {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(policyv1.SchemeGroupVersion.String())
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrap(err, "failed to discover policy/v1 resources")
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Name == "poddisruptionbudgets" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (c *PodDisruptionBudget) Get(ctx context.Context, namespace, name string) (*policyv1.PodDisruptionBudget, error) {
 	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
 	defer cancel()
 
-	return c.clientSet.PolicyV1beta1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if c.legacy() {
+		pdb, err := c.clientSet.PolicyV1beta1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
+
+		return fromV1beta1(pdb), err
+	}
+
+	return c.clientSet.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
-func (c *PodDisruptionBudget) Create(ctx context.Context, namespace string, podDisruptionBudget *policyv1beta1.PodDisruptionBudget) (*policyv1beta1.PodDisruptionBudget, error) {
+func (c *PodDisruptionBudget) Create(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) (*policyv1.PodDisruptionBudget, error) {
 	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
 	defer cancel()
 
-	return c.clientSet.PolicyV1beta1().PodDisruptionBudgets(namespace).Create(ctx, podDisruptionBudget, metav1.CreateOptions{})
+	if c.legacy() {
+		pdb, err := c.clientSet.PolicyV1beta1().PodDisruptionBudgets(namespace).Create(ctx, toV1beta1(podDisruptionBudget), metav1.CreateOptions{})
+
+		return fromV1beta1(pdb), err
+	}
+
+	return c.clientSet.PolicyV1().PodDisruptionBudgets(namespace).Create(ctx, podDisruptionBudget, metav1.CreateOptions{})
+}
+
+func (c *PodDisruptionBudget) Update(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) (*policyv1.PodDisruptionBudget, error) {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	if c.legacy() {
+		pdb, err := c.clientSet.PolicyV1beta1().PodDisruptionBudgets(namespace).Update(ctx, toV1beta1(podDisruptionBudget), metav1.UpdateOptions{})
+
+		return fromV1beta1(pdb), err
+	}
+
+	return c.clientSet.PolicyV1().PodDisruptionBudgets(namespace).Update(ctx, podDisruptionBudget, metav1.UpdateOptions{})
 }
 
 func (c *PodDisruptionBudget) Delete(ctx context.Context, namespace string, name string) error {
 	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
 	defer cancel()
 
-	return c.clientSet.PolicyV1beta1().PodDisruptionBudgets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if c.legacy() {
+		return c.clientSet.PolicyV1beta1().PodDisruptionBudgets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	}
+
+	return c.clientSet.PolicyV1().PodDisruptionBudgets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 }
 
-func (c *PodDisruptionBudget) SetOwner(ctx context.Context, pdb *policyv1beta1.PodDisruptionBudget, owner *appsv1.StatefulSet) (*policyv1beta1.PodDisruptionBudget, error) {
+func (c *PodDisruptionBudget) SetOwner(ctx context.Context, pdb *policyv1.PodDisruptionBudget, owner *appsv1.StatefulSet) (*policyv1.PodDisruptionBudget, error) {
 	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
 	defer cancel()
 
@@ -52,5 +112,58 @@ func (c *PodDisruptionBudget) SetOwner(ctx context.Context, pdb *policyv1beta1.P
 
 	patch := patching.NewSetOwner(pdb.OwnerReferences[0])
 
-	return c.clientSet.PolicyV1beta1().PodDisruptionBudgets(pdb.Namespace).Patch(ctx, pdb.Name, patch.Type(), patch.GetPatchBytes(), metav1.PatchOptions{})
+	if c.legacy() {
+		updatedPDB, err := c.clientSet.PolicyV1beta1().PodDisruptionBudgets(pdb.Namespace).Patch(ctx, pdb.Name, patch.Type(), patch.GetPatchBytes(), metav1.PatchOptions{})
+
+		return fromV1beta1(updatedPDB), err
+	}
+
+	return c.clientSet.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Patch(ctx, pdb.Name, patch.Type(), patch.GetPatchBytes(), metav1.PatchOptions{})
+}
+
+// legacy discovers on first use whether the cluster still needs the
+// policy/v1beta1 API. Should discovery fail, policy/v1 is assumed, as all
+// supported Kubernetes versions serve it.
+func (c *PodDisruptionBudget) legacy() bool {
+	c.discoverOnce.Do(func() {
+		servesV1, err := ServesPolicyV1(c.clientSet.Discovery())
+		c.useV1beta1 = err == nil && !servesV1
+	})
+
+	return c.useV1beta1
+}
+
+func toV1beta1(pdb *policyv1.PodDisruptionBudget) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: pdb.ObjectMeta,
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable:   pdb.Spec.MinAvailable,
+			MaxUnavailable: pdb.Spec.MaxUnavailable,
+			Selector:       pdb.Spec.Selector,
+		},
+	}
+}
+
+func fromV1beta1(pdb *policyv1beta1.PodDisruptionBudget) *policyv1.PodDisruptionBudget {
+	if pdb == nil {
+		return nil
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: pdb.ObjectMeta,
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   pdb.Spec.MinAvailable,
+			MaxUnavailable: pdb.Spec.MaxUnavailable,
+			Selector:       pdb.Spec.Selector,
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			ObservedGeneration: pdb.Status.ObservedGeneration,
+			DisruptedPods:      pdb.Status.DisruptedPods,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			CurrentHealthy:     pdb.Status.CurrentHealthy,
+			DesiredHealthy:     pdb.Status.DesiredHealthy,
+			ExpectedPods:       pdb.Status.ExpectedPods,
+			Conditions:         pdb.Status.Conditions,
+		},
+	}
 }
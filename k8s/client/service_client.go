@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Service struct {
+	clientSet kubernetes.Interface
+}
+
+func NewService(clientSet kubernetes.Interface) *Service {
+	return &Service{clientSet: clientSet}
+}
+
+func (c *Service) Get(ctx context.Context, namespace, name string) (*corev1.Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *Service) Create(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.CoreV1().Services(namespace).Create(ctx, service, metav1.CreateOptions{})
+}
+
+func (c *Service) Update(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error) {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.CoreV1().Services(namespace).Update(ctx, service, metav1.UpdateOptions{})
+}
+
+func (c *Service) Delete(ctx context.Context, namespace string, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, k8sTimeout)
+	defer cancel()
+
+	return c.clientSet.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
@@ -0,0 +1,20 @@
+package callback_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCallback(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Informer Callback Suite")
+}
+
+var ctx context.Context
+
+var _ = BeforeEach(func() {
+	ctx = context.Background()
+})
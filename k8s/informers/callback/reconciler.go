@@ -0,0 +1,188 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/eirini/k8s/outbox"
+	"code.cloudfoundry.org/lager"
+	"github.com/pkg/errors"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	Deliveries     = "eirini_cc_callback_deliveries"
+	DeliveriesHelp = "The total number of attempts to deliver callbacks from the outbox to the cloud controller"
+
+	ResultDelivered = "delivered"
+	ResultFailed    = "failed"
+	ResultAbandoned = "abandoned"
+
+	initialBackoff = time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
+//counterfeiter:generate . CallbackClient
+//counterfeiter:generate -o callbackfakes/fake_controller_runtime_client.go sigs.k8s.io/controller-runtime/pkg/client.Client
+
+type CallbackClient interface {
+	Post(ctx context.Context, url string, data interface{}) error
+}
+
+// Reconciler delivers the callbacks stored in the outbox, retrying failed
+// deliveries with an exponential backoff until the retry limit is reached.
+type Reconciler struct {
+	logger         lager.Logger
+	client         client.Client
+	callbackClient CallbackClient
+	retryLimit     int
+	clock          clock.PassiveClock
+	deliveries     *prometheus_api.CounterVec
+}
+
+func NewReconciler(
+	logger lager.Logger,
+	client client.Client,
+	callbackClient CallbackClient,
+	retryLimit int,
+	clck clock.PassiveClock,
+	registry prometheus_api.Registerer,
+) (*Reconciler, error) {
+	deliveries := prometheus_api.NewCounterVec(prometheus_api.CounterOpts{
+		Name: Deliveries,
+		Help: DeliveriesHelp,
+	}, []string{"result"})
+
+	if err := registry.Register(deliveries); err != nil {
+		var are prometheus_api.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			return nil, errors.Wrap(err, "failed to register callback deliveries metric")
+		}
+
+		deliveries = are.ExistingCollector.(*prometheus_api.CounterVec) //nolint:forcetypeassert
+	}
+
+	return &Reconciler{
+		logger:         logger,
+		client:         client,
+		callbackClient: callbackClient,
+		retryLimit:     retryLimit,
+		clock:          clck,
+		deliveries:     deliveries,
+	}, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := r.logger.Session("deliver-callback", lager.Data{"namespace": request.Namespace, "name": request.Name})
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, request.NamespacedName, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("callback-not-found")
+
+			return reconcile.Result{}, nil
+		}
+
+		logger.Error("failed-to-get-callback", err)
+
+		return reconcile.Result{}, errors.Wrap(err, "failed to get callback")
+	}
+
+	if configMap.Labels[outbox.LabelSourceType] != outbox.SourceType {
+		return reconcile.Result{}, nil
+	}
+
+	if wait := r.untilNextAttempt(configMap); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	url := configMap.Data[outbox.KeyURL]
+	logger = logger.WithData(lager.Data{"url": url})
+
+	err := r.callbackClient.Post(ctx, url, json.RawMessage(configMap.Data[outbox.KeyPayload]))
+	if err == nil {
+		logger.Debug("callback-delivered")
+		r.deliveries.WithLabelValues(ResultDelivered).Inc()
+
+		return reconcile.Result{}, r.delete(ctx, configMap)
+	}
+
+	attempts := parseIntOrZero(configMap.Annotations[outbox.AnnotationAttempts]) + 1
+	logger.Error("failed-to-deliver-callback", err, lager.Data{"attempts": attempts})
+
+	if attempts >= r.retryLimit {
+		logger.Info("abandoning-callback", lager.Data{"attempts": attempts})
+		r.deliveries.WithLabelValues(ResultAbandoned).Inc()
+
+		return reconcile.Result{}, r.delete(ctx, configMap)
+	}
+
+	r.deliveries.WithLabelValues(ResultFailed).Inc()
+
+	wait := backoff(attempts)
+
+	updated := configMap.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+
+	updated.Annotations[outbox.AnnotationAttempts] = strconv.Itoa(attempts)
+	updated.Annotations[outbox.AnnotationNextAttempt] = r.clock.Now().Add(wait).UTC().Format(time.RFC3339)
+
+	if err = r.client.Update(ctx, updated); err != nil {
+		logger.Error("failed-to-record-attempt", err)
+
+		return reconcile.Result{}, errors.Wrap(err, "failed to record callback attempt")
+	}
+
+	return reconcile.Result{RequeueAfter: wait}, nil
+}
+
+// untilNextAttempt returns how long to wait before the next delivery
+// attempt. Keeping the time of the next attempt in the config map makes the
+// backoff survive restarts and leader changes.
+func (r *Reconciler) untilNextAttempt(configMap *corev1.ConfigMap) time.Duration {
+	nextAttempt, err := time.Parse(time.RFC3339, configMap.Annotations[outbox.AnnotationNextAttempt])
+	if err != nil {
+		return 0
+	}
+
+	return nextAttempt.Sub(r.clock.Now())
+}
+
+func (r *Reconciler) delete(ctx context.Context, configMap *corev1.ConfigMap) error {
+	if err := r.client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete callback")
+	}
+
+	return nil
+}
+
+func backoff(attempts int) time.Duration {
+	wait := initialBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+
+	if wait > maxBackoff {
+		return maxBackoff
+	}
+
+	return wait
+}
+
+func parseIntOrZero(s string) int {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
@@ -0,0 +1,254 @@
+package callback_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/eirini/k8s/informers/callback"
+	"code.cloudfoundry.org/eirini/k8s/informers/callback/callbackfakes"
+	"code.cloudfoundry.org/eirini/k8s/outbox"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		runtimeClient  *callbackfakes.FakeClient
+		callbackClient *callbackfakes.FakeCallbackClient
+		registry       *prometheus_api.Registry
+		fakeClock      *clock.FakePassiveClock
+		reconciler     *callback.Reconciler
+		configMap      *corev1.ConfigMap
+		getErr         error
+		reconcileRes   reconcile.Result
+		reconcileErr   error
+	)
+
+	BeforeEach(func() {
+		runtimeClient = new(callbackfakes.FakeClient)
+		callbackClient = new(callbackfakes.FakeCallbackClient)
+		registry = prometheus_api.NewRegistry()
+		fakeClock = clock.NewFakePassiveClock(time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC))
+		getErr = nil
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cc-callback-abc",
+				Namespace: "the-namespace",
+				Labels: map[string]string{
+					outbox.LabelSourceType: outbox.SourceType,
+				},
+			},
+			Data: map[string]string{
+				outbox.KeyURL:     "http://cc/callback",
+				outbox.KeyPayload: `{"task_guid":"the-guid"}`,
+			},
+		}
+
+		var err error
+		reconciler, err = callback.NewReconciler(tests.NewTestLogger("callback-reconciler"), runtimeClient, callbackClient, 3, fakeClock, registry)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		runtimeClient.GetStub = func(_ context.Context, _ types.NamespacedName, obj client.Object) error {
+			if getErr != nil {
+				return getErr
+			}
+
+			configMap.DeepCopyInto(obj.(*corev1.ConfigMap)) //nolint:forcetypeassert
+
+			return nil
+		}
+
+		reconcileRes, reconcileErr = reconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: "the-namespace", Name: "cc-callback-abc"},
+		})
+	})
+
+	It("succeeds", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(reconcileRes).To(Equal(reconcile.Result{}))
+	})
+
+	It("delivers the callback", func() {
+		Expect(callbackClient.PostCallCount()).To(Equal(1))
+		_, url, data := callbackClient.PostArgsForCall(0)
+		Expect(url).To(Equal("http://cc/callback"))
+
+		payload, err := json.Marshal(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(payload).To(MatchJSON(`{"task_guid":"the-guid"}`))
+	})
+
+	It("deletes the delivered callback", func() {
+		Expect(runtimeClient.DeleteCallCount()).To(Equal(1))
+		_, obj, _ := runtimeClient.DeleteArgsForCall(0)
+		Expect(obj.GetName()).To(Equal("cc-callback-abc"))
+	})
+
+	It("counts the delivery", func() {
+		Expect(deliveries(registry, callback.ResultDelivered)).To(Equal(1.0))
+	})
+
+	When("the callback no longer exists", func() {
+		BeforeEach(func() {
+			getErr = apierrors.NewNotFound(schema.GroupResource{}, "cc-callback-abc")
+		})
+
+		It("does nothing", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(callbackClient.PostCallCount()).To(BeZero())
+		})
+	})
+
+	When("getting the callback fails", func() {
+		BeforeEach(func() {
+			getErr = errors.New("boom")
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	When("the config map is not a callback", func() {
+		BeforeEach(func() {
+			configMap.Labels = nil
+		})
+
+		It("ignores it", func() {
+			Expect(callbackClient.PostCallCount()).To(BeZero())
+			Expect(runtimeClient.DeleteCallCount()).To(BeZero())
+		})
+	})
+
+	When("the next attempt is not due yet", func() {
+		BeforeEach(func() {
+			configMap.Annotations = map[string]string{
+				outbox.AnnotationNextAttempt: fakeClock.Now().Add(time.Minute).Format(time.RFC3339),
+			}
+		})
+
+		It("requeues the callback for when it is due", func() {
+			Expect(callbackClient.PostCallCount()).To(BeZero())
+			Expect(reconcileRes.RequeueAfter).To(Equal(time.Minute))
+		})
+	})
+
+	When("the delivery fails", func() {
+		BeforeEach(func() {
+			callbackClient.PostReturns(errors.New("cc is down"))
+		})
+
+		It("keeps the callback", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(runtimeClient.DeleteCallCount()).To(BeZero())
+		})
+
+		It("records the attempt and when to try again", func() {
+			Expect(runtimeClient.UpdateCallCount()).To(Equal(1))
+			_, obj, _ := runtimeClient.UpdateArgsForCall(0)
+			Expect(obj.GetAnnotations()).To(SatisfyAll(
+				HaveKeyWithValue(outbox.AnnotationAttempts, "1"),
+				HaveKeyWithValue(outbox.AnnotationNextAttempt, "2022-03-01T12:00:01Z"),
+			))
+		})
+
+		It("requeues the callback", func() {
+			Expect(reconcileRes.RequeueAfter).To(Equal(time.Second))
+		})
+
+		It("counts the failure", func() {
+			Expect(deliveries(registry, callback.ResultFailed)).To(Equal(1.0))
+		})
+
+		When("it has failed before", func() {
+			BeforeEach(func() {
+				configMap.Annotations = map[string]string{
+					outbox.AnnotationAttempts:    "1",
+					outbox.AnnotationNextAttempt: fakeClock.Now().Add(-time.Second).Format(time.RFC3339),
+				}
+			})
+
+			It("backs off exponentially", func() {
+				Expect(reconcileRes.RequeueAfter).To(Equal(2 * time.Second))
+				_, obj, _ := runtimeClient.UpdateArgsForCall(0)
+				Expect(obj.GetAnnotations()).To(HaveKeyWithValue(outbox.AnnotationAttempts, "2"))
+			})
+		})
+
+		When("the retry limit is reached", func() {
+			BeforeEach(func() {
+				configMap.Annotations = map[string]string{
+					outbox.AnnotationAttempts: "2",
+				}
+			})
+
+			It("gives up and deletes the callback", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+				Expect(runtimeClient.DeleteCallCount()).To(Equal(1))
+			})
+
+			It("counts the abandoned callback", func() {
+				Expect(deliveries(registry, callback.ResultAbandoned)).To(Equal(1.0))
+			})
+		})
+
+		When("recording the attempt fails", func() {
+			BeforeEach(func() {
+				runtimeClient.UpdateReturns(errors.New("conflict"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("conflict")))
+			})
+		})
+	})
+
+	When("deleting the delivered callback fails", func() {
+		BeforeEach(func() {
+			runtimeClient.DeleteReturns(errors.New("delete-failed"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("delete-failed")))
+		})
+	})
+
+	When("a reconciler is created with the same registry", func() {
+		It("adopts the existing metric", func() {
+			_, err := callback.NewReconciler(tests.NewTestLogger("callback-reconciler"), runtimeClient, callbackClient, 3, fakeClock, registry)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
+
+func deliveries(registry *prometheus_api.Registry, result string) float64 {
+	counter := prometheus_api.NewCounterVec(prometheus_api.CounterOpts{
+		Name: callback.Deliveries,
+		Help: callback.DeliveriesHelp,
+	}, []string{"result"})
+
+	var are prometheus_api.AlreadyRegisteredError
+	Expect(errors.As(registry.Register(counter), &are)).To(BeTrue())
+
+	existing, ok := are.ExistingCollector.(*prometheus_api.CounterVec)
+	Expect(ok).To(BeTrue())
+
+	return testutil.ToFloat64(existing.WithLabelValues(result))
+}
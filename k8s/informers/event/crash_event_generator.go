@@ -2,6 +2,7 @@ package event
 
 import (
 	"context"
+	"fmt"
 
 	"code.cloudfoundry.org/eirini/events"
 	"code.cloudfoundry.org/eirini/k8s"
@@ -52,19 +53,46 @@ func (g DefaultCrashEventGenerator) Generate(ctx context.Context, pod *v1.Pod, l
 		return events.CrashEvent{}, false
 	}
 
-	if appStatus.State.Terminated != nil {
-		return g.generateReportForTerminatedPod(ctx, pod, appStatus, logger)
+	if !anyTerminated(statuses) {
+		logger.Debug("skipping-pod-healthy")
+
+		return events.CrashEvent{}, false
 	}
 
-	if appStatus.LastTerminationState.Terminated != nil {
-		exitStatus := int(appStatus.LastTerminationState.Terminated.ExitCode)
-		exitDescription := appStatus.LastTerminationState.Terminated.Reason
-		crashTimestamp := appStatus.LastTerminationState.Terminated.FinishedAt.Unix()
+	// report the most recent crash of any container, so that crashes of
+	// sidecars are not hidden by an older crash of the app container
+	report, crashed := g.generateContainerReport(ctx, pod, appStatus, logger)
+
+	for i := range statuses {
+		status := &statuses[i]
+		if status.Name == stset.ApplicationContainerName {
+			continue
+		}
+
+		sidecarReport, sidecarCrashed := g.generateContainerReport(ctx, pod, status, logger)
+		if !sidecarCrashed || (crashed && sidecarReport.CrashTimestamp <= report.CrashTimestamp) {
+			continue
+		}
 
-		return generateReport(pod, appStatus.LastTerminationState.Terminated.Reason, exitStatus, exitDescription, crashTimestamp, calculateCrashCount(appStatus)), true
+		sidecarReport.ExitDescription = fmt.Sprintf("sidecar %s: %s", status.Name, sidecarReport.ExitDescription)
+		report, crashed = sidecarReport, true
 	}
 
-	logger.Debug("skipping-pod-healthy")
+	return report, crashed
+}
+
+func (g DefaultCrashEventGenerator) generateContainerReport(ctx context.Context, pod *v1.Pod, status *v1.ContainerStatus, logger lager.Logger) (events.CrashEvent, bool) {
+	if status.State.Terminated != nil {
+		return g.generateReportForTerminatedPod(ctx, pod, status, logger)
+	}
+
+	if status.LastTerminationState.Terminated != nil {
+		exitStatus := int(status.LastTerminationState.Terminated.ExitCode)
+		exitDescription := status.LastTerminationState.Terminated.Reason
+		crashTimestamp := status.LastTerminationState.Terminated.FinishedAt.Unix()
+
+		return generateReport(pod, status.LastTerminationState.Terminated.Reason, exitStatus, exitDescription, crashTimestamp, calculateCrashCount(status)), true
+	}
 
 	return events.CrashEvent{}, false
 }
@@ -136,6 +164,16 @@ func calculateCrashCount(containerState *v1.ContainerStatus) int {
 	return int(containerState.RestartCount + 1)
 }
 
+func anyTerminated(statuses []v1.ContainerStatus) bool {
+	for _, status := range statuses {
+		if status.State.Terminated != nil || status.LastTerminationState.Terminated != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
 func isStopped(events []v1.Event) bool {
 	if len(events) == 0 {
 		return false
@@ -164,9 +164,19 @@ var _ = Describe("CrashEventGenerator", func() {
 				pod = newTerminatedSidecarPod()
 			})
 
-			It("should not emit a crashed event", func() {
-				_, returned := generator.Generate(ctx, pod, logger)
-				Expect(returned).To(BeFalse())
+			It("should generate a crashed report for the sidecar", func() {
+				report, returned := generator.Generate(ctx, pod, logger)
+				Expect(returned).To(BeTrue())
+				Expect(report).To(Equal(events.CrashEvent{
+					ProcessGUID: "test-pod-anno",
+					AppCrashedRequest: cc_messages.AppCrashedRequest{
+						Reason:          "better luck next time",
+						Instance:        "test-pod-0",
+						ExitDescription: "sidecar some-sidecar-container: better luck next time",
+						CrashCount:      9,
+						CrashTimestamp:  crashTime.Unix(),
+					},
+				}))
 			})
 		})
 
@@ -200,9 +210,39 @@ var _ = Describe("CrashEventGenerator", func() {
 				})
 			})
 
-			It("should not emit a crashed event", func() {
-				_, returned := generator.Generate(ctx, pod, logger)
-				Expect(returned).To(BeFalse())
+			It("should generate a crashed report for the sidecar", func() {
+				report, returned := generator.Generate(ctx, pod, logger)
+				Expect(returned).To(BeTrue())
+				Expect(report).To(Equal(events.CrashEvent{
+					ProcessGUID: "test-pod-anno",
+					AppCrashedRequest: cc_messages.AppCrashedRequest{
+						Reason:          "better luck next time",
+						Instance:        "test-pod-0",
+						ExitStatus:      1,
+						ExitDescription: "sidecar some-sidecar-container: better luck next time",
+						CrashCount:      2,
+						CrashTimestamp:  crashTime.Unix(),
+					},
+				}))
+			})
+
+			When("the app container crashed more recently", func() {
+				BeforeEach(func() {
+					pod.Status.ContainerStatuses[0].LastTerminationState = v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Reason:     "app crashed",
+							FinishedAt: meta.Time{Time: crashTime.Add(time.Minute)},
+							ExitCode:   2,
+						},
+					}
+				})
+
+				It("should report the app container crash", func() {
+					report, returned := generator.Generate(ctx, pod, logger)
+					Expect(returned).To(BeTrue())
+					Expect(report.ExitDescription).To(Equal("app crashed"))
+					Expect(report.ExitStatus).To(Equal(2))
+				})
 			})
 		})
 	})
@@ -0,0 +1,20 @@
+package lrp_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLRP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Informer LRP Suite")
+}
+
+var ctx context.Context
+
+var _ = BeforeEach(func() {
+	ctx = context.Background()
+})
@@ -0,0 +1,123 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package lrpfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/informers/lrp"
+	v1 "k8s.io/api/apps/v1"
+	v1a "k8s.io/api/core/v1"
+)
+
+type FakeStatefulSetConverter struct {
+	ConvertStub        func(string, *api.LRP, *v1a.Secret) (*v1.StatefulSet, error)
+	convertMutex       sync.RWMutex
+	convertArgsForCall []struct {
+		arg1 string
+		arg2 *api.LRP
+		arg3 *v1a.Secret
+	}
+	convertReturns struct {
+		result1 *v1.StatefulSet
+		result2 error
+	}
+	convertReturnsOnCall map[int]struct {
+		result1 *v1.StatefulSet
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStatefulSetConverter) Convert(arg1 string, arg2 *api.LRP, arg3 *v1a.Secret) (*v1.StatefulSet, error) {
+	fake.convertMutex.Lock()
+	ret, specificReturn := fake.convertReturnsOnCall[len(fake.convertArgsForCall)]
+	fake.convertArgsForCall = append(fake.convertArgsForCall, struct {
+		arg1 string
+		arg2 *api.LRP
+		arg3 *v1a.Secret
+	}{arg1, arg2, arg3})
+	stub := fake.ConvertStub
+	fakeReturns := fake.convertReturns
+	fake.recordInvocation("Convert", []interface{}{arg1, arg2, arg3})
+	fake.convertMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStatefulSetConverter) ConvertCallCount() int {
+	fake.convertMutex.RLock()
+	defer fake.convertMutex.RUnlock()
+	return len(fake.convertArgsForCall)
+}
+
+func (fake *FakeStatefulSetConverter) ConvertCalls(stub func(string, *api.LRP, *v1a.Secret) (*v1.StatefulSet, error)) {
+	fake.convertMutex.Lock()
+	defer fake.convertMutex.Unlock()
+	fake.ConvertStub = stub
+}
+
+func (fake *FakeStatefulSetConverter) ConvertArgsForCall(i int) (string, *api.LRP, *v1a.Secret) {
+	fake.convertMutex.RLock()
+	defer fake.convertMutex.RUnlock()
+	argsForCall := fake.convertArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStatefulSetConverter) ConvertReturns(result1 *v1.StatefulSet, result2 error) {
+	fake.convertMutex.Lock()
+	defer fake.convertMutex.Unlock()
+	fake.ConvertStub = nil
+	fake.convertReturns = struct {
+		result1 *v1.StatefulSet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStatefulSetConverter) ConvertReturnsOnCall(i int, result1 *v1.StatefulSet, result2 error) {
+	fake.convertMutex.Lock()
+	defer fake.convertMutex.Unlock()
+	fake.ConvertStub = nil
+	if fake.convertReturnsOnCall == nil {
+		fake.convertReturnsOnCall = make(map[int]struct {
+			result1 *v1.StatefulSet
+			result2 error
+		})
+	}
+	fake.convertReturnsOnCall[i] = struct {
+		result1 *v1.StatefulSet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStatefulSetConverter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.convertMutex.RLock()
+	defer fake.convertMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStatefulSetConverter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ lrp.StatefulSetConverter = new(FakeStatefulSetConverter)
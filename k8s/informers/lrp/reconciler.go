@@ -0,0 +1,576 @@
+package lrp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/lager"
+	"github.com/pkg/errors"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	DriftCorrections     = "eirini_lrp_drift_corrections"
+	DriftCorrectionsHelp = "The total number of corrections made to LRP resources that drifted from the desired state"
+	DriftFailures        = "eirini_lrp_drift_failures"
+	DriftFailuresHelp    = "The total number of LRP resources that drifted from the desired state and could not be corrected"
+
+	EventReasonDriftCorrected     = "DriftCorrected"
+	EventReasonDriftUncorrectable = "DriftUncorrectable"
+
+	ResourceStatefulSet         = "statefulset"
+	ResourcePodDisruptionBudget = "pod_disruption_budget"
+	ResourceRegistrySecret      = "registry_secret"
+	ResourceEnvSecret           = "env_secret"
+)
+
+//counterfeiter:generate . LRPConverter
+//counterfeiter:generate . StatefulSetConverter
+//counterfeiter:generate . PodDisruptionBudgetUpdater
+//counterfeiter:generate -o lrpfakes/fake_controller_runtime_client.go sigs.k8s.io/controller-runtime/pkg/client.Client
+
+type LRPConverter interface {
+	ConvertLRP(request cf.DesireLRPRequest) (api.LRP, error)
+}
+
+type StatefulSetConverter interface {
+	Convert(statefulSetName string, lrp *api.LRP, privateRegistrySecret *corev1.Secret) (*appsv1.StatefulSet, error)
+}
+
+type PodDisruptionBudgetUpdater interface {
+	Update(ctx context.Context, stset *appsv1.StatefulSet, lrp *api.LRP) error
+}
+
+// Reconciler re-applies the state an LRP was desired with to its
+// statefulset and the resources owned by it, reverting any changes made
+// behind eirini's back.
+//
+// The env secret and the private registry password are redacted from the
+// original request, so a deleted env secret, or a deleted registry secret,
+// cannot be recreated. The reconciler reports those with a warning event and
+// the DriftFailures metric; the LRP has to be desired again by Cloud
+// Controller (e.g. by restarting the app) to recover.
+type Reconciler struct {
+	logger               lager.Logger
+	client               client.Client
+	lrpConverter         LRPConverter
+	statefulSetConverter StatefulSetConverter
+	pdbUpdater           PodDisruptionBudgetUpdater
+	eventRecorder        record.EventRecorder
+	corrections          *prometheus_api.CounterVec
+	failures             *prometheus_api.CounterVec
+}
+
+func NewReconciler(
+	logger lager.Logger,
+	client client.Client,
+	lrpConverter LRPConverter,
+	statefulSetConverter StatefulSetConverter,
+	pdbUpdater PodDisruptionBudgetUpdater,
+	eventRecorder record.EventRecorder,
+	registry prometheus_api.Registerer,
+) (*Reconciler, error) {
+	corrections, err := registerResourceCounter(registry, DriftCorrections, DriftCorrectionsHelp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register drift corrections metric")
+	}
+
+	failures, err := registerResourceCounter(registry, DriftFailures, DriftFailuresHelp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register drift failures metric")
+	}
+
+	return &Reconciler{
+		logger:               logger,
+		client:               client,
+		lrpConverter:         lrpConverter,
+		statefulSetConverter: statefulSetConverter,
+		pdbUpdater:           pdbUpdater,
+		eventRecorder:        eventRecorder,
+		corrections:          corrections,
+		failures:             failures,
+	}, nil
+}
+
+// registerResourceCounter registers a counter with a "resource" label, or
+// returns the one already registered by another reconciler.
+func registerResourceCounter(registry prometheus_api.Registerer, name, help string) (*prometheus_api.CounterVec, error) {
+	counter := prometheus_api.NewCounterVec(prometheus_api.CounterOpts{
+		Name: name,
+		Help: help,
+	}, []string{"resource"})
+
+	if err := registry.Register(counter); err != nil {
+		var are prometheus_api.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			return nil, err
+		}
+
+		return are.ExistingCollector.(*prometheus_api.CounterVec), nil //nolint:forcetypeassert
+	}
+
+	return counter, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := r.logger.Session("reconcile-lrp", lager.Data{"namespace": request.Namespace, "name": request.Name})
+
+	statefulSet := &appsv1.StatefulSet{}
+	if err := r.client.Get(ctx, request.NamespacedName, statefulSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("statefulset-not-found")
+
+			return reconcile.Result{}, nil
+		}
+
+		logger.Error("failed-to-get-statefulset", err)
+
+		return reconcile.Result{}, errors.Wrap(err, "failed to get statefulset")
+	}
+
+	if statefulSet.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	// eirini-controller reconciles the statefulsets of LRP resources
+	if statefulSetOwnedByLRP(statefulSet) {
+		return reconcile.Result{}, nil
+	}
+
+	lrp, err := r.desiredLRP(statefulSet)
+	if err != nil {
+		// retrying will not make the original request any more valid
+		logger.Info("skipping-statefulset-without-valid-original-request", lager.Data{"error": err.Error()})
+
+		return reconcile.Result{}, nil
+	}
+
+	if err = r.reconcileStatefulSet(ctx, logger, statefulSet, lrp); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err = r.reconcilePodDisruptionBudget(ctx, logger, statefulSet, lrp); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err = r.reconcileRegistrySecrets(ctx, logger, statefulSet, lrp); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err = r.checkEnvSecret(ctx, logger, statefulSet); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *Reconciler) desiredLRP(statefulSet *appsv1.StatefulSet) (*api.LRP, error) {
+	originalRequest := statefulSet.Annotations[stset.AnnotationOriginalRequest]
+
+	var request cf.DesireLRPRequest
+	if err := json.Unmarshal([]byte(originalRequest), &request); err != nil {
+		return nil, errors.Wrap(err, "failed to parse original request")
+	}
+
+	request.LRP = originalRequest
+
+	lrp, err := r.lrpConverter.ConvertLRP(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert original request")
+	}
+
+	return &lrp, nil
+}
+
+func (r *Reconciler) reconcileStatefulSet(ctx context.Context, logger lager.Logger, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
+	desired, err := r.statefulSetConverter.Convert(statefulSet.Name, lrp, nil)
+	if err != nil {
+		logger.Error("failed-to-convert-lrp", err)
+
+		return errors.Wrap(err, "failed to convert lrp to statefulset")
+	}
+
+	desiredState := toDesiredState(desired, statefulSet)
+
+	inSync, err := isSubset(desiredState, toDesiredState(statefulSet, statefulSet))
+	if err != nil {
+		return errors.Wrap(err, "failed to compare statefulsets")
+	}
+
+	if inSync {
+		return nil
+	}
+
+	updated := statefulSet.DeepCopy()
+	updated.Spec.Replicas = desiredState.Replicas
+	updated.Spec.Template = desiredState.Template
+	updated.Labels = mergeMaps(updated.Labels, desiredState.Labels)
+	updated.Annotations = mergeMaps(updated.Annotations, desiredState.Annotations)
+
+	if err = r.client.Update(ctx, updated); err != nil {
+		logger.Error("failed-to-update-statefulset", err)
+
+		return errors.Wrap(err, "failed to update statefulset")
+	}
+
+	r.recordCorrection(logger, statefulSet, ResourceStatefulSet, "Reverted changes to the statefulset")
+
+	return nil
+}
+
+func (r *Reconciler) reconcilePodDisruptionBudget(ctx context.Context, logger lager.Logger, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
+	if lrp.TargetInstances <= 1 {
+		return nil
+	}
+
+	pdbName := types.NamespacedName{Namespace: statefulSet.Namespace, Name: statefulSet.Name}
+
+	err := r.client.Get(ctx, pdbName, &policyv1.PodDisruptionBudget{})
+	if meta.IsNoMatchError(err) {
+		// clusters that do not serve policy/v1 yet
+		err = r.client.Get(ctx, pdbName, &policyv1beta1.PodDisruptionBudget{})
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		logger.Error("failed-to-get-pod-disruption-budget", err)
+
+		return errors.Wrap(err, "failed to get pod disruption budget")
+	}
+
+	if err = r.pdbUpdater.Update(ctx, statefulSet, lrp); err != nil {
+		logger.Error("failed-to-recreate-pod-disruption-budget", err)
+
+		return errors.Wrap(err, "failed to recreate pod disruption budget")
+	}
+
+	r.recordCorrection(logger, statefulSet, ResourcePodDisruptionBudget, "Recreated the missing pod disruption budget")
+
+	return nil
+}
+
+func (r *Reconciler) reconcileRegistrySecrets(ctx context.Context, logger lager.Logger, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
+	if lrp.PrivateRegistry == nil {
+		return nil
+	}
+
+	for _, ref := range statefulSet.Spec.Template.Spec.ImagePullSecrets {
+		if !strings.HasPrefix(ref.Name, stset.PrivateRegistrySecretGenerateName) {
+			continue
+		}
+
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: statefulSet.Namespace, Name: ref.Name}, &corev1.Secret{})
+		if err == nil {
+			continue
+		}
+
+		if !apierrors.IsNotFound(err) {
+			logger.Error("failed-to-get-registry-secret", err, lager.Data{"secret": ref.Name})
+
+			return errors.Wrap(err, "failed to get registry secret")
+		}
+
+		// the password is redacted from the original request, so the
+		// secret cannot be recreated from it
+		if lrp.PrivateRegistry.Password == "" {
+			r.recordFailure(logger, statefulSet, ResourceRegistrySecret,
+				"Registry secret "+ref.Name+" is missing and cannot be recreated without the password; the app has to be desired again")
+
+			continue
+		}
+
+		if err = r.recreateRegistrySecret(ctx, statefulSet, lrp, ref.Name); err != nil {
+			logger.Error("failed-to-recreate-registry-secret", err, lager.Data{"secret": ref.Name})
+
+			return err
+		}
+
+		r.recordCorrection(logger, statefulSet, ResourceRegistrySecret, "Recreated the missing registry secret "+ref.Name)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) recreateRegistrySecret(ctx context.Context, statefulSet *appsv1.StatefulSet, lrp *api.LRP, name string) error {
+	secret, err := stset.GenerateRegistryCredsSecret(lrp)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate registry secret")
+	}
+
+	secret.GenerateName = ""
+	secret.Name = name
+	secret.Namespace = statefulSet.Namespace
+
+	if err = controllerutil.SetOwnerReference(statefulSet, secret, scheme.Scheme); err != nil {
+		return errors.Wrap(err, "failed to set owner of registry secret")
+	}
+
+	return errors.Wrap(r.client.Create(ctx, secret), "failed to create registry secret")
+}
+
+// checkEnvSecret reports a missing env secret. Its values are not in the
+// original request, so it cannot be recreated.
+func (r *Reconciler) checkEnvSecret(ctx context.Context, logger lager.Logger, statefulSet *appsv1.StatefulSet) error {
+	secretName := stset.EnvSecretName(statefulSet.Name)
+	if !referencesSecret(statefulSet, secretName) {
+		return nil
+	}
+
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: statefulSet.Namespace, Name: secretName}, &corev1.Secret{})
+	if err == nil {
+		return nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		logger.Error("failed-to-get-env-secret", err, lager.Data{"secret": secretName})
+
+		return errors.Wrap(err, "failed to get env secret")
+	}
+
+	r.recordFailure(logger, statefulSet, ResourceEnvSecret,
+		"Env secret "+secretName+" is missing and cannot be recreated; the app has to be desired again")
+
+	return nil
+}
+
+func referencesSecret(statefulSet *appsv1.StatefulSet, secretName string) bool {
+	for _, container := range statefulSet.Spec.Template.Spec.Containers {
+		for _, envVar := range container.Env {
+			if envVar.ValueFrom != nil && envVar.ValueFrom.SecretKeyRef != nil && envVar.ValueFrom.SecretKeyRef.Name == secretName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r *Reconciler) recordFailure(logger lager.Logger, statefulSet *appsv1.StatefulSet, resource, message string) {
+	logger.Info("cannot-correct-drift", lager.Data{"resource": resource})
+	r.failures.WithLabelValues(resource).Inc()
+	r.eventRecorder.Event(statefulSet, corev1.EventTypeWarning, EventReasonDriftUncorrectable, message)
+}
+
+func (r *Reconciler) recordCorrection(logger lager.Logger, statefulSet *appsv1.StatefulSet, resource, message string) {
+	logger.Info("corrected-drift", lager.Data{"resource": resource})
+	r.corrections.WithLabelValues(resource).Inc()
+	r.eventRecorder.Event(statefulSet, corev1.EventTypeWarning, EventReasonDriftCorrected, message)
+}
+
+// desiredState is the part of a statefulset the reconciler is responsible
+// for.
+type desiredState struct {
+	Labels      map[string]string      `json:"labels"`
+	Annotations map[string]string      `json:"annotations"`
+	Replicas    *int32                 `json:"replicas"`
+	Template    corev1.PodTemplateSpec `json:"template"`
+}
+
+// toDesiredState extracts the reconciled state of a statefulset. Values
+// that legitimately change without the pod template being regenerated are
+// taken from the current statefulset, so that they never count as drift.
+func toDesiredState(statefulSet, current *appsv1.StatefulSet) desiredState {
+	template := *statefulSet.Spec.Template.DeepCopy()
+	template.Spec.ImagePullSecrets = current.Spec.Template.Spec.ImagePullSecrets
+
+	template.Annotations = copyMap(template.Annotations)
+	for _, key := range []string{stset.AnnotationLastUpdated, stset.AnnotationOriginalRequest, stset.AnnotationEnvChecksum, shared.AnnotationLatestMigration} {
+		if value, ok := current.Spec.Template.Annotations[key]; ok {
+			template.Annotations[key] = value
+		} else {
+			delete(template.Annotations, key)
+		}
+	}
+
+	annotations := copyMap(statefulSet.Annotations)
+	delete(annotations, shared.AnnotationLatestMigration)
+
+	// The original request does not hold the environment values, so the
+	// checksum of the env secret can only be taken from the statefulset.
+	if value, ok := current.Annotations[stset.AnnotationEnvChecksum]; ok {
+		annotations[stset.AnnotationEnvChecksum] = value
+	} else {
+		delete(annotations, stset.AnnotationEnvChecksum)
+	}
+
+	return desiredState{
+		Labels:      statefulSet.Labels,
+		Annotations: annotations,
+		Replicas:    statefulSet.Spec.Replicas,
+		Template:    template,
+	}
+}
+
+// isSubset tells whether every value set in desired has the same value in
+// actual. Fields that are only set in actual, such as the ones defaulted by
+// the API server, are ignored.
+func isSubset(desired, actual interface{}) (bool, error) {
+	desiredValue, err := toUnstructured(desired)
+	if err != nil {
+		return false, err
+	}
+
+	actualValue, err := toUnstructured(actual)
+	if err != nil {
+		return false, err
+	}
+
+	return isSubsetValue(desiredValue, actualValue), nil
+}
+
+func isSubsetValue(desired, actual interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return len(d) == 0
+		}
+
+		for key, value := range d {
+			if !isSubsetValue(value, a[key]) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(d) {
+			return len(d) == 0 && len(a) == 0
+		}
+
+		if byName, ok := indexByName(a); ok {
+			return isSubsetNamedList(d, byName)
+		}
+
+		for i := range d {
+			if !isSubsetValue(d[i], a[i]) {
+				return false
+			}
+		}
+
+		return true
+	case nil:
+		return true
+	default:
+		return desired == actual
+	}
+}
+
+// isSubsetNamedList matches desired items to actual items by their name
+// rather than their position, since the order of named lists such as env vars
+// or label selector requirements carries no meaning.
+func isSubsetNamedList(desired []interface{}, actual map[string]interface{}) bool {
+	for _, item := range desired {
+		name, ok := itemName(item)
+		if !ok {
+			return false
+		}
+
+		if !isSubsetValue(item, actual[name]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// indexByName returns the items of list keyed by their "name" (or "key")
+// field, or false if any item has no such field or names are not unique.
+func indexByName(list []interface{}) (map[string]interface{}, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+
+	result := map[string]interface{}{}
+
+	for _, item := range list {
+		name, ok := itemName(item)
+		if !ok {
+			return nil, false
+		}
+
+		if _, exists := result[name]; exists {
+			return nil, false
+		}
+
+		result[name] = item
+	}
+
+	return result, true
+}
+
+func itemName(item interface{}) (string, bool) {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+
+	for _, field := range []string{"name", "key"} {
+		if name, ok := m[field].(string); ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+func toUnstructured(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	err = json.Unmarshal(data, &result)
+
+	return result, err
+}
+
+func copyMap(m map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range m {
+		result[k] = v
+	}
+
+	return result
+}
+
+func mergeMaps(dst, src map[string]string) map[string]string {
+	result := copyMap(dst)
+	for k, v := range src {
+		result[k] = v
+	}
+
+	return result
+}
+
+func statefulSetOwnedByLRP(statefulSet *appsv1.StatefulSet) bool {
+	for _, ref := range statefulSet.GetOwnerReferences() {
+		if ref.Kind == "LRP" {
+			return true
+		}
+	}
+
+	return false
+}
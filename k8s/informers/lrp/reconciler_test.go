@@ -0,0 +1,580 @@
+package lrp_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s"
+	"code.cloudfoundry.org/eirini/k8s/informers/lrp"
+	"code.cloudfoundry.org/eirini/k8s/informers/lrp/lrpfakes"
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		runtimeClient        *lrpfakes.FakeClient
+		lrpConverter         *lrpfakes.FakeLRPConverter
+		statefulSetConverter *lrpfakes.FakeStatefulSetConverter
+		pdbUpdater           *lrpfakes.FakePodDisruptionBudgetUpdater
+		eventRecorder        *record.FakeRecorder
+		registry             *prometheus_api.Registry
+		reconciler           *lrp.Reconciler
+
+		statefulSet    *appsv1.StatefulSet
+		desiredLRP     api.LRP
+		getStsetErr    error
+		getPDBErr      error
+		getV1PDBErr    error
+		getSecretErr   error
+		reconcileErr   error
+		reconcileRes   reconcile.Result
+		desiredReplica int32
+	)
+
+	newStatefulSet := func() *appsv1.StatefulSet {
+		replicas := desiredReplica
+
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "baldur",
+				Namespace: "the-namespace",
+				Labels: map[string]string{
+					stset.LabelGUID:       "the-guid",
+					stset.LabelSourceType: stset.AppSourceType,
+				},
+				Annotations: map[string]string{
+					stset.AnnotationOriginalRequest: `{"guid":"the-guid","instances":2}`,
+					stset.AnnotationLastUpdated:     "now",
+				},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							stset.AnnotationOriginalRequest: `{"guid":"the-guid","instances":2}`,
+							stset.AnnotationLastUpdated:     "now",
+						},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:  stset.ApplicationContainerName,
+								Image: "the/image",
+								Env:   []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+							},
+						},
+						ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-secret"}},
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		runtimeClient = new(lrpfakes.FakeClient)
+		lrpConverter = new(lrpfakes.FakeLRPConverter)
+		statefulSetConverter = new(lrpfakes.FakeStatefulSetConverter)
+		pdbUpdater = new(lrpfakes.FakePodDisruptionBudgetUpdater)
+		eventRecorder = record.NewFakeRecorder(10)
+		registry = prometheus_api.NewRegistry()
+
+		desiredReplica = 2
+		statefulSet = newStatefulSet()
+		getStsetErr = nil
+		getPDBErr = nil
+		getV1PDBErr = nil
+		getSecretErr = nil
+
+		desiredLRP = api.LRP{
+			LRPIdentifier:   api.LRPIdentifier{GUID: "the-guid"},
+			TargetInstances: 2,
+		}
+		lrpConverter.ConvertLRPReturns(desiredLRP, nil)
+
+		statefulSetConverter.ConvertStub = func(string, *api.LRP, *corev1.Secret) (*appsv1.StatefulSet, error) {
+			desired := newStatefulSet()
+			desired.Annotations[shared.AnnotationLatestMigration] = "42"
+			desired.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "other-secret"}}
+
+			return desired, nil
+		}
+
+		var err error
+		reconciler, err = lrp.NewReconciler(
+			tests.NewTestLogger("lrp-reconciler"),
+			runtimeClient,
+			lrpConverter,
+			statefulSetConverter,
+			pdbUpdater,
+			eventRecorder,
+			registry,
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		runtimeClient.GetStub = func(_ context.Context, _ types.NamespacedName, obj client.Object) error {
+			switch o := obj.(type) {
+			case *appsv1.StatefulSet:
+				if getStsetErr != nil {
+					return getStsetErr
+				}
+
+				statefulSet.DeepCopyInto(o)
+			case *policyv1.PodDisruptionBudget:
+				if getV1PDBErr != nil {
+					return getV1PDBErr
+				}
+
+				return getPDBErr
+			case *policyv1beta1.PodDisruptionBudget:
+				return getPDBErr
+			case *corev1.Secret:
+				return getSecretErr
+			}
+
+			return nil
+		}
+
+		reconcileRes, reconcileErr = reconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: "the-namespace", Name: "baldur"},
+		})
+	})
+
+	It("succeeds", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(reconcileRes).To(Equal(reconcile.Result{}))
+	})
+
+	It("regenerates the desired statefulset from the original request", func() {
+		Expect(lrpConverter.ConvertLRPCallCount()).To(Equal(1))
+		request := lrpConverter.ConvertLRPArgsForCall(0)
+		Expect(request.GUID).To(Equal("the-guid"))
+		Expect(request.NumInstances).To(Equal(2))
+		Expect(request.LRP).To(Equal(`{"guid":"the-guid","instances":2}`))
+
+		Expect(statefulSetConverter.ConvertCallCount()).To(Equal(1))
+		name, actualLRP, _ := statefulSetConverter.ConvertArgsForCall(0)
+		Expect(name).To(Equal("baldur"))
+		Expect(*actualLRP).To(Equal(desiredLRP))
+	})
+
+	It("does not update a statefulset that is in sync", func() {
+		Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+		Expect(eventRecorder.Events).To(BeEmpty())
+	})
+
+	When("the statefulset has been generated by the statefulset converter", func() {
+		BeforeEach(func() {
+			desiredLRP.LRP = `{"guid":"the-guid","instances":2}`
+			desiredLRP.Env = map[string]string{
+				"ALPHA": "", "BRAVO": "", "CHARLIE": "", "DELTA": "", "ECHO": "", "FOXTROT": "",
+			}
+			lrpConverter.ConvertLRPReturns(desiredLRP, nil)
+
+			converter := stset.NewLRPToStatefulSetConverter("eirini", "", false, false, nil, nil, eirini.SchedulingConfig{}, 0,
+				k8s.CreateLivenessProbe, k8s.CreateReadinessProbe, k8s.CreateStartupProbe)
+
+			var err error
+			statefulSet, err = converter.Convert("baldur", &desiredLRP, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			reconciler, err = lrp.NewReconciler(
+				tests.NewTestLogger("lrp-reconciler"),
+				runtimeClient,
+				lrpConverter,
+				converter,
+				pdbUpdater,
+				eventRecorder,
+				registry,
+			)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("considers it in sync every time", func() {
+			for i := 0; i < 10; i++ {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: "the-namespace", Name: "baldur"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+		})
+
+		When("the env vars and anti-affinity requirements are in a different order", func() {
+			BeforeEach(func() {
+				reverse(statefulSet.Spec.Template.Spec.Containers[0].Env)
+
+				terms := statefulSet.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+				Expect(terms).To(HaveLen(1))
+				reverse(terms[0].PodAffinityTerm.LabelSelector.MatchExpressions)
+			})
+
+			It("considers it in sync", func() {
+				Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	When("the statefulset has values defaulted by kubernetes", func() {
+		BeforeEach(func() {
+			statefulSet.Spec.Template.Spec.Containers[0].TerminationMessagePath = "/dev/termination-log"
+			statefulSet.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+		})
+
+		It("considers it in sync", func() {
+			Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("the statefulset has been edited", func() {
+		BeforeEach(func() {
+			statefulSet.Spec.Template.Spec.Containers[0].Env = append(statefulSet.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "SNEAKY", Value: "edit"})
+			replicas := int32(7)
+			statefulSet.Spec.Replicas = &replicas
+		})
+
+		It("reverts the changes", func() {
+			Expect(runtimeClient.UpdateCallCount()).To(Equal(1))
+			_, obj, _ := runtimeClient.UpdateArgsForCall(0)
+			updated, ok := obj.(*appsv1.StatefulSet)
+			Expect(ok).To(BeTrue())
+			Expect(*updated.Spec.Replicas).To(Equal(int32(2)))
+			Expect(updated.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(corev1.EnvVar{Name: "FOO", Value: "bar"}))
+		})
+
+		It("keeps the existing image pull secrets", func() {
+			_, obj, _ := runtimeClient.UpdateArgsForCall(0)
+			updated, ok := obj.(*appsv1.StatefulSet)
+			Expect(ok).To(BeTrue())
+			Expect(updated.Spec.Template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "registry-secret"}))
+		})
+
+		It("emits an event", func() {
+			Expect(eventRecorder.Events).To(Receive(ContainSubstring(lrp.EventReasonDriftCorrected)))
+		})
+
+		It("counts the correction", func() {
+			Expect(corrections(registry, lrp.ResourceStatefulSet)).To(Equal(1.0))
+		})
+
+		When("updating the statefulset fails", func() {
+			BeforeEach(func() {
+				runtimeClient.UpdateReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("failed to update statefulset")))
+			})
+		})
+	})
+
+	When("only the last updated annotation of the pod template is stale", func() {
+		BeforeEach(func() {
+			statefulSet.Spec.Template.Annotations[stset.AnnotationLastUpdated] = "earlier"
+		})
+
+		It("does not roll the pods", func() {
+			Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("the env checksum differs from the one generated from the redacted original request", func() {
+		BeforeEach(func() {
+			statefulSet.Annotations[stset.AnnotationEnvChecksum] = "checksum-of-the-real-values"
+			statefulSet.Spec.Template.Annotations[stset.AnnotationEnvChecksum] = "checksum-of-the-real-values"
+
+			statefulSetConverter.ConvertStub = func(string, *api.LRP, *corev1.Secret) (*appsv1.StatefulSet, error) {
+				desired := newStatefulSet()
+				desired.Annotations[stset.AnnotationEnvChecksum] = "checksum-of-the-redacted-values"
+				desired.Spec.Template.Annotations[stset.AnnotationEnvChecksum] = "checksum-of-the-redacted-values"
+
+				return desired, nil
+			}
+		})
+
+		It("does not roll the pods", func() {
+			Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("the statefulset does not exist", func() {
+		BeforeEach(func() {
+			getStsetErr = apierrors.NewNotFound(schema.GroupResource{}, "baldur")
+		})
+
+		It("does nothing", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(lrpConverter.ConvertLRPCallCount()).To(BeZero())
+		})
+	})
+
+	When("getting the statefulset fails", func() {
+		BeforeEach(func() {
+			getStsetErr = errors.New("boom")
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("failed to get statefulset")))
+		})
+	})
+
+	When("the original request is not valid", func() {
+		BeforeEach(func() {
+			statefulSet.Annotations[stset.AnnotationOriginalRequest] = "not-json"
+		})
+
+		It("skips the statefulset", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(statefulSetConverter.ConvertCallCount()).To(BeZero())
+			Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("the statefulset belongs to an LRP resource", func() {
+		BeforeEach(func() {
+			statefulSet.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "eirini.cloudfoundry.org/v1",
+				Kind:       "LRP",
+				Name:       "baldur",
+			}}
+		})
+
+		It("leaves it to eirini-controller", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(lrpConverter.ConvertLRPCallCount()).To(BeZero())
+			Expect(runtimeClient.UpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("the pod disruption budget is missing", func() {
+		BeforeEach(func() {
+			getPDBErr = apierrors.NewNotFound(schema.GroupResource{}, "baldur")
+		})
+
+		It("recreates it", func() {
+			Expect(pdbUpdater.UpdateCallCount()).To(Equal(1))
+			_, actualStatefulSet, actualLRP := pdbUpdater.UpdateArgsForCall(0)
+			Expect(actualStatefulSet.Name).To(Equal("baldur"))
+			Expect(*actualLRP).To(Equal(desiredLRP))
+		})
+
+		It("counts the correction", func() {
+			Expect(corrections(registry, lrp.ResourcePodDisruptionBudget)).To(Equal(1.0))
+		})
+
+		When("the lrp has a single instance", func() {
+			BeforeEach(func() {
+				desiredLRP.TargetInstances = 1
+				lrpConverter.ConvertLRPReturns(desiredLRP, nil)
+			})
+
+			It("does not create one", func() {
+				Expect(pdbUpdater.UpdateCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	When("the pod disruption budget exists", func() {
+		It("leaves it alone", func() {
+			Expect(pdbUpdater.UpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("the cluster does not serve policy/v1", func() {
+		BeforeEach(func() {
+			getV1PDBErr = &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "policy", Kind: "PodDisruptionBudget"}}
+		})
+
+		It("looks the pod disruption budget up through policy/v1beta1", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(pdbUpdater.UpdateCallCount()).To(BeZero())
+		})
+
+		When("the pod disruption budget is missing", func() {
+			BeforeEach(func() {
+				getPDBErr = apierrors.NewNotFound(schema.GroupResource{}, "baldur")
+			})
+
+			It("recreates it", func() {
+				Expect(pdbUpdater.UpdateCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	When("the lrp uses a private registry", func() {
+		BeforeEach(func() {
+			desiredLRP.PrivateRegistry = &api.PrivateRegistry{Server: "registry.example.com", Username: "user", Password: "pass"}
+			lrpConverter.ConvertLRPReturns(desiredLRP, nil)
+			statefulSet.Spec.Template.Spec.ImagePullSecrets = append(statefulSet.Spec.Template.Spec.ImagePullSecrets,
+				corev1.LocalObjectReference{Name: stset.PrivateRegistrySecretGenerateName + "abc"})
+		})
+
+		It("does not recreate an existing secret", func() {
+			Expect(runtimeClient.CreateCallCount()).To(BeZero())
+		})
+
+		When("the registry secret has been deleted", func() {
+			BeforeEach(func() {
+				getSecretErr = apierrors.NewNotFound(schema.GroupResource{}, "secret")
+			})
+
+			It("recreates it with the same name", func() {
+				Expect(runtimeClient.CreateCallCount()).To(Equal(1))
+				_, obj, _ := runtimeClient.CreateArgsForCall(0)
+				secret, ok := obj.(*corev1.Secret)
+				Expect(ok).To(BeTrue())
+				Expect(secret.Name).To(Equal(stset.PrivateRegistrySecretGenerateName + "abc"))
+				Expect(secret.Namespace).To(Equal("the-namespace"))
+				Expect(secret.Type).To(Equal(corev1.SecretTypeDockerConfigJson))
+				Expect(secret.OwnerReferences).To(HaveLen(1))
+				Expect(secret.OwnerReferences[0].Name).To(Equal("baldur"))
+			})
+
+			It("counts the correction", func() {
+				Expect(corrections(registry, lrp.ResourceRegistrySecret)).To(Equal(1.0))
+			})
+
+			When("the password has been redacted from the original request", func() {
+				BeforeEach(func() {
+					desiredLRP.PrivateRegistry.Password = ""
+				})
+
+				It("does not recreate it without credentials", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(runtimeClient.CreateCallCount()).To(BeZero())
+				})
+
+				It("emits a warning event", func() {
+					Expect(eventRecorder.Events).To(Receive(ContainSubstring(lrp.EventReasonDriftUncorrectable)))
+				})
+
+				It("counts the failure", func() {
+					Expect(failures(registry, lrp.ResourceRegistrySecret)).To(Equal(1.0))
+					Expect(corrections(registry, lrp.ResourceRegistrySecret)).To(BeZero())
+				})
+			})
+		})
+	})
+
+	When("the environment is stored in the env secret", func() {
+		BeforeEach(func() {
+			env := []corev1.EnvVar{{
+				Name: "FOO",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: stset.EnvSecretName("baldur")},
+						Key:                  "opi.FOO",
+					},
+				},
+			}}
+			statefulSet.Spec.Template.Spec.Containers[0].Env = env
+
+			statefulSetConverter.ConvertStub = func(string, *api.LRP, *corev1.Secret) (*appsv1.StatefulSet, error) {
+				desired := newStatefulSet()
+				desired.Spec.Template.Spec.Containers[0].Env = env
+
+				return desired, nil
+			}
+		})
+
+		It("checks that the env secret exists", func() {
+			Expect(runtimeClient.GetCallCount()).To(BeNumerically(">", 0))
+			_, name, obj := runtimeClient.GetArgsForCall(runtimeClient.GetCallCount() - 1)
+			Expect(obj).To(BeAssignableToTypeOf(&corev1.Secret{}))
+			Expect(name).To(Equal(types.NamespacedName{Namespace: "the-namespace", Name: "baldur-env"}))
+			Expect(eventRecorder.Events).To(BeEmpty())
+		})
+
+		When("the env secret has been deleted", func() {
+			BeforeEach(func() {
+				getSecretErr = apierrors.NewNotFound(schema.GroupResource{}, "baldur-env")
+			})
+
+			It("succeeds without recreating it", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(runtimeClient.CreateCallCount()).To(BeZero())
+			})
+
+			It("emits a warning event", func() {
+				Expect(eventRecorder.Events).To(Receive(And(
+					ContainSubstring(corev1.EventTypeWarning),
+					ContainSubstring(lrp.EventReasonDriftUncorrectable),
+					ContainSubstring("baldur-env"),
+				)))
+			})
+
+			It("counts the failure", func() {
+				Expect(failures(registry, lrp.ResourceEnvSecret)).To(Equal(1.0))
+			})
+		})
+
+		When("getting the env secret fails", func() {
+			BeforeEach(func() {
+				getSecretErr = errors.New("boom")
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("failed to get env secret")))
+			})
+		})
+	})
+
+	When("a reconciler is created with the same registry", func() {
+		It("adopts the existing metric", func() {
+			_, err := lrp.NewReconciler(tests.NewTestLogger("lrp-reconciler"), runtimeClient, lrpConverter, statefulSetConverter, pdbUpdater, eventRecorder, registry)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
+
+func reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+func corrections(registry *prometheus_api.Registry, resource string) float64 {
+	return counterValue(registry, lrp.DriftCorrections, lrp.DriftCorrectionsHelp, resource)
+}
+
+func failures(registry *prometheus_api.Registry, resource string) float64 {
+	return counterValue(registry, lrp.DriftFailures, lrp.DriftFailuresHelp, resource)
+}
+
+func counterValue(registry *prometheus_api.Registry, name, help, resource string) float64 {
+	counter := prometheus_api.NewCounterVec(prometheus_api.CounterOpts{
+		Name: name,
+		Help: help,
+	}, []string{"resource"})
+
+	var are prometheus_api.AlreadyRegisteredError
+	Expect(errors.As(registry.Register(counter), &are)).To(BeTrue())
+
+	existing, ok := are.ExistingCollector.(*prometheus_api.CounterVec)
+	Expect(ok).To(BeTrue())
+
+	return testutil.ToFloat64(existing.WithLabelValues(resource))
+}
@@ -20,6 +20,10 @@ import (
 //counterfeiter:generate . PodsClient
 //counterfeiter:generate . Deleter
 
+// imagePullGracePeriod is how long a task pod may fail to pull its images,
+// e.g. while the registry is briefly unavailable, before the task fails.
+const imagePullGracePeriod = 5 * time.Minute
+
 type Reporter interface {
 	Report(context.Context, *corev1.Pod) error
 }
@@ -79,7 +83,15 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		return handlePodGetError(logger, err)
 	}
 
-	if !r.taskContainerHasTerminated(logger, pod) {
+	if !r.taskHasFinished(logger, pod) {
+		// pods are not updated while the kubelet backs off pulling, so
+		// nothing else triggers a reconcile once the grace period is over
+		if remaining, ok := imagePullGraceRemaining(pod); ok {
+			logger.Debug("image-pull-failing", lager.Data{"grace-remaining": remaining})
+
+			return reconcile.Result{RequeueAfter: remaining}, nil
+		}
+
 		return reconcile.Result{}, nil
 	}
 
@@ -117,7 +129,9 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		return reconcile.Result{}, errors.Wrap(err, "failed to label the job as completed")
 	}
 
-	if !r.taskHasExpired(logger, pod) {
+	// the kubelet keeps trying to pull the images, so the job has to go
+	// before it runs a task that has already been reported as failed
+	if !imagePullFailed(pod) && !r.taskHasExpired(logger, pod) {
 		logger.Debug("task-hasnt-expired-yet")
 
 		return reconcile.Result{RequeueAfter: time.Duration(r.ttlSeconds) * time.Second}, nil
@@ -171,7 +185,7 @@ func (r *Reconciler) reportIfRequired(ctx context.Context, pod *corev1.Pod) erro
 	return nil
 }
 
-func (r Reconciler) taskContainerHasTerminated(logger lager.Logger, pod *corev1.Pod) bool {
+func (r Reconciler) taskHasFinished(logger lager.Logger, pod *corev1.Pod) bool {
 	status, ok := getTaskContainerStatus(pod)
 	if !ok {
 		logger.Info("pod-has-no-task-container-status")
@@ -179,7 +193,57 @@ func (r Reconciler) taskContainerHasTerminated(logger lager.Logger, pod *corev1.
 		return false
 	}
 
-	return status.State.Terminated != nil
+	if status.State.Terminated != nil || pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+
+	return imagePullFailed(pod)
+}
+
+// imagePullFailed tells whether the images of the task, or of an init
+// container such as the downloader of staging jobs, cannot be pulled. Pull
+// errors that may be transient only count once the pod has been failing to
+// pull for imagePullGracePeriod.
+func imagePullFailed(pod *corev1.Pod) bool {
+	for _, reason := range imagePullFailureReasons(pod) {
+		if !isTransientImagePullFailure(reason) || time.Since(podStartTime(pod)) > imagePullGracePeriod {
+			return true
+		}
+	}
+
+	return false
+}
+
+// imagePullGraceRemaining returns how long the pod may still fail to pull its
+// images before the task fails, or false if it is not failing to pull.
+func imagePullGraceRemaining(pod *corev1.Pod) (time.Duration, bool) {
+	if len(imagePullFailureReasons(pod)) == 0 {
+		return 0, false
+	}
+
+	remaining := imagePullGracePeriod - time.Since(podStartTime(pod))
+	if remaining <= 0 {
+		return 0, false
+	}
+
+	return remaining, true
+}
+
+func imagePullFailureReasons(pod *corev1.Pod) []string {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	if status, ok := getTaskContainerStatus(pod); ok {
+		statuses = append(statuses, status)
+	}
+
+	reasons := []string{}
+
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && isImagePullFailure(waiting.Reason) {
+			reasons = append(reasons, waiting.Reason)
+		}
+	}
+
+	return reasons
 }
 
 func (r Reconciler) taskHasExpired(logger lager.Logger, pod *corev1.Pod) bool {
@@ -191,10 +255,29 @@ func (r Reconciler) taskHasExpired(logger lager.Logger, pod *corev1.Pod) bool {
 	}
 
 	ttlExpire := time.Now().Add(-time.Duration(r.ttlSeconds) * time.Second)
+	completionTime := completionTime(pod, status)
 
-	logger.Debug("task-has-completed", lager.Data{"expiration-time": ttlExpire, "completion-time": status.State.Terminated.FinishedAt.Time})
+	logger.Debug("task-has-completed", lager.Data{"expiration-time": ttlExpire, "completion-time": completionTime})
+
+	return completionTime.Before(ttlExpire)
+}
+
+// completionTime falls back to the pod start time for tasks that never ran,
+// e.g. because their image could not be pulled.
+func completionTime(pod *corev1.Pod, status corev1.ContainerStatus) time.Time {
+	if status.State.Terminated != nil {
+		return status.State.Terminated.FinishedAt.Time
+	}
+
+	return podStartTime(pod)
+}
+
+func podStartTime(pod *corev1.Pod) time.Time {
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
 
-	return status.State.Terminated.FinishedAt.Time.Before(ttlExpire)
+	return pod.CreationTimestamp.Time
 }
 
 func handlePodGetError(logger lager.Logger, err error) (reconcile.Result, error) {
@@ -229,6 +229,117 @@ var _ = Describe("Task Completion Reconciler", func() {
 		})
 	})
 
+	When("the task image cannot be pulled", func() {
+		BeforeEach(func() {
+			pod.Status.StartTime = &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+			pod.Status.ContainerStatuses[0].State.Terminated = nil
+			pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: "Back-off pulling image",
+			}
+		})
+
+		It("reports the task pod", func() {
+			Expect(taskReporter.ReportCallCount()).To(Equal(1))
+		})
+
+		It("deletes the task", func() {
+			Expect(taskDeleter.DeleteCallCount()).To(Equal(1))
+		})
+
+		When("the TTL has not expired yet", func() {
+			BeforeEach(func() {
+				reconciler = task.NewReconciler(logger, runtimeClient, jobsClient, podsClient, taskReporter, taskDeleter, 2, 3600)
+			})
+
+			It("deletes the task right away, so that it cannot run after being reported as failed", func() {
+				Expect(taskDeleter.DeleteCallCount()).To(Equal(1))
+				Expect(reconcileRes.RequeueAfter).To(BeZero())
+			})
+		})
+	})
+
+	When("the task pod has only just failed to pull its image", func() {
+		BeforeEach(func() {
+			pod.Status.StartTime = &metav1.Time{Time: time.Now().Add(-30 * time.Second)}
+			pod.Status.ContainerStatuses[0].State.Terminated = nil
+			pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{
+				Reason:  "ErrImagePull",
+				Message: "registry unavailable",
+			}
+		})
+
+		It("gives the pull a chance to recover", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(taskReporter.ReportCallCount()).To(BeZero())
+			Expect(jobsClient.SetLabelCallCount()).To(BeZero())
+			Expect(taskDeleter.DeleteCallCount()).To(BeZero())
+		})
+
+		It("requeues the pod for when the grace period is over", func() {
+			Expect(reconcileRes.RequeueAfter).To(BeNumerically("~", 4*time.Minute+30*time.Second, time.Second))
+		})
+
+		When("the image is pulled on a later attempt", func() {
+			BeforeEach(func() {
+				pod.Status.ContainerStatuses[0].State.Waiting = nil
+				pod.Status.ContainerStatuses[0].State.Running = &corev1.ContainerStateRunning{}
+			})
+
+			It("lets the task run", func() {
+				Expect(taskReporter.ReportCallCount()).To(BeZero())
+				Expect(taskDeleter.DeleteCallCount()).To(BeZero())
+			})
+
+			It("does not requeue", func() {
+				Expect(reconcileRes.IsZero()).To(BeTrue())
+			})
+		})
+	})
+
+	When("the task image name is invalid", func() {
+		BeforeEach(func() {
+			pod.Status.StartTime = &metav1.Time{Time: time.Now().Add(-30 * time.Second)}
+			pod.Status.ContainerStatuses[0].State.Terminated = nil
+			pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "InvalidImageName"}
+		})
+
+		It("reports the task pod right away", func() {
+			Expect(taskReporter.ReportCallCount()).To(Equal(1))
+		})
+	})
+
+	When("an init container image cannot be pulled", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].State.Terminated = nil
+			pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "PodInitializing"}
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name: "downloader",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"},
+					},
+				},
+			}
+		})
+
+		It("reports the task pod", func() {
+			Expect(taskReporter.ReportCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the task pod failed before the task container started", func() {
+		BeforeEach(func() {
+			pod.Status.Phase = corev1.PodFailed
+			pod.Status.ContainerStatuses[0].State.Terminated = nil
+			pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "PodInitializing"}
+		})
+
+		It("reports the task pod", func() {
+			Expect(taskReporter.ReportCallCount()).To(Equal(1))
+		})
+	})
+
 	When("task container status is missing", func() {
 		BeforeEach(func() {
 			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
@@ -2,7 +2,9 @@ package task
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"unicode/utf8"
 
 	"code.cloudfoundry.org/eirini/k8s/jobs"
 	"code.cloudfoundry.org/eirini/k8s/utils"
@@ -12,6 +14,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+// Tasks have no deadline: the cloud controller does not send a timeout, so
+// their jobs run until the task container exits.
+const (
+	containerReasonOOMKilled = "OOMKilled"
+
+	failureReasonOOMKilled = "Task was killed because it ran out of memory"
+
+	maxTerminationMessageBytes = 1024
+)
+
 type StateReporter struct {
 	Client *http.Client
 	Logger lager.Logger
@@ -40,21 +52,102 @@ func (r StateReporter) generateTaskCompletedRequest(logger lager.Logger, guid st
 		TaskGUID: guid,
 	}
 	taskContainerStatus, _ := getTaskContainerStatus(pod)
-	terminated := taskContainerStatus.State.Terminated
 
-	if terminated.ExitCode != 0 {
+	if waiting := taskContainerStatus.State.Waiting; waiting != nil && isImagePullFailure(waiting.Reason) {
 		res.Failed = true
-		res.FailureReason = terminated.Reason
+		res.FailureReason = fmt.Sprintf("Failed to pull the task image: %s", waiting.Message)
 
 		logger.Error("job-failed", nil, lager.Data{
-			"failure-reason":  terminated.Reason,
-			"failure-message": terminated.Message,
+			"failure-reason":  waiting.Reason,
+			"failure-message": waiting.Message,
 		})
+
+		return res
+	}
+
+	terminated := taskContainerStatus.State.Terminated
+	if terminated == nil {
+		return res
+	}
+
+	res.ExitCode = terminated.ExitCode
+	succeeded := terminated.ExitCode == 0
+
+	// the termination message of a successful task declaring a result file is
+	// the content of that file
+	if succeeded && hasResultFile(pod) {
+		res.Result = terminated.Message
+
+		return res
+	}
+
+	res.TerminationMessage = tail(terminated.Message, maxTerminationMessageBytes)
+
+	if succeeded {
+		return res
 	}
 
+	res.Failed = true
+	res.FailureReason = failureReason(terminated)
+
+	logger.Error("job-failed", nil, lager.Data{
+		"exit-code":       terminated.ExitCode,
+		"failure-reason":  terminated.Reason,
+		"failure-message": terminated.Message,
+	})
+
 	return res
 }
 
+func failureReason(terminated *corev1.ContainerStateTerminated) string {
+	if terminated.Reason == containerReasonOOMKilled {
+		return failureReasonOOMKilled
+	}
+
+	return fmt.Sprintf("Exited with status %d", terminated.ExitCode)
+}
+
+func isImagePullFailure(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+		return true
+	default:
+		return false
+	}
+}
+
+func isTransientImagePullFailure(reason string) bool {
+	return reason == "ErrImagePull" || reason == "ImagePullBackOff"
+}
+
+// tail returns at most the last n bytes of s, without splitting a UTF-8
+// encoded rune.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	start := len(s) - n
+	for start < len(s) && !utf8.RuneStart(s[start]) {
+		start++
+	}
+
+	return s[start:]
+}
+
+// hasResultFile tells whether the task container writes its result to the
+// termination message file.
+func hasResultFile(pod *corev1.Pod) bool {
+	taskContainerName := pod.Annotations[jobs.AnnotationTaskContainerName]
+	for _, container := range pod.Spec.Containers {
+		if container.Name == taskContainerName {
+			return container.TerminationMessagePath != "" && container.TerminationMessagePath != corev1.TerminationMessagePathDefault
+		}
+	}
+
+	return false
+}
+
 func getTaskContainerStatus(pod *corev1.Pod) (corev1.ContainerStatus, bool) {
 	taskContainerName := pod.Annotations[jobs.AnnotationTaskContainerName]
 	for _, status := range pod.Status.ContainerStatuses {
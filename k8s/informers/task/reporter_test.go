@@ -3,6 +3,7 @@ package task_test
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"code.cloudfoundry.org/eirini/k8s/informers/task"
 	"code.cloudfoundry.org/eirini/k8s/jobs"
@@ -105,7 +106,7 @@ var _ = Describe("Reporter", func() {
 					State: corev1.ContainerState{
 						Terminated: &corev1.ContainerStateTerminated{
 							ExitCode: 42,
-							Reason:   "because",
+							Reason:   "Error",
 						},
 					},
 				},
@@ -122,7 +123,8 @@ var _ = Describe("Reporter", func() {
 				ghttp.VerifyJSONRepresenting(cf.TaskCompletedRequest{
 					TaskGUID:      "the-task-guid",
 					Failed:        true,
-					FailureReason: "because",
+					FailureReason: "Exited with status 42",
+					ExitCode:      42,
 				}),
 			}
 		})
@@ -132,6 +134,129 @@ var _ = Describe("Reporter", func() {
 		})
 	})
 
+	When("the task container was OOM killed", func() {
+		BeforeEach(func() {
+			pod = createPod(corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					ExitCode: 137,
+					Reason:   "OOMKilled",
+				},
+			})
+
+			handlers = []http.HandlerFunc{
+				ghttp.VerifyRequest(http.MethodPost, "/the-callback-url"),
+				ghttp.VerifyJSONRepresenting(cf.TaskCompletedRequest{
+					TaskGUID:      "the-task-guid",
+					Failed:        true,
+					FailureReason: "Task was killed because it ran out of memory",
+					ExitCode:      137,
+				}),
+			}
+		})
+
+		It("reports the out of memory failure", func() {
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	When("the task image cannot be pulled", func() {
+		BeforeEach(func() {
+			pod = createPod(corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: "Back-off pulling image \"foo\"",
+				},
+			})
+
+			handlers = []http.HandlerFunc{
+				ghttp.VerifyRequest(http.MethodPost, "/the-callback-url"),
+				ghttp.VerifyJSONRepresenting(cf.TaskCompletedRequest{
+					TaskGUID:      "the-task-guid",
+					Failed:        true,
+					FailureReason: "Failed to pull the task image: Back-off pulling image \"foo\"",
+				}),
+			}
+		})
+
+		It("reports the image pull failure", func() {
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	When("the task container has a termination message", func() {
+		BeforeEach(func() {
+			message := strings.Repeat("a", 10) + strings.Repeat("é", 1024)
+			pod = createPod(corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					ExitCode: 1,
+					Message:  message,
+				},
+			})
+
+			handlers = []http.HandlerFunc{
+				ghttp.VerifyRequest(http.MethodPost, "/the-callback-url"),
+				ghttp.VerifyJSONRepresenting(cf.TaskCompletedRequest{
+					TaskGUID:           "the-task-guid",
+					Failed:             true,
+					FailureReason:      "Exited with status 1",
+					ExitCode:           1,
+					TerminationMessage: strings.Repeat("é", 512),
+				}),
+			}
+		})
+
+		It("sends the tail of the message", func() {
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	When("the task declares a result file", func() {
+		BeforeEach(func() {
+			pod = createPod(corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					ExitCode: 0,
+					Message:  `{"rows":42}`,
+				},
+			})
+			pod.Spec.Containers = []corev1.Container{
+				{Name: "opi-task", TerminationMessagePath: "/home/vcap/result.json"},
+			}
+
+			handlers = []http.HandlerFunc{
+				ghttp.VerifyRequest(http.MethodPost, "/the-callback-url"),
+				ghttp.VerifyJSONRepresenting(cf.TaskCompletedRequest{
+					TaskGUID: "the-task-guid",
+					Result:   `{"rows":42}`,
+				}),
+			}
+		})
+
+		It("sends the content of the result file as the result", func() {
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		When("the task fails", func() {
+			BeforeEach(func() {
+				pod.Status.ContainerStatuses[0].State.Terminated.ExitCode = 1
+
+				handlers = []http.HandlerFunc{
+					ghttp.VerifyRequest(http.MethodPost, "/the-callback-url"),
+					ghttp.VerifyJSONRepresenting(cf.TaskCompletedRequest{
+						TaskGUID:           "the-task-guid",
+						Failed:             true,
+						FailureReason:      "Exited with status 1",
+						ExitCode:           1,
+						TerminationMessage: `{"rows":42}`,
+					}),
+				}
+			})
+
+			It("does not send a result", func() {
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+	})
+
 	When("the cloud controller returns an unexpected status code", func() {
 		BeforeEach(func() {
 			server.Reset()
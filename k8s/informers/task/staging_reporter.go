@@ -0,0 +1,136 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/runtimeschema/cc_messages"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const buildpackLifecycleType = "buildpack"
+
+//counterfeiter:generate . StagingCompleter
+
+type StagingCompleter interface {
+	CompleteStaging(ctx context.Context, req cf.StagingCompletedRequest) error
+}
+
+// StagingReporter reports the result of buildpack staging jobs. The builder
+// writes the process types it detected, and optionally the lifecycle
+// metadata for the cloud controller, as JSON to its termination message.
+type StagingReporter struct {
+	StagingCompleter StagingCompleter
+	Logger           lager.Logger
+}
+
+type builderOutput struct {
+	ProcessTypes      map[string]string `json:"process_types"`
+	LifecycleMetadata json.RawMessage   `json:"lifecycle_metadata"`
+}
+
+type buildpackStagingResult struct {
+	LifecycleType     string            `json:"lifecycle_type"`
+	LifecycleMetadata json.RawMessage   `json:"lifecycle_metadata"`
+	ProcessTypes      map[string]string `json:"process_types"`
+	ExecutionMetadata string            `json:"execution_metadata"`
+}
+
+func (r StagingReporter) Report(ctx context.Context, pod *corev1.Pod) error {
+	stagingGUID := pod.Annotations[jobs.AnnotationGUID]
+
+	logger := r.Logger.Session("report-staging", lager.Data{"staging-guid": stagingGUID})
+
+	annotation, err := json.Marshal(cc_messages.StagingTaskAnnotation{
+		Lifecycle:          buildpackLifecycleType,
+		CompletionCallback: pod.Annotations[jobs.AnnotationCompletionCallback],
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal staging annotation")
+	}
+
+	req := cf.StagingCompletedRequest{
+		TaskGUID:   stagingGUID,
+		Annotation: string(annotation),
+	}
+
+	result, err := stagingResult(pod)
+	if err != nil {
+		logger.Error("staging-failed", err)
+
+		req.Failed = true
+		req.FailureReason = err.Error()
+	}
+
+	req.Result = result
+
+	if err = r.StagingCompleter.CompleteStaging(ctx, req); err != nil {
+		logger.Error("cannot-send-staging-result", err)
+
+		return errors.Wrap(err, "failed to complete staging")
+	}
+
+	return nil
+}
+
+func stagingResult(pod *corev1.Pod) (string, error) {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if err := containerFailure(status); err != nil {
+			return "", err
+		}
+	}
+
+	builderStatus, _ := getTaskContainerStatus(pod)
+	if err := containerFailure(builderStatus); err != nil {
+		return "", err
+	}
+
+	terminated := builderStatus.State.Terminated
+	if terminated == nil {
+		return "", errors.Errorf("staging did not complete: %s", pod.Status.Reason)
+	}
+
+	var output builderOutput
+	if err := json.Unmarshal([]byte(terminated.Message), &output); err != nil {
+		return "", errors.Wrap(err, "builder produced an invalid staging result")
+	}
+
+	lifecycleMetadata := output.LifecycleMetadata
+	if len(lifecycleMetadata) == 0 {
+		lifecycleMetadata = json.RawMessage("{}")
+	}
+
+	resultJSON, err := json.Marshal(buildpackStagingResult{
+		LifecycleType:     buildpackLifecycleType,
+		LifecycleMetadata: lifecycleMetadata,
+		ProcessTypes:      output.ProcessTypes,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal staging result")
+	}
+
+	return string(resultJSON), nil
+}
+
+// containerFailure returns why a staging container failed, or nil if it has
+// not failed (yet).
+func containerFailure(status corev1.ContainerStatus) error {
+	if waiting := status.State.Waiting; waiting != nil && isImagePullFailure(waiting.Reason) {
+		return errors.Errorf("failed to pull the %s image: %s", status.Name, waiting.Message)
+	}
+
+	terminated := status.State.Terminated
+	if terminated == nil || terminated.ExitCode == 0 {
+		return nil
+	}
+
+	if terminated.Reason == containerReasonOOMKilled {
+		return errors.Errorf("%s was killed because it ran out of memory", status.Name)
+	}
+
+	return errors.Errorf("%s exited with status %d: %s", status.Name, terminated.ExitCode, tail(terminated.Message, maxTerminationMessageBytes))
+}
@@ -0,0 +1,198 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/eirini/k8s/informers/task"
+	"code.cloudfoundry.org/eirini/k8s/informers/task/taskfakes"
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("StagingReporter", func() {
+	var (
+		reporter         task.StagingReporter
+		stagingCompleter *taskfakes.FakeStagingCompleter
+		pod              *corev1.Pod
+		reportErr        error
+	)
+
+	completedRequest := func() cf.StagingCompletedRequest {
+		Expect(stagingCompleter.CompleteStagingCallCount()).To(Equal(1))
+		_, req := stagingCompleter.CompleteStagingArgsForCall(0)
+
+		return req
+	}
+
+	BeforeEach(func() {
+		stagingCompleter = new(taskfakes.FakeStagingCompleter)
+		reporter = task.StagingReporter{
+			StagingCompleter: stagingCompleter,
+			Logger:           tests.NewTestLogger("staging-reporter-test"),
+		}
+
+		pod = &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Labels: map[string]string{
+					jobs.LabelSourceType: jobs.StagingSourceType,
+				},
+				Annotations: map[string]string{
+					jobs.AnnotationTaskContainerName:  "opi-stage-builder",
+					jobs.AnnotationGUID:               "the-staging-guid",
+					jobs.AnnotationCompletionCallback: "example.com/staging/complete",
+				},
+			},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "opi-stage-downloader",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+						},
+					},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "opi-stage-builder",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: 0,
+								Message:  `{"process_types": {"web": "bundle exec rackup"}, "lifecycle_metadata": {"detected_buildpack": "ruby"}}`,
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		reportErr = reporter.Report(context.Background(), pod)
+	})
+
+	It("reports the detected process types to the staging callback", func() {
+		Expect(reportErr).NotTo(HaveOccurred())
+
+		req := completedRequest()
+		Expect(req.TaskGUID).To(Equal("the-staging-guid"))
+		Expect(req.Failed).To(BeFalse())
+		Expect(req.Annotation).To(MatchJSON(`{"lifecycle": "buildpack", "completion_callback": "example.com/staging/complete"}`))
+		Expect(req.Result).To(MatchJSON(`{
+			"lifecycle_type": "buildpack",
+			"lifecycle_metadata": {"detected_buildpack": "ruby"},
+			"process_types": {"web": "bundle exec rackup"},
+			"execution_metadata": ""
+		}`))
+	})
+
+	When("the builder does not report lifecycle metadata", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].State.Terminated.Message = `{"process_types": {"web": "node server.js"}}`
+		})
+
+		It("reports empty lifecycle metadata", func() {
+			Expect(completedRequest().Result).To(MatchJSON(`{
+				"lifecycle_type": "buildpack",
+				"lifecycle_metadata": {},
+				"process_types": {"web": "node server.js"},
+				"execution_metadata": ""
+			}`))
+		})
+	})
+
+	When("the builder fails", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].State.Terminated.ExitCode = 1
+			pod.Status.ContainerStatuses[0].State.Terminated.Message = "no buildpack detected"
+		})
+
+		It("reports the failure", func() {
+			req := completedRequest()
+			Expect(req.Failed).To(BeTrue())
+			Expect(req.FailureReason).To(Equal("opi-stage-builder exited with status 1: no buildpack detected"))
+			Expect(req.Result).To(BeEmpty())
+		})
+	})
+
+	When("the builder runs out of memory", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].State.Terminated.ExitCode = 137
+			pod.Status.ContainerStatuses[0].State.Terminated.Reason = "OOMKilled"
+		})
+
+		It("reports the failure", func() {
+			Expect(completedRequest().FailureReason).To(Equal("opi-stage-builder was killed because it ran out of memory"))
+		})
+	})
+
+	When("the builder writes an invalid result", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].State.Terminated.Message = "done"
+		})
+
+		It("reports the failure", func() {
+			req := completedRequest()
+			Expect(req.Failed).To(BeTrue())
+			Expect(req.FailureReason).To(ContainSubstring("builder produced an invalid staging result"))
+		})
+	})
+
+	When("the package cannot be downloaded", func() {
+		BeforeEach(func() {
+			pod.Status.Phase = corev1.PodFailed
+			pod.Status.InitContainerStatuses[0].State.Terminated.ExitCode = 2
+			pod.Status.InitContainerStatuses[0].State.Terminated.Message = "404 Not Found"
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"},
+			}
+		})
+
+		It("reports the failure", func() {
+			req := completedRequest()
+			Expect(req.Failed).To(BeTrue())
+			Expect(req.FailureReason).To(Equal("opi-stage-downloader exited with status 2: 404 Not Found"))
+		})
+	})
+
+	When("the builder image cannot be pulled", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull", Message: "not found"},
+			}
+		})
+
+		It("reports the failure", func() {
+			Expect(completedRequest().FailureReason).To(Equal("failed to pull the opi-stage-builder image: not found"))
+		})
+	})
+
+	When("the builder never ran", func() {
+		BeforeEach(func() {
+			pod.Status.Phase = corev1.PodFailed
+			pod.Status.Reason = "Evicted"
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"},
+			}
+		})
+
+		It("reports the failure", func() {
+			Expect(completedRequest().FailureReason).To(Equal("staging did not complete: Evicted"))
+		})
+	})
+
+	When("completing the staging fails", func() {
+		BeforeEach(func() {
+			stagingCompleter.CompleteStagingReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reportErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+})
@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/lager"
+	"github.com/pkg/errors"
+	batch "k8s.io/api/batch/v1"
+)
+
+//counterfeiter:generate . StagingToJobConverter
+
+type StagingToJobConverter interface {
+	Convert(*api.Staging) *batch.Job
+}
+
+type StagingDesirer struct {
+	logger                lager.Logger
+	stagingToJobConverter StagingToJobConverter
+	jobCreator            JobCreator
+}
+
+func NewStagingDesirer(
+	logger lager.Logger,
+	stagingToJobConverter StagingToJobConverter,
+	jobCreator JobCreator,
+) StagingDesirer {
+	return StagingDesirer{
+		logger:                logger,
+		stagingToJobConverter: stagingToJobConverter,
+		jobCreator:            jobCreator,
+	}
+}
+
+func (d *StagingDesirer) Desire(ctx context.Context, namespace string, staging *api.Staging) error {
+	logger := d.logger.Session("desire-staging", lager.Data{"guid": staging.GUID, "namespace": namespace})
+
+	job := d.stagingToJobConverter.Convert(staging)
+	job.Namespace = namespace
+
+	if _, err := d.jobCreator.Create(ctx, namespace, job); err != nil {
+		logger.Error("failed-to-create-job", err)
+
+		return errors.Wrap(err, "failed to create staging job")
+	}
+
+	return nil
+}
@@ -235,3 +235,44 @@ var _ = Describe("Desire", func() {
 		})
 	})
 })
+
+var _ = Describe("StagingDesirer", func() {
+	var (
+		jobCreator            *jobsfakes.FakeJobCreator
+		stagingToJobConverter *jobsfakes.FakeStagingToJobConverter
+		staging               *api.Staging
+		desireErr             error
+	)
+
+	BeforeEach(func() {
+		jobCreator = new(jobsfakes.FakeJobCreator)
+		stagingToJobConverter = new(jobsfakes.FakeStagingToJobConverter)
+		stagingToJobConverter.ConvertReturns(&batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "the-job-name"}})
+		staging = &api.Staging{GUID: "staging-guid"}
+	})
+
+	JustBeforeEach(func() {
+		desirer := jobs.NewStagingDesirer(tests.NewTestLogger("staging-desirer"), stagingToJobConverter, jobCreator)
+		desireErr = desirer.Desire(ctx, "app-namespace", staging)
+	})
+
+	It("creates the staging job in the namespace", func() {
+		Expect(desireErr).NotTo(HaveOccurred())
+		Expect(stagingToJobConverter.ConvertArgsForCall(0)).To(Equal(staging))
+		Expect(jobCreator.CreateCallCount()).To(Equal(1))
+		_, namespace, job := jobCreator.CreateArgsForCall(0)
+		Expect(namespace).To(Equal("app-namespace"))
+		Expect(job.Name).To(Equal("the-job-name"))
+		Expect(job.Namespace).To(Equal("app-namespace"))
+	})
+
+	When("creating the job fails", func() {
+		BeforeEach(func() {
+			jobCreator.CreateReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(desireErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+})
@@ -0,0 +1,113 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package jobsfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	v1 "k8s.io/api/batch/v1"
+)
+
+type FakeStagingToJobConverter struct {
+	ConvertStub        func(*api.Staging) *v1.Job
+	convertMutex       sync.RWMutex
+	convertArgsForCall []struct {
+		arg1 *api.Staging
+	}
+	convertReturns struct {
+		result1 *v1.Job
+	}
+	convertReturnsOnCall map[int]struct {
+		result1 *v1.Job
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStagingToJobConverter) Convert(arg1 *api.Staging) *v1.Job {
+	fake.convertMutex.Lock()
+	ret, specificReturn := fake.convertReturnsOnCall[len(fake.convertArgsForCall)]
+	fake.convertArgsForCall = append(fake.convertArgsForCall, struct {
+		arg1 *api.Staging
+	}{arg1})
+	stub := fake.ConvertStub
+	fakeReturns := fake.convertReturns
+	fake.recordInvocation("Convert", []interface{}{arg1})
+	fake.convertMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStagingToJobConverter) ConvertCallCount() int {
+	fake.convertMutex.RLock()
+	defer fake.convertMutex.RUnlock()
+	return len(fake.convertArgsForCall)
+}
+
+func (fake *FakeStagingToJobConverter) ConvertCalls(stub func(*api.Staging) *v1.Job) {
+	fake.convertMutex.Lock()
+	defer fake.convertMutex.Unlock()
+	fake.ConvertStub = stub
+}
+
+func (fake *FakeStagingToJobConverter) ConvertArgsForCall(i int) *api.Staging {
+	fake.convertMutex.RLock()
+	defer fake.convertMutex.RUnlock()
+	argsForCall := fake.convertArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStagingToJobConverter) ConvertReturns(result1 *v1.Job) {
+	fake.convertMutex.Lock()
+	defer fake.convertMutex.Unlock()
+	fake.ConvertStub = nil
+	fake.convertReturns = struct {
+		result1 *v1.Job
+	}{result1}
+}
+
+func (fake *FakeStagingToJobConverter) ConvertReturnsOnCall(i int, result1 *v1.Job) {
+	fake.convertMutex.Lock()
+	defer fake.convertMutex.Unlock()
+	fake.ConvertStub = nil
+	if fake.convertReturnsOnCall == nil {
+		fake.convertReturnsOnCall = make(map[int]struct {
+			result1 *v1.Job
+		})
+	}
+	fake.convertReturnsOnCall[i] = struct {
+		result1 *v1.Job
+	}{result1}
+}
+
+func (fake *FakeStagingToJobConverter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.convertMutex.RLock()
+	defer fake.convertMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStagingToJobConverter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ jobs.StagingToJobConverter = new(FakeStagingToJobConverter)
@@ -7,7 +7,8 @@ import (
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 
 const (
-	TaskSourceType = "TASK"
+	TaskSourceType    = "TASK"
+	StagingSourceType = "STG"
 
 	AnnotationGUID                        = "cloudfoundry.org/guid"
 	AnnotationAppName                     = stset.AnnotationAppName
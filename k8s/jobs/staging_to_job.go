@@ -0,0 +1,155 @@
+package jobs
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	"code.cloudfoundry.org/eirini/k8s/utils"
+	"code.cloudfoundry.org/eirini/k8s/utils/dockerutils"
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	stagingTaskName                = "staging"
+	stagingDownloaderContainerName = "opi-stage-downloader"
+	stagingBuilderContainerName    = "opi-stage-builder"
+
+	workspaceVolumeName    = "workspace"
+	workspaceMountPath     = "/workspace"
+	dockerConfigVolumeName = "registry-docker-config"
+	dockerConfigMountPath  = "/var/run/eirini/docker"
+	dockerConfigFileName   = "config.json"
+)
+
+type StagingConverter struct {
+	serviceAccountName                string
+	registrySecretName                string
+	allowAutomountServiceAccountToken bool
+	downloaderImage                   string
+	builderImage                      string
+	latestMigration                   int
+}
+
+func NewStagingToJobConverter(
+	serviceAccountName string,
+	registrySecretName string,
+	allowAutomountServiceAccountToken bool,
+	downloaderImage string,
+	builderImage string,
+	latestMigration int,
+) *StagingConverter {
+	return &StagingConverter{
+		serviceAccountName:                serviceAccountName,
+		registrySecretName:                registrySecretName,
+		allowAutomountServiceAccountToken: allowAutomountServiceAccountToken,
+		downloaderImage:                   downloaderImage,
+		builderImage:                      builderImage,
+		latestMigration:                   latestMigration,
+	}
+}
+
+// Convert returns the job staging a buildpack app. Staging runs like a task
+// of the app whose task container is the builder, so that it is reported
+// and cleaned up the same way tasks are.
+func (m *StagingConverter) Convert(staging *api.Staging) *batch.Job {
+	job := toJob(&api.Task{
+		GUID:      staging.GUID,
+		Name:      stagingTaskName,
+		AppName:   staging.AppName,
+		AppGUID:   staging.AppGUID,
+		OrgName:   staging.OrgName,
+		OrgGUID:   staging.OrgGUID,
+		SpaceName: staging.SpaceName,
+		SpaceGUID: staging.SpaceGUID,
+	}, m.allowAutomountServiceAccountToken, m.latestMigration)
+
+	// an app can be staged several times at once, so unlike task jobs the
+	// name of staging jobs is unique
+	job.Name = utils.SanitizeNameWithMaxStringLen(fmt.Sprintf("%s-%s", stagingTaskName, staging.GUID), staging.GUID, sanitizedNameMaxLen)
+
+	job.Spec.Template.Spec.ServiceAccountName = m.serviceAccountName
+	job.Labels[LabelSourceType] = StagingSourceType
+	job.Labels[LabelName] = stagingTaskName
+	job.Annotations[AnnotationCompletionCallback] = staging.CompletionCallback
+	job.Spec.Template.Annotations[AnnotationGUID] = staging.GUID
+	job.Spec.Template.Annotations[AnnotationTaskContainerName] = stagingBuilderContainerName
+	job.Spec.Template.Annotations[AnnotationCompletionCallback] = staging.CompletionCallback
+
+	resources := shared.ContainerResources(staging.CPUWeight, staging.MemoryMB, staging.DiskMB)
+	workspaceMount := corev1.VolumeMount{Name: workspaceVolumeName, MountPath: workspaceMountPath}
+
+	job.Spec.Template.Spec.InitContainers = []corev1.Container{
+		{
+			Name:  stagingDownloaderContainerName,
+			Image: m.downloaderImage,
+			Env: []corev1.EnvVar{
+				{Name: eirini.EnvDownloadURL, Value: staging.PackageURL},
+			},
+			Resources:    resources,
+			VolumeMounts: []corev1.VolumeMount{workspaceMount},
+
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		},
+	}
+
+	job.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name:      stagingBuilderContainerName,
+			Image:     m.builderImage,
+			Env:       getStagingEnvs(staging),
+			Resources: resources,
+			VolumeMounts: []corev1.VolumeMount{
+				workspaceMount,
+				{Name: dockerConfigVolumeName, MountPath: dockerConfigMountPath, ReadOnly: true},
+			},
+
+			// the builder writes the staging result to the termination message
+			// file; the logs are reported instead if it fails
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		},
+	}
+
+	job.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{
+			Name:         workspaceVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		},
+		{
+			Name: dockerConfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: m.registrySecretName,
+					Items: []corev1.KeyToPath{
+						{Key: dockerutils.DockerConfigKey, Path: dockerConfigFileName},
+					},
+				},
+			},
+		},
+	}
+
+	job.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{
+		{
+			Name: m.registrySecretName,
+		},
+	}
+
+	return job
+}
+
+func getStagingEnvs(staging *api.Staging) []corev1.EnvVar {
+	envs := shared.MapToEnvVar(staging.Env)
+	envs = append(envs,
+		corev1.EnvVar{Name: eirini.EnvOutputImage, Value: staging.Image},
+		corev1.EnvVar{Name: eirini.EnvBuildpacks, Value: staging.Buildpacks},
+		corev1.EnvVar{Name: eirini.EnvDockerConfig, Value: dockerConfigMountPath},
+	)
+
+	if staging.DropletUploadURL != "" {
+		envs = append(envs, corev1.EnvVar{Name: eirini.EnvDropletUploadURL, Value: staging.DropletUploadURL})
+	}
+
+	return envs
+}
@@ -0,0 +1,131 @@
+package jobs_test
+
+import (
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("StagingToJob", func() {
+	const (
+		stagingGUID     = "droplet-123"
+		serviceAccount  = "service-account"
+		registrySecret  = "registry-secret"
+		downloaderImage = "eirini/downloader"
+		builderImage    = "eirini/builder"
+	)
+
+	var (
+		job     *batch.Job
+		staging *api.Staging
+	)
+
+	BeforeEach(func() {
+		staging = &api.Staging{
+			GUID:               stagingGUID,
+			CompletionCallback: "cloud-controller.io/staging/completed",
+			Env:                map[string]string{"FOO": "bar"},
+			PackageURL:         "example.com/download",
+			Buildpacks:         `[{"name":"ruby"}]`,
+			Image:              "registry.example.com/droplets/app-guid:droplet-123",
+			AppName:            "my-app",
+			AppGUID:            "app-guid",
+			OrgName:            "my-org",
+			OrgGUID:            "org-id",
+			SpaceName:          "my-space",
+			SpaceGUID:          "space-id",
+			MemoryMB:           1024,
+			DiskMB:             2048,
+			CPUWeight:          50,
+		}
+	})
+
+	JustBeforeEach(func() {
+		converter := jobs.NewStagingToJobConverter(serviceAccount, registrySecret, false, downloaderImage, builderImage, 1234)
+		job = converter.Convert(staging)
+	})
+
+	It("labels the job as a staging job", func() {
+		Expect(job.Name).To(Equal("staging-droplet-123"))
+		Expect(job.Labels).To(MatchKeys(IgnoreExtras, Keys{
+			jobs.LabelSourceType: Equal(jobs.StagingSourceType),
+			jobs.LabelGUID:       Equal(stagingGUID),
+			jobs.LabelAppGUID:    Equal("app-guid"),
+		}))
+		Expect(job.Spec.Template.Labels).To(HaveKeyWithValue(jobs.LabelSourceType, jobs.StagingSourceType))
+	})
+
+	It("annotates the pods so that staging is reported like a task", func() {
+		Expect(job.Annotations).To(HaveKeyWithValue(jobs.AnnotationCompletionCallback, "cloud-controller.io/staging/completed"))
+		Expect(job.Spec.Template.Annotations).To(MatchKeys(IgnoreExtras, Keys{
+			jobs.AnnotationGUID:               Equal(stagingGUID),
+			jobs.AnnotationCompletionCallback: Equal("cloud-controller.io/staging/completed"),
+			jobs.AnnotationTaskContainerName:  Equal(job.Spec.Template.Spec.Containers[0].Name),
+		}))
+	})
+
+	It("runs once as non root", func() {
+		automountServiceAccountToken := false
+		Expect(job.Spec.BackoffLimit).To(PointTo(BeZero()))
+		Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(job.Spec.Template.Spec.AutomountServiceAccountToken).To(Equal(&automountServiceAccountToken))
+		Expect(job.Spec.Template.Spec.SecurityContext.RunAsNonRoot).To(PointTo(BeTrue()))
+		Expect(job.Spec.Template.Spec.ServiceAccountName).To(Equal(serviceAccount))
+		Expect(job.Spec.Template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: registrySecret}))
+	})
+
+	It("downloads the package into the workspace", func() {
+		Expect(job.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		downloader := job.Spec.Template.Spec.InitContainers[0]
+		Expect(downloader.Image).To(Equal(downloaderImage))
+		Expect(downloader.Env).To(ConsistOf(corev1.EnvVar{Name: eirini.EnvDownloadURL, Value: "example.com/download"}))
+		Expect(downloader.VolumeMounts).To(ConsistOf(corev1.VolumeMount{Name: "workspace", MountPath: "/workspace"}))
+	})
+
+	It("builds the workspace and pushes the droplet image", func() {
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+		builder := job.Spec.Template.Spec.Containers[0]
+		Expect(builder.Image).To(Equal(builderImage))
+		Expect(builder.TerminationMessagePolicy).To(Equal(corev1.TerminationMessageFallbackToLogsOnError))
+		Expect(builder.Env).To(ConsistOf(
+			corev1.EnvVar{Name: "FOO", Value: "bar"},
+			corev1.EnvVar{Name: eirini.EnvOutputImage, Value: "registry.example.com/droplets/app-guid:droplet-123"},
+			corev1.EnvVar{Name: eirini.EnvBuildpacks, Value: `[{"name":"ruby"}]`},
+			corev1.EnvVar{Name: eirini.EnvDockerConfig, Value: "/var/run/eirini/docker"},
+		))
+		Expect(builder.VolumeMounts).To(ConsistOf(
+			corev1.VolumeMount{Name: "workspace", MountPath: "/workspace"},
+			corev1.VolumeMount{Name: "registry-docker-config", MountPath: "/var/run/eirini/docker", ReadOnly: true},
+		))
+		Expect(builder.Resources.Limits.Memory().String()).To(Equal("1024M"))
+	})
+
+	It("mounts the registry secret as docker config", func() {
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "registry-docker-config",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: registrySecret,
+					Items:      []corev1.KeyToPath{{Key: ".dockerconfigjson", Path: "config.json"}},
+				},
+			},
+		}))
+	})
+
+	When("the cloud controller expects a droplet upload", func() {
+		BeforeEach(func() {
+			staging.DropletUploadURL = "example.com/upload"
+		})
+
+		It("passes the upload url to the builder", func() {
+			Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(
+				corev1.EnvVar{Name: eirini.EnvDropletUploadURL, Value: "example.com/upload"},
+			))
+		})
+	})
+})
@@ -23,6 +23,8 @@ type Converter struct {
 	serviceAccountName                string
 	registrySecretName                string
 	allowAutomountServiceAccountToken bool
+	placementTagSelectors             map[string]map[string]string
+	imageRewrites                     map[string]string
 	latestMigration                   int
 }
 
@@ -30,18 +32,22 @@ func NewTaskToJobConverter(
 	serviceAccountName string,
 	registrySecretName string,
 	allowAutomountServiceAccountToken bool,
+	placementTagSelectors map[string]map[string]string,
+	imageRewrites map[string]string,
 	latestMigration int,
 ) *Converter {
 	return &Converter{
 		serviceAccountName:                serviceAccountName,
 		registrySecretName:                registrySecretName,
 		allowAutomountServiceAccountToken: allowAutomountServiceAccountToken,
+		placementTagSelectors:             placementTagSelectors,
+		imageRewrites:                     imageRewrites,
 		latestMigration:                   latestMigration,
 	}
 }
 
 func (m *Converter) Convert(task *api.Task, privateRegistrySecret *corev1.Secret) *batch.Job {
-	job := m.toJob(task)
+	job := toJob(task, m.allowAutomountServiceAccountToken, m.latestMigration)
 	job.Spec.Template.Spec.ServiceAccountName = m.serviceAccountName
 	job.Labels[LabelSourceType] = TaskSourceType
 	job.Labels[LabelName] = task.Name
@@ -54,13 +60,22 @@ func (m *Converter) Convert(task *api.Task, privateRegistrySecret *corev1.Secret
 	containers := []corev1.Container{
 		{
 			Name:            taskContainerName,
-			Image:           task.Image,
+			Image:           shared.RewriteImage(task.Image, m.imageRewrites),
 			ImagePullPolicy: corev1.PullAlways,
 			Env:             envs,
 			Command:         task.Command,
+			Resources:       shared.ContainerResources(task.CPUWeight, task.MemoryMB, task.DiskMB),
+
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 		},
 	}
 
+	// the kubelet reports the content of the termination message file, up to
+	// 4KB, in the container status, which is where the task result is read from
+	if task.ResultFile != "" {
+		containers[0].TerminationMessagePath = task.ResultFile
+	}
+
 	job.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{
 		{
 			Name: m.registrySecretName,
@@ -74,10 +89,14 @@ func (m *Converter) Convert(task *api.Task, privateRegistrySecret *corev1.Secret
 
 	job.Spec.Template.Spec.Containers = containers
 
+	if nodeAffinity := shared.PlacementTagNodeAffinity(task.PlacementTags, m.placementTagSelectors); nodeAffinity != nil {
+		job.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: nodeAffinity}
+	}
+
 	return job
 }
 
-func (m *Converter) toJob(task *api.Task) *batch.Job {
+func toJob(task *api.Task, allowAutomountServiceAccountToken bool, latestMigration int) *batch.Job {
 	runAsNonRoot := true
 
 	job := &batch.Job{
@@ -96,7 +115,7 @@ func (m *Converter) toJob(task *api.Task) *batch.Job {
 		},
 	}
 
-	if !m.allowAutomountServiceAccountToken {
+	if !allowAutomountServiceAccountToken {
 		automountServiceAccountToken := false
 		job.Spec.Template.Spec.AutomountServiceAccountToken = &automountServiceAccountToken
 	}
@@ -123,7 +142,7 @@ func (m *Converter) toJob(task *api.Task) *batch.Job {
 		AnnotationSpaceName:              task.SpaceName,
 		AnnotationSpaceGUID:              task.SpaceGUID,
 		corev1.SeccompPodAnnotationKey:   corev1.SeccompProfileRuntimeDefault,
-		shared.AnnotationLatestMigration: strconv.Itoa(m.latestMigration),
+		shared.AnnotationLatestMigration: strconv.Itoa(latestMigration),
 	}
 
 	job.Spec.Template.Labels = job.Labels
@@ -13,6 +13,7 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 	batch "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,6 +31,8 @@ var _ = Describe("TaskToJob", func() {
 		privateRegistrySecret             *corev1.Secret
 		task                              *api.Task
 		allowAutomountServiceAccountToken bool
+		placementTagSelectors             map[string]map[string]string
+		imageRewrites                     map[string]string
 	)
 
 	assertGeneralSpec := func(job *batch.Job) {
@@ -43,6 +46,7 @@ var _ = Describe("TaskToJob", func() {
 		Expect(container.Name).To(Equal(name))
 		Expect(container.Image).To(Equal(image))
 		Expect(container.ImagePullPolicy).To(Equal(corev1.PullAlways))
+		Expect(container.TerminationMessagePolicy).To(Equal(corev1.TerminationMessageFallbackToLogsOnError))
 
 		Expect(container.Env).To(ContainElements(
 			corev1.EnvVar{Name: eirini.EnvDownloadURL, Value: "example.com/download"},
@@ -60,6 +64,8 @@ var _ = Describe("TaskToJob", func() {
 
 	BeforeEach(func() {
 		allowAutomountServiceAccountToken = false
+		placementTagSelectors = nil
+		imageRewrites = nil
 		privateRegistrySecret = nil
 
 		task = &api.Task{
@@ -86,7 +92,7 @@ var _ = Describe("TaskToJob", func() {
 	})
 
 	JustBeforeEach(func() {
-		job = jobs.NewTaskToJobConverter(serviceAccount, registrySecret, allowAutomountServiceAccountToken, latestMigration).Convert(task, privateRegistrySecret)
+		job = jobs.NewTaskToJobConverter(serviceAccount, registrySecret, allowAutomountServiceAccountToken, placementTagSelectors, imageRewrites, latestMigration).Convert(task, privateRegistrySecret)
 	})
 
 	It("returns a job for the task with the correct attributes", func() {
@@ -101,6 +107,14 @@ var _ = Describe("TaskToJob", func() {
 		assertContainer(containers[0], "opi-task")
 		Expect(containers[0].Command).To(ConsistOf("/lifecycle/launch"))
 
+		By("setting the container resources", func() {
+			resources := containers[0].Resources
+			Expect(resources.Limits.Memory().Equal(resource.MustParse("1M"))).To(BeTrue())
+			Expect(resources.Limits.StorageEphemeral().Equal(resource.MustParse("3M"))).To(BeTrue())
+			Expect(resources.Requests.Memory().Equal(resource.MustParse("1M"))).To(BeTrue())
+			Expect(resources.Requests.Cpu().Equal(resource.MustParse("2m"))).To(BeTrue())
+		})
+
 		By("setting the expected annotations on the job", func() {
 			Expect(job.Annotations).To(SatisfyAll(
 				HaveKeyWithValue(jobs.AnnotationAppName, "my-app"),
@@ -157,6 +171,20 @@ var _ = Describe("TaskToJob", func() {
 		})
 	})
 
+	It("keeps the default termination message path", func() {
+		Expect(job.Spec.Template.Spec.Containers[0].TerminationMessagePath).To(BeEmpty())
+	})
+
+	When("the task declares a result file", func() {
+		BeforeEach(func() {
+			task.ResultFile = "/home/vcap/result.json"
+		})
+
+		It("uses the result file as termination message path", func() {
+			Expect(job.Spec.Template.Spec.Containers[0].TerminationMessagePath).To(Equal("/home/vcap/result.json"))
+		})
+	})
+
 	When("allowAutomountServiceAccountToken is true", func() {
 		BeforeEach(func() {
 			allowAutomountServiceAccountToken = true
@@ -167,6 +195,39 @@ var _ = Describe("TaskToJob", func() {
 		})
 	})
 
+	It("does not set any affinity", func() {
+		Expect(job.Spec.Template.Spec.Affinity).To(BeNil())
+	})
+
+	When("the task has placement tags", func() {
+		BeforeEach(func() {
+			task.PlacementTags = []string{"isolated"}
+			placementTagSelectors = map[string]map[string]string{
+				"isolated": {"example.com/segment": "isolated"},
+			}
+		})
+
+		It("requires nodes matching the placement tags", func() {
+			Expect(job.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+				corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "example.com/segment", Operator: corev1.NodeSelectorOpIn, Values: []string{"isolated"}},
+					},
+				},
+			))
+		})
+	})
+
+	When("the image matches an image rewrite", func() {
+		BeforeEach(func() {
+			imageRewrites = map[string]string{"docker.io": "mirror.example.com/dockerhub"}
+		})
+
+		It("uses the rewritten image", func() {
+			Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("mirror.example.com/dockerhub/library/docker.png"))
+		})
+	})
+
 	When("the app name and space name are too long", func() {
 		BeforeEach(func() {
 			task.AppName = "app-with-very-long-name"
@@ -21,6 +21,14 @@ type PodDisruptionBudgetClient interface {
 	Update(ctx context.Context, stset *appsv1.StatefulSet, lrp *api.LRP) error
 }
 
+type RouteClient interface {
+	Update(ctx context.Context, stset *appsv1.StatefulSet, lrp *api.LRP) error
+}
+
+type NetworkPolicyClient interface {
+	Update(ctx context.Context, stset *appsv1.StatefulSet, lrp *api.LRP) error
+}
+
 type StatefulSetClient interface {
 	Create(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) (*appsv1.StatefulSet, error)
 	Update(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) (*appsv1.StatefulSet, error)
@@ -30,7 +38,9 @@ type StatefulSetClient interface {
 }
 
 type SecretsClient interface {
+	Get(ctx context.Context, namespace, name string) (*corev1.Secret, error)
 	Create(ctx context.Context, namespace string, secret *corev1.Secret) (*corev1.Secret, error)
+	Update(ctx context.Context, namespace string, secret *corev1.Secret) (*corev1.Secret, error)
 	Delete(ctx context.Context, namespace string, name string) error
 	SetOwner(ctx context.Context, secret *corev1.Secret, owner metav1.Object) (*corev1.Secret, error)
 }
@@ -53,15 +63,18 @@ func NewLRPClient(
 	statefulSets StatefulSetClient,
 	pods PodClient,
 	pdbClient PodDisruptionBudgetClient,
+	routeClient RouteClient,
+	networkPolicyClient NetworkPolicyClient,
 	events EventsClient,
 	lrpToStatefulSetConverter stset.LRPToStatefulSetConverter,
 	statefulSetToLRPConverter stset.StatefulSetToLRPConverter,
+	imageRewrites map[string]string,
 ) *LRPClient {
 	return &LRPClient{
-		Desirer: stset.NewDesirer(logger, secrets, statefulSets, lrpToStatefulSetConverter, pdbClient),
+		Desirer: stset.NewDesirer(logger, secrets, statefulSets, lrpToStatefulSetConverter, pdbClient, routeClient, networkPolicyClient),
 		Lister:  stset.NewLister(logger, statefulSets, statefulSetToLRPConverter),
 		Stopper: stset.NewStopper(logger, statefulSets, statefulSets, pods),
-		Updater: stset.NewUpdater(logger, statefulSets, statefulSets, pdbClient),
-		Getter:  stset.NewGetter(logger, statefulSets, pods, events, statefulSetToLRPConverter),
+		Updater: stset.NewUpdater(logger, statefulSets, statefulSets, secrets, lrpToStatefulSetConverter, pdbClient, routeClient, networkPolicyClient, imageRewrites),
+		Getter:  stset.NewGetter(logger, statefulSets, pods, events, secrets, statefulSetToLRPConverter),
 	}
 }
@@ -0,0 +1,203 @@
+package netpol
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ProtocolTCP    = "tcp"
+	ProtocolUDP    = "udp"
+	ProtocolICMP   = "icmp"
+	ProtocolICMPv6 = "icmpv6"
+	ProtocolAll    = "all"
+)
+
+// EgressRule is a single rule of a CF application security group, as sent
+// by Cloud Controller in the egress_rules field of a desired LRP.
+type EgressRule struct {
+	Protocol     string     `json:"protocol"`
+	Destinations []string   `json:"destinations"`
+	Ports        []int32    `json:"ports,omitempty"`
+	PortRange    *PortRange `json:"port_range,omitempty"`
+	ICMPInfo     *ICMPInfo  `json:"icmp_info,omitempty"`
+	Log          bool       `json:"log,omitempty"`
+}
+
+type PortRange struct {
+	Start int32 `json:"start"`
+	End   int32 `json:"end"`
+}
+
+type ICMPInfo struct {
+	Type int32 `json:"type"`
+	Code int32 `json:"code"`
+}
+
+func ParseEgressRules(rawRules []json.RawMessage) ([]EgressRule, error) {
+	rules := make([]EgressRule, 0, len(rawRules))
+
+	for i, raw := range rawRules {
+		var rule EgressRule
+		if err := json.Unmarshal(raw, &rule); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse egress rule %d", i)
+		}
+
+		if err := rule.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid egress rule %d", i)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// CIDRs returns the rule destinations as a list of CIDRs. Single addresses
+// become host CIDRs and address ranges are split into the smallest set of
+// CIDRs covering them.
+func (r EgressRule) CIDRs() ([]string, error) {
+	cidrs := []string{}
+
+	for _, destination := range r.Destinations {
+		destinationCIDRs, err := destinationToCIDRs(destination)
+		if err != nil {
+			return nil, err
+		}
+
+		cidrs = append(cidrs, destinationCIDRs...)
+	}
+
+	return cidrs, nil
+}
+
+func (r EgressRule) validate() error {
+	switch r.Protocol {
+	case ProtocolTCP, ProtocolUDP:
+		if len(r.Ports) > 0 && r.PortRange != nil {
+			return errors.New("ports and port_range are mutually exclusive")
+		}
+
+		if r.ICMPInfo != nil {
+			return errors.Errorf("icmp_info is not allowed for protocol %q", r.Protocol)
+		}
+	case ProtocolICMP, ProtocolICMPv6:
+		if len(r.Ports) > 0 || r.PortRange != nil {
+			return errors.Errorf("ports are not allowed for protocol %q", r.Protocol)
+		}
+	case ProtocolAll:
+		if len(r.Ports) > 0 || r.PortRange != nil || r.ICMPInfo != nil {
+			return errors.Errorf("ports and icmp_info are not allowed for protocol %q", r.Protocol)
+		}
+	default:
+		return errors.Errorf("unsupported protocol %q", r.Protocol)
+	}
+
+	for _, port := range r.Ports {
+		if !validPort(port) {
+			return errors.Errorf("invalid port %d", port)
+		}
+	}
+
+	if r.PortRange != nil {
+		if !validPort(r.PortRange.Start) || !validPort(r.PortRange.End) || r.PortRange.Start > r.PortRange.End {
+			return errors.Errorf("invalid port range %d-%d", r.PortRange.Start, r.PortRange.End)
+		}
+	}
+
+	if len(r.Destinations) == 0 {
+		return errors.New("at least one destination is required")
+	}
+
+	_, err := r.CIDRs()
+
+	return err
+}
+
+func validPort(port int32) bool {
+	return port > 0 && port <= 65535
+}
+
+func destinationToCIDRs(destination string) ([]string, error) {
+	if strings.Contains(destination, "/") {
+		_, ipNet, err := net.ParseCIDR(destination)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid destination %q", destination)
+		}
+
+		return []string{ipNet.String()}, nil
+	}
+
+	start, end, isRange := strings.Cut(destination, "-")
+	if !isRange {
+		end = start
+	}
+
+	startIP, endIP := parseIP(start), parseIP(end)
+	if startIP == nil || endIP == nil || len(startIP) != len(endIP) {
+		return nil, errors.Errorf("invalid destination %q", destination)
+	}
+
+	return rangeToCIDRs(startIP, endIP)
+}
+
+func parseIP(s string) net.IP {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil {
+		return nil
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+
+	return ip
+}
+
+func rangeToCIDRs(startIP, endIP net.IP) ([]string, error) {
+	bits := len(startIP) * 8
+	start := new(big.Int).SetBytes(startIP)
+	end := new(big.Int).SetBytes(endIP)
+
+	if start.Cmp(end) > 0 {
+		return nil, errors.Errorf("invalid destination range %s-%s", startIP, endIP)
+	}
+
+	cidrs := []string{}
+	one := big.NewInt(1)
+
+	for start.Cmp(end) <= 0 {
+		// grow the prefix for as long as it stays aligned to start and within end
+		prefixLen := bits
+		for prefixLen > 0 {
+			size := new(big.Int).Lsh(one, uint(bits-prefixLen+1))
+			if new(big.Int).Mod(start, size).Sign() != 0 {
+				break
+			}
+
+			last := new(big.Int).Add(start, size)
+			if last.Sub(last, one).Cmp(end) > 0 {
+				break
+			}
+
+			prefixLen--
+		}
+
+		cidrs = append(cidrs, fmt.Sprintf("%s/%d", bigIntToIP(start, len(startIP)), prefixLen))
+		start.Add(start, new(big.Int).Lsh(one, uint(bits-prefixLen)))
+	}
+
+	return cidrs, nil
+}
+
+func bigIntToIP(i *big.Int, length int) net.IP {
+	ip := make(net.IP, length)
+	i.FillBytes(ip)
+
+	return ip
+}
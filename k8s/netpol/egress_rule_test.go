@@ -0,0 +1,79 @@
+package netpol_test
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/eirini/k8s/netpol"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EgressRule", func() {
+	Describe("ParseEgressRules", func() {
+		var (
+			rawRules []json.RawMessage
+			rules    []netpol.EgressRule
+			err      error
+		)
+
+		JustBeforeEach(func() {
+			rules, err = netpol.ParseEgressRules(rawRules)
+		})
+
+		When("the rules are valid", func() {
+			BeforeEach(func() {
+				rawRules = []json.RawMessage{
+					json.RawMessage(`{"protocol":"tcp","destinations":["10.0.0.0/8"],"ports":[80,443],"log":true}`),
+					json.RawMessage(`{"protocol":"udp","destinations":["1.1.1.1"],"port_range":{"start":8000,"end":9000}}`),
+					json.RawMessage(`{"protocol":"icmp","destinations":["0.0.0.0/0"],"icmp_info":{"type":0,"code":-1}}`),
+					json.RawMessage(`{"protocol":"all","destinations":["192.168.0.1-192.168.0.10"]}`),
+				}
+			})
+
+			It("parses them", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rules).To(Equal([]netpol.EgressRule{
+					{Protocol: "tcp", Destinations: []string{"10.0.0.0/8"}, Ports: []int32{80, 443}, Log: true},
+					{Protocol: "udp", Destinations: []string{"1.1.1.1"}, PortRange: &netpol.PortRange{Start: 8000, End: 9000}},
+					{Protocol: "icmp", Destinations: []string{"0.0.0.0/0"}, ICMPInfo: &netpol.ICMPInfo{Type: 0, Code: -1}},
+					{Protocol: "all", Destinations: []string{"192.168.0.1-192.168.0.10"}},
+				}))
+			})
+		})
+
+		DescribeTable("invalid rules",
+			func(rule, expectedErr string) {
+				_, err := netpol.ParseEgressRules([]json.RawMessage{json.RawMessage(rule)})
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			},
+			Entry("malformed json", `{"protocol":`, "failed to parse egress rule 0"),
+			Entry("unknown protocol", `{"protocol":"sctp","destinations":["10.0.0.0/8"]}`, `unsupported protocol "sctp"`),
+			Entry("no destinations", `{"protocol":"tcp"}`, "at least one destination is required"),
+			Entry("invalid cidr", `{"protocol":"tcp","destinations":["10.0.0.0/99"]}`, `invalid destination "10.0.0.0/99"`),
+			Entry("invalid ip", `{"protocol":"tcp","destinations":["not-an-ip"]}`, `invalid destination "not-an-ip"`),
+			Entry("reversed range", `{"protocol":"tcp","destinations":["10.0.0.9-10.0.0.1"]}`, "invalid destination range"),
+			Entry("mixed range", `{"protocol":"tcp","destinations":["10.0.0.1-::1"]}`, "invalid destination"),
+			Entry("invalid port", `{"protocol":"tcp","destinations":["10.0.0.1"],"ports":[70000]}`, "invalid port 70000"),
+			Entry("invalid port range", `{"protocol":"tcp","destinations":["10.0.0.1"],"port_range":{"start":90,"end":80}}`, "invalid port range 90-80"),
+			Entry("ports and port range", `{"protocol":"tcp","destinations":["10.0.0.1"],"ports":[80],"port_range":{"start":80,"end":90}}`, "mutually exclusive"),
+			Entry("icmp with ports", `{"protocol":"icmp","destinations":["10.0.0.1"],"ports":[80]}`, "ports are not allowed"),
+			Entry("all with ports", `{"protocol":"all","destinations":["10.0.0.1"],"ports":[80]}`, "not allowed"),
+		)
+	})
+
+	Describe("CIDRs", func() {
+		DescribeTable("converting destinations",
+			func(destination string, expectedCIDRs []string) {
+				cidrs, err := netpol.EgressRule{Destinations: []string{destination}}.CIDRs()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cidrs).To(Equal(expectedCIDRs))
+			},
+			Entry("cidr", "10.1.2.3/8", []string{"10.0.0.0/8"}),
+			Entry("single address", "10.1.2.3", []string{"10.1.2.3/32"}),
+			Entry("aligned range", "10.0.0.0-10.0.0.255", []string{"10.0.0.0/24"}),
+			Entry("unaligned range", "10.0.0.1-10.0.0.10", []string{"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/30", "10.0.0.8/31", "10.0.0.10/32"}),
+			Entry("whole address space", "0.0.0.0-255.255.255.255", []string{"0.0.0.0/0"}),
+			Entry("ipv6 range", "2001:db8::-2001:db8::3", []string{"2001:db8::/126"}),
+		)
+	})
+})
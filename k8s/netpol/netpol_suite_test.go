@@ -0,0 +1,13 @@
+package netpol_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestNetpol(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Netpol Suite")
+}
@@ -0,0 +1,176 @@
+package netpol
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/lager"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+//counterfeiter:generate . K8sClient
+
+type K8sClient interface {
+	Get(ctx context.Context, namespace, name string) (*networkingv1.NetworkPolicy, error)
+	Create(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error)
+	Update(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error)
+	Delete(ctx context.Context, namespace string, name string) error
+}
+
+// Updater keeps an egress NetworkPolicy in sync with the egress rules of an
+// LRP. LRPs without egress rules get no policy, i.e. unrestricted egress.
+// Kubernetes network policies cannot match ICMP traffic, so ICMP rules are
+// not translated and such traffic is denied once a policy is in place.
+type Updater struct {
+	logger    lager.Logger
+	k8sClient K8sClient
+}
+
+func NewUpdater(logger lager.Logger, k8sClient K8sClient) *Updater {
+	return &Updater{
+		logger:    logger,
+		k8sClient: k8sClient,
+	}
+}
+
+func (u *Updater) Update(ctx context.Context, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
+	logger := u.logger.Session("update-network-policy", lager.Data{"guid": lrp.GUID, "version": lrp.Version})
+
+	rules, err := ParseEgressRules(lrp.EgressRules)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse egress rules")
+	}
+
+	if len(rules) == 0 {
+		return u.delete(ctx, statefulSet)
+	}
+
+	egress, err := u.toEgressRules(logger, rules)
+	if err != nil {
+		return err
+	}
+
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      statefulSet.Name,
+			Namespace: statefulSet.Namespace,
+			Labels: map[string]string{
+				stset.LabelGUID:    lrp.GUID,
+				stset.LabelVersion: lrp.Version,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *stset.StatefulSetLabelSelector(lrp),
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		},
+	}
+
+	if err = controllerutil.SetOwnerReference(statefulSet, networkPolicy, scheme.Scheme); err != nil {
+		return errors.Wrap(err, "failed to set owner reference on network policy")
+	}
+
+	return u.apply(ctx, networkPolicy)
+}
+
+func (u *Updater) apply(ctx context.Context, networkPolicy *networkingv1.NetworkPolicy) error {
+	existing, err := u.k8sClient.Get(ctx, networkPolicy.Namespace, networkPolicy.Name)
+	if k8serrors.IsNotFound(err) {
+		_, err = u.k8sClient.Create(ctx, networkPolicy.Namespace, networkPolicy)
+
+		return errors.Wrap(err, "failed to create network policy")
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to get network policy")
+	}
+
+	updated := existing.DeepCopy()
+	updated.Labels = networkPolicy.Labels
+	updated.OwnerReferences = networkPolicy.OwnerReferences
+	updated.Spec = networkPolicy.Spec
+
+	_, err = u.k8sClient.Update(ctx, updated.Namespace, updated)
+
+	return errors.Wrap(err, "failed to update network policy")
+}
+
+func (u *Updater) delete(ctx context.Context, statefulSet *appsv1.StatefulSet) error {
+	err := u.k8sClient.Delete(ctx, statefulSet.Namespace, statefulSet.Name)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+
+	return errors.Wrap(err, "failed to delete network policy")
+}
+
+func (u *Updater) toEgressRules(logger lager.Logger, rules []EgressRule) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	egress := []networkingv1.NetworkPolicyEgressRule{}
+
+	for _, rule := range rules {
+		if rule.Protocol == ProtocolICMP || rule.Protocol == ProtocolICMPv6 {
+			logger.Info("skipping-unsupported-icmp-rule", lager.Data{"destinations": rule.Destinations})
+
+			continue
+		}
+
+		cidrs, err := rule.CIDRs()
+		if err != nil {
+			return nil, err
+		}
+
+		peers := make([]networkingv1.NetworkPolicyPeer, 0, len(cidrs))
+		for _, cidr := range cidrs {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+			})
+		}
+
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To:    peers,
+			Ports: toPolicyPorts(rule),
+		})
+	}
+
+	return egress, nil
+}
+
+func toPolicyPorts(rule EgressRule) []networkingv1.NetworkPolicyPort {
+	if rule.Protocol == ProtocolAll {
+		return nil
+	}
+
+	protocol := corev1.ProtocolTCP
+	if rule.Protocol == ProtocolUDP {
+		protocol = corev1.ProtocolUDP
+	}
+
+	if rule.PortRange != nil {
+		start := intstr.FromInt(int(rule.PortRange.Start))
+		end := rule.PortRange.End
+
+		return []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &start, EndPort: &end}}
+	}
+
+	if len(rule.Ports) == 0 {
+		return []networkingv1.NetworkPolicyPort{{Protocol: &protocol}}
+	}
+
+	ports := make([]networkingv1.NetworkPolicyPort, 0, len(rule.Ports))
+
+	for _, p := range rule.Ports {
+		port := intstr.FromInt(int(p))
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+	}
+
+	return ports
+}
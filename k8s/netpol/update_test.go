@@ -0,0 +1,218 @@
+package netpol_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/netpol"
+	"code.cloudfoundry.org/eirini/k8s/netpol/netpolfakes"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("NetworkPolicy Updater", func() {
+	var (
+		updater   *netpol.Updater
+		k8sClient *netpolfakes.FakeK8sClient
+		stSet     *appsv1.StatefulSet
+		lrp       *api.LRP
+		ctx       context.Context
+		updateErr error
+	)
+
+	BeforeEach(func() {
+		k8sClient = new(netpolfakes.FakeK8sClient)
+		k8sClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "netpol"))
+		updater = netpol.NewUpdater(tests.NewTestLogger("netpol-updater"), k8sClient)
+
+		stSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+				UID:       "uid",
+			},
+		}
+
+		lrp = &api.LRP{
+			LRPIdentifier: api.LRPIdentifier{
+				GUID:    "guid",
+				Version: "version",
+			},
+			EgressRules: []json.RawMessage{
+				json.RawMessage(`{"protocol":"tcp","destinations":["10.0.0.0/8","1.1.1.1"],"ports":[80,443]}`),
+				json.RawMessage(`{"protocol":"udp","destinations":["8.8.8.8"],"port_range":{"start":53,"end":54}}`),
+				json.RawMessage(`{"protocol":"tcp","destinations":["9.9.9.9"]}`),
+				json.RawMessage(`{"protocol":"icmp","destinations":["0.0.0.0/0"],"icmp_info":{"type":0,"code":0}}`),
+				json.RawMessage(`{"protocol":"all","destinations":["192.168.0.0-192.168.0.3"]}`),
+			},
+		}
+
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		updateErr = updater.Update(ctx, stSet, lrp)
+	})
+
+	It("succeeds", func() {
+		Expect(updateErr).NotTo(HaveOccurred())
+	})
+
+	It("creates an egress network policy", func() {
+		Expect(k8sClient.CreateCallCount()).To(Equal(1))
+
+		_, namespace, policy := k8sClient.CreateArgsForCall(0)
+		Expect(namespace).To(Equal("namespace"))
+		Expect(policy.Name).To(Equal("name"))
+		Expect(policy.Namespace).To(Equal("namespace"))
+		Expect(policy.Labels).To(HaveKeyWithValue(stset.LabelGUID, "guid"))
+		Expect(policy.Labels).To(HaveKeyWithValue(stset.LabelVersion, "version"))
+		Expect(policy.Spec.PodSelector).To(Equal(*stset.StatefulSetLabelSelector(lrp)))
+		Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeEgress))
+		Expect(policy.OwnerReferences).To(HaveLen(1))
+		Expect(policy.OwnerReferences[0].Name).To(Equal("name"))
+		Expect(policy.OwnerReferences[0].UID).To(Equal(stSet.UID))
+	})
+
+	It("translates the egress rules, skipping ICMP", func() {
+		_, _, policy := k8sClient.CreateArgsForCall(0)
+		tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+		port80, port443, port53 := intstr.FromInt(80), intstr.FromInt(443), intstr.FromInt(53)
+		endPort := int32(54)
+
+		Expect(policy.Spec.Egress).To(Equal([]networkingv1.NetworkPolicyEgressRule{
+			{
+				To: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "1.1.1.1/32"}},
+				},
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &tcp, Port: &port80},
+					{Protocol: &tcp, Port: &port443},
+				},
+			},
+			{
+				To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "8.8.8.8/32"}}},
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &port53, EndPort: &endPort}},
+			},
+			{
+				To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "9.9.9.9/32"}}},
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp}},
+			},
+			{
+				To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.0.0/30"}}},
+			},
+		}))
+	})
+
+	When("the network policy already exists", func() {
+		BeforeEach(func() {
+			k8sClient.GetReturns(&networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace", ResourceVersion: "42"},
+			}, nil)
+		})
+
+		It("updates it", func() {
+			Expect(k8sClient.CreateCallCount()).To(BeZero())
+			Expect(k8sClient.UpdateCallCount()).To(Equal(1))
+
+			_, namespace, policy := k8sClient.UpdateArgsForCall(0)
+			Expect(namespace).To(Equal("namespace"))
+			Expect(policy.ResourceVersion).To(Equal("42"))
+			Expect(policy.Spec.Egress).To(HaveLen(4))
+			Expect(policy.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{"UID": Equal(stSet.UID)})))
+		})
+
+		When("updating fails", func() {
+			BeforeEach(func() {
+				k8sClient.UpdateReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(updateErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+
+	When("getting the network policy fails", func() {
+		BeforeEach(func() {
+			k8sClient.GetReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("failed to get network policy")))
+		})
+	})
+
+	When("creating the network policy fails", func() {
+		BeforeEach(func() {
+			k8sClient.CreateReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	When("the egress rules are invalid", func() {
+		BeforeEach(func() {
+			lrp.EgressRules = []json.RawMessage{json.RawMessage(`{"protocol":"foo"}`)}
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("failed to parse egress rules")))
+		})
+
+		It("does not touch the network policy", func() {
+			Expect(k8sClient.CreateCallCount()).To(BeZero())
+			Expect(k8sClient.DeleteCallCount()).To(BeZero())
+		})
+	})
+
+	When("there are no egress rules", func() {
+		BeforeEach(func() {
+			lrp.EgressRules = nil
+		})
+
+		It("deletes the network policy", func() {
+			Expect(k8sClient.CreateCallCount()).To(BeZero())
+			Expect(k8sClient.DeleteCallCount()).To(Equal(1))
+
+			_, namespace, name := k8sClient.DeleteArgsForCall(0)
+			Expect(namespace).To(Equal("namespace"))
+			Expect(name).To(Equal("name"))
+		})
+
+		When("the network policy does not exist", func() {
+			BeforeEach(func() {
+				k8sClient.DeleteReturns(k8serrors.NewNotFound(schema.GroupResource{}, "netpol"))
+			})
+
+			It("succeeds", func() {
+				Expect(updateErr).NotTo(HaveOccurred())
+			})
+		})
+
+		When("deleting fails", func() {
+			BeforeEach(func() {
+				k8sClient.DeleteReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(updateErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//counterfeiter:generate . ConfigMapCreator
+
+type ConfigMapCreator interface {
+	Create(ctx context.Context, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error)
+}
+
+// Outbox is a JSON client that stores the requests instead of sending them.
+type Outbox struct {
+	configMaps ConfigMapCreator
+	namespace  string
+}
+
+func NewOutbox(configMaps ConfigMapCreator, namespace string) *Outbox {
+	return &Outbox{
+		configMaps: configMaps,
+		namespace:  namespace,
+	}
+}
+
+func (o *Outbox) Post(ctx context.Context, url string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal callback payload")
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: configMapGenerateName,
+			Labels: map[string]string{
+				LabelSourceType: SourceType,
+			},
+		},
+		Data: map[string]string{
+			KeyURL:     url,
+			KeyPayload: string(payload),
+		},
+	}
+
+	_, err = o.configMaps.Create(ctx, o.namespace, configMap)
+
+	return errors.Wrap(err, "failed to store callback")
+}
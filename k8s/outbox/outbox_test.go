@@ -0,0 +1,65 @@
+package outbox_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/eirini/k8s/outbox"
+	"code.cloudfoundry.org/eirini/k8s/outbox/outboxfakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outbox", func() {
+	var (
+		configMaps *outboxfakes.FakeConfigMapCreator
+		postErr    error
+		data       interface{}
+	)
+
+	BeforeEach(func() {
+		configMaps = new(outboxfakes.FakeConfigMapCreator)
+		data = map[string]string{"task_guid": "the-guid"}
+	})
+
+	JustBeforeEach(func() {
+		postErr = outbox.NewOutbox(configMaps, "the-namespace").Post(ctx, "http://cc/callback", data)
+	})
+
+	It("succeeds", func() {
+		Expect(postErr).NotTo(HaveOccurred())
+	})
+
+	It("stores the callback in a config map", func() {
+		Expect(configMaps.CreateCallCount()).To(Equal(1))
+		_, namespace, configMap := configMaps.CreateArgsForCall(0)
+		Expect(namespace).To(Equal("the-namespace"))
+		Expect(configMap.GenerateName).To(Equal("cc-callback-"))
+		Expect(configMap.Labels).To(HaveKeyWithValue(outbox.LabelSourceType, outbox.SourceType))
+		Expect(configMap.Data).To(HaveKeyWithValue(outbox.KeyURL, "http://cc/callback"))
+		Expect(configMap.Data[outbox.KeyPayload]).To(MatchJSON(`{"task_guid":"the-guid"}`))
+	})
+
+	When("the data cannot be marshalled", func() {
+		BeforeEach(func() {
+			data = make(chan int)
+		})
+
+		It("returns an error", func() {
+			Expect(postErr).To(MatchError(ContainSubstring("failed to marshal callback payload")))
+		})
+
+		It("does not store the callback", func() {
+			Expect(configMaps.CreateCallCount()).To(BeZero())
+		})
+	})
+
+	When("storing the callback fails", func() {
+		BeforeEach(func() {
+			configMaps.CreateReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(postErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+})
@@ -0,0 +1,19 @@
+// Package outbox persists the callbacks to the cloud controller as config
+// maps, so that they are not lost when the process sending them restarts.
+// The config maps are delivered and deleted by the callback reconciler.
+package outbox
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+const (
+	LabelSourceType = "cloudfoundry.org/source_type"
+	SourceType      = "CALLBACK"
+
+	AnnotationAttempts    = "cloudfoundry.org/callback_attempts"
+	AnnotationNextAttempt = "cloudfoundry.org/callback_next_attempt"
+
+	KeyURL     = "url"
+	KeyPayload = "payload"
+
+	configMapGenerateName = "cc-callback-"
+)
@@ -6,23 +6,23 @@ import (
 	"sync"
 
 	"code.cloudfoundry.org/eirini/k8s/pdb"
-	"k8s.io/api/policy/v1beta1"
+	v1 "k8s.io/api/policy/v1"
 )
 
 type FakeK8sClient struct {
-	CreateStub        func(context.Context, string, *v1beta1.PodDisruptionBudget) (*v1beta1.PodDisruptionBudget, error)
+	CreateStub        func(context.Context, string, *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
-		arg3 *v1beta1.PodDisruptionBudget
+		arg3 *v1.PodDisruptionBudget
 	}
 	createReturns struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}
 	createReturnsOnCall map[int]struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}
 	DeleteStub        func(context.Context, string, string) error
@@ -38,17 +38,47 @@ type FakeK8sClient struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	GetStub        func(context.Context, string, string) (*v1.PodDisruptionBudget, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getReturns struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}
+	UpdateStub        func(context.Context, string, *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error)
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1.PodDisruptionBudget
+	}
+	updateReturns struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeK8sClient) Create(arg1 context.Context, arg2 string, arg3 *v1beta1.PodDisruptionBudget) (*v1beta1.PodDisruptionBudget, error) {
+func (fake *FakeK8sClient) Create(arg1 context.Context, arg2 string, arg3 *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-		arg3 *v1beta1.PodDisruptionBudget
+		arg3 *v1.PodDisruptionBudget
 	}{arg1, arg2, arg3})
 	stub := fake.CreateStub
 	fakeReturns := fake.createReturns
@@ -69,41 +99,41 @@ func (fake *FakeK8sClient) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *FakeK8sClient) CreateCalls(stub func(context.Context, string, *v1beta1.PodDisruptionBudget) (*v1beta1.PodDisruptionBudget, error)) {
+func (fake *FakeK8sClient) CreateCalls(stub func(context.Context, string, *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error)) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = stub
 }
 
-func (fake *FakeK8sClient) CreateArgsForCall(i int) (context.Context, string, *v1beta1.PodDisruptionBudget) {
+func (fake *FakeK8sClient) CreateArgsForCall(i int) (context.Context, string, *v1.PodDisruptionBudget) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	argsForCall := fake.createArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeK8sClient) CreateReturns(result1 *v1beta1.PodDisruptionBudget, result2 error) {
+func (fake *FakeK8sClient) CreateReturns(result1 *v1.PodDisruptionBudget, result2 error) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = nil
 	fake.createReturns = struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeK8sClient) CreateReturnsOnCall(i int, result1 *v1beta1.PodDisruptionBudget, result2 error) {
+func (fake *FakeK8sClient) CreateReturnsOnCall(i int, result1 *v1.PodDisruptionBudget, result2 error) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = nil
 	if fake.createReturnsOnCall == nil {
 		fake.createReturnsOnCall = make(map[int]struct {
-			result1 *v1beta1.PodDisruptionBudget
+			result1 *v1.PodDisruptionBudget
 			result2 error
 		})
 	}
 	fake.createReturnsOnCall[i] = struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}{result1, result2}
 }
@@ -171,6 +201,138 @@ func (fake *FakeK8sClient) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeK8sClient) Get(arg1 context.Context, arg2 string, arg3 string) (*v1.PodDisruptionBudget, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{arg1, arg2, arg3})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeK8sClient) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeK8sClient) GetCalls(stub func(context.Context, string, string) (*v1.PodDisruptionBudget, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakeK8sClient) GetArgsForCall(i int) (context.Context, string, string) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeK8sClient) GetReturns(result1 *v1.PodDisruptionBudget, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeK8sClient) GetReturnsOnCall(i int, result1 *v1.PodDisruptionBudget, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 *v1.PodDisruptionBudget
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeK8sClient) Update(arg1 context.Context, arg2 string, arg3 *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error) {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1.PodDisruptionBudget
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1, arg2, arg3})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeK8sClient) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *FakeK8sClient) UpdateCalls(stub func(context.Context, string, *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error)) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *FakeK8sClient) UpdateArgsForCall(i int) (context.Context, string, *v1.PodDisruptionBudget) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeK8sClient) UpdateReturns(result1 *v1.PodDisruptionBudget, result2 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeK8sClient) UpdateReturnsOnCall(i int, result1 *v1.PodDisruptionBudget, result2 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 *v1.PodDisruptionBudget
+			result2 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeK8sClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -178,6 +340,10 @@ func (fake *FakeK8sClient) Invocations() map[string][][]interface{} {
 	defer fake.createMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
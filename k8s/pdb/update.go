@@ -2,12 +2,15 @@ package pdb
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s/stset"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
-	"k8s.io/api/policy/v1beta1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -18,34 +21,117 @@ import (
 //counterfeiter:generate . K8sClient
 
 type K8sClient interface {
-	Create(ctx context.Context, namespace string, podDisruptionBudget *v1beta1.PodDisruptionBudget) (*v1beta1.PodDisruptionBudget, error)
+	Get(ctx context.Context, namespace, name string) (*policyv1.PodDisruptionBudget, error)
+	Create(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) (*policyv1.PodDisruptionBudget, error)
+	Update(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) (*policyv1.PodDisruptionBudget, error)
 	Delete(ctx context.Context, namespace string, name string) error
 }
 
-const PdbMinAvailableInstances = "50%"
+const (
+	PdbMinAvailableInstances = "50%"
+
+	AnnotationMinAvailable   = "cloudfoundry.org/min_available"
+	AnnotationMaxUnavailable = "cloudfoundry.org/max_unavailable"
+)
 
 type Updater struct {
-	pdbClient K8sClient
+	pdbClient           K8sClient
+	defaultMinAvailable string
 }
 
-func NewUpdater(pdbClient K8sClient) *Updater {
+// NewUpdater returns an Updater protecting apps with a pod disruption budget
+// of defaultMinAvailable instances, either a number or a percentage. An empty
+// value defaults to PdbMinAvailableInstances.
+func NewUpdater(pdbClient K8sClient, defaultMinAvailable string) *Updater {
+	if defaultMinAvailable == "" {
+		defaultMinAvailable = PdbMinAvailableInstances
+	}
+
 	return &Updater{
-		pdbClient: pdbClient,
+		pdbClient:           pdbClient,
+		defaultMinAvailable: defaultMinAvailable,
 	}
 }
 
+// ValidateMinAvailable checks the configured default min available value.
+func ValidateMinAvailable(minAvailable string) error {
+	if minAvailable == "" {
+		return nil
+	}
+
+	_, err := parseBudget("min available", minAvailable)
+
+	return err
+}
+
+// Update creates or updates the pod disruption budget of an app with more
+// than one instance, and deletes it otherwise. Apps can set their own budget
+// through the min_available or max_unavailable annotations, in which case a
+// budget is kept even for a single instance.
 func (c *Updater) Update(ctx context.Context, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
-	if lrp.TargetInstances > 1 {
-		return c.createPDB(ctx, statefulSet, lrp)
+	spec, overridden, err := c.budgetSpec(lrp)
+	if err != nil {
+		return errors.Wrap(err, "invalid pod disruption budget")
+	}
+
+	if lrp.TargetInstances > 1 || overridden {
+		return c.createOrUpdatePDB(ctx, statefulSet, lrp, spec)
 	}
 
 	return c.deletePDB(ctx, statefulSet)
 }
 
-func (c *Updater) createPDB(ctx context.Context, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
-	minAvailable := intstr.FromString(PdbMinAvailableInstances)
+func (c *Updater) budgetSpec(lrp *api.LRP) (policyv1.PodDisruptionBudgetSpec, bool, error) {
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector: stset.StatefulSetLabelSelector(lrp),
+	}
+
+	minAvailable, hasMinAvailable := lrp.UserDefinedAnnotations[AnnotationMinAvailable]
+	maxUnavailable, hasMaxUnavailable := lrp.UserDefinedAnnotations[AnnotationMaxUnavailable]
+
+	switch {
+	case hasMinAvailable && hasMaxUnavailable:
+		return policyv1.PodDisruptionBudgetSpec{}, false, fmt.Errorf("only one of %s and %s can be set", AnnotationMinAvailable, AnnotationMaxUnavailable)
+	case hasMaxUnavailable:
+		budget, err := parseBudget(AnnotationMaxUnavailable, maxUnavailable)
+		spec.MaxUnavailable = &budget
+
+		return spec, true, err
+	case hasMinAvailable:
+		budget, err := parseBudget(AnnotationMinAvailable, minAvailable)
+		spec.MinAvailable = &budget
+
+		return spec, true, err
+	default:
+		budget, err := parseBudget("min available", c.defaultMinAvailable)
+		spec.MinAvailable = &budget
 
-	pdb := &v1beta1.PodDisruptionBudget{
+		return spec, false, err
+	}
+}
+
+// parseBudget accepts a non-negative number of instances or a percentage
+// between 0% and 100%.
+func parseBudget(setting, value string) (intstr.IntOrString, error) {
+	if percentage := strings.TrimSuffix(value, "%"); percentage != value {
+		number, err := strconv.Atoi(percentage)
+		if err != nil || number < 0 || number > 100 {
+			return intstr.IntOrString{}, fmt.Errorf("invalid %s %q: must be a percentage between 0%% and 100%%", setting, value)
+		}
+
+		return intstr.FromString(value), nil
+	}
+
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || number < 0 {
+		return intstr.IntOrString{}, fmt.Errorf("invalid %s %q: must be a non-negative number or a percentage", setting, value)
+	}
+
+	return intstr.FromInt(int(number)), nil
+}
+
+func (c *Updater) createOrUpdatePDB(ctx context.Context, statefulSet *appsv1.StatefulSet, lrp *api.LRP, spec policyv1.PodDisruptionBudgetSpec) error {
+	pdb := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      statefulSet.Name,
 			Namespace: statefulSet.Namespace,
@@ -54,10 +140,7 @@ func (c *Updater) createPDB(ctx context.Context, statefulSet *appsv1.StatefulSet
 				stset.LabelVersion: lrp.Version,
 			},
 		},
-		Spec: v1beta1.PodDisruptionBudgetSpec{
-			MinAvailable: &minAvailable,
-			Selector:     stset.StatefulSetLabelSelector(lrp),
-		},
+		Spec: spec,
 	}
 
 	if err := controllerutil.SetOwnerReference(statefulSet, pdb, scheme.Scheme); err != nil {
@@ -65,14 +148,41 @@ func (c *Updater) createPDB(ctx context.Context, statefulSet *appsv1.StatefulSet
 	}
 
 	_, err := c.pdbClient.Create(ctx, statefulSet.Namespace, pdb)
-
 	if k8serrors.IsAlreadyExists(err) {
-		return nil
+		return c.updatePDB(ctx, pdb)
 	}
 
 	return errors.Wrap(err, "failed to create pod distruption budget")
 }
 
+func (c *Updater) updatePDB(ctx context.Context, pdb *policyv1.PodDisruptionBudget) error {
+	existing, err := c.pdbClient.Get(ctx, pdb.Namespace, pdb.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pod distruption budget")
+	}
+
+	if budgetsEqual(existing.Spec.MinAvailable, pdb.Spec.MinAvailable) &&
+		budgetsEqual(existing.Spec.MaxUnavailable, pdb.Spec.MaxUnavailable) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Spec.MinAvailable = pdb.Spec.MinAvailable
+	updated.Spec.MaxUnavailable = pdb.Spec.MaxUnavailable
+
+	_, err = c.pdbClient.Update(ctx, pdb.Namespace, updated)
+
+	return errors.Wrap(err, "failed to update pod distruption budget")
+}
+
+func budgetsEqual(a, b *intstr.IntOrString) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
 func (c *Updater) deletePDB(ctx context.Context, statefulSet *appsv1.StatefulSet) error {
 	err := c.pdbClient.Delete(ctx, statefulSet.Namespace, statefulSet.Name)
 
@@ -13,6 +13,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -30,7 +31,7 @@ var _ = Describe("PDB", func() {
 
 	BeforeEach(func() {
 		k8sClient = new(pdbfakes.FakeK8sClient)
-		creator = pdb.NewUpdater(k8sClient)
+		creator = pdb.NewUpdater(k8sClient, "")
 
 		stSet = &appsv1.StatefulSet{
 			ObjectMeta: v1.ObjectMeta{
@@ -120,14 +121,159 @@ var _ = Describe("PDB", func() {
 			})
 		})
 
+		When("a default min available is configured", func() {
+			BeforeEach(func() {
+				creator = pdb.NewUpdater(k8sClient, "2")
+			})
+
+			It("uses it", func() {
+				_, _, pdb := k8sClient.CreateArgsForCall(0)
+				Expect(pdb.Spec.MinAvailable).To(PointTo(Equal(intstr.FromInt(2))))
+				Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+			})
+		})
+
+		When("the app overrides min available", func() {
+			BeforeEach(func() {
+				lrp.UserDefinedAnnotations = map[string]string{pdb.AnnotationMinAvailable: "75%"}
+			})
+
+			It("uses the app's value", func() {
+				_, _, pdb := k8sClient.CreateArgsForCall(0)
+				Expect(pdb.Spec.MinAvailable).To(PointTo(Equal(intstr.FromString("75%"))))
+				Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+			})
+
+			When("the LRP has a single instance", func() {
+				BeforeEach(func() {
+					lrp.TargetInstances = 1
+				})
+
+				It("still creates the pod disruption budget", func() {
+					Expect(k8sClient.CreateCallCount()).To(Equal(1))
+					Expect(k8sClient.DeleteCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		When("the app overrides max unavailable", func() {
+			BeforeEach(func() {
+				lrp.UserDefinedAnnotations = map[string]string{pdb.AnnotationMaxUnavailable: "1"}
+			})
+
+			It("sets max unavailable instead of min available", func() {
+				_, _, pdb := k8sClient.CreateArgsForCall(0)
+				Expect(pdb.Spec.MaxUnavailable).To(PointTo(Equal(intstr.FromInt(1))))
+				Expect(pdb.Spec.MinAvailable).To(BeNil())
+			})
+		})
+
+		When("the app sets both min available and max unavailable", func() {
+			BeforeEach(func() {
+				lrp.UserDefinedAnnotations = map[string]string{
+					pdb.AnnotationMinAvailable:   "1",
+					pdb.AnnotationMaxUnavailable: "1",
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(updateErr).To(MatchError(ContainSubstring("only one of")))
+				Expect(k8sClient.CreateCallCount()).To(BeZero())
+			})
+		})
+
+		When("the app sets an invalid budget", func() {
+			BeforeEach(func() {
+				lrp.UserDefinedAnnotations = map[string]string{pdb.AnnotationMinAvailable: "150%"}
+			})
+
+			It("returns an error", func() {
+				Expect(updateErr).To(MatchError(ContainSubstring(`invalid cloudfoundry.org/min_available "150%"`)))
+				Expect(k8sClient.CreateCallCount()).To(BeZero())
+			})
+		})
+
 		When("the pod distruption budget already exists", func() {
+			var existing *policyv1.PodDisruptionBudget
+
 			BeforeEach(func() {
 				k8sClient.CreateReturns(nil, k8serrors.NewAlreadyExists(schema.GroupResource{}, "boom"))
+
+				half := intstr.FromString("50%")
+				existing = &policyv1.PodDisruptionBudget{
+					ObjectMeta: v1.ObjectMeta{Name: "name", Namespace: "namespace", ResourceVersion: "42"},
+					Spec:       policyv1.PodDisruptionBudgetSpec{MinAvailable: &half},
+				}
+				k8sClient.GetReturns(existing, nil)
 			})
 
 			It("succeeds", func() {
 				Expect(updateErr).NotTo(HaveOccurred())
 			})
+
+			It("leaves an unchanged budget alone", func() {
+				Expect(k8sClient.UpdateCallCount()).To(BeZero())
+			})
+
+			When("the budget has changed", func() {
+				BeforeEach(func() {
+					lrp.UserDefinedAnnotations = map[string]string{pdb.AnnotationMaxUnavailable: "1"}
+				})
+
+				It("updates the existing pod disruption budget", func() {
+					Expect(k8sClient.GetCallCount()).To(Equal(1))
+					_, getNamespace, getName := k8sClient.GetArgsForCall(0)
+					Expect(getNamespace).To(Equal("namespace"))
+					Expect(getName).To(Equal("name"))
+
+					Expect(k8sClient.UpdateCallCount()).To(Equal(1))
+					_, updateNamespace, updated := k8sClient.UpdateArgsForCall(0)
+					Expect(updateNamespace).To(Equal("namespace"))
+					Expect(updated.ResourceVersion).To(Equal("42"))
+					Expect(updated.Spec.MinAvailable).To(BeNil())
+					Expect(updated.Spec.MaxUnavailable).To(PointTo(Equal(intstr.FromInt(1))))
+				})
+
+				When("updating fails", func() {
+					BeforeEach(func() {
+						k8sClient.UpdateReturns(nil, errors.New("nope"))
+					})
+
+					It("returns an error", func() {
+						Expect(updateErr).To(MatchError(ContainSubstring("nope")))
+					})
+				})
+			})
+
+			When("getting the existing budget fails", func() {
+				BeforeEach(func() {
+					k8sClient.GetReturns(nil, errors.New("gone"))
+				})
+
+				It("returns an error", func() {
+					Expect(updateErr).To(MatchError(ContainSubstring("gone")))
+				})
+			})
 		})
 	})
 })
+
+var _ = Describe("ValidateMinAvailable", func() {
+	DescribeTable("valid values",
+		func(value string) {
+			Expect(pdb.ValidateMinAvailable(value)).To(Succeed())
+		},
+		Entry("unset", ""),
+		Entry("a number", "1"),
+		Entry("a percentage", "50%"),
+	)
+
+	DescribeTable("invalid values",
+		func(value string) {
+			Expect(pdb.ValidateMinAvailable(value)).To(MatchError(ContainSubstring("invalid min available")))
+		},
+		Entry("a negative number", "-1"),
+		Entry("a percentage over 100", "101%"),
+		Entry("garbage", "half"),
+	)
+})
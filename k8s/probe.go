@@ -7,65 +7,97 @@ import (
 )
 
 const (
-	livenessFailureThreshold  = 4
-	readinessFailureThreshold = 1
+	livenessFailureThreshold    = 4
+	readinessFailureThreshold   = 1
+	defaultStartupPeriodSeconds = 2
 )
 
-func CreateLivenessProbe(lrp *api.LRP) *v1.Probe {
-	initialDelay := toSeconds(lrp.Health.TimeoutMs)
+// CreateStartupProbe returns a probe that gives the app up to its start
+// timeout to become healthy before liveness checking kicks in.
+func CreateStartupProbe(lrp *api.LRP) *v1.Probe {
+	if lrp.Health.StartTimeoutMs == 0 {
+		return nil
+	}
 
-	if lrp.Health.Type == "http" {
-		return createHTTPProbe(lrp, initialDelay, livenessFailureThreshold)
-	} else if lrp.Health.Type == "port" {
-		return createPortProbe(lrp, initialDelay, livenessFailureThreshold)
+	probe := createProbe(lrp.Health)
+	if probe == nil {
+		return nil
 	}
 
-	return nil
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = defaultStartupPeriodSeconds
+	}
+
+	probe.FailureThreshold = ceilDiv(toSecondsCeil(lrp.Health.StartTimeoutMs), probe.PeriodSeconds)
+
+	return probe
 }
 
-func CreateReadinessProbe(lrp *api.LRP) *v1.Probe {
-	if lrp.Health.Type == "http" {
-		return createHTTPProbe(lrp, 0, readinessFailureThreshold)
-	} else if lrp.Health.Type == "port" {
-		return createPortProbe(lrp, 0, readinessFailureThreshold)
+func CreateLivenessProbe(lrp *api.LRP) *v1.Probe {
+	probe := createProbe(lrp.Health)
+	if probe == nil {
+		return nil
 	}
 
-	return nil
+	probe.FailureThreshold = livenessFailureThreshold
+
+	return probe
 }
 
-func createPortProbe(lrp *api.LRP, initialDelay, failureThreshold int32) *v1.Probe {
-	return &v1.Probe{
-		ProbeHandler: v1.ProbeHandler{
-			TCPSocket: tcpSocketAction(lrp),
-		},
-		InitialDelaySeconds: initialDelay,
-		FailureThreshold:    failureThreshold,
+// CreateReadinessProbe uses the dedicated readiness health check of the LRP
+// and falls back to its liveness health check when none is configured.
+func CreateReadinessProbe(lrp *api.LRP) *v1.Probe {
+	healthcheck := lrp.Readiness
+	if healthcheck.Type == "" {
+		healthcheck = lrp.Health
 	}
+
+	probe := createProbe(healthcheck)
+	if probe == nil {
+		return nil
+	}
+
+	probe.FailureThreshold = readinessFailureThreshold
+
+	return probe
 }
 
-func createHTTPProbe(lrp *api.LRP, initialDelay, failureThreshold int32) *v1.Probe {
+func createProbe(healthcheck api.Healthcheck) *v1.Probe {
+	var handler v1.ProbeHandler
+
+	switch healthcheck.Type {
+	case api.HealthCheckTypeHTTP:
+		handler.HTTPGet = httpGetAction(healthcheck)
+	case api.HealthCheckTypePort:
+		handler.TCPSocket = tcpSocketAction(healthcheck)
+	default:
+		return nil
+	}
+
 	return &v1.Probe{
-		ProbeHandler: v1.ProbeHandler{
-			HTTPGet: httpGetAction(lrp),
-		},
-		InitialDelaySeconds: initialDelay,
-		FailureThreshold:    failureThreshold,
+		ProbeHandler:   handler,
+		TimeoutSeconds: toSecondsCeil(healthcheck.InvocationTimeoutMs),
+		PeriodSeconds:  toSecondsCeil(healthcheck.IntervalMs),
 	}
 }
 
-func httpGetAction(lrp *api.LRP) *v1.HTTPGetAction {
+func httpGetAction(healthcheck api.Healthcheck) *v1.HTTPGetAction {
 	return &v1.HTTPGetAction{
-		Path: lrp.Health.Endpoint,
-		Port: intstr.IntOrString{Type: intstr.Int, IntVal: lrp.Health.Port},
+		Path: healthcheck.Endpoint,
+		Port: intstr.IntOrString{Type: intstr.Int, IntVal: healthcheck.Port},
 	}
 }
 
-func tcpSocketAction(lrp *api.LRP) *v1.TCPSocketAction {
+func tcpSocketAction(healthcheck api.Healthcheck) *v1.TCPSocketAction {
 	return &v1.TCPSocketAction{
-		Port: intstr.IntOrString{Type: intstr.Int, IntVal: lrp.Health.Port},
+		Port: intstr.IntOrString{Type: intstr.Int, IntVal: healthcheck.Port},
 	}
 }
 
-func toSeconds(millis uint) int32 {
-	return int32(millis / 1000) //nolint:gomnd
+func toSecondsCeil(millis uint) int32 {
+	return int32((millis + 999) / 1000) //nolint:gomnd
+}
+
+func ceilDiv(a, b int32) int32 {
+	return (a + b - 1) / b
 }
@@ -19,13 +19,95 @@ var _ = Describe("PrrobeCreator", func() {
 	BeforeEach(func() {
 		lrp = &api.LRP{
 			Health: api.Healthcheck{
-				Endpoint:  "/healthz",
-				Port:      8080,
-				TimeoutMs: 3000,
+				Endpoint:            "/healthz",
+				Port:                8080,
+				TimeoutMs:           3000,
+				StartTimeoutMs:      60000,
+				InvocationTimeoutMs: 2000,
+				IntervalMs:          5000,
 			},
 		}
 	})
 
+	Context("StartupProbeCreator", func() {
+		JustBeforeEach(func() {
+			probe = CreateStartupProbe(lrp)
+		})
+
+		Context("When healthcheck type is HTTP", func() {
+			BeforeEach(func() {
+				lrp.Health.Type = "http"
+			})
+
+			It("creates a probe that tolerates failures for the start timeout", func() {
+				Expect(probe).To(Equal(&v1.Probe{
+					ProbeHandler: v1.ProbeHandler{
+						HTTPGet: &v1.HTTPGetAction{
+							Path: "/healthz",
+							Port: intstr.IntOrString{Type: intstr.Int, IntVal: 8080},
+						},
+					},
+					TimeoutSeconds:   2,
+					PeriodSeconds:    5,
+					FailureThreshold: 12,
+				}))
+			})
+		})
+
+		Context("When healthcheck type is Port", func() {
+			BeforeEach(func() {
+				lrp.Health.Type = "port"
+			})
+
+			It("creates a probe with TCPSocket action", func() {
+				Expect(probe).To(Equal(&v1.Probe{
+					ProbeHandler: v1.ProbeHandler{
+						TCPSocket: &v1.TCPSocketAction{
+							Port: intstr.IntOrString{Type: intstr.Int, IntVal: 8080},
+						},
+					},
+					TimeoutSeconds:   2,
+					PeriodSeconds:    5,
+					FailureThreshold: 12,
+				}))
+			})
+		})
+
+		Context("When the interval is not set", func() {
+			BeforeEach(func() {
+				lrp.Health.Type = "port"
+				lrp.Health.IntervalMs = 0
+				lrp.Health.StartTimeoutMs = 7500
+			})
+
+			It("probes every 2 seconds, rounding the threshold up", func() {
+				Expect(probe.PeriodSeconds).To(Equal(int32(2)))
+				Expect(probe.FailureThreshold).To(Equal(int32(4)))
+			})
+		})
+
+		Context("When the start timeout is not set", func() {
+			BeforeEach(func() {
+				lrp.Health.Type = "http"
+				lrp.Health.StartTimeoutMs = 0
+			})
+
+			It("returns nil", func() {
+				Expect(probe).To(BeNil())
+			})
+		})
+
+		Context("When healthcheck type is process", func() {
+			BeforeEach(func() {
+				lrp.Health.Type = "process"
+			})
+
+			It("returns nil", func() {
+				Expect(probe).To(BeNil())
+			})
+		})
+	})
+
 	Context("LivenessProbeCreator", func() {
 		JustBeforeEach(func() {
 			probe = CreateLivenessProbe(lrp)
@@ -44,8 +126,9 @@ var _ = Describe("PrrobeCreator", func() {
 							Port: intstr.IntOrString{Type: intstr.Int, IntVal: 8080},
 						},
 					},
-					InitialDelaySeconds: 3,
-					FailureThreshold:    4,
+					TimeoutSeconds:   2,
+					PeriodSeconds:    5,
+					FailureThreshold: 4,
 				}))
 			})
 		})
@@ -62,20 +145,33 @@ var _ = Describe("PrrobeCreator", func() {
 							Port: intstr.IntOrString{Type: intstr.Int, IntVal: 8080},
 						},
 					},
-					InitialDelaySeconds: 3,
-					FailureThreshold:    4,
+					TimeoutSeconds:   2,
+					PeriodSeconds:    5,
+					FailureThreshold: 4,
 				}))
 			})
 		})
 
-		Context("When timeout is not a whole number", func() {
+		Context("When timeouts are not whole numbers", func() {
 			BeforeEach(func() {
 				lrp.Health.Type = "http"
-				lrp.Health.TimeoutMs = 5700
+				lrp.Health.InvocationTimeoutMs = 500
+				lrp.Health.IntervalMs = 5700
 			})
 
-			It("rounds it down", func() {
-				Expect(probe.InitialDelaySeconds).To(Equal(int32(5)))
+			It("rounds them up", func() {
+				Expect(probe.TimeoutSeconds).To(Equal(int32(1)))
+				Expect(probe.PeriodSeconds).To(Equal(int32(6)))
+			})
+		})
+
+		Context("When healthcheck type is process", func() {
+			BeforeEach(func() {
+				lrp.Health.Type = "process"
+			})
+
+			It("returns nil", func() {
+				Expect(probe).To(BeNil())
 			})
 		})
 
@@ -108,8 +204,9 @@ var _ = Describe("PrrobeCreator", func() {
 							Port: intstr.IntOrString{Type: intstr.Int, IntVal: 8080},
 						},
 					},
-					InitialDelaySeconds: 0,
-					FailureThreshold:    1,
+					TimeoutSeconds:   2,
+					PeriodSeconds:    5,
+					FailureThreshold: 1,
 				}))
 			})
 		})
@@ -126,12 +223,49 @@ var _ = Describe("PrrobeCreator", func() {
 							Port: intstr.IntOrString{Type: intstr.Int, IntVal: 8080},
 						},
 					},
-					InitialDelaySeconds: 0,
-					FailureThreshold:    1,
+					TimeoutSeconds:   2,
+					PeriodSeconds:    5,
+					FailureThreshold: 1,
 				}))
 			})
 		})
 
+		Context("When a dedicated readiness healthcheck is configured", func() {
+			BeforeEach(func() {
+				lrp.Health.Type = "port"
+				lrp.Readiness = api.Healthcheck{
+					Type:       "http",
+					Endpoint:   "/ready",
+					Port:       9090,
+					IntervalMs: 1000,
+				}
+			})
+
+			It("should use it instead of the liveness healthcheck", func() {
+				Expect(probe).To(Equal(&v1.Probe{
+					ProbeHandler: v1.ProbeHandler{
+						HTTPGet: &v1.HTTPGetAction{
+							Path: "/ready",
+							Port: intstr.IntOrString{Type: intstr.Int, IntVal: 9090},
+						},
+					},
+					PeriodSeconds:    1,
+					FailureThreshold: 1,
+				}))
+			})
+		})
+
+		Context("When the readiness healthcheck type is process", func() {
+			BeforeEach(func() {
+				lrp.Health.Type = "http"
+				lrp.Readiness.Type = "process"
+			})
+
+			It("returns nil", func() {
+				Expect(probe).To(BeNil())
+			})
+		})
+
 		Context("When healthcheck information is missing", func() {
 			BeforeEach(func() {
 				lrp = &api.LRP{}
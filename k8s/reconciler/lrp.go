@@ -0,0 +1,435 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/bifrost"
+	"code.cloudfoundry.org/eirini/k8s/netpol"
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	"code.cloudfoundry.org/eirini/models/cf"
+	eiriniv1 "code.cloudfoundry.org/eirini/pkg/apis/eirini/v1"
+	"code.cloudfoundry.org/lager"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// LRPFinalizer keeps an LRP resource around until the workload desired for
+// it has been stopped.
+const LRPFinalizer = "eirini.cloudfoundry.org/lrp-cleanup"
+
+//counterfeiter:generate . LRPWorkloadClient
+//counterfeiter:generate -o reconcilerfakes/fake_controller_runtime_client.go sigs.k8s.io/controller-runtime/pkg/client.Client
+//counterfeiter:generate -o reconcilerfakes/fake_status_writer.go sigs.k8s.io/controller-runtime/pkg/client.StatusWriter
+
+type LRPWorkloadClient interface {
+	Desire(ctx context.Context, namespace string, lrp *api.LRP, opts ...shared.Option) error
+	Get(ctx context.Context, identifier api.LRPIdentifier) (*api.LRP, error)
+	Update(ctx context.Context, lrp *api.LRP) error
+	Stop(ctx context.Context, identifier api.LRPIdentifier) error
+}
+
+// LRP reconciles LRP custom resources into statefulsets, the same way LRPs
+// desired through the REST API are.
+type LRP struct {
+	logger         lager.Logger
+	client         client.Client
+	workloadClient LRPWorkloadClient
+	scheme         *runtime.Scheme
+}
+
+func NewLRP(logger lager.Logger, client client.Client, workloadClient LRPWorkloadClient, scheme *runtime.Scheme) *LRP {
+	return &LRP{
+		logger:         logger,
+		client:         client,
+		workloadClient: workloadClient,
+		scheme:         scheme,
+	}
+}
+
+func (r *LRP) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := r.logger.Session("reconcile-lrp", lager.Data{"namespace": request.Namespace, "name": request.Name})
+
+	lrp := &eiriniv1.LRP{}
+	if err := r.client.Get(ctx, request.NamespacedName, lrp); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("lrp-not-found")
+
+			return reconcile.Result{}, nil
+		}
+
+		logger.Error("failed-to-get-lrp", err)
+
+		return reconcile.Result{}, errors.Wrap(err, "failed to get lrp")
+	}
+
+	if !lrp.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, r.stop(ctx, logger, lrp)
+	}
+
+	if !controllerutil.ContainsFinalizer(lrp, LRPFinalizer) {
+		controllerutil.AddFinalizer(lrp, LRPFinalizer)
+
+		if err := r.client.Update(ctx, lrp); err != nil {
+			logger.Error("failed-to-add-finalizer", err)
+
+			return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+		}
+	}
+
+	return reconcile.Result{}, r.do(ctx, logger, lrp)
+}
+
+func (r *LRP) do(ctx context.Context, logger lager.Logger, lrp *eiriniv1.LRP) error {
+	apiLRP, err := toAPILRP(lrp)
+	if err != nil {
+		logger.Error("failed-to-convert-lrp", err)
+
+		return errors.Wrap(err, "failed to convert lrp")
+	}
+
+	current, err := r.workloadClient.Get(ctx, apiLRP.LRPIdentifier)
+	if errors.Is(err, eirini.ErrNotFound) {
+		if err = r.workloadClient.Desire(ctx, lrp.Namespace, apiLRP, setOwner(lrp, r.scheme)); err != nil {
+			logger.Error("failed-to-desire-lrp", err)
+
+			return errors.Wrap(err, "failed to desire lrp")
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		logger.Error("failed-to-get-lrp-workload", err)
+
+		return errors.Wrap(err, "failed to get lrp workload")
+	}
+
+	if err = r.workloadClient.Update(ctx, apiLRP); err != nil {
+		logger.Error("failed-to-update-lrp", err)
+
+		return errors.Wrap(err, "failed to update lrp")
+	}
+
+	if lrp.Status.Replicas == int32(current.RunningInstances) {
+		return nil
+	}
+
+	lrp.Status.Replicas = int32(current.RunningInstances)
+
+	if err = r.client.Status().Update(ctx, lrp); err != nil {
+		logger.Error("failed-to-update-lrp-status", err)
+
+		return errors.Wrap(err, "failed to update lrp status")
+	}
+
+	return nil
+}
+
+func (r *LRP) stop(ctx context.Context, logger lager.Logger, lrp *eiriniv1.LRP) error {
+	if !controllerutil.ContainsFinalizer(lrp, LRPFinalizer) {
+		return nil
+	}
+
+	if err := r.workloadClient.Stop(ctx, api.LRPIdentifier{GUID: lrp.Spec.GUID, Version: lrp.Spec.Version}); err != nil {
+		logger.Error("failed-to-stop-lrp", err)
+
+		return errors.Wrap(err, "failed to stop lrp")
+	}
+
+	controllerutil.RemoveFinalizer(lrp, LRPFinalizer)
+
+	if err := r.client.Update(ctx, lrp); err != nil {
+		logger.Error("failed-to-remove-finalizer", err)
+
+		return errors.Wrap(err, "failed to remove finalizer")
+	}
+
+	return nil
+}
+
+func toAPILRP(lrp *eiriniv1.LRP) (*api.LRP, error) {
+	spec := lrp.Spec
+
+	egressRules, err := toAPIEgressRules(spec.EgressRules)
+	if err != nil {
+		return nil, err
+	}
+
+	apiLRP := &api.LRP{
+		LRPIdentifier: api.LRPIdentifier{
+			GUID:    spec.GUID,
+			Version: spec.Version,
+		},
+		ProcessType:            spec.ProcessType,
+		AppName:                spec.AppName,
+		AppGUID:                spec.AppGUID,
+		OrgName:                spec.OrgName,
+		OrgGUID:                spec.OrgGUID,
+		SpaceName:              spec.SpaceName,
+		SpaceGUID:              spec.SpaceGUID,
+		Image:                  spec.Image,
+		Command:                spec.Command,
+		Sidecars:               toAPISidecars(spec.Sidecars),
+		PlacementTags:          spec.PlacementTags,
+		PrivateRegistry:        toAPIPrivateRegistry(spec.PrivateRegistry),
+		Env:                    spec.Env,
+		Health:                 toAPIHealthcheck(spec.Health),
+		Readiness:              toAPIHealthcheck(spec.Readiness),
+		Ports:                  spec.Ports,
+		Routes:                 toAPIRoutes(spec.Routes),
+		EgressRules:            egressRules,
+		TargetInstances:        spec.Instances,
+		MemoryMB:               spec.MemoryMB,
+		DiskMB:                 spec.DiskMB,
+		CPUWeight:              spec.CPUWeight,
+		VolumeMounts:           toAPIVolumeMounts(spec.VolumeMounts),
+		LastUpdated:            strconv.FormatInt(lrp.Generation, 10),
+		UserDefinedAnnotations: spec.UserDefinedAnnotations,
+	}
+
+	// the statefulset updater only regenerates the pod template when the
+	// desire request changes, as it does for LRPs desired through the API
+	apiLRP.LRP, err = toDesireRequest(lrp, egressRules)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiLRP, nil
+}
+
+// toDesireRequest returns the desire request that Cloud Controller would
+// have sent for the LRP resource.
+func toDesireRequest(lrp *eiriniv1.LRP, egressRules []json.RawMessage) (string, error) {
+	spec := lrp.Spec
+	identifier := api.LRPIdentifier{GUID: spec.GUID, Version: spec.Version}
+
+	routes, err := toDesireRequestRoutes(spec.Routes)
+	if err != nil {
+		return "", err
+	}
+
+	request := cf.DesireLRPRequest{
+		GUID:                                    spec.GUID,
+		Version:                                 spec.Version,
+		ProcessGUID:                             identifier.ProcessGUID(),
+		ProcessType:                             spec.ProcessType,
+		AppGUID:                                 spec.AppGUID,
+		AppName:                                 spec.AppName,
+		SpaceGUID:                               spec.SpaceGUID,
+		SpaceName:                               spec.SpaceName,
+		OrganizationGUID:                        spec.OrgGUID,
+		OrganizationName:                        spec.OrgName,
+		Namespace:                               lrp.Namespace,
+		PlacementTags:                           spec.PlacementTags,
+		Ports:                                   spec.Ports,
+		Routes:                                  routes,
+		Environment:                             spec.Env,
+		EgressRules:                             egressRules,
+		NumInstances:                            spec.Instances,
+		LastUpdated:                             strconv.FormatInt(lrp.Generation, 10),
+		HealthCheckType:                         spec.Health.Type,
+		HealthCheckHTTPEndpoint:                 spec.Health.Endpoint,
+		HealthCheckTimeoutMs:                    spec.Health.TimeoutMs,
+		HealthCheckInvocationTimeoutMs:          spec.Health.InvocationTimeoutMs,
+		HealthCheckIntervalMs:                   spec.Health.IntervalMs,
+		HealthCheckPort:                         spec.Health.Port,
+		ReadinessHealthCheckType:                spec.Readiness.Type,
+		ReadinessHealthCheckHTTPEndpoint:        spec.Readiness.Endpoint,
+		ReadinessHealthCheckInvocationTimeoutMs: spec.Readiness.InvocationTimeoutMs,
+		ReadinessHealthCheckIntervalMs:          spec.Readiness.IntervalMs,
+		ReadinessHealthCheckPort:                spec.Readiness.Port,
+		StartTimeoutMs:                          spec.Health.StartTimeoutMs,
+		MemoryMB:                                spec.MemoryMB,
+		DiskMB:                                  spec.DiskMB,
+		CPUWeight:                               spec.CPUWeight,
+		VolumeMounts:                            toDesireRequestVolumeMounts(spec.VolumeMounts),
+		Lifecycle: cf.Lifecycle{
+			DockerLifecycle: toDesireRequestDockerLifecycle(spec),
+		},
+		Sidecars:               toDesireRequestSidecars(spec.Sidecars),
+		UserDefinedAnnotations: spec.UserDefinedAnnotations,
+	}
+
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal desire request")
+	}
+
+	return string(requestJSON), nil
+}
+
+func toDesireRequestRoutes(routes eiriniv1.Routes) (map[string]json.RawMessage, error) {
+	httpRoutes := []cf.Route{}
+	for _, r := range routes.HTTP {
+		httpRoutes = append(httpRoutes, cf.Route{Hostname: r.Hostname, Port: r.Port})
+	}
+
+	tcpRoutes := []cf.TCPRoute{}
+	for _, r := range routes.TCP {
+		tcpRoutes = append(tcpRoutes, cf.TCPRoute{ExternalPort: r.ExternalPort, ContainerPort: r.ContainerPort})
+	}
+
+	httpRoutesJSON, err := json.Marshal(httpRoutes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal http routes")
+	}
+
+	tcpRoutesJSON, err := json.Marshal(tcpRoutes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal tcp routes")
+	}
+
+	return map[string]json.RawMessage{
+		bifrost.HTTPRouterKey: httpRoutesJSON,
+		bifrost.TCPRouterKey:  tcpRoutesJSON,
+	}, nil
+}
+
+func toDesireRequestVolumeMounts(mounts []eiriniv1.VolumeMount) []cf.VolumeMount {
+	requestMounts := []cf.VolumeMount{}
+
+	for _, m := range mounts {
+		requestMounts = append(requestMounts, cf.VolumeMount{VolumeID: m.ClaimName, MountDir: m.MountPath})
+	}
+
+	return requestMounts
+}
+
+func toDesireRequestDockerLifecycle(spec eiriniv1.LRPSpec) *cf.DockerLifecycle {
+	lifecycle := &cf.DockerLifecycle{
+		Image:   spec.Image,
+		Command: spec.Command,
+	}
+
+	if spec.PrivateRegistry != nil {
+		lifecycle.RegistryUsername = spec.PrivateRegistry.Username
+		lifecycle.RegistryPassword = spec.PrivateRegistry.Password
+	}
+
+	return lifecycle
+}
+
+// toDesireRequestSidecars leaves out the sidecar env, which desire requests
+// cannot carry; changes to it are still detected through the env checksum.
+func toDesireRequestSidecars(sidecars []eiriniv1.Sidecar) []cf.Sidecar {
+	requestSidecars := []cf.Sidecar{}
+
+	for _, s := range sidecars {
+		requestSidecars = append(requestSidecars, cf.Sidecar{
+			Name:      s.Name,
+			Command:   s.Command,
+			Image:     s.Image,
+			MemoryMB:  s.MemoryMB,
+			CPUWeight: s.CPUWeight,
+		})
+	}
+
+	return requestSidecars
+}
+
+func toAPIHealthcheck(healthcheck eiriniv1.Healthcheck) api.Healthcheck {
+	return api.Healthcheck{
+		Type:                healthcheck.Type,
+		Port:                healthcheck.Port,
+		Endpoint:            healthcheck.Endpoint,
+		TimeoutMs:           healthcheck.TimeoutMs,
+		StartTimeoutMs:      healthcheck.StartTimeoutMs,
+		InvocationTimeoutMs: healthcheck.InvocationTimeoutMs,
+		IntervalMs:          healthcheck.IntervalMs,
+	}
+}
+
+func toAPIEgressRules(rules []eiriniv1.EgressRule) ([]json.RawMessage, error) {
+	apiRules := []json.RawMessage{}
+
+	for _, r := range rules {
+		rule := netpol.EgressRule{
+			Protocol:     r.Protocol,
+			Destinations: r.Destinations,
+			Ports:        r.Ports,
+			Log:          r.Log,
+		}
+
+		if r.PortRange != nil {
+			rule.PortRange = &netpol.PortRange{Start: r.PortRange.Start, End: r.PortRange.End}
+		}
+
+		if r.ICMPInfo != nil {
+			rule.ICMPInfo = &netpol.ICMPInfo{Type: r.ICMPInfo.Type, Code: r.ICMPInfo.Code}
+		}
+
+		ruleJSON, err := json.Marshal(rule)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal egress rule")
+		}
+
+		apiRules = append(apiRules, ruleJSON)
+	}
+
+	if _, err := netpol.ParseEgressRules(apiRules); err != nil {
+		return nil, errors.Wrap(err, "invalid egress rules")
+	}
+
+	return apiRules, nil
+}
+
+func toAPISidecars(sidecars []eiriniv1.Sidecar) []api.Sidecar {
+	apiSidecars := []api.Sidecar{}
+
+	for _, s := range sidecars {
+		apiSidecars = append(apiSidecars, api.Sidecar{
+			Name:      s.Name,
+			Command:   s.Command,
+			Image:     s.Image,
+			MemoryMB:  s.MemoryMB,
+			CPUWeight: s.CPUWeight,
+			Env:       s.Env,
+		})
+	}
+
+	return apiSidecars
+}
+
+func toAPIPrivateRegistry(registry *eiriniv1.PrivateRegistry) *api.PrivateRegistry {
+	if registry == nil {
+		return nil
+	}
+
+	return &api.PrivateRegistry{
+		Server:   registry.Server,
+		Username: registry.Username,
+		Password: registry.Password,
+	}
+}
+
+func toAPIRoutes(routes eiriniv1.Routes) api.Routes {
+	apiRoutes := api.Routes{}
+
+	for _, r := range routes.HTTP {
+		apiRoutes.HTTP = append(apiRoutes.HTTP, api.HTTPRoute{Hostname: r.Hostname, Port: r.Port})
+	}
+
+	for _, r := range routes.TCP {
+		apiRoutes.TCP = append(apiRoutes.TCP, api.TCPRoute{ExternalPort: r.ExternalPort, ContainerPort: r.ContainerPort})
+	}
+
+	return apiRoutes
+}
+
+func toAPIVolumeMounts(mounts []eiriniv1.VolumeMount) []api.VolumeMount {
+	apiMounts := []api.VolumeMount{}
+
+	for _, m := range mounts {
+		apiMounts = append(apiMounts, api.VolumeMount{MountPath: m.MountPath, ClaimName: m.ClaimName})
+	}
+
+	return apiMounts
+}
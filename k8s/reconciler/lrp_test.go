@@ -0,0 +1,456 @@
+package reconciler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s"
+	"code.cloudfoundry.org/eirini/k8s/reconciler"
+	"code.cloudfoundry.org/eirini/k8s/reconciler/reconcilerfakes"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/k8s/stset/stsetfakes"
+	"code.cloudfoundry.org/eirini/models/cf"
+	eiriniv1 "code.cloudfoundry.org/eirini/pkg/apis/eirini/v1"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("LRP", func() {
+	var (
+		ctx            context.Context
+		runtimeClient  *reconcilerfakes.FakeClient
+		statusWriter   *reconcilerfakes.FakeStatusWriter
+		workloadClient *reconcilerfakes.FakeLRPWorkloadClient
+		lrpReconciler  *reconciler.LRP
+		lrp            *eiriniv1.LRP
+		resultErr      error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		runtimeClient = new(reconcilerfakes.FakeClient)
+		statusWriter = new(reconcilerfakes.FakeStatusWriter)
+		runtimeClient.StatusReturns(statusWriter)
+		workloadClient = new(reconcilerfakes.FakeLRPWorkloadClient)
+
+		scheme := runtime.NewScheme()
+		Expect(eiriniv1.AddToScheme(scheme)).To(Succeed())
+
+		lrp = &eiriniv1.LRP{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "my-lrp",
+				Namespace:  "my-namespace",
+				UID:        "my-uid",
+				Generation: 3,
+			},
+			Spec: eiriniv1.LRPSpec{
+				GUID:      "guid-1234",
+				Version:   "version-1234",
+				AppName:   "my-app",
+				SpaceName: "my-space",
+				Image:     "my/image",
+				Command:   []string{"ls"},
+				Env:       map[string]string{"FOO": "BAR"},
+				Health: eiriniv1.Healthcheck{
+					Type:                "http",
+					Port:                8080,
+					Endpoint:            "/healthz",
+					TimeoutMs:           400,
+					InvocationTimeoutMs: 2000,
+					IntervalMs:          5000,
+				},
+				Readiness: eiriniv1.Healthcheck{
+					Type:     "http",
+					Endpoint: "/ready",
+				},
+				Ports: []int32{8080},
+				Routes: eiriniv1.Routes{
+					HTTP: []eiriniv1.HTTPRoute{{Hostname: "app.example.com", Port: 8080}},
+				},
+				EgressRules: []eiriniv1.EgressRule{{
+					Protocol:     "tcp",
+					Destinations: []string{"10.0.0.0/8"},
+					PortRange:    &eiriniv1.PortRange{Start: 80, End: 443},
+				}},
+				Instances: 2,
+				MemoryMB:  256,
+				DiskMB:    512,
+				CPUWeight: 10,
+				PrivateRegistry: &eiriniv1.PrivateRegistry{
+					Server:   "registry.example.com",
+					Username: "admin",
+					Password: "p4ssw0rd",
+				},
+			},
+		}
+
+		runtimeClient.GetStub = func(_ context.Context, _ types.NamespacedName, obj client.Object) error {
+			lrp.DeepCopyInto(obj.(*eiriniv1.LRP))
+
+			return nil
+		}
+
+		workloadClient.GetReturns(nil, eirini.ErrNotFound)
+
+		lrpReconciler = reconciler.NewLRP(tests.NewTestLogger("lrp-reconciler"), runtimeClient, workloadClient, scheme)
+	})
+
+	JustBeforeEach(func() {
+		_, resultErr = lrpReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: "my-namespace", Name: "my-lrp"},
+		})
+	})
+
+	It("fetches the lrp resource", func() {
+		Expect(runtimeClient.GetCallCount()).To(Equal(1))
+		_, namespacedName, _ := runtimeClient.GetArgsForCall(0)
+		Expect(namespacedName).To(Equal(types.NamespacedName{Namespace: "my-namespace", Name: "my-lrp"}))
+	})
+
+	It("adds the cleanup finalizer", func() {
+		Expect(runtimeClient.UpdateCallCount()).To(Equal(1))
+		_, obj, _ := runtimeClient.UpdateArgsForCall(0)
+		Expect(obj.GetFinalizers()).To(ConsistOf(reconciler.LRPFinalizer))
+	})
+
+	It("desires the lrp", func() {
+		Expect(resultErr).NotTo(HaveOccurred())
+		Expect(workloadClient.DesireCallCount()).To(Equal(1))
+
+		_, namespace, apiLRP, _ := workloadClient.DesireArgsForCall(0)
+		Expect(namespace).To(Equal("my-namespace"))
+		Expect(apiLRP.GUID).To(Equal("guid-1234"))
+		Expect(apiLRP.Version).To(Equal("version-1234"))
+		Expect(apiLRP.AppName).To(Equal("my-app"))
+		Expect(apiLRP.SpaceName).To(Equal("my-space"))
+		Expect(apiLRP.Image).To(Equal("my/image"))
+		Expect(apiLRP.Command).To(ConsistOf("ls"))
+		Expect(apiLRP.Env).To(Equal(map[string]string{"FOO": "BAR"}))
+		Expect(apiLRP.Health).To(Equal(api.Healthcheck{Type: "http", Port: 8080, Endpoint: "/healthz", TimeoutMs: 400, InvocationTimeoutMs: 2000, IntervalMs: 5000}))
+		Expect(apiLRP.Readiness).To(Equal(api.Healthcheck{Type: "http", Endpoint: "/ready"}))
+		Expect(apiLRP.Ports).To(ConsistOf(int32(8080)))
+		Expect(apiLRP.Routes.HTTP).To(ConsistOf(api.HTTPRoute{Hostname: "app.example.com", Port: 8080}))
+		Expect(apiLRP.EgressRules).To(HaveLen(1))
+		Expect(apiLRP.EgressRules[0]).To(MatchJSON(`{"protocol":"tcp","destinations":["10.0.0.0/8"],"port_range":{"start":80,"end":443}}`))
+		Expect(apiLRP.TargetInstances).To(Equal(2))
+		Expect(apiLRP.MemoryMB).To(Equal(int64(256)))
+		Expect(apiLRP.DiskMB).To(Equal(int64(512)))
+		Expect(apiLRP.CPUWeight).To(Equal(uint8(10)))
+		Expect(apiLRP.LastUpdated).To(Equal("3"))
+		Expect(apiLRP.PrivateRegistry).To(Equal(&api.PrivateRegistry{
+			Server:   "registry.example.com",
+			Username: "admin",
+			Password: "p4ssw0rd",
+		}))
+	})
+
+	It("desires the lrp with the desire request of the lrp resource", func() {
+		_, _, apiLRP, _ := workloadClient.DesireArgsForCall(0)
+
+		var request cf.DesireLRPRequest
+		Expect(json.Unmarshal([]byte(apiLRP.LRP), &request)).To(Succeed())
+		Expect(request.ProcessGUID).To(Equal("guid-1234-version-1234"))
+		Expect(request.Namespace).To(Equal("my-namespace"))
+		Expect(request.Environment).To(Equal(map[string]string{"FOO": "BAR"}))
+		Expect(request.NumInstances).To(Equal(2))
+		Expect(request.MemoryMB).To(Equal(int64(256)))
+		Expect(request.HealthCheckType).To(Equal("http"))
+		Expect(request.HealthCheckInvocationTimeoutMs).To(Equal(uint(2000)))
+		Expect(request.ReadinessHealthCheckHTTPEndpoint).To(Equal("/ready"))
+		Expect(request.Routes).To(HaveKeyWithValue("cf-router", MatchJSON(`[{"hostname":"app.example.com","port":8080}]`)))
+		Expect(request.EgressRules).To(Equal(apiLRP.EgressRules))
+		Expect(request.Lifecycle.DockerLifecycle).To(Equal(&cf.DockerLifecycle{
+			Image:            "my/image",
+			Command:          []string{"ls"},
+			RegistryUsername: "admin",
+			RegistryPassword: "p4ssw0rd",
+		}))
+	})
+
+	When("the egress rules are not valid", func() {
+		BeforeEach(func() {
+			lrp.Spec.EgressRules[0].Protocol = "carrier-pigeon"
+		})
+
+		It("returns an error", func() {
+			Expect(resultErr).To(MatchError(ContainSubstring("invalid egress rules")))
+		})
+
+		It("does not desire the lrp", func() {
+			Expect(workloadClient.DesireCallCount()).To(BeZero())
+		})
+	})
+
+	It("makes the lrp resource the controller of the statefulset", func() {
+		_, _, _, opts := workloadClient.DesireArgsForCall(0)
+		Expect(opts).To(HaveLen(1))
+
+		statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "my-namespace"}}
+		Expect(opts[0](statefulSet)).To(Succeed())
+
+		Expect(statefulSet.OwnerReferences).To(HaveLen(1))
+		ownerRef := statefulSet.OwnerReferences[0]
+		Expect(ownerRef.Kind).To(Equal("LRP"))
+		Expect(ownerRef.APIVersion).To(Equal("eirini.cloudfoundry.org/v1"))
+		Expect(ownerRef.Name).To(Equal("my-lrp"))
+		Expect(ownerRef.UID).To(Equal(types.UID("my-uid")))
+		Expect(*ownerRef.Controller).To(BeTrue())
+	})
+
+	It("does not update the lrp", func() {
+		Expect(workloadClient.UpdateCallCount()).To(Equal(0))
+	})
+
+	When("the lrp resource already has the finalizer", func() {
+		BeforeEach(func() {
+			lrp.Finalizers = []string{reconciler.LRPFinalizer}
+		})
+
+		It("does not update the lrp resource", func() {
+			Expect(runtimeClient.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the lrp resource does not exist", func() {
+		BeforeEach(func() {
+			runtimeClient.GetReturns(apierrors.NewNotFound(schema.GroupResource{}, "my-lrp"))
+			runtimeClient.GetStub = nil
+		})
+
+		It("does nothing", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(workloadClient.DesireCallCount()).To(Equal(0))
+			Expect(workloadClient.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	When("getting the lrp resource fails", func() {
+		BeforeEach(func() {
+			runtimeClient.GetReturns(errors.New("boom"))
+			runtimeClient.GetStub = nil
+		})
+
+		It("returns an error", func() {
+			Expect(resultErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	When("adding the finalizer fails", func() {
+		BeforeEach(func() {
+			runtimeClient.UpdateReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(resultErr).To(MatchError(ContainSubstring("failed to add finalizer")))
+		})
+
+		It("does not desire the lrp", func() {
+			Expect(workloadClient.DesireCallCount()).To(Equal(0))
+		})
+	})
+
+	When("desiring the lrp fails", func() {
+		BeforeEach(func() {
+			workloadClient.DesireReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(resultErr).To(MatchError(ContainSubstring("failed to desire lrp")))
+		})
+	})
+
+	When("the lrp has already been desired", func() {
+		BeforeEach(func() {
+			workloadClient.GetReturns(&api.LRP{RunningInstances: 1}, nil)
+		})
+
+		It("updates the lrp", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(workloadClient.DesireCallCount()).To(Equal(0))
+			Expect(workloadClient.UpdateCallCount()).To(Equal(1))
+
+			_, apiLRP := workloadClient.UpdateArgsForCall(0)
+			Expect(apiLRP.GUID).To(Equal("guid-1234"))
+			Expect(apiLRP.Version).To(Equal("version-1234"))
+			Expect(apiLRP.TargetInstances).To(Equal(2))
+			Expect(apiLRP.Image).To(Equal("my/image"))
+		})
+
+		It("sets the number of running replicas in the status", func() {
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			_, obj, _ := statusWriter.UpdateArgsForCall(0)
+			Expect(obj.(*eiriniv1.LRP).Status.Replicas).To(Equal(int32(1)))
+		})
+
+		When("the status is up to date", func() {
+			BeforeEach(func() {
+				lrp.Status.Replicas = 1
+			})
+
+			It("does not update the status", func() {
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		When("updating the status fails", func() {
+			BeforeEach(func() {
+				statusWriter.UpdateReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(resultErr).To(MatchError(ContainSubstring("failed to update lrp status")))
+			})
+		})
+
+		When("updating the lrp fails", func() {
+			BeforeEach(func() {
+				workloadClient.UpdateReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(resultErr).To(MatchError(ContainSubstring("failed to update lrp")))
+			})
+		})
+	})
+
+	When("the spec of an existing lrp resource changes", func() {
+		var (
+			statefulSetUpdater *stsetfakes.FakeStatefulSetUpdater
+			envSecrets         *stsetfakes.FakeEnvSecretsClient
+			existing           *appsv1.StatefulSet
+		)
+
+		BeforeEach(func() {
+			converter := stset.NewLRPToStatefulSetConverter("eirini", "", false, false, nil, nil, eirini.SchedulingConfig{}, 0,
+				k8s.CreateLivenessProbe, k8s.CreateReadinessProbe, k8s.CreateStartupProbe)
+
+			_, err := lrpReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "my-namespace", Name: "my-lrp"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(workloadClient.DesireCallCount()).To(Equal(1))
+			_, _, desiredLRP, _ := workloadClient.DesireArgsForCall(0)
+
+			existing, err = converter.Convert("my-lrp-statefulset", desiredLRP, nil)
+			Expect(err).NotTo(HaveOccurred())
+			existing.Namespace = "my-namespace"
+
+			statefulSetGetter := new(stsetfakes.FakeStatefulSetByLRPIdentifierGetter)
+			statefulSetGetter.GetByLRPIdentifierReturns([]appsv1.StatefulSet{*existing}, nil)
+			statefulSetUpdater = new(stsetfakes.FakeStatefulSetUpdater)
+			envSecrets = new(stsetfakes.FakeEnvSecretsClient)
+			envSecrets.GetReturns(stset.GenerateEnvSecret(existing.Name, desiredLRP), nil)
+
+			updater := stset.NewUpdater(
+				tests.NewTestLogger("updater"),
+				statefulSetGetter,
+				statefulSetUpdater,
+				envSecrets,
+				converter,
+				new(stsetfakes.FakePodDisruptionBudgetUpdater),
+				new(stsetfakes.FakeRouteUpdater),
+				new(stsetfakes.FakeNetworkPolicyUpdater),
+				nil,
+			)
+			workloadClient.GetReturns(&api.LRP{}, nil)
+			workloadClient.UpdateStub = updater.Update
+
+			lrp.Generation = 4
+			lrp.Spec.Env = map[string]string{"FOO": "BAZ"}
+			lrp.Spec.MemoryMB = 512
+		})
+
+		It("updates the environment", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(envSecrets.UpdateCallCount()).To(Equal(1))
+			_, _, secret := envSecrets.UpdateArgsForCall(0)
+			Expect(secret.StringData).To(HaveKeyWithValue("opi.FOO", "BAZ"))
+		})
+
+		It("regenerates the pod template", func() {
+			Expect(statefulSetUpdater.UpdateCallCount()).To(Equal(1))
+			_, _, updated := statefulSetUpdater.UpdateArgsForCall(0)
+
+			Expect(updated.Spec.Template.Annotations[stset.AnnotationEnvChecksum]).NotTo(Equal(existing.Spec.Template.Annotations[stset.AnnotationEnvChecksum]))
+			Expect(updated.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().Value()).To(Equal(int64(512 * 1000 * 1000)))
+			Expect(updated.Spec.Template.Spec.Containers[0].Resources).NotTo(Equal(existing.Spec.Template.Spec.Containers[0].Resources))
+		})
+	})
+
+	When("getting the lrp workload fails", func() {
+		BeforeEach(func() {
+			workloadClient.GetReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(resultErr).To(MatchError(ContainSubstring("failed to get lrp workload")))
+		})
+
+		It("neither desires nor updates the lrp", func() {
+			Expect(workloadClient.DesireCallCount()).To(Equal(0))
+			Expect(workloadClient.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the lrp resource is being deleted", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			lrp.DeletionTimestamp = &now
+			lrp.Finalizers = []string{reconciler.LRPFinalizer}
+		})
+
+		It("stops the lrp", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(workloadClient.StopCallCount()).To(Equal(1))
+			_, identifier := workloadClient.StopArgsForCall(0)
+			Expect(identifier).To(Equal(api.LRPIdentifier{GUID: "guid-1234", Version: "version-1234"}))
+		})
+
+		It("removes the finalizer", func() {
+			Expect(runtimeClient.UpdateCallCount()).To(Equal(1))
+			_, obj, _ := runtimeClient.UpdateArgsForCall(0)
+			Expect(obj.GetFinalizers()).To(BeEmpty())
+		})
+
+		It("neither desires nor updates the lrp", func() {
+			Expect(workloadClient.DesireCallCount()).To(Equal(0))
+			Expect(workloadClient.UpdateCallCount()).To(Equal(0))
+		})
+
+		When("stopping the lrp fails", func() {
+			BeforeEach(func() {
+				workloadClient.StopReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(resultErr).To(MatchError(ContainSubstring("failed to stop lrp")))
+			})
+
+			It("keeps the finalizer", func() {
+				Expect(runtimeClient.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the finalizer has already been removed", func() {
+			BeforeEach(func() {
+				lrp.Finalizers = nil
+			})
+
+			It("does not stop the lrp again", func() {
+				Expect(workloadClient.StopCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
@@ -0,0 +1,24 @@
+package reconciler
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// setOwner makes the custom resource the controller of the workload
+// generated for it, so that the workload is garbage collected along with it
+// and changes to the workload trigger a reconciliation of its owner.
+func setOwner(owner metav1.Object, scheme *runtime.Scheme) shared.Option {
+	return func(resource interface{}) error {
+		obj, ok := resource.(metav1.Object)
+		if !ok {
+			return fmt.Errorf("could not set owner of %T: not a kubernetes object", resource)
+		}
+
+		return controllerutil.SetControllerReference(owner, obj, scheme)
+	}
+}
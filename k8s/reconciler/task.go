@@ -0,0 +1,225 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	eiriniv1 "code.cloudfoundry.org/eirini/pkg/apis/eirini/v1"
+	"code.cloudfoundry.org/lager"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//counterfeiter:generate . TaskDesirer
+
+type TaskDesirer interface {
+	Desire(ctx context.Context, namespace string, task *api.Task, opts ...shared.Option) error
+}
+
+// Task runs Task custom resources as jobs, mirrors the state of the job in
+// the status of the resource and deletes the resource once it has been
+// completed for longer than the configured TTL.
+type Task struct {
+	logger     lager.Logger
+	client     client.Client
+	desirer    TaskDesirer
+	scheme     *runtime.Scheme
+	ttlSeconds int
+}
+
+func NewTask(logger lager.Logger, client client.Client, desirer TaskDesirer, scheme *runtime.Scheme, ttlSeconds int) *Task {
+	return &Task{
+		logger:     logger,
+		client:     client,
+		desirer:    desirer,
+		scheme:     scheme,
+		ttlSeconds: ttlSeconds,
+	}
+}
+
+func (t *Task) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := t.logger.Session("reconcile-task", lager.Data{"namespace": request.Namespace, "name": request.Name})
+
+	task := &eiriniv1.Task{}
+	if err := t.client.Get(ctx, request.NamespacedName, task); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("task-not-found")
+
+			return reconcile.Result{}, nil
+		}
+
+		logger.Error("failed-to-get-task", err)
+
+		return reconcile.Result{}, errors.Wrap(err, "failed to get task")
+	}
+
+	jobList := &batchv1.JobList{}
+	if err := t.client.List(ctx, jobList, client.InNamespace(task.Namespace), client.MatchingLabels{jobs.LabelGUID: task.Spec.GUID}); err != nil {
+		logger.Error("failed-to-list-jobs", err)
+
+		return reconcile.Result{}, errors.Wrap(err, "failed to list jobs")
+	}
+
+	if len(jobList.Items) == 0 {
+		return reconcile.Result{}, t.desire(ctx, logger, task)
+	}
+
+	return t.syncStatus(ctx, logger, task, jobList.Items[0])
+}
+
+func (t *Task) desire(ctx context.Context, logger lager.Logger, task *eiriniv1.Task) error {
+	// a task with a status has already been run; it must not be run again
+	// just because its job has gone away
+	if task.Status.ExecutionStatus != "" {
+		logger.Debug("task-already-run")
+
+		return nil
+	}
+
+	// the CRD requires memory and disk, but resources created before it did
+	// would otherwise get jobs without a memory or ephemeral storage limit
+	if err := validateTaskSpec(task.Spec); err != nil {
+		logger.Info("invalid-task", lager.Data{"error": err.Error()})
+
+		return t.markFailed(ctx, logger, task)
+	}
+
+	if err := t.desirer.Desire(ctx, task.Namespace, toAPITask(task), setOwner(task, t.scheme)); err != nil {
+		logger.Error("failed-to-desire-task", err)
+
+		return errors.Wrap(err, "failed to desire task")
+	}
+
+	task.Status.ExecutionStatus = eiriniv1.TaskStarting
+
+	if err := t.client.Status().Update(ctx, task); err != nil {
+		logger.Error("failed-to-update-task-status", err)
+
+		return errors.Wrap(err, "failed to update task status")
+	}
+
+	return nil
+}
+
+func (t *Task) markFailed(ctx context.Context, logger lager.Logger, task *eiriniv1.Task) error {
+	now := metav1.Now()
+	task.Status = eiriniv1.TaskStatus{
+		ExecutionStatus: eiriniv1.TaskFailed,
+		StartTime:       &now,
+		EndTime:         &now,
+	}
+
+	if err := t.client.Status().Update(ctx, task); err != nil {
+		logger.Error("failed-to-update-task-status", err)
+
+		return errors.Wrap(err, "failed to update task status")
+	}
+
+	return nil
+}
+
+func validateTaskSpec(spec eiriniv1.TaskSpec) error {
+	if spec.MemoryMB <= 0 {
+		return errors.New("memoryMB must be greater than 0")
+	}
+
+	if spec.DiskMB <= 0 {
+		return errors.New("diskMB must be greater than 0")
+	}
+
+	return nil
+}
+
+func (t *Task) syncStatus(ctx context.Context, logger lager.Logger, task *eiriniv1.Task, job batchv1.Job) (reconcile.Result, error) {
+	status := toTaskStatus(job)
+
+	if !equality.Semantic.DeepEqual(status, task.Status) {
+		task.Status = status
+
+		if err := t.client.Status().Update(ctx, task); err != nil {
+			logger.Error("failed-to-update-task-status", err)
+
+			return reconcile.Result{}, errors.Wrap(err, "failed to update task status")
+		}
+	}
+
+	if status.EndTime == nil {
+		return reconcile.Result{}, nil
+	}
+
+	expiry := status.EndTime.Add(time.Duration(t.ttlSeconds) * time.Second)
+	if time.Now().Before(expiry) {
+		logger.Debug("task-hasnt-expired-yet", lager.Data{"expiration-time": expiry})
+
+		return reconcile.Result{RequeueAfter: time.Until(expiry)}, nil
+	}
+
+	logger.Debug("deleting-expired-task")
+
+	if err := t.client.Delete(ctx, task); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error("failed-to-delete-task", err)
+
+		return reconcile.Result{}, errors.Wrap(err, "failed to delete task")
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func toTaskStatus(job batchv1.Job) eiriniv1.TaskStatus {
+	status := eiriniv1.TaskStatus{
+		ExecutionStatus: eiriniv1.TaskStarting,
+		StartTime:       job.Status.StartTime,
+	}
+
+	if job.Status.Active > 0 {
+		status.ExecutionStatus = eiriniv1.TaskRunning
+	}
+
+	if job.Status.Succeeded > 0 {
+		status.ExecutionStatus = eiriniv1.TaskSucceeded
+		status.EndTime = job.Status.CompletionTime
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			status.ExecutionStatus = eiriniv1.TaskFailed
+			endTime := condition.LastTransitionTime
+			status.EndTime = &endTime
+		}
+	}
+
+	return status
+}
+
+func toAPITask(task *eiriniv1.Task) *api.Task {
+	spec := task.Spec
+
+	return &api.Task{
+		GUID:            spec.GUID,
+		Name:            spec.Name,
+		Image:           spec.Image,
+		PrivateRegistry: toAPIPrivateRegistry(spec.PrivateRegistry),
+		Env:             spec.Env,
+		Command:         spec.Command,
+		PlacementTags:   spec.PlacementTags,
+		AppName:         spec.AppName,
+		AppGUID:         spec.AppGUID,
+		OrgName:         spec.OrgName,
+		OrgGUID:         spec.OrgGUID,
+		SpaceName:       spec.SpaceName,
+		SpaceGUID:       spec.SpaceGUID,
+		MemoryMB:        spec.MemoryMB,
+		DiskMB:          spec.DiskMB,
+		CPUWeight:       spec.CPUWeight,
+	}
+}
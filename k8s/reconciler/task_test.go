@@ -0,0 +1,376 @@
+package reconciler_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/k8s/reconciler"
+	"code.cloudfoundry.org/eirini/k8s/reconciler/reconcilerfakes"
+	eiriniv1 "code.cloudfoundry.org/eirini/pkg/apis/eirini/v1"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Task", func() {
+	var (
+		ctx            context.Context
+		runtimeClient  *reconcilerfakes.FakeClient
+		statusWriter   *reconcilerfakes.FakeStatusWriter
+		desirer        *reconcilerfakes.FakeTaskDesirer
+		taskReconciler *reconciler.Task
+		task           *eiriniv1.Task
+		taskJobs       []batchv1.Job
+		result         reconcile.Result
+		resultErr      error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		runtimeClient = new(reconcilerfakes.FakeClient)
+		statusWriter = new(reconcilerfakes.FakeStatusWriter)
+		runtimeClient.StatusReturns(statusWriter)
+		desirer = new(reconcilerfakes.FakeTaskDesirer)
+
+		scheme := runtime.NewScheme()
+		Expect(eiriniv1.AddToScheme(scheme)).To(Succeed())
+
+		task = &eiriniv1.Task{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-task",
+				Namespace: "my-namespace",
+				UID:       "my-uid",
+			},
+			Spec: eiriniv1.TaskSpec{
+				GUID:      "guid-1234",
+				Name:      "migrate",
+				Image:     "my/image",
+				Command:   []string{"rake", "db:migrate"},
+				Env:       map[string]string{"FOO": "BAR"},
+				AppName:   "my-app",
+				SpaceName: "my-space",
+				MemoryMB:  256,
+				DiskMB:    512,
+				CPUWeight: 10,
+			},
+		}
+		taskJobs = nil
+
+		runtimeClient.GetStub = func(_ context.Context, _ types.NamespacedName, obj client.Object) error {
+			task.DeepCopyInto(obj.(*eiriniv1.Task))
+
+			return nil
+		}
+
+		runtimeClient.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			list.(*batchv1.JobList).Items = taskJobs
+
+			return nil
+		}
+
+		taskReconciler = reconciler.NewTask(tests.NewTestLogger("task-reconciler"), runtimeClient, desirer, scheme, 60)
+	})
+
+	JustBeforeEach(func() {
+		result, resultErr = taskReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: "my-namespace", Name: "my-task"},
+		})
+	})
+
+	It("lists the jobs of the task", func() {
+		Expect(runtimeClient.ListCallCount()).To(Equal(1))
+		_, _, opts := runtimeClient.ListArgsForCall(0)
+
+		listOpts := &client.ListOptions{}
+		for _, opt := range opts {
+			opt.ApplyToList(listOpts)
+		}
+
+		Expect(listOpts.Namespace).To(Equal("my-namespace"))
+		Expect(listOpts.LabelSelector.String()).To(Equal(jobs.LabelGUID + "=guid-1234"))
+	})
+
+	It("desires the task", func() {
+		Expect(resultErr).NotTo(HaveOccurred())
+		Expect(desirer.DesireCallCount()).To(Equal(1))
+
+		_, namespace, apiTask, _ := desirer.DesireArgsForCall(0)
+		Expect(namespace).To(Equal("my-namespace"))
+		Expect(apiTask).To(Equal(&api.Task{
+			GUID:      "guid-1234",
+			Name:      "migrate",
+			Image:     "my/image",
+			Command:   []string{"rake", "db:migrate"},
+			Env:       map[string]string{"FOO": "BAR"},
+			AppName:   "my-app",
+			SpaceName: "my-space",
+			MemoryMB:  256,
+			DiskMB:    512,
+			CPUWeight: 10,
+		}))
+	})
+
+	It("makes the task resource the controller of the job", func() {
+		_, _, _, opts := desirer.DesireArgsForCall(0)
+		Expect(opts).To(HaveLen(1))
+
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "my-namespace"}}
+		Expect(opts[0](job)).To(Succeed())
+
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.OwnerReferences[0].Kind).To(Equal("Task"))
+		Expect(job.OwnerReferences[0].Name).To(Equal("my-task"))
+		Expect(*job.OwnerReferences[0].Controller).To(BeTrue())
+	})
+
+	It("marks the task as starting", func() {
+		Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+		_, obj, _ := statusWriter.UpdateArgsForCall(0)
+		Expect(obj.(*eiriniv1.Task).Status.ExecutionStatus).To(Equal(eiriniv1.TaskStarting))
+	})
+
+	When("desiring the task fails", func() {
+		BeforeEach(func() {
+			desirer.DesireReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(resultErr).To(MatchError(ContainSubstring("failed to desire task")))
+		})
+
+		It("does not update the status", func() {
+			Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the task has no memory limit", func() {
+		BeforeEach(func() {
+			task.Spec.MemoryMB = 0
+		})
+
+		It("does not run it", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(desirer.DesireCallCount()).To(Equal(0))
+		})
+
+		It("marks the task as failed", func() {
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			_, obj, _ := statusWriter.UpdateArgsForCall(0)
+			status := obj.(*eiriniv1.Task).Status
+			Expect(status.ExecutionStatus).To(Equal(eiriniv1.TaskFailed))
+			Expect(status.EndTime).NotTo(BeNil())
+		})
+	})
+
+	When("the task has no disk limit", func() {
+		BeforeEach(func() {
+			task.Spec.DiskMB = 0
+		})
+
+		It("does not run it", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(desirer.DesireCallCount()).To(Equal(0))
+		})
+
+		It("marks the task as failed", func() {
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			_, obj, _ := statusWriter.UpdateArgsForCall(0)
+			Expect(obj.(*eiriniv1.Task).Status.ExecutionStatus).To(Equal(eiriniv1.TaskFailed))
+		})
+	})
+
+	When("the task has already been run", func() {
+		BeforeEach(func() {
+			task.Status.ExecutionStatus = eiriniv1.TaskSucceeded
+		})
+
+		It("does not run it again", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(desirer.DesireCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the task resource does not exist", func() {
+		BeforeEach(func() {
+			runtimeClient.GetStub = nil
+			runtimeClient.GetReturns(apierrors.NewNotFound(schema.GroupResource{}, "my-task"))
+		})
+
+		It("does nothing", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(runtimeClient.ListCallCount()).To(Equal(0))
+			Expect(desirer.DesireCallCount()).To(Equal(0))
+		})
+	})
+
+	When("getting the task resource fails", func() {
+		BeforeEach(func() {
+			runtimeClient.GetStub = nil
+			runtimeClient.GetReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(resultErr).To(MatchError(ContainSubstring("failed to get task")))
+		})
+	})
+
+	When("listing the jobs fails", func() {
+		BeforeEach(func() {
+			runtimeClient.ListStub = nil
+			runtimeClient.ListReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(resultErr).To(MatchError(ContainSubstring("failed to list jobs")))
+		})
+	})
+
+	When("the job is running", func() {
+		var startTime metav1.Time
+
+		BeforeEach(func() {
+			startTime = metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			taskJobs = []batchv1.Job{{
+				Status: batchv1.JobStatus{StartTime: &startTime, Active: 1},
+			}}
+		})
+
+		It("does not desire the task again", func() {
+			Expect(desirer.DesireCallCount()).To(Equal(0))
+		})
+
+		It("marks the task as running", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+			_, obj, _ := statusWriter.UpdateArgsForCall(0)
+			Expect(obj.(*eiriniv1.Task).Status).To(Equal(eiriniv1.TaskStatus{
+				ExecutionStatus: eiriniv1.TaskRunning,
+				StartTime:       &startTime,
+			}))
+		})
+
+		It("does not requeue", func() {
+			Expect(result).To(Equal(reconcile.Result{}))
+		})
+
+		When("the status is up to date", func() {
+			BeforeEach(func() {
+				task.Status = eiriniv1.TaskStatus{
+					ExecutionStatus: eiriniv1.TaskRunning,
+					StartTime:       &startTime,
+				}
+			})
+
+			It("does not update the status", func() {
+				Expect(statusWriter.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		When("updating the status fails", func() {
+			BeforeEach(func() {
+				statusWriter.UpdateReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(resultErr).To(MatchError(ContainSubstring("failed to update task status")))
+			})
+		})
+	})
+
+	When("the job has succeeded", func() {
+		var completionTime metav1.Time
+
+		BeforeEach(func() {
+			completionTime = metav1.NewTime(time.Now().Add(-10 * time.Second).Truncate(time.Second))
+			taskJobs = []batchv1.Job{{
+				Status: batchv1.JobStatus{CompletionTime: &completionTime, Succeeded: 1},
+			}}
+		})
+
+		It("marks the task as succeeded", func() {
+			_, obj, _ := statusWriter.UpdateArgsForCall(0)
+			status := obj.(*eiriniv1.Task).Status
+			Expect(status.ExecutionStatus).To(Equal(eiriniv1.TaskSucceeded))
+			Expect(status.EndTime).To(Equal(&completionTime))
+		})
+
+		It("requeues the task for when it expires", func() {
+			Expect(resultErr).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 50*time.Second, 2*time.Second))
+		})
+
+		It("does not delete the task yet", func() {
+			Expect(runtimeClient.DeleteCallCount()).To(Equal(0))
+		})
+
+		When("the task has expired", func() {
+			BeforeEach(func() {
+				completionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+			})
+
+			It("deletes the task resource", func() {
+				Expect(resultErr).NotTo(HaveOccurred())
+				Expect(runtimeClient.DeleteCallCount()).To(Equal(1))
+				_, obj, _ := runtimeClient.DeleteArgsForCall(0)
+				Expect(obj.GetName()).To(Equal("my-task"))
+			})
+
+			When("deleting the task fails", func() {
+				BeforeEach(func() {
+					runtimeClient.DeleteReturns(errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(resultErr).To(MatchError(ContainSubstring("failed to delete task")))
+				})
+			})
+
+			When("the task has already been deleted", func() {
+				BeforeEach(func() {
+					runtimeClient.DeleteReturns(apierrors.NewNotFound(schema.GroupResource{}, "my-task"))
+				})
+
+				It("succeeds", func() {
+					Expect(resultErr).NotTo(HaveOccurred())
+				})
+			})
+		})
+	})
+
+	When("the job has failed", func() {
+		var failureTime metav1.Time
+
+		BeforeEach(func() {
+			failureTime = metav1.NewTime(time.Now().Add(-10 * time.Second).Truncate(time.Second))
+			taskJobs = []batchv1.Job{{
+				Status: batchv1.JobStatus{
+					Failed: 1,
+					Conditions: []batchv1.JobCondition{{
+						Type:               batchv1.JobFailed,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: failureTime,
+					}},
+				},
+			}}
+		})
+
+		It("marks the task as failed", func() {
+			_, obj, _ := statusWriter.UpdateArgsForCall(0)
+			status := obj.(*eiriniv1.Task).Status
+			Expect(status.ExecutionStatus).To(Equal(eiriniv1.TaskFailed))
+			Expect(status.EndTime).To(Equal(&failureTime))
+		})
+	})
+})
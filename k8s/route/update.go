@@ -0,0 +1,347 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+//counterfeiter:generate . ServiceClient
+//counterfeiter:generate . IngressClient
+
+type ServiceClient interface {
+	Get(ctx context.Context, namespace, name string) (*corev1.Service, error)
+	Create(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error)
+	Update(ctx context.Context, namespace string, service *corev1.Service) (*corev1.Service, error)
+	Delete(ctx context.Context, namespace string, name string) error
+}
+
+type IngressClient interface {
+	Get(ctx context.Context, namespace, name string) (*networkingv1.Ingress, error)
+	Create(ctx context.Context, namespace string, ingress *networkingv1.Ingress) (*networkingv1.Ingress, error)
+	Update(ctx context.Context, namespace string, ingress *networkingv1.Ingress) (*networkingv1.Ingress, error)
+	Delete(ctx context.Context, namespace string, name string) error
+}
+
+const TCPServiceSuffix = "-tcp"
+
+// Updater makes the routes of an LRP reachable. HTTP routes are exposed via
+// a ClusterIP Service fronted by an Ingress, TCP routes via a LoadBalancer
+// Service. The ClusterIP Service also exposes all other ports of the LRP.
+// All objects are owned by the LRP StatefulSet and are deleted once the
+// corresponding routes or ports are gone.
+type Updater struct {
+	serviceClient    ServiceClient
+	ingressClient    IngressClient
+	ingressClassName string
+}
+
+func NewUpdater(serviceClient ServiceClient, ingressClient IngressClient, ingressClassName string) *Updater {
+	return &Updater{
+		serviceClient:    serviceClient,
+		ingressClient:    ingressClient,
+		ingressClassName: ingressClassName,
+	}
+}
+
+func (u *Updater) Update(ctx context.Context, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
+	if err := u.updateHTTPRoutes(ctx, statefulSet, lrp); err != nil {
+		return err
+	}
+
+	return u.updateTCPRoutes(ctx, statefulSet, lrp)
+}
+
+// updateHTTPRoutes also maintains the ClusterIP Service for the LRP ports
+// that no route refers to, so that every port is reachable in the cluster.
+func (u *Updater) updateHTTPRoutes(ctx context.Context, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
+	ports := clusterServicePorts(lrp)
+
+	if len(lrp.Routes.HTTP) == 0 {
+		if err := u.deleteIngress(ctx, statefulSet.Namespace, statefulSet.Name); err != nil {
+			return err
+		}
+	}
+
+	if len(ports) == 0 {
+		return u.deleteService(ctx, statefulSet.Namespace, statefulSet.Name)
+	}
+
+	service := u.newService(statefulSet, lrp, statefulSet.Name, corev1.ServiceTypeClusterIP, ports)
+	if err := u.applyService(ctx, statefulSet, service); err != nil {
+		return err
+	}
+
+	if len(lrp.Routes.HTTP) == 0 {
+		return nil
+	}
+
+	return u.applyIngress(ctx, statefulSet, u.newIngress(statefulSet, lrp))
+}
+
+func (u *Updater) updateTCPRoutes(ctx context.Context, statefulSet *appsv1.StatefulSet, lrp *api.LRP) error {
+	name := statefulSet.Name + TCPServiceSuffix
+
+	if len(lrp.Routes.TCP) == 0 {
+		return u.deleteService(ctx, statefulSet.Namespace, name)
+	}
+
+	service := u.newService(statefulSet, lrp, name, corev1.ServiceTypeLoadBalancer, tcpServicePorts(lrp.Routes.TCP))
+
+	return u.applyService(ctx, statefulSet, service)
+}
+
+func (u *Updater) applyService(ctx context.Context, statefulSet *appsv1.StatefulSet, service *corev1.Service) error {
+	if err := controllerutil.SetOwnerReference(statefulSet, service, scheme.Scheme); err != nil {
+		return errors.Wrap(err, "failed to set owner reference on service")
+	}
+
+	existing, err := u.serviceClient.Get(ctx, service.Namespace, service.Name)
+	if k8serrors.IsNotFound(err) {
+		_, err = u.serviceClient.Create(ctx, service.Namespace, service)
+
+		return errors.Wrap(err, "failed to create service")
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to get service")
+	}
+
+	updated := existing.DeepCopy()
+	updated.Labels = service.Labels
+	updated.OwnerReferences = service.OwnerReferences
+	updated.Spec.Type = service.Spec.Type
+	updated.Spec.Selector = service.Spec.Selector
+	updated.Spec.Ports = preserveNodePorts(existing.Spec.Ports, service.Spec.Ports)
+
+	_, err = u.serviceClient.Update(ctx, updated.Namespace, updated)
+
+	return errors.Wrap(err, "failed to update service")
+}
+
+func (u *Updater) applyIngress(ctx context.Context, statefulSet *appsv1.StatefulSet, ingress *networkingv1.Ingress) error {
+	if err := controllerutil.SetOwnerReference(statefulSet, ingress, scheme.Scheme); err != nil {
+		return errors.Wrap(err, "failed to set owner reference on ingress")
+	}
+
+	existing, err := u.ingressClient.Get(ctx, ingress.Namespace, ingress.Name)
+	if k8serrors.IsNotFound(err) {
+		_, err = u.ingressClient.Create(ctx, ingress.Namespace, ingress)
+
+		return errors.Wrap(err, "failed to create ingress")
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to get ingress")
+	}
+
+	updated := existing.DeepCopy()
+	updated.Labels = ingress.Labels
+	updated.OwnerReferences = ingress.OwnerReferences
+	updated.Spec = ingress.Spec
+
+	_, err = u.ingressClient.Update(ctx, updated.Namespace, updated)
+
+	return errors.Wrap(err, "failed to update ingress")
+}
+
+func (u *Updater) deleteService(ctx context.Context, namespace, name string) error {
+	err := u.serviceClient.Delete(ctx, namespace, name)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+
+	return errors.Wrap(err, "failed to delete service")
+}
+
+func (u *Updater) deleteIngress(ctx context.Context, namespace, name string) error {
+	err := u.ingressClient.Delete(ctx, namespace, name)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+
+	return errors.Wrap(err, "failed to delete ingress")
+}
+
+func (u *Updater) newService(statefulSet *appsv1.StatefulSet, lrp *api.LRP, name string, serviceType corev1.ServiceType, ports []corev1.ServicePort) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: statefulSet.Namespace,
+			Labels:    routeLabels(lrp),
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     serviceType,
+			Selector: stset.StatefulSetLabelSelector(lrp).MatchLabels,
+			Ports:    ports,
+		},
+	}
+}
+
+func (u *Updater) newIngress(statefulSet *appsv1.StatefulSet, lrp *api.LRP) *networkingv1.Ingress {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      statefulSet.Name,
+			Namespace: statefulSet.Namespace,
+			Labels:    routeLabels(lrp),
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: ingressRules(statefulSet.Name, lrp.Routes.HTTP),
+		},
+	}
+
+	if u.ingressClassName != "" {
+		className := u.ingressClassName
+		ingress.Spec.IngressClassName = &className
+	}
+
+	return ingress
+}
+
+func ingressRules(serviceName string, routes []api.HTTPRoute) []networkingv1.IngressRule {
+	rules := []networkingv1.IngressRule{}
+	ruleIndex := map[string]int{}
+	pathType := networkingv1.PathTypePrefix
+
+	for _, route := range routes {
+		host, path := splitHostAndPath(route.Hostname)
+
+		index, ok := ruleIndex[host]
+		if !ok {
+			rules = append(rules, networkingv1.IngressRule{
+				Host: host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{},
+				},
+			})
+			index = len(rules) - 1
+			ruleIndex[host] = index
+		}
+
+		rules[index].HTTP.Paths = append(rules[index].HTTP.Paths, networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: &pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: serviceName,
+					Port: networkingv1.ServiceBackendPort{Number: route.Port},
+				},
+			},
+		})
+	}
+
+	return rules
+}
+
+// Cloud Controller sends context path routes as part of the hostname, e.g.
+// "my-app.example.com/some/path".
+func splitHostAndPath(hostname string) (string, string) {
+	host, path, found := strings.Cut(hostname, "/")
+	if !found {
+		return host, "/"
+	}
+
+	return host, "/" + path
+}
+
+func httpServicePorts(routes []api.HTTPRoute) []corev1.ServicePort {
+	ports := []corev1.ServicePort{}
+	seen := map[int32]bool{}
+
+	for _, route := range routes {
+		if seen[route.Port] {
+			continue
+		}
+
+		seen[route.Port] = true
+
+		ports = append(ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("http-%d", route.Port),
+			Protocol:   corev1.ProtocolTCP,
+			Port:       route.Port,
+			TargetPort: intstr.FromInt(int(route.Port)),
+		})
+	}
+
+	return ports
+}
+
+// clusterServicePorts returns the ports of the HTTP routes followed by the
+// remaining LRP ports, named after their protocol.
+func clusterServicePorts(lrp *api.LRP) []corev1.ServicePort {
+	ports := httpServicePorts(lrp.Routes.HTTP)
+
+	routed := map[int32]bool{}
+	for _, p := range ports {
+		routed[p.Port] = true
+	}
+
+	for _, port := range lrp.Ports {
+		if routed[port] {
+			continue
+		}
+
+		protocol := lrp.PortProtocol(port)
+		servicePort := corev1.ServicePort{
+			Name:       fmt.Sprintf("%s-%d", protocol, port),
+			Protocol:   corev1.ProtocolTCP,
+			Port:       port,
+			TargetPort: intstr.FromInt(int(port)),
+		}
+
+		if protocol == api.ProtocolUDP {
+			servicePort.Protocol = corev1.ProtocolUDP
+		}
+
+		ports = append(ports, servicePort)
+	}
+
+	return ports
+}
+
+func tcpServicePorts(routes []api.TCPRoute) []corev1.ServicePort {
+	ports := []corev1.ServicePort{}
+
+	for _, route := range routes {
+		ports = append(ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("tcp-%d", route.ExternalPort),
+			Protocol:   corev1.ProtocolTCP,
+			Port:       route.ExternalPort,
+			TargetPort: intstr.FromInt(int(route.ContainerPort)),
+		})
+	}
+
+	return ports
+}
+
+func preserveNodePorts(existing, desired []corev1.ServicePort) []corev1.ServicePort {
+	nodePorts := map[int32]int32{}
+	for _, p := range existing {
+		nodePorts[p.Port] = p.NodePort
+	}
+
+	for i := range desired {
+		desired[i].NodePort = nodePorts[desired[i].Port]
+	}
+
+	return desired
+}
+
+func routeLabels(lrp *api.LRP) map[string]string {
+	return map[string]string{
+		stset.LabelGUID:    lrp.GUID,
+		stset.LabelVersion: lrp.Version,
+	}
+}
@@ -0,0 +1,318 @@
+package route_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/route"
+	"code.cloudfoundry.org/eirini/k8s/route/routefakes"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("Route Updater", func() {
+	var (
+		updater       *route.Updater
+		serviceClient *routefakes.FakeServiceClient
+		ingressClient *routefakes.FakeIngressClient
+		stSet         *appsv1.StatefulSet
+		lrp           *api.LRP
+		ctx           context.Context
+		updateErr     error
+	)
+
+	BeforeEach(func() {
+		serviceClient = new(routefakes.FakeServiceClient)
+		ingressClient = new(routefakes.FakeIngressClient)
+		serviceClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "service"))
+		ingressClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "ingress"))
+		updater = route.NewUpdater(serviceClient, ingressClient, "nginx")
+
+		stSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "name",
+				Namespace: "namespace",
+				UID:       "uid",
+			},
+		}
+
+		lrp = &api.LRP{
+			LRPIdentifier: api.LRPIdentifier{
+				GUID:    "guid",
+				Version: "version",
+			},
+			Routes: api.Routes{
+				HTTP: []api.HTTPRoute{
+					{Hostname: "app.example.com", Port: 8080},
+					{Hostname: "app.example.com/api", Port: 9090},
+					{Hostname: "other.example.com", Port: 8080},
+				},
+				TCP: []api.TCPRoute{
+					{ExternalPort: 61000, ContainerPort: 9000},
+				},
+			},
+		}
+
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		updateErr = updater.Update(ctx, stSet, lrp)
+	})
+
+	It("succeeds", func() {
+		Expect(updateErr).NotTo(HaveOccurred())
+	})
+
+	It("creates a cluster IP service for the HTTP routes", func() {
+		Expect(serviceClient.CreateCallCount()).To(Equal(2))
+
+		_, namespace, service := serviceClient.CreateArgsForCall(0)
+		Expect(namespace).To(Equal("namespace"))
+		Expect(service.Name).To(Equal("name"))
+		Expect(service.Labels).To(HaveKeyWithValue(stset.LabelGUID, "guid"))
+		Expect(service.Labels).To(HaveKeyWithValue(stset.LabelVersion, "version"))
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(service.Spec.Selector).To(Equal(stset.StatefulSetLabelSelector(lrp).MatchLabels))
+		Expect(service.Spec.Ports).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{"Name": Equal("http-8080"), "Port": Equal(int32(8080)), "TargetPort": Equal(intstr.FromInt(8080))}),
+			MatchFields(IgnoreExtras, Fields{"Name": Equal("http-9090"), "Port": Equal(int32(9090)), "TargetPort": Equal(intstr.FromInt(9090))}),
+		))
+		Expect(service.OwnerReferences).To(HaveLen(1))
+		Expect(service.OwnerReferences[0].Name).To(Equal("name"))
+		Expect(service.OwnerReferences[0].UID).To(Equal(stSet.UID))
+	})
+
+	When("the LRP has ports", func() {
+		BeforeEach(func() {
+			lrp.Ports = []int32{8080, 7000, 5353}
+			lrp.PortProtocols = map[int32]string{5353: api.ProtocolUDP}
+		})
+
+		It("exposes every port on the cluster IP service", func() {
+			_, _, service := serviceClient.CreateArgsForCall(0)
+			Expect(service.Spec.Ports).To(ConsistOf(
+				MatchFields(IgnoreExtras, Fields{"Name": Equal("http-8080"), "Port": Equal(int32(8080)), "Protocol": Equal(corev1.ProtocolTCP)}),
+				MatchFields(IgnoreExtras, Fields{"Name": Equal("http-9090"), "Port": Equal(int32(9090)), "Protocol": Equal(corev1.ProtocolTCP)}),
+				MatchFields(IgnoreExtras, Fields{"Name": Equal("tcp-7000"), "Port": Equal(int32(7000)), "Protocol": Equal(corev1.ProtocolTCP)}),
+				MatchFields(IgnoreExtras, Fields{"Name": Equal("udp-5353"), "Port": Equal(int32(5353)), "Protocol": Equal(corev1.ProtocolUDP)}),
+			))
+		})
+
+		When("the LRP has no HTTP routes", func() {
+			BeforeEach(func() {
+				lrp.Routes.HTTP = nil
+			})
+
+			It("still creates the cluster IP service", func() {
+				_, _, service := serviceClient.CreateArgsForCall(0)
+				Expect(service.Name).To(Equal("name"))
+				Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+				Expect(service.Spec.Ports).To(HaveLen(3))
+			})
+
+			It("deletes the ingress", func() {
+				Expect(ingressClient.CreateCallCount()).To(BeZero())
+				Expect(ingressClient.DeleteCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	It("creates a load balancer service for the TCP routes", func() {
+		_, namespace, service := serviceClient.CreateArgsForCall(1)
+		Expect(namespace).To(Equal("namespace"))
+		Expect(service.Name).To(Equal("name-tcp"))
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(service.Spec.Ports).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{"Name": Equal("tcp-61000"), "Port": Equal(int32(61000)), "TargetPort": Equal(intstr.FromInt(9000))}),
+		))
+		Expect(service.OwnerReferences).To(HaveLen(1))
+	})
+
+	It("creates an ingress for the HTTP routes", func() {
+		Expect(ingressClient.CreateCallCount()).To(Equal(1))
+
+		_, namespace, ingress := ingressClient.CreateArgsForCall(0)
+		Expect(namespace).To(Equal("namespace"))
+		Expect(ingress.Name).To(Equal("name"))
+		Expect(ingress.Spec.IngressClassName).To(PointTo(Equal("nginx")))
+		Expect(ingress.OwnerReferences).To(HaveLen(1))
+		Expect(ingress.Spec.Rules).To(HaveLen(2))
+
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("app.example.com"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths).To(HaveLen(2))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Path).To(Equal("/"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name).To(Equal("name"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number).To(Equal(int32(8080)))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[1].Path).To(Equal("/api"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[1].Backend.Service.Port.Number).To(Equal(int32(9090)))
+
+		Expect(ingress.Spec.Rules[1].Host).To(Equal("other.example.com"))
+		Expect(ingress.Spec.Rules[1].HTTP.Paths).To(HaveLen(1))
+	})
+
+	When("no ingress class is configured", func() {
+		BeforeEach(func() {
+			updater = route.NewUpdater(serviceClient, ingressClient, "")
+		})
+
+		It("does not set the ingress class", func() {
+			_, _, ingress := ingressClient.CreateArgsForCall(0)
+			Expect(ingress.Spec.IngressClassName).To(BeNil())
+		})
+	})
+
+	When("the service and ingress already exist", func() {
+		BeforeEach(func() {
+			serviceClient.GetStub = func(_ context.Context, namespace, name string) (*corev1.Service, error) {
+				return &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, ResourceVersion: "42"},
+					Spec: corev1.ServiceSpec{
+						ClusterIP: "10.0.0.1",
+						Ports:     []corev1.ServicePort{{Port: 61000, NodePort: 31234}},
+					},
+				}, nil
+			}
+			ingressClient.GetReturns(&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace", ResourceVersion: "43"},
+			}, nil)
+		})
+
+		It("updates the services", func() {
+			Expect(serviceClient.CreateCallCount()).To(BeZero())
+			Expect(serviceClient.UpdateCallCount()).To(Equal(2))
+
+			_, _, service := serviceClient.UpdateArgsForCall(0)
+			Expect(service.ResourceVersion).To(Equal("42"))
+			Expect(service.Spec.ClusterIP).To(Equal("10.0.0.1"))
+			Expect(service.Spec.Ports).To(HaveLen(2))
+		})
+
+		It("keeps the allocated node ports", func() {
+			_, _, service := serviceClient.UpdateArgsForCall(1)
+			Expect(service.Spec.Ports).To(HaveLen(1))
+			Expect(service.Spec.Ports[0].NodePort).To(Equal(int32(31234)))
+		})
+
+		It("updates the ingress", func() {
+			Expect(ingressClient.CreateCallCount()).To(BeZero())
+			Expect(ingressClient.UpdateCallCount()).To(Equal(1))
+
+			_, _, ingress := ingressClient.UpdateArgsForCall(0)
+			Expect(ingress.ResourceVersion).To(Equal("43"))
+			Expect(ingress.Spec.Rules).To(HaveLen(2))
+		})
+	})
+
+	When("the LRP has no routes", func() {
+		BeforeEach(func() {
+			lrp.Routes = api.Routes{}
+		})
+
+		It("succeeds", func() {
+			Expect(updateErr).NotTo(HaveOccurred())
+		})
+
+		It("does not create anything", func() {
+			Expect(serviceClient.CreateCallCount()).To(BeZero())
+			Expect(ingressClient.CreateCallCount()).To(BeZero())
+		})
+
+		It("deletes the ingress and the services", func() {
+			Expect(ingressClient.DeleteCallCount()).To(Equal(1))
+			_, namespace, name := ingressClient.DeleteArgsForCall(0)
+			Expect(namespace).To(Equal("namespace"))
+			Expect(name).To(Equal("name"))
+
+			Expect(serviceClient.DeleteCallCount()).To(Equal(2))
+			_, _, name = serviceClient.DeleteArgsForCall(0)
+			Expect(name).To(Equal("name"))
+			_, _, name = serviceClient.DeleteArgsForCall(1)
+			Expect(name).To(Equal("name-tcp"))
+		})
+
+		When("the LRP has ports", func() {
+			BeforeEach(func() {
+				lrp.Ports = []int32{8080}
+			})
+
+			It("creates a cluster IP service for the ports", func() {
+				Expect(serviceClient.CreateCallCount()).To(Equal(1))
+				_, _, service := serviceClient.CreateArgsForCall(0)
+				Expect(service.Name).To(Equal("name"))
+				Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+				Expect(service.Spec.Ports).To(ConsistOf(
+					MatchFields(IgnoreExtras, Fields{"Name": Equal("tcp-8080"), "Port": Equal(int32(8080))}),
+				))
+			})
+
+			It("deletes the ingress and the load balancer service only", func() {
+				Expect(ingressClient.DeleteCallCount()).To(Equal(1))
+				Expect(serviceClient.DeleteCallCount()).To(Equal(1))
+				_, _, name := serviceClient.DeleteArgsForCall(0)
+				Expect(name).To(Equal("name-tcp"))
+			})
+		})
+
+		When("the objects do not exist", func() {
+			BeforeEach(func() {
+				serviceClient.DeleteReturns(k8serrors.NewNotFound(schema.GroupResource{}, "service"))
+				ingressClient.DeleteReturns(k8serrors.NewNotFound(schema.GroupResource{}, "ingress"))
+			})
+
+			It("succeeds", func() {
+				Expect(updateErr).NotTo(HaveOccurred())
+			})
+		})
+
+		When("deleting the ingress fails", func() {
+			BeforeEach(func() {
+				ingressClient.DeleteReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(updateErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+
+	When("getting the service fails", func() {
+		BeforeEach(func() {
+			serviceClient.GetReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	When("creating the service fails", func() {
+		BeforeEach(func() {
+			serviceClient.CreateReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	When("creating the ingress fails", func() {
+		BeforeEach(func() {
+			ingressClient.CreateReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(updateErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+})
@@ -1,7 +1,13 @@
 package shared
 
-import v1 "k8s.io/api/core/v1"
+import (
+	"sort"
 
+	v1 "k8s.io/api/core/v1"
+)
+
+// MapToEnvVar returns the variables of env sorted by name, so that the same
+// environment always results in the same pod template.
 func MapToEnvVar(env map[string]string) []v1.EnvVar {
 	envVars := []v1.EnvVar{}
 
@@ -10,5 +16,25 @@ func MapToEnvVar(env map[string]string) []v1.EnvVar {
 		envVars = append(envVars, envVar)
 	}
 
+	sort.Slice(envVars, func(i, j int) bool {
+		return envVars[i].Name < envVars[j].Name
+	})
+
 	return envVars
 }
+
+// EnvVarToMap is the inverse of MapToEnvVar. Variables that are populated
+// from a source rather than a literal value are left out.
+func EnvVarToMap(envVars []v1.EnvVar) map[string]string {
+	env := map[string]string{}
+
+	for _, envVar := range envVars {
+		if envVar.ValueFrom != nil {
+			continue
+		}
+
+		env[envVar.Name] = envVar.Value
+	}
+
+	return env
+}
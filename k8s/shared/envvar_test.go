@@ -39,4 +39,16 @@ var _ = Describe("EnvVar", func() {
 			})
 		})
 	})
+
+	Describe("Map from Kubernetes EnvVar", func() {
+		It("translates EnvVars with values to key-values", func() {
+			env := shared.EnvVarToMap([]v1.EnvVar{
+				{Name: "foo", Value: "bar"},
+				{Name: "empty"},
+				{Name: "pod", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			})
+
+			Expect(env).To(Equal(map[string]string{"foo": "bar", "empty": ""}))
+		})
+	})
 })
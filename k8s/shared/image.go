@@ -0,0 +1,65 @@
+package shared
+
+import (
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ImagePullPolicy returns the pull policy for a workload image. Images
+// pinned to a digest cannot change, so they are only pulled when missing.
+func ImagePullPolicy(image string) corev1.PullPolicy {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return corev1.PullAlways
+	}
+
+	if _, ok := ref.(reference.Digested); ok {
+		return corev1.PullIfNotPresent
+	}
+
+	return corev1.PullAlways
+}
+
+// RewriteImage replaces the longest prefix of the fully qualified image name
+// (e.g. docker.io/library/busybox) that has an entry in imageRewrites with
+// the configured replacement. Prefixes only match whole registry or
+// repository path components, so that a rule for docker.io/eirini does not
+// apply to docker.io/eirinix. Images that no rule applies to, or that cannot
+// be parsed, are returned unchanged.
+func RewriteImage(image string, imageRewrites map[string]string) string {
+	if len(imageRewrites) == 0 {
+		return image
+	}
+
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+
+	name := ref.String()
+	longestPrefix := ""
+
+	for prefix := range imageRewrites {
+		if len(prefix) > len(longestPrefix) && hasPathPrefix(name, prefix) {
+			longestPrefix = prefix
+		}
+	}
+
+	if longestPrefix == "" {
+		return image
+	}
+
+	return imageRewrites[longestPrefix] + strings.TrimPrefix(name, longestPrefix)
+}
+
+func hasPathPrefix(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+
+	rest := strings.TrimPrefix(name, prefix)
+
+	return rest == "" || strings.ContainsAny(rest[:1], "/:@")
+}
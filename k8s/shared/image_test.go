@@ -0,0 +1,47 @@
+package shared_test
+
+import (
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = DescribeTable("ImagePullPolicy",
+	func(image string, expectedPolicy corev1.PullPolicy) {
+		Expect(shared.ImagePullPolicy(image)).To(Equal(expectedPolicy))
+	},
+	Entry("untagged image", "eirini/some-app", corev1.PullAlways),
+	Entry("tagged image", "eirini/some-app:latest", corev1.PullAlways),
+	Entry("image in a private registry", "private-registry.io:5000/user/repo:tag", corev1.PullAlways),
+	Entry("image pinned to a digest", "eirini/some-app@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1", corev1.PullIfNotPresent),
+	Entry("tagged image pinned to a digest", "private-registry.io/repo:tag@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1", corev1.PullIfNotPresent),
+	Entry("invalid image", "this is invalid", corev1.PullAlways),
+)
+
+var _ = DescribeTable("RewriteImage",
+	func(image string, expectedImage string) {
+		imageRewrites := map[string]string{
+			"docker.io":                   "mirror.example.com/dockerhub",
+			"docker.io/eirini":            "mirror.example.com/eirini",
+			"private-registry.io:5000":    "mirror.example.com/private",
+			"quay.io/some-org/some-image": "mirror.example.com/some-image",
+		}
+
+		Expect(shared.RewriteImage(image, imageRewrites)).To(Equal(expectedImage))
+	},
+	Entry("official docker hub image", "busybox", "mirror.example.com/dockerhub/library/busybox"),
+	Entry("tagged docker hub image", "library/busybox:1.35", "mirror.example.com/dockerhub/library/busybox:1.35"),
+	Entry("image matching the longest prefix", "eirini/some-app:latest", "mirror.example.com/eirini/some-app:latest"),
+	Entry("image matching only part of a path component", "eirinix/some-app", "mirror.example.com/dockerhub/eirinix/some-app"),
+	Entry("image in a registry with a port", "private-registry.io:5000/user/repo:tag", "mirror.example.com/private/user/repo:tag"),
+	Entry("image matching a whole repository", "quay.io/some-org/some-image@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1", "mirror.example.com/some-image@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1"),
+	Entry("image without a matching rule", "quay.io/some-org/other-image", "quay.io/some-org/other-image"),
+	Entry("invalid image", "this is invalid", "this is invalid"),
+)
+
+var _ = Describe("RewriteImage without rules", func() {
+	It("returns the image unchanged", func() {
+		Expect(shared.RewriteImage("busybox", nil)).To(Equal("busybox"))
+	})
+})
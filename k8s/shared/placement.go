@@ -0,0 +1,65 @@
+package shared
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LabelPlacementTagPrefix prefixes the node labels used to match placement
+// tags that have no explicit node selector configured. Each tag has a label
+// of its own, so that a node can carry several tags.
+const LabelPlacementTagPrefix = "cloudfoundry.org/placement-tag."
+
+// PlacementTagLabel returns the node label a node carrying the given
+// placement tag is labelled with, e.g. cloudfoundry.org/placement-tag.gpu.
+func PlacementTagLabel(tag string) string {
+	return LabelPlacementTagPrefix + tag
+}
+
+// PlacementTagNodeAffinity returns a required node affinity that only
+// schedules workloads on nodes matching all of the given placement tags.
+// Tags present in placementTagSelectors are matched against the configured
+// node labels, all other tags require their PlacementTagLabel on the node.
+func PlacementTagNodeAffinity(placementTags []string, placementTagSelectors map[string]map[string]string) *corev1.NodeAffinity {
+	if len(placementTags) == 0 {
+		return nil
+	}
+
+	requirements := []corev1.NodeSelectorRequirement{}
+
+	for _, tag := range placementTags {
+		selector, ok := placementTagSelectors[tag]
+		if !ok {
+			requirements = append(requirements, corev1.NodeSelectorRequirement{
+				Key:      PlacementTagLabel(tag),
+				Operator: corev1.NodeSelectorOpExists,
+			})
+
+			continue
+		}
+
+		keys := make([]string, 0, len(selector))
+		for k := range selector {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			requirements = append(requirements, corev1.NodeSelectorRequirement{
+				Key:      k,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{selector[k]},
+			})
+		}
+	}
+
+	return &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: requirements},
+			},
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package shared_test
+
+import (
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Placement", func() {
+	var (
+		placementTags         []string
+		placementTagSelectors map[string]map[string]string
+		nodeAffinity          *corev1.NodeAffinity
+	)
+
+	BeforeEach(func() {
+		placementTags = []string{"isolated", "gpu"}
+		placementTagSelectors = map[string]map[string]string{
+			"gpu": {
+				"example.com/gpu":  "true",
+				"example.com/zone": "a",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		nodeAffinity = shared.PlacementTagNodeAffinity(placementTags, placementTagSelectors)
+	})
+
+	It("requires nodes matching all placement tags", func() {
+		Expect(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+		Expect(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+			corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "cloudfoundry.org/placement-tag.isolated", Operator: corev1.NodeSelectorOpExists},
+					{Key: "example.com/gpu", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}},
+					{Key: "example.com/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+				},
+			},
+		))
+	})
+
+	When("there are several placement tags without a node selector", func() {
+		BeforeEach(func() {
+			placementTags = []string{"isolated", "ssd"}
+		})
+
+		It("requires a label per tag, so that one node can match them all", func() {
+			Expect(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+				corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "cloudfoundry.org/placement-tag.isolated", Operator: corev1.NodeSelectorOpExists},
+						{Key: "cloudfoundry.org/placement-tag.ssd", Operator: corev1.NodeSelectorOpExists},
+					},
+				},
+			))
+		})
+	})
+
+	When("there are no placement tags", func() {
+		BeforeEach(func() {
+			placementTags = nil
+		})
+
+		It("returns no affinity", func() {
+			Expect(nodeAffinity).To(BeNil())
+		})
+	})
+})
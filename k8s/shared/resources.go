@@ -0,0 +1,30 @@
+package shared
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ContainerResources returns the requests and limits for a workload
+// container. Memory is both requested and limited, disk is only limited and
+// the CPU weight is requested as millicores.
+func ContainerResources(cpuWeight uint8, memoryMB, diskMB int64) corev1.ResourceRequirements {
+	memory := *resource.NewScaledQuantity(memoryMB, resource.Mega)
+	cpu := toCPUMillicores(cpuWeight)
+	ephemeralStorage := *resource.NewScaledQuantity(diskMB, resource.Mega)
+
+	return corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory:           memory,
+			corev1.ResourceEphemeralStorage: ephemeralStorage,
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: memory,
+			corev1.ResourceCPU:    cpu,
+		},
+	}
+}
+
+func toCPUMillicores(cpuPercentage uint8) resource.Quantity {
+	return *resource.NewScaledQuantity(int64(cpuPercentage), resource.Milli)
+}
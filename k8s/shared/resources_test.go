@@ -0,0 +1,32 @@
+package shared_test
+
+import (
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("ContainerResources", func() {
+	var resources corev1.ResourceRequirements
+
+	BeforeEach(func() {
+		resources = shared.ContainerResources(10, 256, 512)
+	})
+
+	It("requests and limits the memory", func() {
+		Expect(resources.Requests.Memory().Equal(resource.MustParse("256M"))).To(BeTrue())
+		Expect(resources.Limits.Memory().Equal(resource.MustParse("256M"))).To(BeTrue())
+	})
+
+	It("limits the ephemeral storage", func() {
+		Expect(resources.Limits.StorageEphemeral().Equal(resource.MustParse("512M"))).To(BeTrue())
+		Expect(resources.Requests).NotTo(HaveKey(corev1.ResourceEphemeralStorage))
+	})
+
+	It("requests the CPU weight as millicores", func() {
+		Expect(resources.Requests.Cpu().Equal(resource.MustParse("10m"))).To(BeTrue())
+		Expect(resources.Limits).NotTo(HaveKey(corev1.ResourceCPU))
+	})
+})
@@ -20,6 +20,8 @@ import (
 //counterfeiter:generate . StatefulSetCreator
 //counterfeiter:generate . LRPToStatefulSetConverter
 //counterfeiter:generate . PodDisruptionBudgetUpdater
+//counterfeiter:generate . RouteUpdater
+//counterfeiter:generate . NetworkPolicyUpdater
 
 type LRPToStatefulSetConverter interface {
 	Convert(statefulSetName string, lrp *api.LRP, privateRegistrySecret *corev1.Secret) (*appsv1.StatefulSet, error)
@@ -39,12 +41,22 @@ type PodDisruptionBudgetUpdater interface {
 	Update(ctx context.Context, stset *appsv1.StatefulSet, lrp *api.LRP) error
 }
 
+type RouteUpdater interface {
+	Update(ctx context.Context, stset *appsv1.StatefulSet, lrp *api.LRP) error
+}
+
+type NetworkPolicyUpdater interface {
+	Update(ctx context.Context, stset *appsv1.StatefulSet, lrp *api.LRP) error
+}
+
 type Desirer struct {
 	logger                     lager.Logger
 	secrets                    SecretsClient
 	statefulSets               StatefulSetCreator
 	lrpToStatefulSetConverter  LRPToStatefulSetConverter
 	podDisruptionBudgetCreator PodDisruptionBudgetUpdater
+	routeUpdater               RouteUpdater
+	networkPolicyUpdater       NetworkPolicyUpdater
 }
 
 func NewDesirer(
@@ -53,6 +65,8 @@ func NewDesirer(
 	statefulSets StatefulSetCreator,
 	lrpToStatefulSetConverter LRPToStatefulSetConverter,
 	podDisruptionBudgetCreator PodDisruptionBudgetUpdater,
+	routeUpdater RouteUpdater,
+	networkPolicyUpdater NetworkPolicyUpdater,
 ) Desirer {
 	return Desirer{
 		logger:                     logger,
@@ -60,6 +74,8 @@ func NewDesirer(
 		statefulSets:               statefulSets,
 		lrpToStatefulSetConverter:  lrpToStatefulSetConverter,
 		podDisruptionBudgetCreator: podDisruptionBudgetCreator,
+		routeUpdater:               routeUpdater,
+		networkPolicyUpdater:       networkPolicyUpdater,
 	}
 }
 
@@ -76,9 +92,14 @@ func (d *Desirer) Desire(ctx context.Context, namespace string, lrp *api.LRP, op
 		return err
 	}
 
+	envSecret, err := d.createEnvSecretIfRequired(ctx, logger, namespace, statefulSetName, lrp)
+	if err != nil {
+		return d.cleanupAndError(ctx, err, privateRegistrySecret)
+	}
+
 	st, err := d.lrpToStatefulSetConverter.Convert(statefulSetName, lrp, privateRegistrySecret)
 	if err != nil {
-		return err
+		return d.cleanupAndError(ctx, err, privateRegistrySecret, envSecret)
 	}
 
 	st.Namespace = namespace
@@ -97,7 +118,7 @@ func (d *Desirer) Desire(ctx context.Context, namespace string, lrp *api.LRP, op
 			return nil
 		}
 
-		return d.cleanupAndError(ctx, errors.Wrap(err, "failed to create statefulset"), privateRegistrySecret)
+		return d.cleanupAndError(ctx, errors.Wrap(err, "failed to create statefulset"), privateRegistrySecret, envSecret)
 	}
 
 	if err := d.setSecretOwner(ctx, privateRegistrySecret, stSet); err != nil {
@@ -106,31 +127,67 @@ func (d *Desirer) Desire(ctx context.Context, namespace string, lrp *api.LRP, op
 		return errors.Wrap(err, "failed to set owner to the registry secret")
 	}
 
+	if err := d.setSecretOwner(ctx, envSecret, stSet); err != nil {
+		logger.Error("failed-to-set-owner-to-the-env-secret", err)
+
+		return errors.Wrap(err, "failed to set owner to the env secret")
+	}
+
 	if err := d.podDisruptionBudgetCreator.Update(ctx, stSet, lrp); err != nil {
 		logger.Error("failed-to-create-pod-disruption-budget", err)
 
 		return errors.Wrap(err, "failed to create pod disruption budget")
 	}
 
+	if err := d.routeUpdater.Update(ctx, stSet, lrp); err != nil {
+		logger.Error("failed-to-create-routes", err)
+
+		return errors.Wrap(err, "failed to create routes")
+	}
+
+	if err := d.networkPolicyUpdater.Update(ctx, stSet, lrp); err != nil {
+		logger.Error("failed-to-create-network-policy", err)
+
+		return errors.Wrap(err, "failed to create network policy")
+	}
+
 	return nil
 }
 
-func (d *Desirer) setSecretOwner(ctx context.Context, privateRegistrySecret *corev1.Secret, stSet *appsv1.StatefulSet) error {
-	if privateRegistrySecret == nil {
+func (d *Desirer) setSecretOwner(ctx context.Context, secret *corev1.Secret, stSet *appsv1.StatefulSet) error {
+	if secret == nil {
 		return nil
 	}
 
-	_, err := d.secrets.SetOwner(ctx, privateRegistrySecret, stSet)
+	_, err := d.secrets.SetOwner(ctx, secret, stSet)
 
 	return err
 }
 
+// createEnvSecretIfRequired returns nil if the LRP has no environment or if
+// the secret already exists, e.g. because the LRP has been desired before.
+func (d *Desirer) createEnvSecretIfRequired(ctx context.Context, logger lager.Logger, namespace, statefulSetName string, lrp *api.LRP) (*corev1.Secret, error) {
+	secret := GenerateEnvSecret(statefulSetName, lrp)
+	if secret == nil {
+		return nil, nil // nolint:nilnil
+	}
+
+	secret, err := d.secrets.Create(ctx, namespace, secret)
+	if k8serrors.IsAlreadyExists(err) {
+		logger.Debug("env-secret-already-exists", lager.Data{"error": err.Error()})
+
+		return nil, nil // nolint:nilnil
+	}
+
+	return secret, errors.Wrap(err, "failed to create env secret for statefulset")
+}
+
 func (d *Desirer) createRegistryCredsSecretIfRequired(ctx context.Context, namespace string, lrp *api.LRP) (*corev1.Secret, error) {
 	if lrp.PrivateRegistry == nil {
 		return nil, nil // nolint:nilnil
 	}
 
-	secret, err := generateRegistryCredsSecret(lrp)
+	secret, err := GenerateRegistryCredsSecret(lrp)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to generate private registry secret for statefulset")
 	}
@@ -140,20 +197,26 @@ func (d *Desirer) createRegistryCredsSecretIfRequired(ctx context.Context, names
 	return secret, errors.Wrap(err, "failed to create private registry secret for statefulset")
 }
 
-func (d *Desirer) cleanupAndError(ctx context.Context, stsetCreationError error, privateRegistrySecret *corev1.Secret) error {
+func (d *Desirer) cleanupAndError(ctx context.Context, stsetCreationError error, secrets ...*corev1.Secret) error {
 	resultError := multierror.Append(nil, stsetCreationError)
 
-	if privateRegistrySecret != nil {
-		err := d.secrets.Delete(ctx, privateRegistrySecret.Namespace, privateRegistrySecret.Name)
+	for _, secret := range secrets {
+		if secret == nil {
+			continue
+		}
+
+		err := d.secrets.Delete(ctx, secret.Namespace, secret.Name)
 		if err != nil {
-			resultError = multierror.Append(resultError, errors.Wrap(err, "failed to cleanup registry secret"))
+			resultError = multierror.Append(resultError, errors.Wrapf(err, "failed to cleanup secret %s", secret.Name))
 		}
 	}
 
 	return resultError
 }
 
-func generateRegistryCredsSecret(lrp *api.LRP) (*corev1.Secret, error) {
+// GenerateRegistryCredsSecret returns the image pull secret for an LRP
+// using a private registry. The secret has a generated name.
+func GenerateRegistryCredsSecret(lrp *api.LRP) (*corev1.Secret, error) {
 	dockerConfig := dockerutils.NewDockerConfig(
 		lrp.PrivateRegistry.Server,
 		lrp.PrivateRegistry.Username,
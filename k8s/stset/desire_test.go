@@ -28,6 +28,8 @@ var _ = Describe("Desirer", func() {
 		statefulSets               *stsetfakes.FakeStatefulSetCreator
 		lrpToStatefulSetConverter  *stsetfakes.FakeLRPToStatefulSetConverter
 		podDisruptionBudgetUpdater *stsetfakes.FakePodDisruptionBudgetUpdater
+		routeUpdater               *stsetfakes.FakeRouteUpdater
+		networkPolicyUpdater       *stsetfakes.FakeNetworkPolicyUpdater
 		desireOptOne, desireOptTwo *sharedfakes.FakeOption
 
 		lrp       *api.LRP
@@ -53,10 +55,12 @@ var _ = Describe("Desirer", func() {
 		}
 
 		podDisruptionBudgetUpdater = new(stsetfakes.FakePodDisruptionBudgetUpdater)
+		routeUpdater = new(stsetfakes.FakeRouteUpdater)
+		networkPolicyUpdater = new(stsetfakes.FakeNetworkPolicyUpdater)
 		lrp = createLRP("Baldur")
 		desireOptOne = new(sharedfakes.FakeOption)
 		desireOptTwo = new(sharedfakes.FakeOption)
-		desirer = stset.NewDesirer(logger, secrets, statefulSets, lrpToStatefulSetConverter, podDisruptionBudgetUpdater)
+		desirer = stset.NewDesirer(logger, secrets, statefulSets, lrpToStatefulSetConverter, podDisruptionBudgetUpdater, routeUpdater, networkPolicyUpdater)
 	})
 
 	JustBeforeEach(func() {
@@ -94,6 +98,42 @@ var _ = Describe("Desirer", func() {
 		})
 	})
 
+	It("updates the routes", func() {
+		Expect(routeUpdater.UpdateCallCount()).To(Equal(1))
+		_, actualStatefulSet, actualLRP := routeUpdater.UpdateArgsForCall(0)
+		Expect(actualStatefulSet.Namespace).To(Equal("the-namespace"))
+		Expect(actualStatefulSet.Name).To(Equal("baldur-space-foo-34f869d015"))
+		Expect(actualLRP).To(Equal(lrp))
+	})
+
+	When("updating the routes fails", func() {
+		BeforeEach(func() {
+			routeUpdater.UpdateReturns(errors.New("route-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(desireErr).To(MatchError(ContainSubstring("route-error")))
+		})
+	})
+
+	It("updates the network policy", func() {
+		Expect(networkPolicyUpdater.UpdateCallCount()).To(Equal(1))
+		_, actualStatefulSet, actualLRP := networkPolicyUpdater.UpdateArgsForCall(0)
+		Expect(actualStatefulSet.Namespace).To(Equal("the-namespace"))
+		Expect(actualStatefulSet.Name).To(Equal("baldur-space-foo-34f869d015"))
+		Expect(actualLRP).To(Equal(lrp))
+	})
+
+	When("updating the network policy fails", func() {
+		BeforeEach(func() {
+			networkPolicyUpdater.UpdateReturns(errors.New("netpol-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(desireErr).To(MatchError(ContainSubstring("netpol-error")))
+		})
+	})
+
 	It("should invoke the opts with the StatefulSet", func() {
 		Expect(desireOptOne.CallCount()).To(Equal(1))
 		Expect(desireOptTwo.CallCount()).To(Equal(1))
@@ -221,10 +261,88 @@ var _ = Describe("Desirer", func() {
 			})
 		})
 	})
+
+	It("does not create an env secret when the app has no environment", func() {
+		Expect(secrets.CreateCallCount()).To(BeZero())
+	})
+
+	When("the app has environment variables", func() {
+		BeforeEach(func() {
+			lrp.Env = map[string]string{"FOO": "secret"}
+			secrets.CreateStub = func(_ context.Context, namespace string, secret *corev1.Secret) (*corev1.Secret, error) {
+				created := secret.DeepCopy()
+				created.Namespace = namespace
+
+				return created, nil
+			}
+		})
+
+		It("creates the env secret", func() {
+			Expect(secrets.CreateCallCount()).To(Equal(1))
+			_, secretNamespace, actualSecret := secrets.CreateArgsForCall(0)
+			Expect(secretNamespace).To(Equal("the-namespace"))
+			Expect(actualSecret.Name).To(Equal("baldur-space-foo-34f869d015-env"))
+			Expect(actualSecret.Type).To(Equal(corev1.SecretTypeOpaque))
+			Expect(actualSecret.StringData).To(Equal(map[string]string{"opi.FOO": "secret"}))
+		})
+
+		It("sets the statefulset as the env secret owner", func() {
+			Expect(secrets.SetOwnerCallCount()).To(Equal(1))
+			_, actualSecret, actualStatefulSet := secrets.SetOwnerArgsForCall(0)
+			Expect(actualSecret.Name).To(Equal("baldur-space-foo-34f869d015-env"))
+			Expect(actualStatefulSet.GetName()).To(Equal("baldur-space-foo-34f869d015"))
+		})
+
+		When("the env secret already exists", func() {
+			BeforeEach(func() {
+				secrets.CreateReturns(nil, k8serrors.NewAlreadyExists(schema.GroupResource{}, "baldur-space-foo-34f869d015-env"))
+			})
+
+			It("succeeds", func() {
+				Expect(desireErr).NotTo(HaveOccurred())
+			})
+		})
+
+		When("creating the env secret fails", func() {
+			BeforeEach(func() {
+				secrets.CreateReturns(nil, errors.New("create-secret-failed"))
+			})
+
+			It("returns an error", func() {
+				Expect(desireErr).To(MatchError(ContainSubstring("create-secret-failed")))
+			})
+
+			It("does not create the statefulset", func() {
+				Expect(statefulSets.CreateCallCount()).To(BeZero())
+			})
+		})
+
+		When("creating the statefulset fails", func() {
+			BeforeEach(func() {
+				statefulSets.CreateReturns(nil, errors.New("potato"))
+			})
+
+			It("deletes the env secret", func() {
+				Expect(secrets.DeleteCallCount()).To(Equal(1))
+				_, actualNamespace, actualName := secrets.DeleteArgsForCall(0)
+				Expect(actualNamespace).To(Equal("the-namespace"))
+				Expect(actualName).To(Equal("baldur-space-foo-34f869d015-env"))
+			})
+		})
+	})
 })
 
 const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
+func expectedSecretRef(secretName, key string) *corev1.EnvVarSource {
+	return &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			Key:                  key,
+		},
+	}
+}
+
 func expectedValFrom(fieldPath string) *corev1.EnvVarSource {
 	return &corev1.EnvVarSource{
 		FieldRef: &corev1.ObjectFieldSelector{
@@ -0,0 +1,318 @@
+package stset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	redactedEnvValue         = ""
+	redactedRegistryPassword = ""
+)
+
+// EnvSecretName returns the name of the secret holding the environment of
+// the containers of a statefulset.
+func EnvSecretName(statefulSetName string) string {
+	return fmt.Sprintf("%s-env", statefulSetName)
+}
+
+// GenerateEnvSecret returns the secret holding the environment of the LRP
+// containers, or nil if the LRP has no environment.
+func GenerateEnvSecret(statefulSetName string, lrp *api.LRP) *corev1.Secret {
+	secretName := EnvSecretName(statefulSetName)
+
+	return newEnvSecret(secretName, envSecretData(secretName, lrp))
+}
+
+func envSecretData(secretName string, lrp *api.LRP) map[string]string {
+	data := map[string]string{}
+
+	toSecretEnvVars(secretName, ApplicationContainerName, shared.MapToEnvVar(lrp.Env), data)
+
+	for _, sidecar := range lrp.Sidecars {
+		toSecretEnvVars(secretName, sidecar.Name, shared.MapToEnvVar(sidecar.Env), data)
+	}
+
+	return data
+}
+
+// MoveEnvToSecret replaces the literal environment values of all
+// containers of the statefulset with references to the returned secret. It
+// returns nil if none of the containers has literal environment values.
+func MoveEnvToSecret(statefulSet *appsv1.StatefulSet) *corev1.Secret {
+	data := map[string]string{}
+	secretName := EnvSecretName(statefulSet.Name)
+	containers := statefulSet.Spec.Template.Spec.Containers
+
+	for i, container := range containers {
+		containers[i].Env = toSecretEnvVars(secretName, container.Name, container.Env, data)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	setEnvChecksum(statefulSet, envChecksum(data))
+
+	return newEnvSecret(secretName, data)
+}
+
+// RedactOriginalRequest blanks the environment values of a desire request
+// that are stored in the env secret, and the private registry password,
+// which is stored in the registry secret. The variable names are kept, so
+// that the request can still be used to generate the statefulset. Requests
+// that are not JSON objects are returned unchanged.
+func RedactOriginalRequest(request string) string {
+	return replaceRequestSecrets(request,
+		func(string, string) string { return redactedEnvValue },
+		func(string) string { return redactedRegistryPassword },
+	)
+}
+
+// RestoreOriginalRequest is the inverse of RedactOriginalRequest, taking the
+// environment values from env and the registry password from
+// registryPassword, if not empty.
+func RestoreOriginalRequest(request string, env map[string]string, registryPassword string) string {
+	return replaceRequestSecrets(request,
+		func(name, value string) string {
+			if restored, ok := env[name]; ok {
+				return restored
+			}
+
+			return value
+		},
+		func(password string) string {
+			if registryPassword != "" {
+				return registryPassword
+			}
+
+			return password
+		},
+	)
+}
+
+func replaceRequestSecrets(request string, replaceEnv func(name, value string) string, replacePassword func(string) string) string {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(request), &fields); err != nil {
+		return request
+	}
+
+	envReplaced := replaceRequestEnv(fields, replaceEnv)
+	passwordReplaced := replaceRegistryPassword(fields, replacePassword)
+
+	if !envReplaced && !passwordReplaced {
+		return request
+	}
+
+	requestJSON, err := json.Marshal(fields)
+	if err != nil {
+		return request
+	}
+
+	return string(requestJSON)
+}
+
+func replaceRequestEnv(fields map[string]json.RawMessage, replace func(name, value string) string) bool {
+	environment := map[string]string{}
+	if err := json.Unmarshal(fields["environment"], &environment); err != nil || len(environment) == 0 {
+		return false
+	}
+
+	replaced := false
+
+	for name, value := range environment {
+		if !isSecretKey(ApplicationContainerName, name) {
+			continue
+		}
+
+		if newValue := replace(name, value); newValue != value {
+			environment[name] = newValue
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		return false
+	}
+
+	environmentJSON, err := json.Marshal(environment)
+	if err != nil {
+		return false
+	}
+
+	fields["environment"] = environmentJSON
+
+	return true
+}
+
+// replaceRegistryPassword replaces lifecycle.docker_lifecycle.registry_password,
+// keeping all other lifecycle fields as they are.
+func replaceRegistryPassword(fields map[string]json.RawMessage, replace func(string) string) bool {
+	lifecycle := map[string]json.RawMessage{}
+	if err := json.Unmarshal(fields["lifecycle"], &lifecycle); err != nil {
+		return false
+	}
+
+	dockerLifecycle := map[string]json.RawMessage{}
+	if err := json.Unmarshal(lifecycle["docker_lifecycle"], &dockerLifecycle); err != nil {
+		return false
+	}
+
+	var password string
+	if err := json.Unmarshal(dockerLifecycle["registry_password"], &password); err != nil {
+		return false
+	}
+
+	newPassword := replace(password)
+	if newPassword == password {
+		return false
+	}
+
+	passwordJSON, err := json.Marshal(newPassword)
+	if err != nil {
+		return false
+	}
+
+	dockerLifecycle["registry_password"] = passwordJSON
+
+	if lifecycle["docker_lifecycle"], err = json.Marshal(dockerLifecycle); err != nil {
+		return false
+	}
+
+	if fields["lifecycle"], err = json.Marshal(lifecycle); err != nil {
+		return false
+	}
+
+	return true
+}
+
+// resolveEnv returns the environment of a container, taking the values
+// referenced from the env secret from the secret.
+func resolveEnv(envVars []corev1.EnvVar, envSecret *corev1.Secret) map[string]string {
+	env := shared.EnvVarToMap(envVars)
+
+	for _, envVar := range envVars {
+		if !isEnvSecretRef(envVar, envSecret.Name) {
+			continue
+		}
+
+		key := envVar.ValueFrom.SecretKeyRef.Key
+		if value, ok := envSecret.Data[key]; ok {
+			env[envVar.Name] = string(value)
+		} else {
+			env[envVar.Name] = envSecret.StringData[key]
+		}
+	}
+
+	return env
+}
+
+func referencesEnvSecret(container corev1.Container, secretName string) bool {
+	for _, envVar := range container.Env {
+		if isEnvSecretRef(envVar, secretName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEnvSecretRef(envVar corev1.EnvVar, secretName string) bool {
+	return envVar.ValueFrom != nil &&
+		envVar.ValueFrom.SecretKeyRef != nil &&
+		envVar.ValueFrom.SecretKeyRef.Name == secretName
+}
+
+// toSecretEnvVars replaces the literal values of envVars with references to
+// keys of the env secret and stores the values under those keys in data.
+// Variables whose name cannot be used as a secret key keep their value.
+func toSecretEnvVars(secretName, containerName string, envVars []corev1.EnvVar, data map[string]string) []corev1.EnvVar {
+	result := make([]corev1.EnvVar, 0, len(envVars))
+
+	for _, envVar := range envVars {
+		if envVar.ValueFrom != nil || !isSecretKey(containerName, envVar.Name) {
+			result = append(result, envVar)
+
+			continue
+		}
+
+		key := envSecretKey(containerName, envVar.Name)
+		data[key] = envVar.Value
+		result = append(result, corev1.EnvVar{
+			Name: envVar.Name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+					Key:                  key,
+				},
+			},
+		})
+	}
+
+	return result
+}
+
+func envSecretKey(containerName, envName string) string {
+	return fmt.Sprintf("%s.%s", containerName, envName)
+}
+
+func isSecretKey(containerName, envName string) bool {
+	return len(validation.IsConfigMapKey(envSecretKey(containerName, envName))) == 0
+}
+
+func newEnvSecret(secretName string, data map[string]string) *corev1.Secret {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: secretName,
+		},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: data,
+	}
+}
+
+// envChecksum changes whenever the env secret content changes, so that
+// putting it in the pod template restarts the pods to pick up new values.
+func envChecksum(data map[string]string) string {
+	dataJSON, _ := json.Marshal(data) // marshalling a map of strings cannot fail
+
+	sum := sha256.Sum256(dataJSON)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// lrpEnvChecksum returns the checksum of the env secret of the LRP, or an
+// empty string if the LRP has no environment.
+func lrpEnvChecksum(statefulSetName string, lrp *api.LRP) string {
+	data := envSecretData(EnvSecretName(statefulSetName), lrp)
+	if len(data) == 0 {
+		return ""
+	}
+
+	return envChecksum(data)
+}
+
+func setEnvChecksum(statefulSet *appsv1.StatefulSet, checksum string) {
+	if statefulSet.Annotations == nil {
+		statefulSet.Annotations = map[string]string{}
+	}
+
+	if statefulSet.Spec.Template.Annotations == nil {
+		statefulSet.Spec.Template.Annotations = map[string]string{}
+	}
+
+	statefulSet.Annotations[AnnotationEnvChecksum] = checksum
+	statefulSet.Spec.Template.Annotations[AnnotationEnvChecksum] = checksum
+}
@@ -2,19 +2,24 @@ package stset
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 
 	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s/utils"
+	"code.cloudfoundry.org/eirini/k8s/utils/dockerutils"
 	"code.cloudfoundry.org/eirini/util"
 	"code.cloudfoundry.org/lager"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 //counterfeiter:generate . PodGetter
 //counterfeiter:generate . EventGetter
+//counterfeiter:generate . SecretGetter
 
 const (
 	eventKilling          = "Killing"
@@ -30,10 +35,15 @@ type EventGetter interface {
 	GetByPod(ctx context.Context, pod corev1.Pod) ([]corev1.Event, error)
 }
 
+type SecretGetter interface {
+	Get(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+}
+
 type Getter struct {
 	logger                    lager.Logger
 	podGetter                 PodGetter
 	eventGetter               EventGetter
+	secretGetter              SecretGetter
 	statefulsetToLrpConverter StatefulSetToLRPConverter
 	getStatefulSet            getStatefulSetFunc
 }
@@ -43,12 +53,14 @@ func NewGetter(
 	statefulSetGetter StatefulSetByLRPIdentifierGetter,
 	podGetter PodGetter,
 	eventGetter EventGetter,
+	secretGetter SecretGetter,
 	statefulsetToLrpConverter StatefulSetToLRPConverter,
 ) Getter {
 	return Getter{
 		logger:                    logger,
 		podGetter:                 podGetter,
 		eventGetter:               eventGetter,
+		secretGetter:              secretGetter,
 		statefulsetToLrpConverter: statefulsetToLrpConverter,
 		getStatefulSet:            newGetStatefulSetFunc(statefulSetGetter),
 	}
@@ -57,12 +69,32 @@ func NewGetter(
 func (g *Getter) Get(ctx context.Context, identifier api.LRPIdentifier) (*api.LRP, error) {
 	logger := g.logger.Session("get", lager.Data{"guid": identifier.GUID, "version": identifier.Version})
 
-	return g.getLRP(ctx, logger, identifier)
+	statefulset, lrp, err := g.getLRP(ctx, logger, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = g.resolveEnv(ctx, statefulset, lrp); err != nil {
+		logger.Error("failed-to-resolve-env", err)
+
+		return nil, err
+	}
+
+	registryPassword, err := g.registryPassword(ctx, statefulset)
+	if err != nil {
+		logger.Error("failed-to-resolve-registry-password", err)
+
+		return nil, err
+	}
+
+	lrp.LRP = RestoreOriginalRequest(lrp.LRP, lrp.Env, registryPassword)
+
+	return lrp, nil
 }
 
 func (g *Getter) GetInstances(ctx context.Context, identifier api.LRPIdentifier) ([]*api.Instance, error) {
 	logger := g.logger.Session("get-instance", lager.Data{"guid": identifier.GUID, "version": identifier.Version})
-	if _, err := g.getLRP(ctx, logger, identifier); errors.Is(err, eirini.ErrNotFound) {
+	if _, _, err := g.getLRP(ctx, logger, identifier); errors.Is(err, eirini.ErrNotFound) {
 		return nil, err
 	}
 
@@ -118,22 +150,87 @@ func (g *Getter) GetInstances(ctx context.Context, identifier api.LRPIdentifier)
 	return instances, nil
 }
 
-func (g *Getter) getLRP(ctx context.Context, logger lager.Logger, identifier api.LRPIdentifier) (*api.LRP, error) {
+func (g *Getter) getLRP(ctx context.Context, logger lager.Logger, identifier api.LRPIdentifier) (*appsv1.StatefulSet, *api.LRP, error) {
 	statefulset, err := g.getStatefulSet(ctx, identifier)
 	if err != nil {
 		logger.Error("failed-to-get-statefulset", err)
 
-		return nil, err
+		return nil, nil, err
 	}
 
 	lrp, err := g.statefulsetToLrpConverter.Convert(*statefulset)
 	if err != nil {
 		logger.Error("failed-to-map-statefulset-to-lrp", err)
 
-		return nil, err
+		return nil, nil, err
 	}
 
-	return lrp, nil
+	return statefulset, lrp, nil
+}
+
+// resolveEnv fills in the environment values kept in the env secret, so that
+// the LRP can be updated without losing them.
+func (g *Getter) resolveEnv(ctx context.Context, statefulset *appsv1.StatefulSet, lrp *api.LRP) error {
+	secretName := EnvSecretName(statefulset.Name)
+	containers := statefulset.Spec.Template.Spec.Containers
+
+	if len(containers) == 0 || !referencesEnvSecret(containers[0], secretName) {
+		return nil
+	}
+
+	secret, err := g.secretGetter.Get(ctx, statefulset.Namespace, secretName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get env secret")
+	}
+
+	lrp.Env = resolveEnv(containers[0].Env, secret)
+
+	for i := range lrp.Sidecars {
+		lrp.Sidecars[i].Env = resolveEnv(containers[i+1].Env, secret)
+	}
+
+	return nil
+}
+
+// registryPassword returns the private registry password kept in the
+// registry secret of the statefulset, or an empty string if it has none.
+func (g *Getter) registryPassword(ctx context.Context, statefulset *appsv1.StatefulSet) (string, error) {
+	for _, ref := range statefulset.Spec.Template.Spec.ImagePullSecrets {
+		if !strings.HasPrefix(ref.Name, PrivateRegistrySecretGenerateName) {
+			continue
+		}
+
+		secret, err := g.secretGetter.Get(ctx, statefulset.Namespace, ref.Name)
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get registry secret")
+		}
+
+		return dockerConfigPassword(secret)
+	}
+
+	return "", nil
+}
+
+func dockerConfigPassword(secret *corev1.Secret) (string, error) {
+	dockerConfigJSON, ok := secret.Data[dockerutils.DockerConfigKey]
+	if !ok {
+		dockerConfigJSON = []byte(secret.StringData[dockerutils.DockerConfigKey])
+	}
+
+	var config dockerutils.Config
+	if err := json.Unmarshal(dockerConfigJSON, &config); err != nil {
+		return "", errors.Wrap(err, "failed to parse registry secret")
+	}
+
+	for _, auth := range config.Auths {
+		return auth.Password, nil
+	}
+
+	return "", nil
 }
 
 func isStopped(events []corev1.Event) bool {
@@ -7,6 +7,7 @@ import (
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s/stset"
 	"code.cloudfoundry.org/eirini/k8s/stset/stsetfakes"
+	"code.cloudfoundry.org/eirini/k8s/utils/dockerutils"
 	"code.cloudfoundry.org/eirini/tests"
 	"code.cloudfoundry.org/lager"
 	. "github.com/onsi/ginkgo/v2"
@@ -14,7 +15,9 @@ import (
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 const (
@@ -26,6 +29,7 @@ var _ = Describe("Get StatefulSet", func() {
 		logger                    lager.Logger
 		podGetter                 *stsetfakes.FakePodGetter
 		eventGetter               *stsetfakes.FakeEventGetter
+		secretGetter              *stsetfakes.FakeSecretGetter
 		statefulSetGetter         *stsetfakes.FakeStatefulSetByLRPIdentifierGetter
 		statefulsetToLRPConverter *stsetfakes.FakeStatefulSetToLRPConverter
 
@@ -36,11 +40,12 @@ var _ = Describe("Get StatefulSet", func() {
 		logger = tests.NewTestLogger("handler-test")
 		podGetter = new(stsetfakes.FakePodGetter)
 		eventGetter = new(stsetfakes.FakeEventGetter)
+		secretGetter = new(stsetfakes.FakeSecretGetter)
 		statefulSetGetter = new(stsetfakes.FakeStatefulSetByLRPIdentifierGetter)
 		statefulsetToLRPConverter = new(stsetfakes.FakeStatefulSetToLRPConverter)
 		statefulsetToLRPConverter.ConvertReturns(&api.LRP{AppName: "baldur-app"}, nil)
 
-		getter = stset.NewGetter(logger, statefulSetGetter, podGetter, eventGetter, statefulsetToLRPConverter)
+		getter = stset.NewGetter(logger, statefulSetGetter, podGetter, eventGetter, secretGetter, statefulsetToLRPConverter)
 	})
 
 	Describe("Get", func() {
@@ -55,6 +60,153 @@ var _ = Describe("Get StatefulSet", func() {
 			lrp, _ := getter.Get(ctx, api.LRPIdentifier{GUID: "guid_1234", Version: "version_1234"})
 			Expect(statefulsetToLRPConverter.ConvertCallCount()).To(Equal(1))
 			Expect(lrp.AppName).To(Equal("baldur-app"))
+			Expect(secretGetter.GetCallCount()).To(BeZero())
+		})
+
+		When("the environment is stored in the env secret", func() {
+			var (
+				lrp *api.LRP
+				err error
+			)
+
+			BeforeEach(func() {
+				envVar := corev1.EnvVar{
+					Name: "FOO",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "baldur-env"},
+							Key:                  "opi.FOO",
+						},
+					},
+				}
+				statefulSetGetter.GetByLRPIdentifierReturns([]appsv1.StatefulSet{{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "baldur",
+						Namespace: "the-namespace",
+					},
+					Spec: appsv1.StatefulSetSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Name: "opi",
+									Env:  []corev1.EnvVar{envVar, {Name: "BAR", Value: "bar"}},
+								}},
+							},
+						},
+					},
+				}}, nil)
+				statefulsetToLRPConverter.ConvertReturns(&api.LRP{
+					AppName: "baldur-app",
+					LRP:     `{"environment":{"FOO":""}}`,
+				}, nil)
+				secretGetter.GetReturns(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "baldur-env"},
+					Data:       map[string][]byte{"opi.FOO": []byte("foo")},
+				}, nil)
+			})
+
+			JustBeforeEach(func() {
+				lrp, err = getter.Get(ctx, api.LRPIdentifier{GUID: "guid_1234", Version: "version_1234"})
+			})
+
+			It("gets the env secret", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(secretGetter.GetCallCount()).To(Equal(1))
+				_, actualNamespace, actualName := secretGetter.GetArgsForCall(0)
+				Expect(actualNamespace).To(Equal("the-namespace"))
+				Expect(actualName).To(Equal("baldur-env"))
+			})
+
+			It("resolves the environment values from the secret", func() {
+				Expect(lrp.Env).To(Equal(map[string]string{"FOO": "foo", "BAR": "bar"}))
+			})
+
+			It("restores the environment values in the original request", func() {
+				Expect(lrp.LRP).To(MatchJSON(`{"environment":{"FOO":"foo"}}`))
+			})
+
+			When("getting the env secret fails", func() {
+				BeforeEach(func() {
+					secretGetter.GetReturns(nil, errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError(ContainSubstring("boom")))
+				})
+			})
+		})
+
+		When("the app uses a private registry", func() {
+			var (
+				lrp *api.LRP
+				err error
+			)
+
+			BeforeEach(func() {
+				statefulSetGetter.GetByLRPIdentifierReturns([]appsv1.StatefulSet{{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "baldur",
+						Namespace: "the-namespace",
+					},
+					Spec: appsv1.StatefulSetSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								ImagePullSecrets: []corev1.LocalObjectReference{
+									{Name: "registry-secret"},
+									{Name: stset.PrivateRegistrySecretGenerateName + "abc"},
+								},
+								Containers: []corev1.Container{{Name: "opi"}},
+							},
+						},
+					},
+				}}, nil)
+				statefulsetToLRPConverter.ConvertReturns(&api.LRP{
+					AppName: "baldur-app",
+					LRP:     `{"lifecycle":{"docker_lifecycle":{"image":"my/image","registry_username":"user","registry_password":""}}}`,
+				}, nil)
+				secretGetter.GetReturns(&corev1.Secret{
+					Data: map[string][]byte{
+						dockerutils.DockerConfigKey: []byte(`{"auths":{"registry.example.com":{"username":"user","password":"hunter2"}}}`),
+					},
+				}, nil)
+			})
+
+			JustBeforeEach(func() {
+				lrp, err = getter.Get(ctx, api.LRPIdentifier{GUID: "guid_1234", Version: "version_1234"})
+			})
+
+			It("gets the registry secret", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(secretGetter.GetCallCount()).To(Equal(1))
+				_, actualNamespace, actualName := secretGetter.GetArgsForCall(0)
+				Expect(actualNamespace).To(Equal("the-namespace"))
+				Expect(actualName).To(Equal(stset.PrivateRegistrySecretGenerateName + "abc"))
+			})
+
+			It("restores the registry password in the original request", func() {
+				Expect(lrp.LRP).To(MatchJSON(`{"lifecycle":{"docker_lifecycle":{"image":"my/image","registry_username":"user","registry_password":"hunter2"}}}`))
+			})
+
+			When("the registry secret does not exist", func() {
+				BeforeEach(func() {
+					secretGetter.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "secret"))
+				})
+
+				It("keeps the password redacted", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(lrp.LRP).To(MatchJSON(`{"lifecycle":{"docker_lifecycle":{"image":"my/image","registry_username":"user","registry_password":""}}}`))
+				})
+			})
+
+			When("getting the registry secret fails", func() {
+				BeforeEach(func() {
+					secretGetter.GetReturns(nil, errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError(ContainSubstring("boom")))
+				})
+			})
 		})
 
 		When("the app does not exist", func() {
@@ -1,14 +1,17 @@
 package stset
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
 
 	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s/shared"
+	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -23,9 +26,13 @@ type LRPToStatefulSet struct {
 	registrySecretName                string
 	allowAutomountServiceAccountToken bool
 	allowRunImageAsRoot               bool
+	placementTagSelectors             map[string]map[string]string
+	imageRewrites                     map[string]string
+	scheduling                        eirini.SchedulingConfig
 	latestMigration                   int
 	livenessProbeCreator              ProbeCreator
 	readinessProbeCreator             ProbeCreator
+	startupProbeCreator               ProbeCreator
 }
 
 func NewLRPToStatefulSetConverter(
@@ -33,23 +40,33 @@ func NewLRPToStatefulSetConverter(
 	registrySecretName string,
 	allowAutomountServiceAccountToken bool,
 	allowRunImageAsRoot bool,
+	placementTagSelectors map[string]map[string]string,
+	imageRewrites map[string]string,
+	scheduling eirini.SchedulingConfig,
 	latestMigration int,
 	livenessProbeCreator ProbeCreator,
 	readinessProbeCreator ProbeCreator,
+	startupProbeCreator ProbeCreator,
 ) *LRPToStatefulSet {
 	return &LRPToStatefulSet{
 		applicationServiceAccount:         applicationServiceAccount,
 		registrySecretName:                registrySecretName,
 		allowAutomountServiceAccountToken: allowAutomountServiceAccountToken,
 		allowRunImageAsRoot:               allowRunImageAsRoot,
+		placementTagSelectors:             placementTagSelectors,
+		imageRewrites:                     imageRewrites,
+		scheduling:                        scheduling,
 		latestMigration:                   latestMigration,
 		livenessProbeCreator:              livenessProbeCreator,
 		readinessProbeCreator:             readinessProbeCreator,
+		startupProbeCreator:               startupProbeCreator,
 	}
 }
 
 func (c *LRPToStatefulSet) Convert(statefulSetName string, lrp *api.LRP, privateRegistrySecret *corev1.Secret) (*appsv1.StatefulSet, error) {
-	envs := shared.MapToEnvVar(lrp.Env)
+	envSecretName := EnvSecretName(statefulSetName)
+	envSecretData := map[string]string{}
+	envs := toSecretEnvVars(envSecretName, ApplicationContainerName, shared.MapToEnvVar(lrp.Env), envSecretData)
 	fieldEnvs := []corev1.EnvVar{
 		{
 			Name: eirini.EnvPodName,
@@ -89,35 +106,38 @@ func (c *LRPToStatefulSet) Convert(statefulSetName string, lrp *api.LRP, private
 	ports := []corev1.ContainerPort{}
 
 	for _, port := range lrp.Ports {
-		ports = append(ports, corev1.ContainerPort{ContainerPort: port})
+		ports = append(ports, containerPort(lrp, port))
 	}
 
 	livenessProbe := c.livenessProbeCreator(lrp)
 	readinessProbe := c.readinessProbeCreator(lrp)
+	startupProbe := c.startupProbeCreator(lrp)
 
 	volumes, volumeMounts := getVolumeSpecs(lrp.VolumeMounts)
 	allowPrivilegeEscalation := false
 	imagePullSecrets := c.calculateImagePullSecrets(privateRegistrySecret)
+	image := shared.RewriteImage(lrp.Image, c.imageRewrites)
 
 	containers := []corev1.Container{
 		{
 			Name:            ApplicationContainerName,
-			Image:           lrp.Image,
-			ImagePullPolicy: corev1.PullAlways,
+			Image:           image,
+			ImagePullPolicy: shared.ImagePullPolicy(image),
 			Command:         lrp.Command,
 			Env:             envs,
 			Ports:           ports,
 			SecurityContext: &corev1.SecurityContext{
 				AllowPrivilegeEscalation: &allowPrivilegeEscalation,
 			},
-			Resources:      getContainerResources(lrp.CPUWeight, lrp.MemoryMB, lrp.DiskMB),
+			Resources:      shared.ContainerResources(lrp.CPUWeight, lrp.MemoryMB, lrp.DiskMB),
 			LivenessProbe:  livenessProbe,
 			ReadinessProbe: readinessProbe,
+			StartupProbe:   startupProbe,
 			VolumeMounts:   volumeMounts,
 		},
 	}
 
-	sidecarContainers := getSidecarContainers(lrp)
+	sidecarContainers := c.getSidecarContainers(lrp, image, envSecretName, envSecretData)
 	containers = append(containers, sidecarContainers...)
 	statefulSet := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -145,21 +165,16 @@ func (c *LRPToStatefulSet) Convert(statefulSetName string, lrp *api.LRP, private
 
 	statefulSet.Spec.Selector = StatefulSetLabelSelector(lrp)
 
+	scheduling, err := resolveScheduling(c.scheduling, lrp.UserDefinedAnnotations)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid scheduling policy")
+	}
+
 	statefulSet.Spec.Template.Spec.Affinity = &corev1.Affinity{
-		PodAntiAffinity: &corev1.PodAntiAffinity{
-			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
-				{
-					Weight: PodAffinityTermWeight,
-					PodAffinityTerm: corev1.PodAffinityTerm{
-						TopologyKey: corev1.LabelHostname,
-						LabelSelector: &metav1.LabelSelector{
-							MatchExpressions: toLabelSelectorRequirements(statefulSet.Spec.Selector),
-						},
-					},
-				},
-			},
-		},
+		NodeAffinity:    shared.PlacementTagNodeAffinity(lrp.PlacementTags, c.placementTagSelectors),
+		PodAntiAffinity: podAntiAffinity(scheduling, statefulSet.Spec.Selector),
 	}
+	statefulSet.Spec.Template.Spec.TopologySpreadConstraints = topologySpreadConstraints(scheduling, statefulSet.Spec.Selector)
 
 	labels := map[string]string{
 		LabelOrgGUID:     lrp.OrgGUID,
@@ -179,7 +194,7 @@ func (c *LRPToStatefulSet) Convert(statefulSetName string, lrp *api.LRP, private
 	annotations := map[string]string{
 		AnnotationSpaceName:              lrp.SpaceName,
 		AnnotationSpaceGUID:              lrp.SpaceGUID,
-		AnnotationOriginalRequest:        lrp.LRP,
+		AnnotationOriginalRequest:        RedactOriginalRequest(lrp.LRP),
 		AnnotationAppID:                  lrp.AppGUID,
 		AnnotationVersion:                lrp.Version,
 		AnnotationLastUpdated:            lrp.LastUpdated,
@@ -190,6 +205,19 @@ func (c *LRPToStatefulSet) Convert(statefulSetName string, lrp *api.LRP, private
 		shared.AnnotationLatestMigration: strconv.Itoa(c.latestMigration),
 	}
 
+	if !lrp.Routes.IsEmpty() {
+		routes, err := MarshalRoutes(lrp.Routes)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations[AnnotationRoutes] = routes
+	}
+
+	if len(envSecretData) != 0 {
+		annotations[AnnotationEnvChecksum] = envChecksum(envSecretData)
+	}
+
 	for k, v := range lrp.UserDefinedAnnotations {
 		annotations[k] = v
 	}
@@ -250,39 +278,49 @@ func getVolumeSpecs(lrpVolumeMounts []api.VolumeMount) ([]corev1.Volume, []corev
 	return volumes, volumeMounts
 }
 
-func getContainerResources(cpuWeight uint8, memoryMB, diskMB int64) corev1.ResourceRequirements {
-	memory := *resource.NewScaledQuantity(memoryMB, resource.Mega)
-	cpu := toCPUMillicores(cpuWeight)
-	ephemeralStorage := *resource.NewScaledQuantity(diskMB, resource.Mega)
+// containerPort names the port after its protocol and number, e.g. tcp-8080,
+// so that it can be referred to by name.
+func containerPort(lrp *api.LRP, port int32) corev1.ContainerPort {
+	protocol := lrp.PortProtocol(port)
 
-	return corev1.ResourceRequirements{
-		Limits: corev1.ResourceList{
-			corev1.ResourceMemory:           memory,
-			corev1.ResourceEphemeralStorage: ephemeralStorage,
-		},
-		Requests: corev1.ResourceList{
-			corev1.ResourceMemory: memory,
-			corev1.ResourceCPU:    cpu,
-		},
+	containerPort := corev1.ContainerPort{
+		Name:          fmt.Sprintf("%s-%d", protocol, port),
+		ContainerPort: port,
+		Protocol:      corev1.ProtocolTCP,
+	}
+
+	if protocol == api.ProtocolUDP {
+		containerPort.Protocol = corev1.ProtocolUDP
 	}
-}
 
-func toCPUMillicores(cpuPercentage uint8) resource.Quantity {
-	return *resource.NewScaledQuantity(int64(cpuPercentage), resource.Milli)
+	return containerPort
 }
 
-func getSidecarContainers(lrp *api.LRP) []corev1.Container {
+// getSidecarContainers renders the sidecars of the LRP. Sidecars without an
+// image of their own run the (already rewritten) app image and sidecars
+// without a CPU weight get the one of the app.
+func (c *LRPToStatefulSet) getSidecarContainers(lrp *api.LRP, appImage, envSecretName string, envSecretData map[string]string) []corev1.Container {
 	containers := []corev1.Container{}
 
 	for _, s := range lrp.Sidecars {
-		c := corev1.Container{
-			Name:      s.Name,
-			Command:   s.Command,
-			Image:     lrp.Image,
-			Env:       shared.MapToEnvVar(s.Env),
-			Resources: getContainerResources(lrp.CPUWeight, s.MemoryMB, lrp.DiskMB),
+		image := appImage
+		if s.Image != "" {
+			image = shared.RewriteImage(s.Image, c.imageRewrites)
 		}
-		containers = append(containers, c)
+
+		cpuWeight := lrp.CPUWeight
+		if s.CPUWeight != 0 {
+			cpuWeight = s.CPUWeight
+		}
+
+		containers = append(containers, corev1.Container{
+			Name:            s.Name,
+			Command:         s.Command,
+			Image:           image,
+			ImagePullPolicy: shared.ImagePullPolicy(image),
+			Env:             toSecretEnvVars(envSecretName, s.Name, shared.MapToEnvVar(s.Env), envSecretData),
+			Resources:       shared.ContainerResources(cpuWeight, s.MemoryMB, lrp.DiskMB),
+		})
 	}
 
 	return containers
@@ -306,6 +344,10 @@ func toLabelSelectorRequirements(selector *metav1.LabelSelector) []metav1.LabelS
 		})
 	}
 
+	sort.Slice(reqs, func(i, j int) bool {
+		return reqs[i].Key < reqs[j].Key
+	})
+
 	return reqs
 }
 
@@ -318,3 +360,25 @@ func StatefulSetLabelSelector(lrp *api.LRP) *metav1.LabelSelector {
 		},
 	}
 }
+
+func MarshalRoutes(routes api.Routes) (string, error) {
+	routesJSON, err := json.Marshal(routes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal routes")
+	}
+
+	return string(routesJSON), nil
+}
+
+func UnmarshalRoutes(routesJSON string) (api.Routes, error) {
+	routes := api.Routes{}
+	if routesJSON == "" {
+		return routes, nil
+	}
+
+	if err := json.Unmarshal([]byte(routesJSON), &routes); err != nil {
+		return api.Routes{}, errors.Wrap(err, "failed to unmarshal routes")
+	}
+
+	return routes, nil
+}
@@ -19,20 +19,29 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 	var (
 		allowAutomountServiceAccountToken bool
 		allowRunImageAsRoot               bool
+		placementTagSelectors             map[string]map[string]string
+		imageRewrites                     map[string]string
+		scheduling                        eirini.SchedulingConfig
 		livenessProbeCreator              *stsetfakes.FakeProbeCreator
 		readinessProbeCreator             *stsetfakes.FakeProbeCreator
+		startupProbeCreator               *stsetfakes.FakeProbeCreator
 		lrp                               *api.LRP
 		statefulSet                       *appsv1.StatefulSet
 		privateRegistrySecret             *corev1.Secret
 		livenessProbe                     *corev1.Probe
 		readinessProbe                    *corev1.Probe
+		startupProbe                      *corev1.Probe
 	)
 
 	BeforeEach(func() {
 		allowAutomountServiceAccountToken = false
 		allowRunImageAsRoot = false
+		placementTagSelectors = nil
+		imageRewrites = nil
+		scheduling = eirini.SchedulingConfig{}
 		livenessProbeCreator = new(stsetfakes.FakeProbeCreator)
 		readinessProbeCreator = new(stsetfakes.FakeProbeCreator)
+		startupProbeCreator = new(stsetfakes.FakeProbeCreator)
 		lrp = createLRP("Baldur")
 		privateRegistrySecret = nil
 
@@ -41,10 +50,13 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 
 		readinessProbe = &corev1.Probe{}
 		readinessProbeCreator.Returns(readinessProbe)
+
+		startupProbe = &corev1.Probe{}
+		startupProbeCreator.Returns(startupProbe)
 	})
 
 	JustBeforeEach(func() {
-		converter := stset.NewLRPToStatefulSetConverter("eirini", "secret-name", allowAutomountServiceAccountToken, allowRunImageAsRoot, 999, livenessProbeCreator.Spy, readinessProbeCreator.Spy)
+		converter := stset.NewLRPToStatefulSetConverter("eirini", "secret-name", allowAutomountServiceAccountToken, allowRunImageAsRoot, placementTagSelectors, imageRewrites, scheduling, 999, livenessProbeCreator.Spy, readinessProbeCreator.Spy, startupProbeCreator.Spy)
 
 		var err error
 		statefulSet, err = converter.Convert("Baldur", lrp, privateRegistrySecret)
@@ -59,6 +71,11 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 		Expect(readinessProbeCreator.CallCount()).To(Equal(1))
 	})
 
+	It("should create a startup probe", func() {
+		Expect(startupProbeCreator.CallCount()).To(Equal(1))
+		Expect(startupProbeCreator.ArgsForCall(0)).To(Equal(lrp))
+	})
+
 	DescribeTable("Statefulset Annotations",
 		func(annotationName, expectedValue string) {
 			Expect(statefulSet.Annotations).To(HaveKeyWithValue(annotationName, expectedValue))
@@ -95,6 +112,22 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 		Expect(statefulSet.Annotations).To(HaveKeyWithValue(stset.AnnotationLastUpdated, lrp.LastUpdated))
 	})
 
+	It("should not set the routes annotation when there are no routes", func() {
+		Expect(statefulSet.Annotations).NotTo(HaveKey(stset.AnnotationRoutes))
+	})
+
+	When("the LRP has routes", func() {
+		BeforeEach(func() {
+			lrp.Routes = api.Routes{
+				HTTP: []api.HTTPRoute{{Hostname: "baldur.example.com", Port: 8888}},
+			}
+		})
+
+		It("should store the routes in an annotation", func() {
+			Expect(statefulSet.Annotations).To(HaveKeyWithValue(stset.AnnotationRoutes, `{"http":[{"hostname":"baldur.example.com","port":8888}]}`))
+		})
+	})
+
 	It("should set seccomp pod annotation", func() {
 		Expect(statefulSet.Spec.Template.Annotations[corev1.SeccompPodAnnotationKey]).To(Equal(corev1.SeccompProfileRuntimeDefault))
 	})
@@ -121,6 +154,30 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 		Expect(statefulSet.Spec.Template.Spec.Containers[0].ReadinessProbe).To(Equal(readinessProbe))
 	})
 
+	It("should set the startup probe", func() {
+		Expect(statefulSet.Spec.Template.Spec.Containers[0].StartupProbe).To(Equal(startupProbe))
+	})
+
+	It("should set named container ports", func() {
+		Expect(statefulSet.Spec.Template.Spec.Containers[0].Ports).To(Equal([]corev1.ContainerPort{
+			{Name: "tcp-8888", ContainerPort: 8888, Protocol: corev1.ProtocolTCP},
+			{Name: "tcp-9999", ContainerPort: 9999, Protocol: corev1.ProtocolTCP},
+		}))
+	})
+
+	When("a port uses the udp protocol", func() {
+		BeforeEach(func() {
+			lrp.PortProtocols = map[int32]string{9999: api.ProtocolUDP}
+		})
+
+		It("should set the protocol of the container port", func() {
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].Ports).To(Equal([]corev1.ContainerPort{
+				{Name: "tcp-8888", ContainerPort: 8888, Protocol: corev1.ProtocolTCP},
+				{Name: "udp-9999", ContainerPort: 9999, Protocol: corev1.ProtocolUDP},
+			}))
+		})
+	})
+
 	It("should not automount service account token", func() {
 		f := false
 		Expect(statefulSet.Spec.Template.Spec.AutomountServiceAccountToken).To(Equal(&f))
@@ -130,6 +187,30 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 		Expect(string(statefulSet.Spec.Template.Spec.Containers[0].ImagePullPolicy)).To(Equal("Always"))
 	})
 
+	When("the image is pinned to a digest", func() {
+		BeforeEach(func() {
+			lrp.Image = "busybox@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1"
+		})
+
+		It("should set imagePullPolicy to IfNotPresent", func() {
+			Expect(statefulSet.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		})
+	})
+
+	When("the image matches an image rewrite", func() {
+		BeforeEach(func() {
+			imageRewrites = map[string]string{"docker.io/library": "mirror.example.com/dockerhub"}
+			lrp.Sidecars = []api.Sidecar{{Name: "the-sidecar", Command: []string{"echo"}}}
+		})
+
+		It("should use the rewritten image for all containers", func() {
+			containers := statefulSet.Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(2))
+			Expect(containers[0].Image).To(Equal("mirror.example.com/dockerhub/busybox"))
+			Expect(containers[1].Image).To(Equal("mirror.example.com/dockerhub/busybox"))
+		})
+	})
+
 	It("should set app_guid as a label", func() {
 		Expect(statefulSet.Labels).To(HaveKeyWithValue(stset.LabelAppGUID, "premium_app_guid_1234"))
 		Expect(statefulSet.Spec.Template.Labels).To(HaveKeyWithValue(stset.LabelAppGUID, "premium_app_guid_1234"))
@@ -237,6 +318,97 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 		))
 	})
 
+	It("should not spread instances across zones", func() {
+		Expect(statefulSet.Spec.Template.Spec.TopologySpreadConstraints).To(BeEmpty())
+	})
+
+	When("required anti-affinity and zone spread are configured", func() {
+		BeforeEach(func() {
+			scheduling = eirini.SchedulingConfig{
+				AntiAffinity: stset.SchedulingRequired,
+				ZoneSpread:   stset.SchedulingPreferred,
+				MaxSkew:      2,
+			}
+		})
+
+		It("should set hard inter-pod anti-affinity", func() {
+			podAntiAffinity := statefulSet.Spec.Template.Spec.Affinity.PodAntiAffinity
+			Expect(podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+			Expect(podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			Expect(podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey).To(Equal("kubernetes.io/hostname"))
+		})
+
+		It("should spread the instances across zones", func() {
+			Expect(statefulSet.Spec.Template.Spec.TopologySpreadConstraints).To(ConsistOf(corev1.TopologySpreadConstraint{
+				MaxSkew:           2,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						stset.LabelGUID:       "guid_1234",
+						stset.LabelVersion:    "version_1234",
+						stset.LabelSourceType: "APP",
+					},
+				},
+			}))
+		})
+
+		When("the app overrides the scheduling policy", func() {
+			BeforeEach(func() {
+				lrp.UserDefinedAnnotations = map[string]string{
+					stset.AnnotationAntiAffinity: stset.SchedulingNone,
+					stset.AnnotationZoneSpread:   stset.SchedulingRequired,
+					stset.AnnotationMaxSkew:      "3",
+				}
+			})
+
+			It("should not set inter-pod anti-affinity", func() {
+				Expect(statefulSet.Spec.Template.Spec.Affinity.PodAntiAffinity).To(BeNil())
+			})
+
+			It("should strictly spread the instances across zones", func() {
+				constraints := statefulSet.Spec.Template.Spec.TopologySpreadConstraints
+				Expect(constraints).To(HaveLen(1))
+				Expect(constraints[0].MaxSkew).To(Equal(int32(3)))
+				Expect(constraints[0].WhenUnsatisfiable).To(Equal(corev1.DoNotSchedule))
+			})
+		})
+	})
+
+	It("should fail when an app has an invalid scheduling annotation", func() {
+		converter := stset.NewLRPToStatefulSetConverter("eirini", "secret-name", false, false, nil, nil, eirini.SchedulingConfig{}, 999, livenessProbeCreator.Spy, readinessProbeCreator.Spy, startupProbeCreator.Spy)
+		lrp.UserDefinedAnnotations = map[string]string{stset.AnnotationZoneSpread: "always"}
+
+		_, err := converter.Convert("Baldur", lrp, nil)
+		Expect(err).To(MatchError(ContainSubstring(`invalid zone spread "always"`)))
+	})
+
+	It("should not set node affinity", func() {
+		Expect(statefulSet.Spec.Template.Spec.Affinity.NodeAffinity).To(BeNil())
+	})
+
+	When("the LRP has placement tags", func() {
+		BeforeEach(func() {
+			lrp.PlacementTags = []string{"isolated", "unmapped"}
+			placementTagSelectors = map[string]map[string]string{
+				"isolated": {"example.com/segment": "isolated"},
+			}
+		})
+
+		It("should set required node affinity", func() {
+			nodeAffinity := statefulSet.Spec.Template.Spec.Affinity.NodeAffinity
+			Expect(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+			Expect(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+				corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "example.com/segment", Operator: corev1.NodeSelectorOpIn, Values: []string{"isolated"}},
+						{Key: shared.PlacementTagLabel("unmapped"), Operator: corev1.NodeSelectorOpExists},
+					},
+				},
+			))
+		})
+	})
+
 	It("should set application service account", func() {
 		Expect(statefulSet.Spec.Template.Spec.ServiceAccountName).To(Equal("eirini"))
 	})
@@ -252,6 +424,47 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 		))
 	})
 
+	When("the app has environment variables", func() {
+		BeforeEach(func() {
+			lrp.Env = map[string]string{"FOO": "secret", "BAR": "baz"}
+			lrp.LRP = `{"instances":1,"environment":{"FOO":"secret","BAR":"baz"}}`
+		})
+
+		It("refers to the env secret instead of setting the values", func() {
+			container := statefulSet.Spec.Template.Spec.Containers[0]
+			Expect(container.Env).To(ContainElements(
+				corev1.EnvVar{Name: "FOO", ValueFrom: expectedSecretRef("Baldur-env", "opi.FOO")},
+				corev1.EnvVar{Name: "BAR", ValueFrom: expectedSecretRef("Baldur-env", "opi.BAR")},
+			))
+		})
+
+		It("redacts the environment values in the original request annotation", func() {
+			Expect(statefulSet.Annotations[stset.AnnotationOriginalRequest]).To(MatchJSON(`{"instances":1,"environment":{"FOO":"","BAR":""}}`))
+			Expect(statefulSet.Spec.Template.Annotations[stset.AnnotationOriginalRequest]).To(MatchJSON(`{"instances":1,"environment":{"FOO":"","BAR":""}}`))
+		})
+
+		It("sets the checksum of the env secret on the pod template", func() {
+			Expect(statefulSet.Spec.Template.Annotations).To(HaveKeyWithValue(stset.AnnotationEnvChecksum, Not(BeEmpty())))
+			Expect(statefulSet.Annotations[stset.AnnotationEnvChecksum]).To(Equal(statefulSet.Spec.Template.Annotations[stset.AnnotationEnvChecksum]))
+		})
+	})
+
+	When("the app uses a private registry", func() {
+		BeforeEach(func() {
+			lrp.Env = map[string]string{"FOO": "secret"}
+			lrp.PrivateRegistry = &api.PrivateRegistry{Server: "registry.example.com", Username: "user", Password: "hunter2"}
+			lrp.LRP = `{"environment":{"FOO":"secret"},"lifecycle":{"docker_lifecycle":{"image":"my/image","registry_username":"user","registry_password":"hunter2"}}}`
+		})
+
+		It("does not store any secret value in the original request annotation", func() {
+			for _, annotations := range []map[string]string{statefulSet.Annotations, statefulSet.Spec.Template.Annotations} {
+				Expect(annotations[stset.AnnotationOriginalRequest]).NotTo(ContainSubstring("secret"))
+				Expect(annotations[stset.AnnotationOriginalRequest]).NotTo(ContainSubstring("hunter2"))
+				Expect(annotations[stset.AnnotationOriginalRequest]).To(MatchJSON(`{"environment":{"FOO":""},"lifecycle":{"docker_lifecycle":{"image":"my/image","registry_username":"user","registry_password":""}}}`))
+			}
+		})
+	})
+
 	When("the app has sidecars", func() {
 		BeforeEach(func() {
 			lrp.Sidecars = []api.Sidecar{
@@ -280,10 +493,11 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 
 			Expect(containers).To(ContainElements(
 				corev1.Container{
-					Name:    "first-sidecar",
-					Image:   "busybox",
-					Command: []string{"echo", "the first sidecar"},
-					Env:     []corev1.EnvVar{{Name: "FOO", Value: "BAR"}},
+					Name:            "first-sidecar",
+					Image:           "busybox",
+					ImagePullPolicy: corev1.PullAlways,
+					Command:         []string{"echo", "the first sidecar"},
+					Env:             []corev1.EnvVar{{Name: "FOO", ValueFrom: expectedSecretRef("Baldur-env", "first-sidecar.FOO")}},
 					Resources: corev1.ResourceRequirements{
 						Limits: corev1.ResourceList{
 							corev1.ResourceMemory:           *resource.NewScaledQuantity(101, resource.Mega),
@@ -296,10 +510,11 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 					},
 				},
 				corev1.Container{
-					Name:    "second-sidecar",
-					Image:   "busybox",
-					Command: []string{"echo", "the second sidecar"},
-					Env:     []corev1.EnvVar{{Name: "FOO", Value: "BAZ"}},
+					Name:            "second-sidecar",
+					Image:           "busybox",
+					ImagePullPolicy: corev1.PullAlways,
+					Command:         []string{"echo", "the second sidecar"},
+					Env:             []corev1.EnvVar{{Name: "FOO", ValueFrom: expectedSecretRef("Baldur-env", "second-sidecar.FOO")}},
 					Resources: corev1.ResourceRequirements{
 						Limits: corev1.ResourceList{
 							corev1.ResourceMemory:           *resource.NewScaledQuantity(102, resource.Mega),
@@ -313,7 +528,29 @@ var _ = Describe("LRP to StatefulSet Converter", func() {
 				},
 			))
 		})
+
+		When("a sidecar has its own image and cpu weight", func() {
+			BeforeEach(func() {
+				imageRewrites = map[string]string{"docker.io/apm": "mirror.example.com/apm"}
+				lrp.Sidecars[0].Image = "apm/agent"
+				lrp.Sidecars[0].CPUWeight = 7
+			})
+
+			It("should run the sidecar image with the sidecar cpu weight", func() {
+				sidecar := statefulSet.Spec.Template.Spec.Containers[1]
+				Expect(sidecar.Image).To(Equal("mirror.example.com/apm/agent"))
+				Expect(sidecar.ImagePullPolicy).To(Equal(corev1.PullAlways))
+				Expect(sidecar.Resources.Requests.Cpu().MilliValue()).To(Equal(int64(7)))
+			})
+
+			It("should not affect the other sidecars", func() {
+				sidecar := statefulSet.Spec.Template.Spec.Containers[2]
+				Expect(sidecar.Image).To(Equal("busybox"))
+				Expect(sidecar.Resources.Requests.Cpu().MilliValue()).To(Equal(int64(lrp.CPUWeight)))
+			})
+		})
 	})
+
 	When("automounting service account token is allowed", func() {
 		BeforeEach(func() {
 			allowAutomountServiceAccountToken = true
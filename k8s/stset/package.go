@@ -15,8 +15,13 @@ const (
 	AnnotationLastUpdated          = "cloudfoundry.org/last_updated"
 	AnnotationProcessGUID          = "cloudfoundry.org/process_guid"
 	AnnotationOriginalRequest      = "cloudfoundry.org/original_request"
+	AnnotationRoutes               = "cloudfoundry.org/routes"
 	AnnotationLastReportedAppCrash = "cloudfoundry.org/last_reported_app_crash"
 	AnnotationLastReportedLRPCrash = "cloudfoundry.org/last_reported_lrp_crash"
+	AnnotationEnvChecksum          = "cloudfoundry.org/env_checksum"
+	AnnotationAntiAffinity         = "cloudfoundry.org/anti_affinity"
+	AnnotationZoneSpread           = "cloudfoundry.org/zone_spread"
+	AnnotationMaxSkew              = "cloudfoundry.org/max_skew"
 
 	LabelGUID        = "cloudfoundry.org/guid"
 	LabelOrgGUID     = AnnotationOrgGUID
@@ -0,0 +1,132 @@
+package stset
+
+import (
+	"fmt"
+	"strconv"
+
+	"code.cloudfoundry.org/eirini"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SchedulingNone      = "none"
+	SchedulingPreferred = "preferred"
+	SchedulingRequired  = "required"
+
+	defaultMaxSkew = 1
+)
+
+// ValidateSchedulingConfig checks the configured scheduling policy, with
+// unset settings taking their default.
+func ValidateSchedulingConfig(config eirini.SchedulingConfig) error {
+	_, err := resolveScheduling(config, nil)
+
+	return err
+}
+
+// resolveScheduling applies the scheduling annotations of an app to the
+// configured policy and fills in the defaults.
+func resolveScheduling(config eirini.SchedulingConfig, annotations map[string]string) (eirini.SchedulingConfig, error) {
+	policy := eirini.SchedulingConfig{
+		AntiAffinity: valueOrDefault(annotations[AnnotationAntiAffinity], config.AntiAffinity, SchedulingPreferred),
+		ZoneSpread:   valueOrDefault(annotations[AnnotationZoneSpread], config.ZoneSpread, SchedulingNone),
+		MaxSkew:      config.MaxSkew,
+	}
+
+	if maxSkew, ok := annotations[AnnotationMaxSkew]; ok {
+		value, err := strconv.ParseInt(maxSkew, 10, 32)
+		if err != nil {
+			return eirini.SchedulingConfig{}, errors.Wrapf(err, "invalid %s annotation", AnnotationMaxSkew)
+		}
+
+		policy.MaxSkew = int32(value)
+	}
+
+	if policy.MaxSkew == 0 {
+		policy.MaxSkew = defaultMaxSkew
+	}
+
+	if err := validateSchedulingMode("anti-affinity", policy.AntiAffinity); err != nil {
+		return eirini.SchedulingConfig{}, err
+	}
+
+	if err := validateSchedulingMode("zone spread", policy.ZoneSpread); err != nil {
+		return eirini.SchedulingConfig{}, err
+	}
+
+	if policy.MaxSkew < 0 {
+		return eirini.SchedulingConfig{}, fmt.Errorf("max skew must be positive, got %d", policy.MaxSkew)
+	}
+
+	return policy, nil
+}
+
+func valueOrDefault(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func validateSchedulingMode(setting, mode string) error {
+	switch mode {
+	case SchedulingNone, SchedulingPreferred, SchedulingRequired:
+		return nil
+	default:
+		return fmt.Errorf("invalid %s %q: must be one of %s, %s or %s", setting, mode, SchedulingNone, SchedulingPreferred, SchedulingRequired)
+	}
+}
+
+// podAntiAffinity keeps the instances of an app on different nodes.
+func podAntiAffinity(policy eirini.SchedulingConfig, selector *metav1.LabelSelector) *corev1.PodAntiAffinity {
+	term := corev1.PodAffinityTerm{
+		TopologyKey: corev1.LabelHostname,
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: toLabelSelectorRequirements(selector),
+		},
+	}
+
+	switch policy.AntiAffinity {
+	case SchedulingRequired:
+		return &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+		}
+	case SchedulingPreferred:
+		return &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{Weight: PodAffinityTermWeight, PodAffinityTerm: term},
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// topologySpreadConstraints spread the instances of an app across zones.
+func topologySpreadConstraints(policy eirini.SchedulingConfig, selector *metav1.LabelSelector) []corev1.TopologySpreadConstraint {
+	whenUnsatisfiable := corev1.ScheduleAnyway
+
+	switch policy.ZoneSpread {
+	case SchedulingRequired:
+		whenUnsatisfiable = corev1.DoNotSchedule
+	case SchedulingPreferred:
+	default:
+		return nil
+	}
+
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           policy.MaxSkew,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: selector.MatchLabels,
+			},
+		},
+	}
+}
@@ -0,0 +1,34 @@
+package stset_test
+
+import (
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateSchedulingConfig", func() {
+	It("accepts the defaults", func() {
+		Expect(stset.ValidateSchedulingConfig(eirini.SchedulingConfig{})).To(Succeed())
+	})
+
+	It("accepts a complete policy", func() {
+		Expect(stset.ValidateSchedulingConfig(eirini.SchedulingConfig{
+			AntiAffinity: stset.SchedulingNone,
+			ZoneSpread:   stset.SchedulingRequired,
+			MaxSkew:      2,
+		})).To(Succeed())
+	})
+
+	It("rejects an unknown anti-affinity", func() {
+		Expect(stset.ValidateSchedulingConfig(eirini.SchedulingConfig{AntiAffinity: "soft"})).To(MatchError(ContainSubstring(`invalid anti-affinity "soft"`)))
+	})
+
+	It("rejects an unknown zone spread", func() {
+		Expect(stset.ValidateSchedulingConfig(eirini.SchedulingConfig{ZoneSpread: "always"})).To(MatchError(ContainSubstring(`invalid zone spread "always"`)))
+	})
+
+	It("rejects a negative max skew", func() {
+		Expect(stset.ValidateSchedulingConfig(eirini.SchedulingConfig{MaxSkew: -1})).To(MatchError(ContainSubstring("max skew")))
+	})
+})
@@ -1,13 +1,37 @@
 package stset
 
 import (
+	"encoding/json"
+
 	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/shared"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type StatefulSetToLRP func(s appsv1.StatefulSet) (*api.LRP, error)
 
+// originalRequest holds the parts of the desire request that cannot be
+// recovered from the statefulset spec itself.
+type originalRequest struct {
+	PlacementTags                           []string          `json:"placement_tags"`
+	EgressRules                             []json.RawMessage `json:"egress_rules"`
+	HealthCheckType                         string            `json:"health_check_type"`
+	HealthCheckHTTPEndpoint                 string            `json:"health_check_http_endpoint"`
+	HealthCheckTimeoutMs                    uint              `json:"health_check_timeout_ms"`
+	HealthCheckInvocationTimeoutMs          uint              `json:"health_check_invocation_timeout_ms"`
+	HealthCheckIntervalMs                   uint              `json:"health_check_interval_ms"`
+	ReadinessHealthCheckType                string            `json:"readiness_health_check_type"`
+	ReadinessHealthCheckHTTPEndpoint        string            `json:"readiness_health_check_http_endpoint"`
+	ReadinessHealthCheckInvocationTimeoutMs uint              `json:"readiness_health_check_invocation_timeout_ms"`
+	ReadinessHealthCheckIntervalMs          uint              `json:"readiness_health_check_interval_ms"`
+	HealthCheckPort                         int32             `json:"health_check_port"`
+	ReadinessHealthCheckPort                int32             `json:"readiness_health_check_port"`
+	StartTimeoutMs                          uint              `json:"start_timeout_ms"`
+	UserDefinedAnnotations                  map[string]string `json:"user_defined_annotations"`
+}
+
 func NewStatefulSetToLRPConverter() StatefulSetToLRP {
 	return MapStatefulSetToLRP
 }
@@ -18,14 +42,20 @@ func (f StatefulSetToLRP) Convert(s appsv1.StatefulSet) (*api.LRP, error) {
 
 func MapStatefulSetToLRP(s appsv1.StatefulSet) (*api.LRP, error) {
 	ports := []int32{}
+	portProtocols := map[int32]string{}
 	container := s.Spec.Template.Spec.Containers[0]
 
 	for _, port := range container.Ports {
 		ports = append(ports, port.ContainerPort)
+
+		if port.Protocol == corev1.ProtocolUDP {
+			portProtocols[port.ContainerPort] = api.ProtocolUDP
+		}
 	}
 
 	memory := container.Resources.Requests.Memory().ScaledValue(resource.Mega)
 	disk := container.Resources.Limits.StorageEphemeral().ScaledValue(resource.Mega)
+	cpu := container.Resources.Requests.Cpu().MilliValue()
 	volMounts := []api.VolumeMount{}
 
 	for _, vol := range container.VolumeMounts {
@@ -35,22 +65,97 @@ func MapStatefulSetToLRP(s appsv1.StatefulSet) (*api.LRP, error) {
 		})
 	}
 
+	routes, err := UnmarshalRoutes(s.Annotations[AnnotationRoutes])
+	if err != nil {
+		return nil, err
+	}
+
+	var request originalRequest
+
+	// statefulsets created by older eirini versions may carry an original
+	// request that is not valid JSON; treat them as having no such settings
+	_ = json.Unmarshal([]byte(s.Annotations[AnnotationOriginalRequest]), &request)
+
+	var port int32
+	if len(ports) != 0 {
+		port = ports[0]
+	}
+
 	return &api.LRP{
 		LRPIdentifier: api.LRPIdentifier{
 			GUID:    s.Labels[LabelGUID],
 			Version: s.Annotations[AnnotationVersion],
 		},
-		AppName:          s.Annotations[AnnotationAppName],
-		SpaceName:        s.Annotations[AnnotationSpaceName],
-		Image:            container.Image,
-		Command:          container.Command,
-		RunningInstances: int(s.Status.ReadyReplicas),
-		TargetInstances:  int(*s.Spec.Replicas),
-		Ports:            ports,
-		LastUpdated:      s.Annotations[AnnotationLastUpdated],
-		AppGUID:          s.Annotations[AnnotationAppID],
-		MemoryMB:         memory,
-		DiskMB:           disk,
-		VolumeMounts:     volMounts,
+		ProcessType:            s.Labels[LabelProcessType],
+		AppName:                s.Annotations[AnnotationAppName],
+		AppGUID:                s.Annotations[AnnotationAppID],
+		OrgName:                s.Annotations[AnnotationOrgName],
+		OrgGUID:                s.Annotations[AnnotationOrgGUID],
+		SpaceName:              s.Annotations[AnnotationSpaceName],
+		SpaceGUID:              s.Annotations[AnnotationSpaceGUID],
+		Image:                  container.Image,
+		Command:                container.Command,
+		Sidecars:               toSidecars(s.Spec.Template.Spec.Containers[1:]),
+		PlacementTags:          request.PlacementTags,
+		Env:                    shared.EnvVarToMap(container.Env),
+		Health:                 toHealthcheck(request, port),
+		Readiness:              toReadinessHealthcheck(request, port),
+		RunningInstances:       int(s.Status.ReadyReplicas),
+		TargetInstances:        int(*s.Spec.Replicas),
+		Ports:                  ports,
+		PortProtocols:          portProtocols,
+		Routes:                 routes,
+		EgressRules:            request.EgressRules,
+		LastUpdated:            s.Annotations[AnnotationLastUpdated],
+		MemoryMB:               memory,
+		DiskMB:                 disk,
+		CPUWeight:              uint8(cpu),
+		VolumeMounts:           volMounts,
+		LRP:                    s.Annotations[AnnotationOriginalRequest],
+		UserDefinedAnnotations: request.UserDefinedAnnotations,
 	}, nil
 }
+
+func toSidecars(containers []corev1.Container) []api.Sidecar {
+	sidecars := []api.Sidecar{}
+
+	for _, c := range containers {
+		sidecars = append(sidecars, api.Sidecar{
+			Name:      c.Name,
+			Command:   c.Command,
+			Image:     c.Image,
+			MemoryMB:  c.Resources.Requests.Memory().ScaledValue(resource.Mega),
+			CPUWeight: uint8(c.Resources.Requests.Cpu().MilliValue()),
+			Env:       shared.EnvVarToMap(c.Env),
+		})
+	}
+
+	return sidecars
+}
+
+func toHealthcheck(request originalRequest, port int32) api.Healthcheck {
+	startTimeoutMs := request.StartTimeoutMs
+	if startTimeoutMs == 0 {
+		startTimeoutMs = request.HealthCheckTimeoutMs
+	}
+
+	return api.Healthcheck{
+		Type:                request.HealthCheckType,
+		Port:                api.HealthCheckPort(request.HealthCheckPort, port),
+		Endpoint:            request.HealthCheckHTTPEndpoint,
+		TimeoutMs:           request.HealthCheckTimeoutMs,
+		StartTimeoutMs:      startTimeoutMs,
+		InvocationTimeoutMs: request.HealthCheckInvocationTimeoutMs,
+		IntervalMs:          request.HealthCheckIntervalMs,
+	}
+}
+
+func toReadinessHealthcheck(request originalRequest, port int32) api.Healthcheck {
+	return api.Healthcheck{
+		Type:                request.ReadinessHealthCheckType,
+		Port:                api.HealthCheckPort(request.ReadinessHealthCheckPort, port),
+		Endpoint:            request.ReadinessHealthCheckHTTPEndpoint,
+		InvocationTimeoutMs: request.ReadinessHealthCheckInvocationTimeoutMs,
+		IntervalMs:          request.ReadinessHealthCheckIntervalMs,
+	}
+}
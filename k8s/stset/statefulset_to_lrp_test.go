@@ -12,6 +12,19 @@ import (
 )
 
 var _ = Describe("Statefulset to LRP Converter", func() {
+	const originalRequest = `{
+		"egress_rules": [{"protocol":"tcp","destinations":["10.0.0.0/8"]}],
+		"placement_tags": ["isolated"],
+		"health_check_type": "http",
+		"health_check_http_endpoint": "/healthz",
+		"health_check_timeout_ms": 60000,
+		"health_check_invocation_timeout_ms": 2000,
+		"health_check_interval_ms": 5000,
+		"readiness_health_check_type": "port",
+		"readiness_health_check_port": 9999,
+		"user_defined_annotations": {"prometheus.io/scrape": "true"}
+	}`
+
 	var lrp *api.LRP
 
 	BeforeEach(func() {
@@ -20,15 +33,21 @@ var _ = Describe("Statefulset to LRP Converter", func() {
 				Name:      "baldur",
 				Namespace: "baldur-ns",
 				Labels: map[string]string{
-					stset.LabelGUID: "Bald-guid",
+					stset.LabelGUID:        "Bald-guid",
+					stset.LabelProcessType: "web",
 				},
 				Annotations: map[string]string{
-					stset.AnnotationProcessGUID: "Baldur-guid",
-					stset.AnnotationLastUpdated: "last-updated-some-time-ago",
-					stset.AnnotationAppID:       "guid_1234",
-					stset.AnnotationVersion:     "version_1234",
-					stset.AnnotationAppName:     "Baldur",
-					stset.AnnotationSpaceName:   "space-foo",
+					stset.AnnotationProcessGUID:     "Baldur-guid",
+					stset.AnnotationLastUpdated:     "last-updated-some-time-ago",
+					stset.AnnotationAppID:           "guid_1234",
+					stset.AnnotationVersion:         "version_1234",
+					stset.AnnotationAppName:         "Baldur",
+					stset.AnnotationSpaceName:       "space-foo",
+					stset.AnnotationSpaceGUID:       "space-guid",
+					stset.AnnotationOrgName:         "org-foo",
+					stset.AnnotationOrgGUID:         "org-guid",
+					stset.AnnotationOriginalRequest: originalRequest,
+					stset.AnnotationRoutes:          `{"http":[{"hostname":"baldur.example.com","port":8888}],"tcp":[{"external_port":1234,"container_port":9999}]}`,
 				},
 			},
 			Spec: appsv1.StatefulSetSpec{
@@ -49,11 +68,17 @@ var _ = Describe("Statefulset to LRP Converter", func() {
 									},
 									{
 										ContainerPort: 9999,
+										Protocol:      corev1.ProtocolUDP,
 									},
 								},
+								Env: []corev1.EnvVar{
+									{Name: "FOO", Value: "bar"},
+									{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+								},
 								Resources: corev1.ResourceRequirements{
 									Requests: corev1.ResourceList{
 										corev1.ResourceMemory: *resource.NewScaledQuantity(1024, resource.Mega),
+										corev1.ResourceCPU:    *resource.NewScaledQuantity(20, resource.Milli),
 									},
 									Limits: corev1.ResourceList{
 										corev1.ResourceEphemeralStorage: *resource.NewScaledQuantity(2048, resource.Mega),
@@ -66,6 +91,18 @@ var _ = Describe("Statefulset to LRP Converter", func() {
 									},
 								},
 							},
+							{
+								Name:    "the-sidecar",
+								Image:   "apm/agent",
+								Command: []string{"run", "sidecar"},
+								Env:     []corev1.EnvVar{{Name: "SIDE", Value: "car"}},
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceMemory: *resource.NewScaledQuantity(128, resource.Mega),
+										corev1.ResourceCPU:    *resource.NewScaledQuantity(5, resource.Milli),
+									},
+								},
+							},
 						},
 					},
 				},
@@ -109,6 +146,67 @@ var _ = Describe("Statefulset to LRP Converter", func() {
 
 	It("should set the correct LRP ports", func() {
 		Expect(lrp.Ports).To(Equal([]int32{8888, 9999}))
+		Expect(lrp.PortProtocols).To(Equal(map[int32]string{9999: api.ProtocolUDP}))
+	})
+
+	It("should set the correct LRP routes", func() {
+		Expect(lrp.Routes).To(Equal(api.Routes{
+			HTTP: []api.HTTPRoute{{Hostname: "baldur.example.com", Port: 8888}},
+			TCP:  []api.TCPRoute{{ExternalPort: 1234, ContainerPort: 9999}},
+		}))
+	})
+
+	It("should recover the egress rules from the original request", func() {
+		Expect(lrp.EgressRules).To(HaveLen(1))
+		Expect(lrp.EgressRules[0]).To(MatchJSON(`{"protocol":"tcp","destinations":["10.0.0.0/8"]}`))
+	})
+
+	It("should keep the original request", func() {
+		Expect(lrp.LRP).To(Equal(originalRequest))
+	})
+
+	It("should set the org, space and process type", func() {
+		Expect(lrp.OrgName).To(Equal("org-foo"))
+		Expect(lrp.OrgGUID).To(Equal("org-guid"))
+		Expect(lrp.SpaceGUID).To(Equal("space-guid"))
+		Expect(lrp.ProcessType).To(Equal("web"))
+	})
+
+	It("should set the env vars with literal values", func() {
+		Expect(lrp.Env).To(Equal(map[string]string{"FOO": "bar"}))
+	})
+
+	It("should set the cpu weight", func() {
+		Expect(lrp.CPUWeight).To(Equal(uint8(20)))
+	})
+
+	It("should set the sidecars", func() {
+		Expect(lrp.Sidecars).To(ConsistOf(api.Sidecar{
+			Name:      "the-sidecar",
+			Image:     "apm/agent",
+			Command:   []string{"run", "sidecar"},
+			MemoryMB:  128,
+			CPUWeight: 5,
+			Env:       map[string]string{"SIDE": "car"},
+		}))
+	})
+
+	It("should recover the health checks from the original request", func() {
+		Expect(lrp.Health).To(Equal(api.Healthcheck{
+			Type:                "http",
+			Port:                8888,
+			Endpoint:            "/healthz",
+			TimeoutMs:           60000,
+			StartTimeoutMs:      60000,
+			InvocationTimeoutMs: 2000,
+			IntervalMs:          5000,
+		}))
+		Expect(lrp.Readiness).To(Equal(api.Healthcheck{Type: "port", Port: 9999}))
+	})
+
+	It("should recover the placement tags and user defined annotations", func() {
+		Expect(lrp.PlacementTags).To(ConsistOf("isolated"))
+		Expect(lrp.UserDefinedAnnotations).To(Equal(map[string]string{"prometheus.io/scrape": "true"}))
 	})
 
 	It("should set the correct LRP LastUpdated", func() {
@@ -2,38 +2,66 @@ package stset
 
 import (
 	"context"
+	"encoding/json"
 
 	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/shared"
 	"code.cloudfoundry.org/lager"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
 )
 
 //counterfeiter:generate . StatefulSetUpdater
+//counterfeiter:generate . EnvSecretsClient
 
 type StatefulSetUpdater interface {
 	Update(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) (*appsv1.StatefulSet, error)
 }
 
+type EnvSecretsClient interface {
+	Get(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+	Create(ctx context.Context, namespace string, secret *corev1.Secret) (*corev1.Secret, error)
+	Update(ctx context.Context, namespace string, secret *corev1.Secret) (*corev1.Secret, error)
+	SetOwner(ctx context.Context, secret *corev1.Secret, owner metav1.Object) (*corev1.Secret, error)
+}
+
 type Updater struct {
-	logger             lager.Logger
-	statefulSetUpdater StatefulSetUpdater
-	getStatefulSet     getStatefulSetFunc
-	pdbUpdater         PodDisruptionBudgetUpdater
+	logger                    lager.Logger
+	statefulSetUpdater        StatefulSetUpdater
+	envSecrets                EnvSecretsClient
+	getStatefulSet            getStatefulSetFunc
+	lrpToStatefulSetConverter LRPToStatefulSetConverter
+	pdbUpdater                PodDisruptionBudgetUpdater
+	routeUpdater              RouteUpdater
+	netpolUpdater             NetworkPolicyUpdater
+	imageRewrites             map[string]string
 }
 
 func NewUpdater(
 	logger lager.Logger,
 	statefulSetGetter StatefulSetByLRPIdentifierGetter,
 	statefulSetUpdater StatefulSetUpdater,
+	envSecrets EnvSecretsClient,
+	lrpToStatefulSetConverter LRPToStatefulSetConverter,
 	pdbUpdater PodDisruptionBudgetUpdater,
+	routeUpdater RouteUpdater,
+	netpolUpdater NetworkPolicyUpdater,
+	imageRewrites map[string]string,
 ) Updater {
 	return Updater{
-		logger:             logger,
-		statefulSetUpdater: statefulSetUpdater,
-		pdbUpdater:         pdbUpdater,
-		getStatefulSet:     newGetStatefulSetFunc(statefulSetGetter),
+		logger:                    logger,
+		statefulSetUpdater:        statefulSetUpdater,
+		envSecrets:                envSecrets,
+		lrpToStatefulSetConverter: lrpToStatefulSetConverter,
+		pdbUpdater:                pdbUpdater,
+		routeUpdater:              routeUpdater,
+		netpolUpdater:             netpolUpdater,
+		imageRewrites:             imageRewrites,
+		getStatefulSet:            newGetStatefulSetFunc(statefulSetGetter),
 	}
 }
 
@@ -55,11 +83,15 @@ func (u *Updater) update(ctx context.Context, lrp *api.LRP) error {
 		return err
 	}
 
-	updatedStatefulSet, err := u.getUpdatedStatefulSetObj(statefulSet,
-		lrp.TargetInstances,
-		lrp.LastUpdated,
-		lrp.Image,
-	)
+	if originalRequestChanged(statefulSet, lrp) {
+		if err = u.updateEnvSecret(ctx, statefulSet, lrp); err != nil {
+			logger.Error("failed-to-update-env-secret", err, lager.Data{"namespace": statefulSet.Namespace})
+
+			return err
+		}
+	}
+
+	updatedStatefulSet, err := u.getUpdatedStatefulSetObj(statefulSet, lrp)
 	if err != nil {
 		logger.Error("failed-to-get-updated-statefulset", err)
 
@@ -78,23 +110,177 @@ func (u *Updater) update(ctx context.Context, lrp *api.LRP) error {
 		return errors.Wrap(err, "failed to delete pod disruption budget")
 	}
 
+	if err = u.routeUpdater.Update(ctx, statefulSet, lrp); err != nil {
+		logger.Error("failed-to-update-routes", err, lager.Data{"namespace": statefulSet.Namespace})
+
+		return errors.Wrap(err, "failed to update routes")
+	}
+
+	if err = u.netpolUpdater.Update(ctx, statefulSet, lrp); err != nil {
+		logger.Error("failed-to-update-network-policy", err, lager.Data{"namespace": statefulSet.Namespace})
+
+		return errors.Wrap(err, "failed to update network policy")
+	}
+
 	return nil
 }
 
-func (u *Updater) getUpdatedStatefulSetObj(sts *appsv1.StatefulSet, instances int, lastUpdated, image string) (*appsv1.StatefulSet, error) {
+func (u *Updater) getUpdatedStatefulSetObj(sts *appsv1.StatefulSet, lrp *api.LRP) (*appsv1.StatefulSet, error) {
 	updatedSts := sts.DeepCopy()
 
-	count := int32(instances)
+	count := int32(lrp.TargetInstances)
 	updatedSts.Spec.Replicas = &count
-	updatedSts.Annotations[AnnotationLastUpdated] = lastUpdated
+	updatedSts.Annotations[AnnotationLastUpdated] = lrp.LastUpdated
+
+	if lrp.Image != "" {
+		image := shared.RewriteImage(lrp.Image, u.imageRewrites)
+		containers := updatedSts.Spec.Template.Spec.Containers
+		appImage := appContainerImage(containers)
 
-	if image != "" {
-		for i, container := range updatedSts.Spec.Template.Spec.Containers {
-			if container.Name == ApplicationContainerName {
-				updatedSts.Spec.Template.Spec.Containers[i].Image = image
+		// sidecars without an image of their own run the app image
+		for i, container := range containers {
+			if container.Name == ApplicationContainerName || container.Image == appImage {
+				containers[i].Image = image
+				containers[i].ImagePullPolicy = shared.ImagePullPolicy(image)
 			}
 		}
 	}
 
+	if originalRequestChanged(sts, lrp) {
+		if err := u.updatePodTemplate(updatedSts, lrp); err != nil {
+			return nil, err
+		}
+	}
+
+	delete(updatedSts.Annotations, AnnotationRoutes)
+
+	if !lrp.Routes.IsEmpty() {
+		routes, err := MarshalRoutes(lrp.Routes)
+		if err != nil {
+			return nil, err
+		}
+
+		updatedSts.Annotations[AnnotationRoutes] = routes
+	}
+
 	return updatedSts, nil
 }
+
+func (u *Updater) updateEnvSecret(ctx context.Context, sts *appsv1.StatefulSet, lrp *api.LRP) error {
+	desired := GenerateEnvSecret(sts.Name, lrp)
+
+	secret, err := u.envSecrets.Get(ctx, sts.Namespace, EnvSecretName(sts.Name))
+	if k8serrors.IsNotFound(err) {
+		if desired == nil {
+			return nil
+		}
+
+		desired.Namespace = sts.Namespace
+
+		secret, err = u.envSecrets.Create(ctx, sts.Namespace, desired)
+		if err != nil {
+			return errors.Wrap(err, "failed to create env secret")
+		}
+
+		_, err = u.envSecrets.SetOwner(ctx, secret, sts)
+
+		return errors.Wrap(err, "failed to set owner to the env secret")
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to get env secret")
+	}
+
+	updated := secret.DeepCopy()
+	updated.Data = nil
+	updated.StringData = map[string]string{}
+
+	if desired != nil {
+		updated.StringData = desired.StringData
+	}
+
+	_, err = u.envSecrets.Update(ctx, updated.Namespace, updated)
+
+	return errors.Wrap(err, "failed to update env secret")
+}
+
+func appContainerImage(containers []corev1.Container) string {
+	for _, container := range containers {
+		if container.Name == ApplicationContainerName {
+			return container.Image
+		}
+	}
+
+	return ""
+}
+
+// originalRequestChanged tells whether the LRP carries a desire request that
+// differs from the one the statefulset was last generated from. Only then is
+// the LRP complete enough to regenerate the pod template from. As the stored
+// request is redacted, environment changes are detected by checksum.
+func originalRequestChanged(sts *appsv1.StatefulSet, lrp *api.LRP) bool {
+	if lrp.LRP == "" {
+		return false
+	}
+
+	return RedactOriginalRequest(lrp.LRP) != sts.Annotations[AnnotationOriginalRequest] ||
+		lrpEnvChecksum(sts.Name, lrp) != sts.Annotations[AnnotationEnvChecksum]
+}
+
+func (u *Updater) updatePodTemplate(sts *appsv1.StatefulSet, lrp *api.LRP) error {
+	desiredStatefulSet, err := u.lrpToStatefulSetConverter.Convert(sts.Name, lrp, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert lrp to statefulset")
+	}
+
+	podSpec := desiredStatefulSet.Spec.Template.Spec
+	// private registry secrets are only created on desire, keep referring to the existing ones
+	podSpec.ImagePullSecrets = sts.Spec.Template.Spec.ImagePullSecrets
+	sts.Spec.Template.Spec = podSpec
+
+	if sts.Spec.Template.Annotations == nil {
+		sts.Spec.Template.Annotations = map[string]string{}
+	}
+
+	removeStaleUserDefinedAnnotations(sts, desiredStatefulSet)
+
+	redactedRequest := RedactOriginalRequest(lrp.LRP)
+
+	sts.Annotations[AnnotationOriginalRequest] = redactedRequest
+	if _, ok := sts.Spec.Template.Annotations[AnnotationOriginalRequest]; ok {
+		sts.Spec.Template.Annotations[AnnotationOriginalRequest] = redactedRequest
+	}
+
+	delete(sts.Annotations, AnnotationEnvChecksum)
+	delete(sts.Spec.Template.Annotations, AnnotationEnvChecksum)
+
+	if checksum, ok := desiredStatefulSet.Annotations[AnnotationEnvChecksum]; ok {
+		setEnvChecksum(sts, checksum)
+	}
+
+	for k, v := range lrp.UserDefinedAnnotations {
+		sts.Annotations[k] = v
+		sts.Spec.Template.Annotations[k] = v
+	}
+
+	return nil
+}
+
+// removeStaleUserDefinedAnnotations removes the annotations that were user
+// defined in the original request of the statefulset and are no longer set on
+// the desired statefulset.
+func removeStaleUserDefinedAnnotations(sts, desiredStatefulSet *appsv1.StatefulSet) {
+	var request originalRequest
+	if err := json.Unmarshal([]byte(sts.Annotations[AnnotationOriginalRequest]), &request); err != nil {
+		return
+	}
+
+	for k := range request.UserDefinedAnnotations {
+		if _, ok := desiredStatefulSet.Annotations[k]; ok {
+			continue
+		}
+
+		delete(sts.Annotations, k)
+		delete(sts.Spec.Template.Annotations, k)
+	}
+}
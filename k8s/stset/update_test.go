@@ -1,6 +1,8 @@
 package stset_test
 
 import (
+	"context"
+
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s/stset"
 	"code.cloudfoundry.org/eirini/k8s/stset/stsetfakes"
@@ -21,10 +23,16 @@ var _ = Describe("Update", func() {
 		logger             lager.Logger
 		statefulSetGetter  *stsetfakes.FakeStatefulSetByLRPIdentifierGetter
 		statefulSetUpdater *stsetfakes.FakeStatefulSetUpdater
+		envSecrets         *stsetfakes.FakeEnvSecretsClient
+		lrpToStatefulSet   *stsetfakes.FakeLRPToStatefulSetConverter
 		pdbUpdater         *stsetfakes.FakePodDisruptionBudgetUpdater
+		routeUpdater       *stsetfakes.FakeRouteUpdater
+		netpolUpdater      *stsetfakes.FakeNetworkPolicyUpdater
+		imageRewrites      map[string]string
 
-		updatedLRP *api.LRP
-		err        error
+		updatedLRP   *api.LRP
+		statefulSets []appsv1.StatefulSet
+		err          error
 	)
 
 	BeforeEach(func() {
@@ -32,7 +40,16 @@ var _ = Describe("Update", func() {
 
 		statefulSetGetter = new(stsetfakes.FakeStatefulSetByLRPIdentifierGetter)
 		statefulSetUpdater = new(stsetfakes.FakeStatefulSetUpdater)
+		envSecrets = new(stsetfakes.FakeEnvSecretsClient)
+		envSecrets.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "baldur-env"))
+		envSecrets.CreateStub = func(_ context.Context, _ string, secret *corev1.Secret) (*corev1.Secret, error) {
+			return secret, nil
+		}
+		lrpToStatefulSet = new(stsetfakes.FakeLRPToStatefulSetConverter)
 		pdbUpdater = new(stsetfakes.FakePodDisruptionBudgetUpdater)
+		routeUpdater = new(stsetfakes.FakeRouteUpdater)
+		netpolUpdater = new(stsetfakes.FakeNetworkPolicyUpdater)
+		imageRewrites = nil
 
 		updatedLRP = &api.LRP{
 			LRPIdentifier: api.LRPIdentifier{
@@ -48,20 +65,22 @@ var _ = Describe("Update", func() {
 
 		replicas := int32(3)
 
-		st := []appsv1.StatefulSet{
+		statefulSets = []appsv1.StatefulSet{
 			{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "baldur",
 					Namespace: "the-namespace",
 					Annotations: map[string]string{
-						stset.AnnotationProcessGUID: "Baldur-guid",
-						stset.AnnotationLastUpdated: "never",
+						stset.AnnotationProcessGUID:     "Baldur-guid",
+						stset.AnnotationLastUpdated:     "never",
+						stset.AnnotationOriginalRequest: `{"instances":3}`,
 					},
 				},
 				Spec: appsv1.StatefulSetSpec{
 					Replicas: &replicas,
 					Template: corev1.PodTemplateSpec{
 						Spec: corev1.PodSpec{
+							ImagePullSecrets: []corev1.LocalObjectReference{{Name: "private-registry-secret"}},
 							Containers: []corev1.Container{
 								{Name: "another-container", Image: "another/image"},
 								{Name: stset.ApplicationContainerName, Image: "old/image"},
@@ -72,11 +91,11 @@ var _ = Describe("Update", func() {
 			},
 		}
 
-		statefulSetGetter.GetByLRPIdentifierReturns(st, nil)
+		statefulSetGetter.GetByLRPIdentifierReturns(statefulSets, nil)
 	})
 
 	JustBeforeEach(func() {
-		updater := stset.NewUpdater(logger, statefulSetGetter, statefulSetUpdater, pdbUpdater)
+		updater := stset.NewUpdater(logger, statefulSetGetter, statefulSetUpdater, envSecrets, lrpToStatefulSet, pdbUpdater, routeUpdater, netpolUpdater, imageRewrites)
 		err = updater.Update(ctx, updatedLRP)
 	})
 
@@ -94,6 +113,43 @@ var _ = Describe("Update", func() {
 		Expect(*st.Spec.Replicas).To(Equal(int32(5)))
 		Expect(st.Spec.Template.Spec.Containers[0].Image).To(Equal("another/image"))
 		Expect(st.Spec.Template.Spec.Containers[1].Image).To(Equal("new/image"))
+		Expect(st.Spec.Template.Spec.Containers[1].ImagePullPolicy).To(Equal(corev1.PullAlways))
+	})
+
+	When("the new image matches an image rewrite", func() {
+		BeforeEach(func() {
+			imageRewrites = map[string]string{"docker.io/new": "mirror.example.com/new"}
+		})
+
+		It("updates the app container with the rewritten image", func() {
+			_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+			Expect(st.Spec.Template.Spec.Containers[0].Image).To(Equal("another/image"))
+			Expect(st.Spec.Template.Spec.Containers[1].Image).To(Equal("mirror.example.com/new/image"))
+		})
+	})
+
+	When("a sidecar runs the app image", func() {
+		BeforeEach(func() {
+			podSpec := &statefulSets[0].Spec.Template.Spec
+			podSpec.Containers = append(podSpec.Containers, corev1.Container{Name: "the-sidecar", Image: "old/image"})
+		})
+
+		It("updates the sidecar image too", func() {
+			_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+			Expect(st.Spec.Template.Spec.Containers[0].Image).To(Equal("another/image"))
+			Expect(st.Spec.Template.Spec.Containers[2].Image).To(Equal("new/image"))
+		})
+	})
+
+	When("the new image is pinned to a digest", func() {
+		BeforeEach(func() {
+			updatedLRP.Image = "new/image@sha256:0ac9d3ac4f35e4b33e9c34ae6ca9bf8e48e7f2d9e1b0f74d9bca3e8bf4a2e6a1"
+		})
+
+		It("only pulls the image when it is not present", func() {
+			_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+			Expect(st.Spec.Template.Spec.Containers[1].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		})
 	})
 
 	It("updates the pod disruption budget", func() {
@@ -114,6 +170,236 @@ var _ = Describe("Update", func() {
 		})
 	})
 
+	It("updates the routes", func() {
+		Expect(routeUpdater.UpdateCallCount()).To(Equal(1))
+		_, actualStatefulSet, actualLRP := routeUpdater.UpdateArgsForCall(0)
+		Expect(actualStatefulSet.Name).To(Equal("baldur"))
+		Expect(actualLRP).To(Equal(updatedLRP))
+	})
+
+	When("updating the routes fails", func() {
+		BeforeEach(func() {
+			routeUpdater.UpdateReturns(errors.New("route-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError(ContainSubstring("route-error")))
+		})
+	})
+
+	It("updates the network policy", func() {
+		Expect(netpolUpdater.UpdateCallCount()).To(Equal(1))
+		_, actualStatefulSet, actualLRP := netpolUpdater.UpdateArgsForCall(0)
+		Expect(actualStatefulSet.Name).To(Equal("baldur"))
+		Expect(actualLRP).To(Equal(updatedLRP))
+	})
+
+	When("updating the network policy fails", func() {
+		BeforeEach(func() {
+			netpolUpdater.UpdateReturns(errors.New("netpol-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError(ContainSubstring("netpol-error")))
+		})
+	})
+
+	When("the routes change", func() {
+		BeforeEach(func() {
+			updatedLRP.Routes = api.Routes{
+				HTTP: []api.HTTPRoute{{Hostname: "baldur.example.com", Port: 8080}},
+			}
+		})
+
+		It("stores the routes on the statefulset", func() {
+			_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+			Expect(st.Annotations).To(HaveKeyWithValue(stset.AnnotationRoutes, `{"http":[{"hostname":"baldur.example.com","port":8080}]}`))
+		})
+	})
+
+	It("does not regenerate the pod template", func() {
+		Expect(lrpToStatefulSet.ConvertCallCount()).To(BeZero())
+	})
+
+	It("does not touch the env secret", func() {
+		Expect(envSecrets.GetCallCount()).To(BeZero())
+	})
+
+	When("the lrp carries a changed original request", func() {
+		BeforeEach(func() {
+			updatedLRP.LRP = `{"instances":5}`
+			updatedLRP.UserDefinedAnnotations = map[string]string{"prometheus.io/scrape": "true"}
+
+			lrpToStatefulSet.ConvertReturns(&appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  stset.ApplicationContainerName,
+									Image: "new/image",
+									Env:   []corev1.EnvVar{{Name: "FOO", Value: "new"}},
+								},
+							},
+							ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-secret"}},
+						},
+					},
+				},
+			}, nil)
+		})
+
+		It("regenerates the pod template from the lrp", func() {
+			Expect(lrpToStatefulSet.ConvertCallCount()).To(Equal(1))
+			name, lrp, _ := lrpToStatefulSet.ConvertArgsForCall(0)
+			Expect(name).To(Equal("baldur"))
+			Expect(lrp).To(Equal(updatedLRP))
+
+			_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+			Expect(st.Spec.Template.Spec.Containers).To(ConsistOf(corev1.Container{
+				Name:  stset.ApplicationContainerName,
+				Image: "new/image",
+				Env:   []corev1.EnvVar{{Name: "FOO", Value: "new"}},
+			}))
+		})
+
+		It("keeps the existing image pull secrets", func() {
+			_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+			Expect(st.Spec.Template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "private-registry-secret"}))
+		})
+
+		It("keeps the original request annotation in sync", func() {
+			_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+			Expect(st.Annotations).To(HaveKeyWithValue(stset.AnnotationOriginalRequest, `{"instances":5}`))
+		})
+
+		It("does not create an env secret", func() {
+			Expect(envSecrets.CreateCallCount()).To(BeZero())
+		})
+
+		When("user defined annotations have been removed", func() {
+			BeforeEach(func() {
+				statefulSets[0].Annotations[stset.AnnotationOriginalRequest] = `{"instances":3,"user_defined_annotations":{"old/annotation":"x","prometheus.io/scrape":"false"}}`
+				statefulSets[0].Annotations["old/annotation"] = "x"
+				statefulSets[0].Annotations["prometheus.io/scrape"] = "false"
+				statefulSets[0].Spec.Template.Annotations = map[string]string{
+					"old/annotation":       "x",
+					"prometheus.io/scrape": "false",
+				}
+
+				lrpToStatefulSet.ConvertReturns(&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{"prometheus.io/scrape": "true"},
+					},
+				}, nil)
+			})
+
+			It("removes them from the statefulset and the pod template", func() {
+				_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+				Expect(st.Annotations).NotTo(HaveKey("old/annotation"))
+				Expect(st.Spec.Template.Annotations).NotTo(HaveKey("old/annotation"))
+			})
+
+			It("keeps the ones that are still user defined", func() {
+				_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+				Expect(st.Annotations).To(HaveKeyWithValue("prometheus.io/scrape", "true"))
+				Expect(st.Spec.Template.Annotations).To(HaveKeyWithValue("prometheus.io/scrape", "true"))
+			})
+
+			It("keeps the annotations that were not user defined", func() {
+				_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+				Expect(st.Annotations).To(HaveKeyWithValue(stset.AnnotationProcessGUID, "Baldur-guid"))
+			})
+		})
+
+		When("the lrp has an environment", func() {
+			BeforeEach(func() {
+				updatedLRP.LRP = `{"instances":5,"environment":{"FOO":"new"}}`
+				updatedLRP.Env = map[string]string{"FOO": "new"}
+			})
+
+			It("redacts the environment in the original request annotation", func() {
+				_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+				Expect(st.Annotations[stset.AnnotationOriginalRequest]).To(MatchJSON(`{"instances":5,"environment":{"FOO":""}}`))
+			})
+
+			It("creates the env secret", func() {
+				Expect(envSecrets.CreateCallCount()).To(Equal(1))
+				_, actualNamespace, secret := envSecrets.CreateArgsForCall(0)
+				Expect(actualNamespace).To(Equal("the-namespace"))
+				Expect(secret.Name).To(Equal("baldur-env"))
+				Expect(secret.StringData).To(Equal(map[string]string{"opi.FOO": "new"}))
+			})
+
+			It("makes the statefulset own the env secret", func() {
+				Expect(envSecrets.SetOwnerCallCount()).To(Equal(1))
+				_, secret, owner := envSecrets.SetOwnerArgsForCall(0)
+				Expect(secret.Name).To(Equal("baldur-env"))
+				Expect(owner.GetName()).To(Equal("baldur"))
+			})
+
+			When("the env secret exists", func() {
+				BeforeEach(func() {
+					envSecrets.GetReturns(&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: "baldur-env", Namespace: "the-namespace"},
+						Data:       map[string][]byte{"opi.FOO": []byte("old"), "opi.BAR": []byte("bar")},
+					}, nil)
+				})
+
+				It("replaces the content of the env secret", func() {
+					Expect(envSecrets.CreateCallCount()).To(BeZero())
+					Expect(envSecrets.UpdateCallCount()).To(Equal(1))
+					_, _, secret := envSecrets.UpdateArgsForCall(0)
+					Expect(secret.Data).To(BeEmpty())
+					Expect(secret.StringData).To(Equal(map[string]string{"opi.FOO": "new"}))
+				})
+			})
+
+			When("updating the env secret fails", func() {
+				BeforeEach(func() {
+					envSecrets.GetReturns(nil, errors.New("get-error"))
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError(ContainSubstring("get-error")))
+				})
+
+				It("does not update the statefulset", func() {
+					Expect(statefulSetUpdater.UpdateCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		It("applies the user defined annotations", func() {
+			_, _, st := statefulSetUpdater.UpdateArgsForCall(0)
+			Expect(st.Annotations).To(HaveKeyWithValue("prometheus.io/scrape", "true"))
+			Expect(st.Spec.Template.Annotations).To(HaveKeyWithValue("prometheus.io/scrape", "true"))
+		})
+
+		When("converting the lrp fails", func() {
+			BeforeEach(func() {
+				lrpToStatefulSet.ConvertReturns(nil, errors.New("convert-error"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("convert-error")))
+			})
+
+			It("does not update the statefulset", func() {
+				Expect(statefulSetUpdater.UpdateCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	When("the lrp carries an unchanged original request", func() {
+		BeforeEach(func() {
+			updatedLRP.LRP = `{"instances":3}`
+		})
+
+		It("does not regenerate the pod template", func() {
+			Expect(lrpToStatefulSet.ConvertCallCount()).To(BeZero())
+		})
+	})
+
 	When("the image is missing", func() {
 		BeforeEach(func() {
 			updatedLRP.Image = ""
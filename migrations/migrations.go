@@ -19,6 +19,8 @@ func CreateMigrationStepsProvider(stSetClient *client.StatefulSet, pdbClient *cl
 		NewAdoptPDB(pdbClient),
 		NewAdoptStatefulsetRegistrySecret(secretsClient),
 		NewAdoptJobRegistrySecret(secretsClient),
+		NewMoveEnvToSecret(secretsClient, stSetClient),
+		NewRewritePDB(pdbClient),
 	}
 
 	return NewMigrationStepsProvider(migrationSteps)
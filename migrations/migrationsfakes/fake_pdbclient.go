@@ -6,12 +6,12 @@ import (
 	"sync"
 
 	"code.cloudfoundry.org/eirini/migrations"
-	v1 "k8s.io/api/apps/v1"
-	"k8s.io/api/policy/v1beta1"
+	v1a "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/policy/v1"
 )
 
 type FakePDBClient struct {
-	GetStub        func(context.Context, string, string) (*v1beta1.PodDisruptionBudget, error)
+	GetStub        func(context.Context, string, string) (*v1.PodDisruptionBudget, error)
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
 		arg1 context.Context
@@ -19,33 +19,48 @@ type FakePDBClient struct {
 		arg3 string
 	}
 	getReturns struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}
 	getReturnsOnCall map[int]struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}
-	SetOwnerStub        func(context.Context, *v1beta1.PodDisruptionBudget, *v1.StatefulSet) (*v1beta1.PodDisruptionBudget, error)
+	SetOwnerStub        func(context.Context, *v1.PodDisruptionBudget, *v1a.StatefulSet) (*v1.PodDisruptionBudget, error)
 	setOwnerMutex       sync.RWMutex
 	setOwnerArgsForCall []struct {
 		arg1 context.Context
-		arg2 *v1beta1.PodDisruptionBudget
-		arg3 *v1.StatefulSet
+		arg2 *v1.PodDisruptionBudget
+		arg3 *v1a.StatefulSet
 	}
 	setOwnerReturns struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}
 	setOwnerReturnsOnCall map[int]struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}
+	UpdateStub        func(context.Context, string, *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error)
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1.PodDisruptionBudget
+	}
+	updateReturns struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakePDBClient) Get(arg1 context.Context, arg2 string, arg3 string) (*v1beta1.PodDisruptionBudget, error) {
+func (fake *FakePDBClient) Get(arg1 context.Context, arg2 string, arg3 string) (*v1.PodDisruptionBudget, error) {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
 	fake.getArgsForCall = append(fake.getArgsForCall, struct {
@@ -72,7 +87,7 @@ func (fake *FakePDBClient) GetCallCount() int {
 	return len(fake.getArgsForCall)
 }
 
-func (fake *FakePDBClient) GetCalls(stub func(context.Context, string, string) (*v1beta1.PodDisruptionBudget, error)) {
+func (fake *FakePDBClient) GetCalls(stub func(context.Context, string, string) (*v1.PodDisruptionBudget, error)) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = stub
@@ -85,39 +100,39 @@ func (fake *FakePDBClient) GetArgsForCall(i int) (context.Context, string, strin
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakePDBClient) GetReturns(result1 *v1beta1.PodDisruptionBudget, result2 error) {
+func (fake *FakePDBClient) GetReturns(result1 *v1.PodDisruptionBudget, result2 error) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = nil
 	fake.getReturns = struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakePDBClient) GetReturnsOnCall(i int, result1 *v1beta1.PodDisruptionBudget, result2 error) {
+func (fake *FakePDBClient) GetReturnsOnCall(i int, result1 *v1.PodDisruptionBudget, result2 error) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = nil
 	if fake.getReturnsOnCall == nil {
 		fake.getReturnsOnCall = make(map[int]struct {
-			result1 *v1beta1.PodDisruptionBudget
+			result1 *v1.PodDisruptionBudget
 			result2 error
 		})
 	}
 	fake.getReturnsOnCall[i] = struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakePDBClient) SetOwner(arg1 context.Context, arg2 *v1beta1.PodDisruptionBudget, arg3 *v1.StatefulSet) (*v1beta1.PodDisruptionBudget, error) {
+func (fake *FakePDBClient) SetOwner(arg1 context.Context, arg2 *v1.PodDisruptionBudget, arg3 *v1a.StatefulSet) (*v1.PodDisruptionBudget, error) {
 	fake.setOwnerMutex.Lock()
 	ret, specificReturn := fake.setOwnerReturnsOnCall[len(fake.setOwnerArgsForCall)]
 	fake.setOwnerArgsForCall = append(fake.setOwnerArgsForCall, struct {
 		arg1 context.Context
-		arg2 *v1beta1.PodDisruptionBudget
-		arg3 *v1.StatefulSet
+		arg2 *v1.PodDisruptionBudget
+		arg3 *v1a.StatefulSet
 	}{arg1, arg2, arg3})
 	stub := fake.SetOwnerStub
 	fakeReturns := fake.setOwnerReturns
@@ -138,41 +153,107 @@ func (fake *FakePDBClient) SetOwnerCallCount() int {
 	return len(fake.setOwnerArgsForCall)
 }
 
-func (fake *FakePDBClient) SetOwnerCalls(stub func(context.Context, *v1beta1.PodDisruptionBudget, *v1.StatefulSet) (*v1beta1.PodDisruptionBudget, error)) {
+func (fake *FakePDBClient) SetOwnerCalls(stub func(context.Context, *v1.PodDisruptionBudget, *v1a.StatefulSet) (*v1.PodDisruptionBudget, error)) {
 	fake.setOwnerMutex.Lock()
 	defer fake.setOwnerMutex.Unlock()
 	fake.SetOwnerStub = stub
 }
 
-func (fake *FakePDBClient) SetOwnerArgsForCall(i int) (context.Context, *v1beta1.PodDisruptionBudget, *v1.StatefulSet) {
+func (fake *FakePDBClient) SetOwnerArgsForCall(i int) (context.Context, *v1.PodDisruptionBudget, *v1a.StatefulSet) {
 	fake.setOwnerMutex.RLock()
 	defer fake.setOwnerMutex.RUnlock()
 	argsForCall := fake.setOwnerArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakePDBClient) SetOwnerReturns(result1 *v1beta1.PodDisruptionBudget, result2 error) {
+func (fake *FakePDBClient) SetOwnerReturns(result1 *v1.PodDisruptionBudget, result2 error) {
 	fake.setOwnerMutex.Lock()
 	defer fake.setOwnerMutex.Unlock()
 	fake.SetOwnerStub = nil
 	fake.setOwnerReturns = struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakePDBClient) SetOwnerReturnsOnCall(i int, result1 *v1beta1.PodDisruptionBudget, result2 error) {
+func (fake *FakePDBClient) SetOwnerReturnsOnCall(i int, result1 *v1.PodDisruptionBudget, result2 error) {
 	fake.setOwnerMutex.Lock()
 	defer fake.setOwnerMutex.Unlock()
 	fake.SetOwnerStub = nil
 	if fake.setOwnerReturnsOnCall == nil {
 		fake.setOwnerReturnsOnCall = make(map[int]struct {
-			result1 *v1beta1.PodDisruptionBudget
+			result1 *v1.PodDisruptionBudget
 			result2 error
 		})
 	}
 	fake.setOwnerReturnsOnCall[i] = struct {
-		result1 *v1beta1.PodDisruptionBudget
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePDBClient) Update(arg1 context.Context, arg2 string, arg3 *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error) {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1.PodDisruptionBudget
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1, arg2, arg3})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePDBClient) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *FakePDBClient) UpdateCalls(stub func(context.Context, string, *v1.PodDisruptionBudget) (*v1.PodDisruptionBudget, error)) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *FakePDBClient) UpdateArgsForCall(i int) (context.Context, string, *v1.PodDisruptionBudget) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePDBClient) UpdateReturns(result1 *v1.PodDisruptionBudget, result2 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 *v1.PodDisruptionBudget
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePDBClient) UpdateReturnsOnCall(i int, result1 *v1.PodDisruptionBudget, result2 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 *v1.PodDisruptionBudget
+			result2 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 *v1.PodDisruptionBudget
 		result2 error
 	}{result1, result2}
 }
@@ -184,6 +265,8 @@ func (fake *FakePDBClient) Invocations() map[string][][]interface{} {
 	defer fake.getMutex.RUnlock()
 	fake.setOwnerMutex.RLock()
 	defer fake.setOwnerMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,114 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+//counterfeiter:generate . EnvSecretsClient
+
+type EnvSecretsClient interface {
+	Create(ctx context.Context, namespace string, secret *corev1.Secret) (*corev1.Secret, error)
+	Update(ctx context.Context, namespace string, secret *corev1.Secret) (*corev1.Secret, error)
+	SetOwner(ctx context.Context, secret *corev1.Secret, owner metav1.Object) (*corev1.Secret, error)
+}
+
+//counterfeiter:generate . StatefulSetUpdater
+
+type StatefulSetUpdater interface {
+	Update(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) (*appsv1.StatefulSet, error)
+}
+
+type MoveEnvToSecret struct {
+	secretsClient      EnvSecretsClient
+	statefulSetUpdater StatefulSetUpdater
+}
+
+func NewMoveEnvToSecret(secretsClient EnvSecretsClient, statefulSetUpdater StatefulSetUpdater) MoveEnvToSecret {
+	return MoveEnvToSecret{
+		secretsClient:      secretsClient,
+		statefulSetUpdater: statefulSetUpdater,
+	}
+}
+
+func (m MoveEnvToSecret) Apply(ctx context.Context, obj runtime.Object) error {
+	stSet, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return fmt.Errorf("expected *v1.StatefulSet, got: %T", obj)
+	}
+
+	updatedStSet := stSet.DeepCopy()
+
+	var envSecret *corev1.Secret
+	if _, ok := stSet.Annotations[stset.AnnotationEnvChecksum]; !ok {
+		envSecret = stset.MoveEnvToSecret(updatedStSet)
+	}
+
+	// statefulsets whose env has already been moved may still have the
+	// private registry password in their original request
+	redactOriginalRequest(updatedStSet.Annotations)
+	redactOriginalRequest(updatedStSet.Spec.Template.Annotations)
+
+	if envSecret == nil {
+		return m.updateRedactedStatefulSet(ctx, stSet, updatedStSet)
+	}
+
+	// the secret has to exist before the pods referring to it are restarted
+	if err := m.createOrUpdateSecret(ctx, stSet.Namespace, envSecret); err != nil {
+		return err
+	}
+
+	updatedStSet, err := m.statefulSetUpdater.Update(ctx, stSet.Namespace, updatedStSet)
+	if err != nil {
+		return errors.Wrap(err, "failed to update statefulset")
+	}
+
+	envSecret.Namespace = stSet.Namespace
+
+	_, err = m.secretsClient.SetOwner(ctx, envSecret, updatedStSet)
+
+	return errors.Wrapf(err, "failed to set ownership on secret %s", envSecret.Name)
+}
+
+func (m MoveEnvToSecret) updateRedactedStatefulSet(ctx context.Context, stSet, redactedStSet *appsv1.StatefulSet) error {
+	if reflect.DeepEqual(stSet.Annotations, redactedStSet.Annotations) &&
+		reflect.DeepEqual(stSet.Spec.Template.Annotations, redactedStSet.Spec.Template.Annotations) {
+		return nil
+	}
+
+	_, err := m.statefulSetUpdater.Update(ctx, stSet.Namespace, redactedStSet)
+
+	return errors.Wrap(err, "failed to update statefulset")
+}
+
+func (m MoveEnvToSecret) createOrUpdateSecret(ctx context.Context, namespace string, secret *corev1.Secret) error {
+	_, err := m.secretsClient.Create(ctx, namespace, secret)
+	if k8serrors.IsAlreadyExists(err) {
+		_, err = m.secretsClient.Update(ctx, namespace, secret)
+	}
+
+	return errors.Wrapf(err, "failed to create secret %s", secret.Name)
+}
+
+func redactOriginalRequest(annotations map[string]string) {
+	if request, ok := annotations[stset.AnnotationOriginalRequest]; ok {
+		annotations[stset.AnnotationOriginalRequest] = stset.RedactOriginalRequest(request)
+	}
+}
+
+func (m MoveEnvToSecret) SequenceID() int {
+	return MoveEnvToSecretSequenceID
+}
+
+func (m MoveEnvToSecret) AppliesTo() ObjectType {
+	return StatefulSetObjectType
+}
@@ -0,0 +1,212 @@
+package migrations_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/migrations"
+	"code.cloudfoundry.org/eirini/migrations/migrationsfakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Move Env To Secret", func() {
+	var (
+		moveEnvMigration   migrations.MoveEnvToSecret
+		stSet              *appsv1.StatefulSet
+		secretsClient      *migrationsfakes.FakeEnvSecretsClient
+		statefulSetUpdater *migrationsfakes.FakeStatefulSetUpdater
+		migrateErr         error
+	)
+
+	BeforeEach(func() {
+		secretsClient = new(migrationsfakes.FakeEnvSecretsClient)
+		statefulSetUpdater = new(migrationsfakes.FakeStatefulSetUpdater)
+		statefulSetUpdater.UpdateStub = func(_ context.Context, _ string, statefulSet *appsv1.StatefulSet) (*appsv1.StatefulSet, error) {
+			return statefulSet, nil
+		}
+		moveEnvMigration = migrations.NewMoveEnvToSecret(secretsClient, statefulSetUpdater)
+
+		originalRequest := `{"instances":1,"environment":{"FOO":"secret"}}`
+		stSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-stateful-set",
+				Namespace: "my-namespace",
+				Annotations: map[string]string{
+					stset.AnnotationOriginalRequest: originalRequest,
+				},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							stset.AnnotationOriginalRequest: originalRequest,
+						},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: stset.ApplicationContainerName,
+								Env: []corev1.EnvVar{
+									{Name: "FOO", Value: "secret"},
+									{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		migrateErr = moveEnvMigration.Apply(ctx, stSet)
+	})
+
+	It("succeeds", func() {
+		Expect(migrateErr).NotTo(HaveOccurred())
+	})
+
+	It("creates the env secret", func() {
+		Expect(secretsClient.CreateCallCount()).To(Equal(1))
+		_, actualNamespace, actualSecret := secretsClient.CreateArgsForCall(0)
+		Expect(actualNamespace).To(Equal("my-namespace"))
+		Expect(actualSecret.Name).To(Equal("my-stateful-set-env"))
+		Expect(actualSecret.StringData).To(Equal(map[string]string{"opi.FOO": "secret"}))
+	})
+
+	It("replaces the environment values with references to the env secret", func() {
+		Expect(statefulSetUpdater.UpdateCallCount()).To(Equal(1))
+		_, actualNamespace, actualStatefulSet := statefulSetUpdater.UpdateArgsForCall(0)
+		Expect(actualNamespace).To(Equal("my-namespace"))
+		Expect(actualStatefulSet.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: "FOO", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "my-stateful-set-env"},
+				Key:                  "opi.FOO",
+			}}},
+			stSet.Spec.Template.Spec.Containers[0].Env[1],
+		))
+		Expect(actualStatefulSet.Annotations).To(HaveKey(stset.AnnotationEnvChecksum))
+		Expect(actualStatefulSet.Spec.Template.Annotations).To(HaveKey(stset.AnnotationEnvChecksum))
+	})
+
+	It("redacts the original request", func() {
+		_, _, actualStatefulSet := statefulSetUpdater.UpdateArgsForCall(0)
+		Expect(actualStatefulSet.Annotations[stset.AnnotationOriginalRequest]).To(MatchJSON(`{"instances":1,"environment":{"FOO":""}}`))
+		Expect(actualStatefulSet.Spec.Template.Annotations[stset.AnnotationOriginalRequest]).To(MatchJSON(`{"instances":1,"environment":{"FOO":""}}`))
+	})
+
+	It("does not modify the original statefulset object", func() {
+		Expect(stSet.Spec.Template.Spec.Containers[0].Env[0].Value).To(Equal("secret"))
+	})
+
+	It("sets the ownership of the env secret", func() {
+		Expect(secretsClient.SetOwnerCallCount()).To(Equal(1))
+		_, actualSecret, actualOwner := secretsClient.SetOwnerArgsForCall(0)
+		Expect(actualSecret.Name).To(Equal("my-stateful-set-env"))
+		Expect(actualSecret.Namespace).To(Equal("my-namespace"))
+		Expect(actualOwner.GetName()).To(Equal("my-stateful-set"))
+	})
+
+	When("the env secret already exists", func() {
+		BeforeEach(func() {
+			secretsClient.CreateReturns(nil, k8serrors.NewAlreadyExists(schema.GroupResource{}, "my-stateful-set-env"))
+		})
+
+		It("updates it", func() {
+			Expect(migrateErr).NotTo(HaveOccurred())
+			Expect(secretsClient.UpdateCallCount()).To(Equal(1))
+			_, _, actualSecret := secretsClient.UpdateArgsForCall(0)
+			Expect(actualSecret.StringData).To(Equal(map[string]string{"opi.FOO": "secret"}))
+		})
+	})
+
+	When("creating the env secret fails", func() {
+		BeforeEach(func() {
+			secretsClient.CreateReturns(nil, errors.New("create-secret-failed"))
+		})
+
+		It("returns the error", func() {
+			Expect(migrateErr).To(MatchError(ContainSubstring("create-secret-failed")))
+		})
+
+		It("does not update the statefulset", func() {
+			Expect(statefulSetUpdater.UpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("updating the statefulset fails", func() {
+		BeforeEach(func() {
+			statefulSetUpdater.UpdateReturns(nil, errors.New("update-failed"))
+		})
+
+		It("returns the error", func() {
+			Expect(migrateErr).To(MatchError(ContainSubstring("update-failed")))
+		})
+	})
+
+	When("setting the owner of the secret fails", func() {
+		BeforeEach(func() {
+			secretsClient.SetOwnerReturns(nil, errors.New("set-owner-failed"))
+		})
+
+		It("returns the error", func() {
+			Expect(migrateErr).To(MatchError(ContainSubstring("set-owner-failed")))
+		})
+	})
+
+	When("the statefulset has already been migrated", func() {
+		BeforeEach(func() {
+			stSet.Annotations[stset.AnnotationEnvChecksum] = "checksum"
+			stSet.Annotations[stset.AnnotationOriginalRequest] = `{"instances":1,"environment":{"FOO":""}}`
+			stSet.Spec.Template.Annotations[stset.AnnotationOriginalRequest] = `{"instances":1,"environment":{"FOO":""}}`
+		})
+
+		It("is noop", func() {
+			Expect(secretsClient.CreateCallCount()).To(BeZero())
+			Expect(statefulSetUpdater.UpdateCallCount()).To(BeZero())
+		})
+
+		When("the original request still contains the registry password", func() {
+			BeforeEach(func() {
+				originalRequest := `{"instances":1,"environment":{"FOO":""},"lifecycle":{"docker_lifecycle":{"image":"my/image","registry_username":"user","registry_password":"hunter2"}}}`
+				stSet.Annotations[stset.AnnotationOriginalRequest] = originalRequest
+				stSet.Spec.Template.Annotations[stset.AnnotationOriginalRequest] = originalRequest
+			})
+
+			It("redacts the registry password", func() {
+				Expect(migrateErr).NotTo(HaveOccurred())
+				Expect(statefulSetUpdater.UpdateCallCount()).To(Equal(1))
+				_, _, actualStatefulSet := statefulSetUpdater.UpdateArgsForCall(0)
+				redactedRequest := `{"instances":1,"environment":{"FOO":""},"lifecycle":{"docker_lifecycle":{"image":"my/image","registry_username":"user","registry_password":""}}}`
+				Expect(actualStatefulSet.Annotations[stset.AnnotationOriginalRequest]).To(MatchJSON(redactedRequest))
+				Expect(actualStatefulSet.Spec.Template.Annotations[stset.AnnotationOriginalRequest]).To(MatchJSON(redactedRequest))
+			})
+
+			It("does not create the env secret", func() {
+				Expect(secretsClient.CreateCallCount()).To(BeZero())
+				Expect(secretsClient.SetOwnerCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	When("the statefulset has no environment values", func() {
+		BeforeEach(func() {
+			stSet.Spec.Template.Spec.Containers[0].Env = stSet.Spec.Template.Spec.Containers[0].Env[1:]
+			stSet.Annotations[stset.AnnotationOriginalRequest] = `{"instances":1}`
+			stSet.Spec.Template.Annotations[stset.AnnotationOriginalRequest] = `{"instances":1}`
+		})
+
+		It("is noop", func() {
+			Expect(migrateErr).NotTo(HaveOccurred())
+			Expect(secretsClient.CreateCallCount()).To(BeZero())
+			Expect(statefulSetUpdater.UpdateCallCount()).To(BeZero())
+		})
+	})
+})
@@ -7,4 +7,6 @@ const (
 	AdoptPDBSequenceID          = 2
 	AdoptStSetSecretSequenceID  = 3
 	AdoptJobSecretSequenceID    = 4
+	MoveEnvToSecretSequenceID   = 5
+	RewritePDBSequenceID        = 6
 )
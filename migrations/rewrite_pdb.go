@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RewritePDB writes the pod disruption budget of a statefulset back through
+// the policy/v1 API, so that budgets created through policy/v1beta1 are
+// stored as policy/v1 before that API is removed from the cluster.
+type RewritePDB struct {
+	pdbClient PDBClient
+}
+
+func NewRewritePDB(pdbClient PDBClient) RewritePDB {
+	return RewritePDB{
+		pdbClient: pdbClient,
+	}
+}
+
+func (m RewritePDB) Apply(ctx context.Context, obj runtime.Object) error {
+	stSet, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return fmt.Errorf("expected *v1.StatefulSet, got: %T", obj)
+	}
+
+	pdb, err := m.pdbClient.Get(ctx, stSet.Namespace, stSet.Name)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "rewrite-pdb-migration-get-pdb-failed")
+	}
+
+	// an empty selector matches no pods in policy/v1beta1, but all pods of
+	// the namespace in policy/v1
+	if isEmptySelector(pdb.Spec.Selector) {
+		pdb.Spec.Selector = stSet.Spec.Selector
+	}
+
+	if _, err := m.pdbClient.Update(ctx, stSet.Namespace, pdb); err != nil {
+		return errors.Wrap(err, "rewrite-pdb-migration-update-pdb-failed")
+	}
+
+	return nil
+}
+
+func isEmptySelector(selector *metav1.LabelSelector) bool {
+	return selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0)
+}
+
+func (m RewritePDB) SequenceID() int {
+	return RewritePDBSequenceID
+}
+
+func (m RewritePDB) AppliesTo() ObjectType {
+	return StatefulSetObjectType
+}
@@ -0,0 +1,154 @@
+package migrations_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/eirini/migrations"
+	"code.cloudfoundry.org/eirini/migrations/migrationsfakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("PDB Rewrite", func() {
+	var (
+		rewritePDBMigration migrations.RewritePDB
+		stSet               runtime.Object
+		selector            *metav1.LabelSelector
+		pdb                 *policyv1.PodDisruptionBudget
+		pdbClient           *migrationsfakes.FakePDBClient
+		migrateErr          error
+	)
+
+	BeforeEach(func() {
+		pdbClient = new(migrationsfakes.FakePDBClient)
+		rewritePDBMigration = migrations.NewRewritePDB(pdbClient)
+
+		selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "asdf"}}
+		stSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-stateful-set",
+				Namespace: "my-namespace",
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: selector,
+			},
+		}
+
+		half := intstr.FromString("50%")
+		pdb = &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-stateful-set",
+				Namespace: "my-namespace",
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable: &half,
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "pdb"}},
+			},
+		}
+
+		pdbClient.GetReturns(pdb, nil)
+	})
+
+	JustBeforeEach(func() {
+		migrateErr = rewritePDBMigration.Apply(ctx, stSet)
+	})
+
+	It("succeeds", func() {
+		Expect(migrateErr).NotTo(HaveOccurred())
+	})
+
+	It("gets the PDB matching the stateful set", func() {
+		Expect(pdbClient.GetCallCount()).To(Equal(1))
+		_, actualNS, actualName := pdbClient.GetArgsForCall(0)
+		Expect(actualNS).To(Equal("my-namespace"))
+		Expect(actualName).To(Equal("my-stateful-set"))
+	})
+
+	It("writes the PDB back unchanged", func() {
+		Expect(pdbClient.UpdateCallCount()).To(Equal(1))
+		_, actualNS, actualPDB := pdbClient.UpdateArgsForCall(0)
+		Expect(actualNS).To(Equal("my-namespace"))
+		Expect(actualPDB).To(Equal(pdb))
+		Expect(actualPDB.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": "pdb"}))
+	})
+
+	It("has the right sequence ID", func() {
+		Expect(rewritePDBMigration.SequenceID()).To(Equal(migrations.RewritePDBSequenceID))
+	})
+
+	It("applies to statefulsets", func() {
+		Expect(rewritePDBMigration.AppliesTo()).To(Equal(migrations.StatefulSetObjectType))
+	})
+
+	When("the PDB has no selector", func() {
+		BeforeEach(func() {
+			pdb.Spec.Selector = nil
+		})
+
+		It("selects the pods of the stateful set", func() {
+			Expect(pdbClient.UpdateCallCount()).To(Equal(1))
+			_, _, actualPDB := pdbClient.UpdateArgsForCall(0)
+			Expect(actualPDB.Spec.Selector).To(Equal(selector))
+		})
+	})
+
+	When("the PDB has an empty selector", func() {
+		BeforeEach(func() {
+			pdb.Spec.Selector = &metav1.LabelSelector{}
+		})
+
+		It("selects the pods of the stateful set instead of all pods", func() {
+			Expect(pdbClient.UpdateCallCount()).To(Equal(1))
+			_, _, actualPDB := pdbClient.UpdateArgsForCall(0)
+			Expect(actualPDB.Spec.Selector).To(Equal(selector))
+		})
+	})
+
+	When("the stateful set has no PDB", func() {
+		BeforeEach(func() {
+			pdbClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "my-stateful-set"))
+		})
+
+		It("does nothing", func() {
+			Expect(migrateErr).NotTo(HaveOccurred())
+			Expect(pdbClient.UpdateCallCount()).To(BeZero())
+		})
+	})
+
+	When("a non-statefulset object is received", func() {
+		BeforeEach(func() {
+			stSet = &appsv1.ReplicaSet{}
+		})
+
+		It("errors", func() {
+			Expect(migrateErr).To(MatchError("expected *v1.StatefulSet, got: *v1.ReplicaSet"))
+		})
+	})
+
+	When("getting the PDB fails", func() {
+		BeforeEach(func() {
+			pdbClient.GetReturns(nil, errors.New("no pdb for you"))
+		})
+
+		It("bubbles up the error", func() {
+			Expect(migrateErr).To(MatchError(ContainSubstring("no pdb for you")))
+		})
+	})
+
+	When("updating the PDB fails", func() {
+		BeforeEach(func() {
+			pdbClient.UpdateReturns(nil, errors.New("can't update"))
+		})
+
+		It("bubbles up the error", func() {
+			Expect(migrateErr).To(MatchError(ContainSubstring("can't update")))
+		})
+	})
+})
@@ -7,6 +7,9 @@ const (
 	EnvEiriniNamespace            = "EIRINI_NAMESPACE"
 	EnvDownloadURL                = "DOWNLOAD_URL"
 	EnvDropletUploadURL           = "DROPLET_UPLOAD_URL"
+	EnvBuildpacks                 = "BUILDPACKS"
+	EnvOutputImage                = "OUTPUT_IMAGE"
+	EnvDockerConfig               = "DOCKER_CONFIG"
 	EnvAppID                      = "APP_ID"
 	EnvCompletionCallback         = "COMPLETION_CALLBACK"
 	EnvEiriniAddress              = "EIRINI_ADDRESS"
@@ -45,6 +48,12 @@ var ErrNotFound = errors.New("not found")
 
 var ErrInvalidInstanceIndex = errors.New("invalid instance index")
 
+var ErrInvalidTaskRequest = errors.New("invalid task request")
+
+var ErrStagingQueueFull = errors.New("staging queue is full")
+
+var ErrImageNotAllowed = errors.New("image not allowed by policy")
+
 type CommonConfig struct {
 	KubeConfig `yaml:",inline"`
 
@@ -53,10 +62,53 @@ type CommonConfig struct {
 	AllowRunImageAsRoot                     bool   `yaml:"allow_run_image_as_root"`
 	UnsafeAllowAutomountServiceAccountToken bool   `yaml:"unsafe_allow_automount_service_account_token"`
 	DefaultMinAvailableInstances            string `yaml:"default_min_available_instances"`
+	IngressClassName                        string `yaml:"ingress_class_name"`
+
+	// PlacementTagNodeSelectors maps CF placement tags (isolation segments)
+	// to the node labels a workload with that tag must be scheduled on.
+	// Tags without an entry require the node to have the
+	// cloudfoundry.org/placement-tag.<tag> label, with any value.
+	PlacementTagNodeSelectors map[string]map[string]string `yaml:"placement_tag_node_selectors"`
+
+	// ImageRewrites maps image name prefixes, such as a registry host or a
+	// repository, to the prefix they are replaced with in workload images and
+	// when staging, e.g. to pull docker hub images through a mirror. Prefixes
+	// are matched against the fully qualified image name
+	// (docker.io/library/busybox) and the longest matching prefix wins.
+	// Private registry credentials are used for the rewritten registry.
+	ImageRewrites map[string]string `yaml:"image_rewrites"`
+
+	// DropletRegistry is the repository prefix, e.g. registry.example.com/droplets,
+	// that buildpack staging pushes droplet images to and that buildpack
+	// apps and tasks run their droplet from. The registry secret must allow
+	// pulling from it.
+	DropletRegistry string `yaml:"droplet_registry"`
+
+	// Scheduling controls how the instances of an app are spread across
+	// nodes and zones.
+	Scheduling SchedulingConfig `yaml:"scheduling"`
 
 	WorkloadsNamespace string
 }
 
+// SchedulingConfig configures how the instances of an app are spread. Apps
+// can override each setting with the cloudfoundry.org/anti_affinity,
+// cloudfoundry.org/zone_spread and cloudfoundry.org/max_skew annotations.
+type SchedulingConfig struct {
+	// AntiAffinity keeps the instances of an app on different nodes. It is
+	// "preferred" (the default), "required" or "none". Required
+	// anti-affinity leaves instances pending if there are not enough nodes.
+	AntiAffinity string `yaml:"anti_affinity"`
+	// ZoneSpread spreads the instances of an app evenly across the
+	// topology.kubernetes.io/zone node label. It is "none" (the default),
+	// "preferred" or "required". Required spreading leaves instances
+	// pending rather than exceed MaxSkew.
+	ZoneSpread string `yaml:"zone_spread"`
+	// MaxSkew is the largest allowed difference between the number of
+	// instances of an app in any two zones. It defaults to 1.
+	MaxSkew int32 `yaml:"max_skew"`
+}
+
 type APIConfig struct {
 	CommonConfig `yaml:",inline"`
 
@@ -65,6 +117,80 @@ type APIConfig struct {
 	ServePlaintext            bool   `yaml:"serve_plaintext"`
 	TLSPort                   int    `yaml:"tls_port"`
 	PlaintextPort             int    `yaml:"plaintext_port"`
+	PrometheusPort            int    `yaml:"prometheus_port"`
+
+	InformerCacheEnabled       bool `yaml:"informer_cache_enabled"`
+	InformerCacheResyncSeconds int  `yaml:"informer_cache_resync_seconds"`
+
+	// StagingWorkers is the number of docker stagings that run at the same
+	// time. Up to StagingQueueSize further stagings wait for a worker; any
+	// more are rejected until the queue drains.
+	StagingWorkers   int `yaml:"staging_workers"`
+	StagingQueueSize int `yaml:"staging_queue_size"`
+	// StagingTimeoutSeconds limits how long fetching the metadata of the
+	// image being staged may take.
+	StagingTimeoutSeconds int `yaml:"staging_timeout_seconds"`
+
+	// ImageOS and ImageArchitecture choose the image that docker staging
+	// resolves for multi-arch images. They default to the platform the API
+	// runs on.
+	ImageOS           string `yaml:"image_os"`
+	ImageArchitecture string `yaml:"image_architecture"`
+
+	// RegistryCAFiles maps registry hosts (host[:port]) to a PEM file of CA
+	// certificates that docker staging trusts in addition to the system
+	// ones when fetching image metadata from that registry.
+	RegistryCAFiles map[string]string `yaml:"registry_ca_files"`
+	// InsecureRegistries lists the registry hosts (host[:port]) whose TLS
+	// certificates docker staging does not verify. Plain HTTP is used if
+	// the registry does not serve HTTPS.
+	InsecureRegistries []string `yaml:"insecure_registries"`
+
+	// ImagePolicy restricts the images that apps and tasks can be desired
+	// with and that docker staging accepts.
+	ImagePolicy ImagePolicyConfig `yaml:"image_policy"`
+
+	// BuildpackStaging configures the jobs that stage buildpack apps. It is
+	// disabled unless a builder image is set.
+	BuildpackStaging BuildpackStagingConfig `yaml:"buildpack_staging"`
+
+	// CallbackOutboxEnabled stores staging and task cancellation callbacks
+	// in the default workloads namespace instead of posting them to the
+	// cloud controller directly. The task reporter delivers them, so its
+	// workloads namespace must be the same, or empty to watch all
+	// namespaces.
+	CallbackOutboxEnabled bool `yaml:"callback_outbox_enabled"`
+}
+
+// BuildpackStagingConfig configures buildpack staging. The downloader
+// extracts the app package from DOWNLOAD_URL into /workspace. The builder
+// builds it with the BUILDPACKS, pushes the droplet image to OUTPUT_IMAGE
+// using the registry secret mounted at DOCKER_CONFIG, and writes the
+// detected process types as JSON to its termination message. The cloud
+// controller's DROPLET_UPLOAD_URL is passed on to the builder if it sent one.
+type BuildpackStagingConfig struct {
+	DownloaderImage string `yaml:"downloader_image"`
+	BuilderImage    string `yaml:"builder_image"`
+	// RegistrySecretName is the docker config secret used to push droplet
+	// images. It defaults to the registry secret of the workloads.
+	RegistrySecretName string `yaml:"registry_secret_name"`
+}
+
+// ImagePolicyConfig configures which images are allowed. Registries are
+// given as host[:port], Docker Hub images are in the docker.io registry.
+type ImagePolicyConfig struct {
+	// AllowedRegistries, if not empty, are the only registries images can
+	// come from.
+	AllowedRegistries []string `yaml:"allowed_registries"`
+	DeniedRegistries  []string `yaml:"denied_registries"`
+	// RequireDigest only allows apps and tasks with images pinned to a
+	// digest. Docker staging pins the images it stages, so it still accepts
+	// tags.
+	RequireDigest bool `yaml:"require_digest"`
+	// BannedTags, such as latest, cannot be used to reference images.
+	// Images without a tag or digest use the latest tag, images pinned to a
+	// digest are not affected.
+	BannedTags []string `yaml:"banned_tags"`
 }
 
 type ControllerConfig struct {
@@ -76,6 +202,15 @@ type ControllerConfig struct {
 	LeaderElectionNamespace string
 }
 
+type LRPReconcilerConfig struct {
+	CommonConfig        `yaml:",inline"`
+	PrometheusPort      int `yaml:"prometheus_port"`
+	ResyncPeriodSeconds int `yaml:"resync_period_seconds"`
+
+	LeaderElectionID        string
+	LeaderElectionNamespace string
+}
+
 type KubeConfig struct {
 	ConfigPath string `yaml:"kube_config_path"`
 }
@@ -96,8 +231,18 @@ type TaskReporterConfig struct {
 	LeaderElectionID             string
 	LeaderElectionNamespace      string
 	CompletionCallbackRetryLimit int `yaml:"completion_callback_retry_limit"`
+	CallbackOutboxRetryLimit     int `yaml:"callback_outbox_retry_limit"`
 	TTLSeconds                   int `yaml:"ttl_seconds"`
+	PrometheusPort               int `yaml:"prometheus_port"`
+
+	// CallbackOutboxEnabled stores the results of buildpack staging in the
+	// callback outbox, like the API does, instead of posting them to the
+	// cloud controller directly. It requires a WorkloadsNamespace.
+	CallbackOutboxEnabled bool `yaml:"callback_outbox_enabled"`
 
+	// WorkloadsNamespace restricts the task reporter to one namespace, all
+	// namespaces if empty. Callbacks stored by the API in its default
+	// workloads namespace are only delivered if it is watched.
 	WorkloadsNamespace string
 
 	KubeConfig `yaml:",inline"`
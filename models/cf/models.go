@@ -9,12 +9,46 @@ type VolumeMount struct {
 	MountDir string `json:"mount_dir"`
 }
 
+// DesiredLRP mirrors the Diego BBS representation of a desired LRP, so
+// that the Cloud Controller can compare it with what it expects to run.
 type DesiredLRP struct {
-	ProcessGUID string                     `json:"process_guid"`
-	Instances   int32                      `json:"instances"`
-	Routes      map[string]json.RawMessage `json:"routes,omitempty"`
-	Annotation  string                     `json:"annotation"`
-	Image       string                     `json:"image"`
+	ProcessGUID          string                     `json:"process_guid"`
+	Instances            int32                      `json:"instances"`
+	Routes               map[string]json.RawMessage `json:"routes,omitempty"`
+	Annotation           string                     `json:"annotation"`
+	Image                string                     `json:"image"`
+	EnvironmentVariables []EnvironmentVariable      `json:"environment_variables"`
+	Ports                []int32                    `json:"ports"`
+	MemoryMB             int64                      `json:"memory_mb"`
+	DiskMB               int64                      `json:"disk_mb"`
+	CPUWeight            uint8                      `json:"cpu_weight"`
+	StartTimeoutMs       uint                       `json:"start_timeout_ms"`
+	CheckDefinition      *CheckDefinition           `json:"check_definition,omitempty"`
+	PlacementTags        []string                   `json:"placement_tags,omitempty"`
+	EgressRules          []json.RawMessage          `json:"egress_rules,omitempty"`
+}
+
+type CheckDefinition struct {
+	Checks          []Check `json:"checks"`
+	ReadinessChecks []Check `json:"readiness_checks,omitempty"`
+}
+
+type Check struct {
+	TCPCheck  *TCPCheck  `json:"tcp_check,omitempty"`
+	HTTPCheck *HTTPCheck `json:"http_check,omitempty"`
+}
+
+type TCPCheck struct {
+	Port             int32 `json:"port"`
+	ConnectTimeoutMs uint  `json:"connect_timeout_ms,omitempty"`
+	IntervalMs       uint  `json:"interval_ms,omitempty"`
+}
+
+type HTTPCheck struct {
+	Port             int32  `json:"port"`
+	RequestTimeoutMs uint   `json:"request_timeout_ms,omitempty"`
+	Path             string `json:"path"`
+	IntervalMs       uint   `json:"interval_ms,omitempty"`
 }
 
 type DesiredLRPResponse struct {
@@ -22,35 +56,57 @@ type DesiredLRPResponse struct {
 }
 
 type DesireLRPRequest struct {
-	GUID                    string                     `json:"guid"`
-	Version                 string                     `json:"version"`
-	ProcessGUID             string                     `json:"process_guid"`
-	ProcessType             string                     `json:"process_type"`
-	AppGUID                 string                     `json:"app_guid"`
-	AppName                 string                     `json:"app_name"`
-	SpaceGUID               string                     `json:"space_guid"`
-	SpaceName               string                     `json:"space_name"`
-	OrganizationGUID        string                     `json:"organization_guid"`
-	OrganizationName        string                     `json:"organization_name"`
-	Namespace               string                     `json:"namespace"`
-	PlacementTags           []string                   `json:"placement_tags"`
-	Ports                   []int32                    `json:"ports"`
-	Routes                  map[string]json.RawMessage `json:"routes"`
-	Environment             map[string]string          `json:"environment"`
-	EgressRules             []json.RawMessage          `json:"egress_rules"`
-	NumInstances            int                        `json:"instances"`
-	LastUpdated             string                     `json:"last_updated"`
-	HealthCheckType         string                     `json:"health_check_type"`
-	HealthCheckHTTPEndpoint string                     `json:"health_check_http_endpoint"`
-	HealthCheckTimeoutMs    uint                       `json:"health_check_timeout_ms"`
-	StartTimeoutMs          uint                       `json:"start_timeout_ms"`
-	MemoryMB                int64                      `json:"memory_mb"`
-	DiskMB                  int64                      `json:"disk_mb"`
-	CPUWeight               uint8                      `json:"cpu_weight"`
-	VolumeMounts            []VolumeMount              `json:"volume_mounts"`
-	Lifecycle               Lifecycle                  `json:"lifecycle"`
-	UserDefinedAnnotations  map[string]string          `json:"user_defined_annotations"`
-	LRP                     string
+	GUID                                    string                     `json:"guid"`
+	Version                                 string                     `json:"version"`
+	ProcessGUID                             string                     `json:"process_guid"`
+	ProcessType                             string                     `json:"process_type"`
+	AppGUID                                 string                     `json:"app_guid"`
+	AppName                                 string                     `json:"app_name"`
+	SpaceGUID                               string                     `json:"space_guid"`
+	SpaceName                               string                     `json:"space_name"`
+	OrganizationGUID                        string                     `json:"organization_guid"`
+	OrganizationName                        string                     `json:"organization_name"`
+	Namespace                               string                     `json:"namespace"`
+	PlacementTags                           []string                   `json:"placement_tags"`
+	Ports                                   []int32                    `json:"ports"`
+	PortProtocols                           map[int32]string           `json:"port_protocols"`
+	Routes                                  map[string]json.RawMessage `json:"routes"`
+	Environment                             map[string]string          `json:"environment"`
+	EgressRules                             []json.RawMessage          `json:"egress_rules"`
+	NumInstances                            int                        `json:"instances"`
+	LastUpdated                             string                     `json:"last_updated"`
+	HealthCheckType                         string                     `json:"health_check_type"`
+	HealthCheckHTTPEndpoint                 string                     `json:"health_check_http_endpoint"`
+	HealthCheckTimeoutMs                    uint                       `json:"health_check_timeout_ms"`
+	HealthCheckInvocationTimeoutMs          uint                       `json:"health_check_invocation_timeout_ms"`
+	HealthCheckIntervalMs                   uint                       `json:"health_check_interval_ms"`
+	HealthCheckPort                         int32                      `json:"health_check_port"`
+	ReadinessHealthCheckType                string                     `json:"readiness_health_check_type"`
+	ReadinessHealthCheckHTTPEndpoint        string                     `json:"readiness_health_check_http_endpoint"`
+	ReadinessHealthCheckInvocationTimeoutMs uint                       `json:"readiness_health_check_invocation_timeout_ms"`
+	ReadinessHealthCheckIntervalMs          uint                       `json:"readiness_health_check_interval_ms"`
+	ReadinessHealthCheckPort                int32                      `json:"readiness_health_check_port"`
+	StartTimeoutMs                          uint                       `json:"start_timeout_ms"`
+	MemoryMB                                int64                      `json:"memory_mb"`
+	DiskMB                                  int64                      `json:"disk_mb"`
+	CPUWeight                               uint8                      `json:"cpu_weight"`
+	VolumeMounts                            []VolumeMount              `json:"volume_mounts"`
+	Lifecycle                               Lifecycle                  `json:"lifecycle"`
+	Sidecars                                []Sidecar                  `json:"sidecars"`
+	UserDefinedAnnotations                  map[string]string          `json:"user_defined_annotations"`
+	LRP                                     string                     `json:"-"`
+}
+
+// Sidecar is an additional process running next to the app process in each
+// instance. It only runs with the process types it lists, or with all of them
+// if it lists none. Sidecars run the app image unless they specify one.
+type Sidecar struct {
+	Name         string   `json:"name"`
+	Command      []string `json:"command"`
+	MemoryMB     int64    `json:"memory_mb"`
+	CPUWeight    uint8    `json:"cpu_weight"`
+	ProcessTypes []string `json:"process_types"`
+	Image        string   `json:"image"`
 }
 
 type DesiredLRPSchedulingInfo struct {
@@ -73,7 +129,8 @@ type DesiredLRPLifecycleResponse struct {
 }
 
 type Lifecycle struct {
-	DockerLifecycle *DockerLifecycle `json:"docker_lifecycle"`
+	DockerLifecycle    *DockerLifecycle    `json:"docker_lifecycle"`
+	BuildpackLifecycle *BuildpackLifecycle `json:"buildpack_lifecycle"`
 }
 
 type DockerLifecycle struct {
@@ -83,6 +140,14 @@ type DockerLifecycle struct {
 	RegistryPassword string   `json:"registry_password"`
 }
 
+// BuildpackLifecycle describes a workload running the droplet built by
+// buildpack staging.
+type BuildpackLifecycle struct {
+	DropletGUID  string `json:"droplet_guid"`
+	DropletHash  string `json:"droplet_hash"`
+	StartCommand string `json:"start_command"`
+}
+
 type TaskRequest struct {
 	GUID               string                `json:"guid"`
 	Name               string                `json:"name"`
@@ -95,7 +160,12 @@ type TaskRequest struct {
 	Namespace          string                `json:"namespace"`
 	CompletionCallback string                `json:"completion_callback"`
 	Environment        []EnvironmentVariable `json:"environment"`
+	PlacementTags      []string              `json:"placement_tags"`
 	Lifecycle          Lifecycle             `json:"lifecycle"`
+	MemoryMB           int64                 `json:"memory_mb"`
+	DiskMB             int64                 `json:"disk_mb"`
+	CPUWeight          uint8                 `json:"cpu_weight"`
+	ResultFile         string                `json:"result_file"`
 }
 
 type TaskResponse struct {
@@ -105,9 +175,12 @@ type TaskResponse struct {
 type TasksResponse []TaskResponse
 
 type TaskCompletedRequest struct {
-	TaskGUID      string `json:"task_guid"`
-	Failed        bool   `json:"failed"`
-	FailureReason string `json:"failure_reason"`
+	TaskGUID           string `json:"task_guid"`
+	Failed             bool   `json:"failed"`
+	FailureReason      string `json:"failure_reason"`
+	ExitCode           int32  `json:"exit_code"`
+	TerminationMessage string `json:"termination_message,omitempty"`
+	Result             string `json:"result"`
 }
 
 type StagingRequest struct {
@@ -134,7 +207,8 @@ type StagingCompletedRequest struct {
 }
 
 type StagingLifecycle struct {
-	DockerLifecycle *StagingDockerLifecycle `json:"docker_lifecycle"`
+	DockerLifecycle    *StagingDockerLifecycle    `json:"docker_lifecycle"`
+	BuildpackLifecycle *StagingBuildpackLifecycle `json:"buildpack_lifecycle"`
 }
 
 type StagingDockerLifecycle struct {
@@ -143,6 +217,19 @@ type StagingDockerLifecycle struct {
 	RegistryPassword string `json:"registry_password"`
 }
 
+type StagingBuildpackLifecycle struct {
+	AppBitsDownloadURI string      `json:"app_bits_download_uri"`
+	DropletUploadURI   string      `json:"droplet_upload_uri"`
+	Buildpacks         []Buildpack `json:"buildpacks"`
+}
+
+type Buildpack struct {
+	Name       string `json:"name"`
+	Key        string `json:"key"`
+	URL        string `json:"url"`
+	SkipDetect bool   `json:"skip_detect"`
+}
+
 type EnvironmentVariable struct {
 	Name  string `json:"name"`
 	Value string `json:"value"`
@@ -154,10 +241,32 @@ type UpdateDesiredLRPRequest struct {
 	Update  DesiredLRPUpdate `json:"update,omitempty"`
 }
 
+// DesiredLRPUpdate describes changes to a running LRP. Fields that are
+// omitted from the request keep their current value.
 type DesiredLRPUpdate struct {
-	Instances  int    `json:"instances"`
-	Annotation string `json:"annotation"`
-	Image      string `json:"image"`
+	Instances                               int                        `json:"instances"`
+	Routes                                  map[string]json.RawMessage `json:"routes,omitempty"`
+	Annotation                              string                     `json:"annotation"`
+	Image                                   string                     `json:"image"`
+	Environment                             map[string]string          `json:"environment,omitempty"`
+	MemoryMB                                *int64                     `json:"memory_mb,omitempty"`
+	DiskMB                                  *int64                     `json:"disk_mb,omitempty"`
+	CPUWeight                               *uint8                     `json:"cpu_weight,omitempty"`
+	Ports                                   []int32                    `json:"ports,omitempty"`
+	PortProtocols                           map[int32]string           `json:"port_protocols,omitempty"`
+	HealthCheckType                         *string                    `json:"health_check_type,omitempty"`
+	HealthCheckHTTPEndpoint                 *string                    `json:"health_check_http_endpoint,omitempty"`
+	HealthCheckTimeoutMs                    *uint                      `json:"health_check_timeout_ms,omitempty"`
+	HealthCheckInvocationTimeoutMs          *uint                      `json:"health_check_invocation_timeout_ms,omitempty"`
+	HealthCheckIntervalMs                   *uint                      `json:"health_check_interval_ms,omitempty"`
+	HealthCheckPort                         *int32                     `json:"health_check_port,omitempty"`
+	ReadinessHealthCheckType                *string                    `json:"readiness_health_check_type,omitempty"`
+	ReadinessHealthCheckHTTPEndpoint        *string                    `json:"readiness_health_check_http_endpoint,omitempty"`
+	ReadinessHealthCheckInvocationTimeoutMs *uint                      `json:"readiness_health_check_invocation_timeout_ms,omitempty"`
+	ReadinessHealthCheckIntervalMs          *uint                      `json:"readiness_health_check_interval_ms,omitempty"`
+	ReadinessHealthCheckPort                *int32                     `json:"readiness_health_check_port,omitempty"`
+	StartTimeoutMs                          *uint                      `json:"start_timeout_ms,omitempty"`
+	UserDefinedAnnotations                  map[string]string          `json:"user_defined_annotations,omitempty"`
 }
 
 type GetInstancesResponse struct {
@@ -178,6 +287,12 @@ type Route struct {
 	Port     int32  `json:"port"`
 }
 
+type TCPRoute struct {
+	RouterGroupGUID string `json:"router_group_guid"`
+	ExternalPort    int32  `json:"external_port"`
+	ContainerPort   int32  `json:"container_port"`
+}
+
 type AppCrashedRequest struct {
 	Instance        string `json:"instance"`
 	Index           int    `json:"index"`
@@ -0,0 +1,11 @@
+// Package v1 contains the v1 version of the eirini.cloudfoundry.org API
+// group, which lets LRPs and tasks be desired as Kubernetes custom resources.
+//
+// The CRD manifests in config/crd/bases are generated from the kubebuilder
+// markers of the types with controller-gen.
+//
+// +k8s:deepcopy-gen=package
+// +groupName=eirini.cloudfoundry.org
+package v1
+
+//go:generate go run sigs.k8s.io/controller-tools/cmd/controller-gen@v0.9.2 crd paths=. output:crd:artifacts:config=../../../../config/crd/bases
@@ -0,0 +1,195 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.spec.instances`
+// +kubebuilder:printcolumn:name="Running",type=integer,JSONPath=`.status.replicas`
+
+// LRP describes a long running process that should be kept running with the
+// desired number of instances, just like the ones desired through the REST
+// API.
+type LRP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LRPSpec   `json:"spec"`
+	Status LRPStatus `json:"status,omitempty"`
+}
+
+type LRPSpec struct {
+	GUID                   string            `json:"GUID"`
+	Version                string            `json:"version"`
+	ProcessType            string            `json:"processType,omitempty"`
+	AppGUID                string            `json:"appGUID,omitempty"`
+	AppName                string            `json:"appName,omitempty"`
+	SpaceGUID              string            `json:"spaceGUID,omitempty"`
+	SpaceName              string            `json:"spaceName,omitempty"`
+	OrgGUID                string            `json:"orgGUID,omitempty"`
+	OrgName                string            `json:"orgName,omitempty"`
+	Image                  string            `json:"image"`
+	Command                []string          `json:"command,omitempty"`
+	Sidecars               []Sidecar         `json:"sidecars,omitempty"`
+	PrivateRegistry        *PrivateRegistry  `json:"privateRegistry,omitempty"`
+	Env                    map[string]string `json:"env,omitempty"`
+	Health                 Healthcheck       `json:"health,omitempty"`
+	Readiness              Healthcheck       `json:"readiness,omitempty"`
+	Ports                  []int32           `json:"ports,omitempty"`
+	Routes                 Routes            `json:"routes,omitempty"`
+	EgressRules            []EgressRule      `json:"egressRules,omitempty"`
+	Instances              int               `json:"instances"`
+	MemoryMB               int64             `json:"memoryMB"`
+	DiskMB                 int64             `json:"diskMB"`
+	CPUWeight              uint8             `json:"cpuWeight,omitempty"`
+	VolumeMounts           []VolumeMount     `json:"volumeMounts,omitempty"`
+	PlacementTags          []string          `json:"placementTags,omitempty"`
+	UserDefinedAnnotations map[string]string `json:"userDefinedAnnotations,omitempty"`
+}
+
+type LRPStatus struct {
+	Replicas int32 `json:"replicas"`
+}
+
+type Sidecar struct {
+	Name      string            `json:"name"`
+	Command   []string          `json:"command"`
+	Image     string            `json:"image,omitempty"`
+	MemoryMB  int64             `json:"memoryMB,omitempty"`
+	CPUWeight uint8             `json:"cpuWeight,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+type PrivateRegistry struct {
+	Server   string `json:"server,omitempty"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type Healthcheck struct {
+	Type                string `json:"type,omitempty"`
+	Port                int32  `json:"port,omitempty"`
+	Endpoint            string `json:"endpoint,omitempty"`
+	TimeoutMs           uint   `json:"timeoutMs,omitempty"`
+	StartTimeoutMs      uint   `json:"startTimeoutMs,omitempty"`
+	InvocationTimeoutMs uint   `json:"invocationTimeoutMs,omitempty"`
+	IntervalMs          uint   `json:"intervalMs,omitempty"`
+}
+
+type Routes struct {
+	HTTP []HTTPRoute `json:"http,omitempty"`
+	TCP  []TCPRoute  `json:"tcp,omitempty"`
+}
+
+type HTTPRoute struct {
+	Hostname string `json:"hostname"`
+	Port     int32  `json:"port"`
+}
+
+type TCPRoute struct {
+	ExternalPort  int32 `json:"externalPort"`
+	ContainerPort int32 `json:"containerPort"`
+}
+
+// EgressRule allows the LRP instances to reach destinations outside the
+// cluster, like a rule of a CF application security group does.
+type EgressRule struct {
+	Protocol     string     `json:"protocol"`
+	Destinations []string   `json:"destinations"`
+	Ports        []int32    `json:"ports,omitempty"`
+	PortRange    *PortRange `json:"portRange,omitempty"`
+	ICMPInfo     *ICMPInfo  `json:"icmpInfo,omitempty"`
+	Log          bool       `json:"log,omitempty"`
+}
+
+type PortRange struct {
+	Start int32 `json:"start"`
+	End   int32 `json:"end"`
+}
+
+type ICMPInfo struct {
+	Type int32 `json:"type"`
+	Code int32 `json:"code"`
+}
+
+type VolumeMount struct {
+	MountPath string `json:"mountPath"`
+	ClaimName string `json:"claimName"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+type LRPList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []LRP `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.executionStatus`
+
+// Task describes a one-off process that is run to completion exactly once.
+type Task struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TaskSpec   `json:"spec"`
+	Status TaskStatus `json:"status,omitempty"`
+}
+
+type TaskSpec struct {
+	GUID            string            `json:"GUID"`
+	Name            string            `json:"name"`
+	Image           string            `json:"image"`
+	PrivateRegistry *PrivateRegistry  `json:"privateRegistry,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+	Command         []string          `json:"command,omitempty"`
+	PlacementTags   []string          `json:"placementTags,omitempty"`
+	AppGUID         string            `json:"appGUID,omitempty"`
+	AppName         string            `json:"appName,omitempty"`
+	OrgGUID         string            `json:"orgGUID,omitempty"`
+	OrgName         string            `json:"orgName,omitempty"`
+	SpaceGUID       string            `json:"spaceGUID,omitempty"`
+	SpaceName       string            `json:"spaceName,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	MemoryMB int64 `json:"memoryMB"`
+	// +kubebuilder:validation:Minimum=1
+	DiskMB    int64 `json:"diskMB"`
+	CPUWeight uint8 `json:"cpuWeight,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=starting;running;succeeded;failed
+
+type ExecutionStatus string
+
+const (
+	TaskStarting  ExecutionStatus = "starting"
+	TaskRunning   ExecutionStatus = "running"
+	TaskSucceeded ExecutionStatus = "succeeded"
+	TaskFailed    ExecutionStatus = "failed"
+)
+
+type TaskStatus struct {
+	ExecutionStatus ExecutionStatus `json:"executionStatus,omitempty"`
+	StartTime       *metav1.Time    `json:"startTime,omitempty"`
+	EndTime         *metav1.Time    `json:"endTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+type TaskList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Task `json:"items"`
+}
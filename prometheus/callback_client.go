@@ -0,0 +1,54 @@
+package prometheus
+
+import (
+	"context"
+
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	CCCallbacks     = "eirini_cc_callbacks"
+	CCCallbacksHelp = "The total number of callbacks made to the Cloud Controller"
+
+	LabelResult   = "result"
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+//counterfeiter:generate . CallbackClient
+
+type CallbackClient interface {
+	Post(ctx context.Context, url string, data interface{}) error
+}
+
+// CallbackClientDecorator counts the callbacks made to the Cloud Controller,
+// such as staging and task completions, by whether they succeeded.
+type CallbackClientDecorator struct {
+	CallbackClient
+	callbacks *prometheus_api.CounterVec
+}
+
+func NewCallbackClientDecorator(callbackClient CallbackClient, registry prometheus_api.Registerer) (*CallbackClientDecorator, error) {
+	callbacks, err := registerCounterVec(registry, CCCallbacks, CCCallbacksHelp, LabelResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CallbackClientDecorator{
+		CallbackClient: callbackClient,
+		callbacks:      callbacks,
+	}, nil
+}
+
+func (d *CallbackClientDecorator) Post(ctx context.Context, url string, data interface{}) error {
+	err := d.CallbackClient.Post(ctx, url, data)
+
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+
+	d.callbacks.WithLabelValues(result).Inc()
+
+	return err
+}
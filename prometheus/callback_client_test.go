@@ -0,0 +1,72 @@
+package prometheus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/eirini/prometheus"
+	"code.cloudfoundry.org/eirini/prometheus/prometheusfakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Callback Client Prometheus Decorator", func() {
+	var (
+		callbackClient *prometheusfakes.FakeCallbackClient
+		decorator      *prometheus.CallbackClientDecorator
+		registry       metrics.RegistererGatherer
+		postErr        error
+	)
+
+	BeforeEach(func() {
+		callbackClient = new(prometheusfakes.FakeCallbackClient)
+		registry = prometheus_api.NewRegistry()
+
+		var err error
+		decorator, err = prometheus.NewCallbackClientDecorator(callbackClient, registry)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		postErr = decorator.Post(context.Background(), "http://cc/callback", "the-data")
+	})
+
+	It("delegates to the callback client", func() {
+		Expect(postErr).NotTo(HaveOccurred())
+		Expect(callbackClient.PostCallCount()).To(Equal(1))
+		_, url, data := callbackClient.PostArgsForCall(0)
+		Expect(url).To(Equal("http://cc/callback"))
+		Expect(data).To(Equal("the-data"))
+	})
+
+	It("counts the successful callback", func() {
+		Expect(registry).To(HaveMetric(prometheus.CCCallbacks, fmt.Sprintf(`
+			# HELP %[1]s %[2]s
+			# TYPE %[1]s counter
+			%[1]s{result="success"} 1
+			`, prometheus.CCCallbacks, prometheus.CCCallbacksHelp,
+		)))
+	})
+
+	When("the callback fails", func() {
+		BeforeEach(func() {
+			callbackClient.PostReturns(errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			Expect(postErr).To(MatchError("boom"))
+		})
+
+		It("counts the failed callback", func() {
+			Expect(registry).To(HaveMetric(prometheus.CCCallbacks, fmt.Sprintf(`
+				# HELP %[1]s %[2]s
+				# TYPE %[1]s counter
+				%[1]s{result="failure"} 1
+				`, prometheus.CCCallbacks, prometheus.CCCallbacksHelp,
+			)))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	"k8s.io/utils/clock"
+)
+
+const (
+	HTTPRequests             = "eirini_http_requests"
+	HTTPRequestsHelp         = "The total number of HTTP requests handled by the API"
+	HTTPRequestDurations     = "eirini_http_request_durations"
+	HTTPRequestDurationsHelp = "The duration of HTTP requests handled by the API in milliseconds"
+
+	LabelMethod = "method"
+	LabelRoute  = "route"
+	LabelCode   = "code"
+)
+
+// HTTPMetrics instruments the handles of the API routes. Requests are
+// labelled by the route they matched rather than by their path, so that
+// GUIDs do not blow up the number of series.
+type HTTPMetrics struct {
+	requests  *prometheus_api.CounterVec
+	durations *prometheus_api.HistogramVec
+	clock     clock.PassiveClock
+}
+
+func NewHTTPMetrics(registry prometheus_api.Registerer, clck clock.PassiveClock) (*HTTPMetrics, error) {
+	requests, err := registerCounterVec(registry, HTTPRequests, HTTPRequestsHelp, LabelMethod, LabelRoute, LabelCode)
+	if err != nil {
+		return nil, err
+	}
+
+	durations, err := registerHistogramVec(registry, HTTPRequestDurations, HTTPRequestDurationsHelp, LabelMethod, LabelRoute)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HTTPMetrics{
+		requests:  requests,
+		durations: durations,
+		clock:     clck,
+	}, nil
+}
+
+func (m *HTTPMetrics) Instrument(method, route string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		start := m.clock.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		handle(recorder, r, ps)
+
+		m.durations.WithLabelValues(method, route).Observe(float64(m.clock.Since(start).Milliseconds()))
+		m.requests.WithLabelValues(method, route, strconv.Itoa(recorder.status)).Inc()
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
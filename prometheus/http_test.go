@@ -0,0 +1,91 @@
+package prometheus_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/eirini/prometheus"
+	"github.com/julienschmidt/httprouter"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	clock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("HTTP Metrics", func() {
+	var (
+		registry    metrics.RegistererGatherer
+		fakeClock   *clock.FakePassiveClock
+		httpMetrics *prometheus.HTTPMetrics
+		status      int
+		recorder    *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		registry = prometheus_api.NewRegistry()
+		t0 := time.Now()
+		fakeClock = clock.NewFakePassiveClock(t0)
+		status = 0
+
+		var err error
+		httpMetrics, err = prometheus.NewHTTPMetrics(registry, fakeClock)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		handle := httpMetrics.Instrument(http.MethodGet, "/apps/:process_guid", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			fakeClock.SetTime(fakeClock.Now().Add(20 * time.Millisecond))
+
+			if status != 0 {
+				w.WriteHeader(status)
+			}
+
+			fmt.Fprint(w, ps.ByName("process_guid"))
+		})
+
+		recorder = httptest.NewRecorder()
+		handle(recorder, httptest.NewRequest(http.MethodGet, "/apps/the-guid", nil), httprouter.Params{{Key: "process_guid", Value: "the-guid"}})
+	})
+
+	It("calls the wrapped handle", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(Equal("the-guid"))
+	})
+
+	It("counts the request by route and status code", func() {
+		Expect(registry).To(HaveMetric(prometheus.HTTPRequests, fmt.Sprintf(`
+			# HELP %[1]s %[2]s
+			# TYPE %[1]s counter
+			%[1]s{code="200",method="GET",route="/apps/:process_guid"} 1
+			`, prometheus.HTTPRequests, prometheus.HTTPRequestsHelp,
+		)))
+	})
+
+	It("measures the duration of the request", func() {
+		Expect(registry).To(HaveMetric(prometheus.HTTPRequestDurations, histogramText(
+			prometheus.HTTPRequestDurations,
+			prometheus.HTTPRequestDurationsHelp,
+			`method="GET",route="/apps/:process_guid"`,
+			20,
+		)))
+	})
+
+	When("the handle responds with an explicit status code", func() {
+		BeforeEach(func() {
+			status = http.StatusNotFound
+		})
+
+		It("records that status code", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+			Expect(registry).To(HaveMetric(prometheus.HTTPRequests, fmt.Sprintf(`
+				# HELP %[1]s %[2]s
+				# TYPE %[1]s counter
+				%[1]s{code="404",method="GET",route="/apps/:process_guid"} 1
+				`, prometheus.HTTPRequests, prometheus.HTTPRequestsHelp,
+			)))
+		})
+	})
+})
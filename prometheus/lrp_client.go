@@ -2,7 +2,6 @@ package prometheus
 
 import (
 	"context"
-	"errors"
 
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s/shared"
@@ -12,18 +11,26 @@ import (
 )
 
 const (
-	LRPCreations             = "eirini_lrp_creations"
-	LRPCreationsHelp         = "The total number of created lrps"
-	LRPCreationDurations     = "eirini_lrp_creation_durations"
-	LRPCreationDurationsHelp = "The duration of lrp creations"
+	LRPCreations              = "eirini_lrp_creations"
+	LRPCreationsHelp          = "The total number of created lrps"
+	LRPCreationDurations      = "eirini_lrp_creation_durations"
+	LRPCreationDurationsHelp  = "The duration of lrp creations"
+	LRPOperationDurations     = "eirini_lrp_operation_durations"
+	LRPOperationDurationsHelp = "The duration of lrp operations in milliseconds"
+	LRPOperationErrors        = "eirini_lrp_operation_errors"
+	LRPOperationErrorsHelp    = "The total number of failed lrp operations"
 )
 
 //counterfeiter:generate . LRPClient
 
 type LRPClient interface {
 	Desire(ctx context.Context, namespace string, lrp *api.LRP, opts ...shared.Option) error
+	List(ctx context.Context) ([]*api.LRP, error)
 	Get(ctx context.Context, identifier api.LRPIdentifier) (*api.LRP, error)
+	GetInstances(ctx context.Context, identifier api.LRPIdentifier) ([]*api.Instance, error)
 	Update(ctx context.Context, lrp *api.LRP) error
+	Stop(ctx context.Context, identifier api.LRPIdentifier) error
+	StopInstance(ctx context.Context, identifier api.LRPIdentifier, index uint) error
 }
 
 type LRPClientDecorator struct {
@@ -31,6 +38,7 @@ type LRPClientDecorator struct {
 	logger            lager.Logger
 	creations         prometheus_api.Counter
 	creationDurations prometheus_api.Histogram
+	operations        operationMetrics
 	clock             clock.PassiveClock
 }
 
@@ -40,7 +48,7 @@ func NewLRPClientDecorator(
 	registry prometheus_api.Registerer,
 	clck clock.PassiveClock,
 ) (*LRPClientDecorator, error) {
-	creations, err := registerCounter(registry, LRPCreations, "The total number of created lrps")
+	creations, err := registerCounter(registry, LRPCreations, LRPCreationsHelp)
 	if err != nil {
 		return nil, err
 	}
@@ -50,11 +58,20 @@ func NewLRPClientDecorator(
 		return nil, err
 	}
 
+	operations, err := newOperationMetrics(registry, clck,
+		LRPOperationDurations, LRPOperationDurationsHelp,
+		LRPOperationErrors, LRPOperationErrorsHelp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &LRPClientDecorator{
 		LRPClient:         lrpClient,
 		logger:            logger,
 		creations:         creations,
 		creationDurations: creationDurations,
+		operations:        operations,
 		clock:             clck,
 	}, nil
 }
@@ -68,43 +85,55 @@ func (d *LRPClientDecorator) Desire(ctx context.Context, namespace string, lrp *
 		d.creationDurations.Observe(float64(d.clock.Since(start).Milliseconds()))
 	}
 
+	d.operations.observe(OperationDesire, start, err)
+
 	return err
 }
 
-func registerCounter(registry prometheus_api.Registerer, name, help string) (prometheus_api.Counter, error) {
-	c := prometheus_api.NewCounter(prometheus_api.CounterOpts{
-		Name: name,
-		Help: help,
-	})
+func (d *LRPClientDecorator) List(ctx context.Context) ([]*api.LRP, error) {
+	start := d.clock.Now()
+	lrps, err := d.LRPClient.List(ctx)
+	d.operations.observe(OperationList, start, err)
 
-	err := registry.Register(c)
-	if err == nil {
-		return c, nil
-	}
+	return lrps, err
+}
 
-	var are prometheus_api.AlreadyRegisteredError
-	if errors.As(err, &are) {
-		return are.ExistingCollector.(prometheus_api.Counter), nil //nolint:forcetypeassert
-	}
+func (d *LRPClientDecorator) Get(ctx context.Context, identifier api.LRPIdentifier) (*api.LRP, error) {
+	start := d.clock.Now()
+	lrp, err := d.LRPClient.Get(ctx, identifier)
+	d.operations.observe(OperationGet, start, err)
 
-	return nil, err
+	return lrp, err
 }
 
-func registerHistogram(registry prometheus_api.Registerer, name, help string) (prometheus_api.Histogram, error) {
-	h := prometheus_api.NewHistogram(prometheus_api.HistogramOpts{
-		Name: name,
-		Help: help,
-	})
+func (d *LRPClientDecorator) GetInstances(ctx context.Context, identifier api.LRPIdentifier) ([]*api.Instance, error) {
+	start := d.clock.Now()
+	instances, err := d.LRPClient.GetInstances(ctx, identifier)
+	d.operations.observe(OperationGetInstances, start, err)
 
-	err := registry.Register(h)
-	if err == nil {
-		return h, nil
-	}
+	return instances, err
+}
 
-	var are prometheus_api.AlreadyRegisteredError
-	if errors.As(err, &are) {
-		return are.ExistingCollector.(prometheus_api.Histogram), nil //nolint:forcetypeassert
-	}
+func (d *LRPClientDecorator) Update(ctx context.Context, lrp *api.LRP) error {
+	start := d.clock.Now()
+	err := d.LRPClient.Update(ctx, lrp)
+	d.operations.observe(OperationUpdate, start, err)
+
+	return err
+}
+
+func (d *LRPClientDecorator) Stop(ctx context.Context, identifier api.LRPIdentifier) error {
+	start := d.clock.Now()
+	err := d.LRPClient.Stop(ctx, identifier)
+	d.operations.observe(OperationStop, start, err)
+
+	return err
+}
 
-	return nil, err
+func (d *LRPClientDecorator) StopInstance(ctx context.Context, identifier api.LRPIdentifier, index uint) error {
+	start := d.clock.Now()
+	err := d.LRPClient.StopInstance(ctx, identifier, index)
+	d.operations.observe(OperationStopInstance, start, err)
+
+	return err
 }
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s/shared"
 	"code.cloudfoundry.org/eirini/prometheus"
@@ -17,6 +18,8 @@ import (
 	"github.com/onsi/gomega/types"
 	prometheus_api "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clock "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -124,6 +127,136 @@ var _ = Describe("LRP Client Prometheus Decorator", func() {
 	})
 })
 
+var _ = Describe("LRP Client Prometheus Decorator operations", func() {
+	var (
+		lrpClient  *prometheusfakes.FakeLRPClient
+		decorator  *prometheus.LRPClientDecorator
+		registry   metrics.RegistererGatherer
+		fakeClock  *clock.FakePassiveClock
+		t0         time.Time
+		ctx        context.Context
+		identifier api.LRPIdentifier
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		lrpClient = new(prometheusfakes.FakeLRPClient)
+		registry = prometheus_api.NewRegistry()
+		identifier = api.LRPIdentifier{GUID: "the-guid", Version: "the-version"}
+
+		t0 = time.Now()
+		fakeClock = clock.NewFakePassiveClock(t0)
+
+		var err error
+		decorator, err = prometheus.NewLRPClientDecorator(tests.NewTestLogger("lrp-decorator"), lrpClient, registry, fakeClock)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	advanceClock := func(d time.Duration) {
+		fakeClock.SetTime(t0.Add(d))
+	}
+
+	It("delegates Get and measures its duration", func() {
+		lrpClient.GetStub = func(context.Context, api.LRPIdentifier) (*api.LRP, error) {
+			advanceClock(30 * time.Millisecond)
+
+			return &api.LRP{AppName: "the-app"}, nil
+		}
+
+		lrp, err := decorator.Get(ctx, identifier)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lrp.AppName).To(Equal("the-app"))
+
+		_, actualIdentifier := lrpClient.GetArgsForCall(0)
+		Expect(actualIdentifier).To(Equal(identifier))
+		Expect(registry).To(HaveOperationHistogram(prometheus.LRPOperationDurations, prometheus.LRPOperationDurationsHelp, prometheus.OperationGet, 30))
+	})
+
+	It("delegates Update and measures its duration", func() {
+		lrpClient.UpdateStub = func(context.Context, *api.LRP) error {
+			advanceClock(100 * time.Millisecond)
+
+			return nil
+		}
+
+		lrp := &api.LRP{TargetInstances: 3}
+		Expect(decorator.Update(ctx, lrp)).To(Succeed())
+
+		_, actualLRP := lrpClient.UpdateArgsForCall(0)
+		Expect(actualLRP).To(Equal(lrp))
+		Expect(registry).To(HaveOperationHistogram(prometheus.LRPOperationDurations, prometheus.LRPOperationDurationsHelp, prometheus.OperationUpdate, 100))
+	})
+
+	It("delegates Stop and measures its duration", func() {
+		Expect(decorator.Stop(ctx, identifier)).To(Succeed())
+
+		_, actualIdentifier := lrpClient.StopArgsForCall(0)
+		Expect(actualIdentifier).To(Equal(identifier))
+		Expect(registry).To(HaveOperationHistogram(prometheus.LRPOperationDurations, prometheus.LRPOperationDurationsHelp, prometheus.OperationStop, 0))
+	})
+
+	It("delegates StopInstance and measures its duration", func() {
+		Expect(decorator.StopInstance(ctx, identifier, 2)).To(Succeed())
+
+		_, actualIdentifier, actualIndex := lrpClient.StopInstanceArgsForCall(0)
+		Expect(actualIdentifier).To(Equal(identifier))
+		Expect(actualIndex).To(Equal(uint(2)))
+		Expect(registry).To(HaveOperationHistogram(prometheus.LRPOperationDurations, prometheus.LRPOperationDurationsHelp, prometheus.OperationStopInstance, 0))
+	})
+
+	It("does not count successful operations as errors", func() {
+		Expect(decorator.Stop(ctx, identifier)).To(Succeed())
+		Expect(testutil.GatherAndCount(registry, prometheus.LRPOperationErrors)).To(BeZero())
+	})
+
+	DescribeTable("counting failed operations by error class",
+		func(operation func() error, expectedOperation string, err error, expectedClass string) {
+			lrpClient.GetReturns(nil, err)
+			lrpClient.UpdateReturns(err)
+			lrpClient.StopReturns(err)
+			lrpClient.StopInstanceReturns(err)
+
+			Expect(operation()).To(MatchError(err))
+			Expect(registry).To(HaveMetric(prometheus.LRPOperationErrors, fmt.Sprintf(`
+				# HELP %[1]s %[2]s
+				# TYPE %[1]s counter
+				%[1]s{error="%[3]s",operation="%[4]s"} 1
+				`, prometheus.LRPOperationErrors, prometheus.LRPOperationErrorsHelp, expectedClass, expectedOperation,
+			)))
+		},
+		Entry("get not found",
+			func() error { _, err := decorator.Get(ctx, identifier); return err }, //nolint:nlreturn
+			prometheus.OperationGet, fmt.Errorf("wrapped: %w", eirini.ErrNotFound), prometheus.ErrorClassNotFound),
+		Entry("update conflict",
+			func() error { return decorator.Update(ctx, &api.LRP{}) },
+			prometheus.OperationUpdate, apierrors.NewConflict(schema.GroupResource{}, "foo", errors.New("boom")), prometheus.ErrorClassConflict),
+		Entry("stop timeout",
+			func() error { return decorator.Stop(ctx, identifier) },
+			prometheus.OperationStop, fmt.Errorf("wrapped: %w", context.DeadlineExceeded), prometheus.ErrorClassTimeout),
+		Entry("stop instance with invalid index",
+			func() error { return decorator.StopInstance(ctx, identifier, 5) },
+			prometheus.OperationStopInstance, eirini.ErrInvalidInstanceIndex, prometheus.ErrorClassInvalidRequest),
+		Entry("stop forbidden",
+			func() error { return decorator.Stop(ctx, identifier) },
+			prometheus.OperationStop, apierrors.NewForbidden(schema.GroupResource{}, "foo", errors.New("boom")), prometheus.ErrorClassForbidden),
+		Entry("update with an unknown error",
+			func() error { return decorator.Update(ctx, &api.LRP{}) },
+			prometheus.OperationUpdate, errors.New("boom"), prometheus.ErrorClassInternal),
+	)
+
+	It("counts failed desires", func() {
+		lrpClient.DesireReturns(apierrors.NewAlreadyExists(schema.GroupResource{}, "foo"))
+
+		Expect(decorator.Desire(ctx, "the-namespace", &api.LRP{})).NotTo(Succeed())
+		Expect(registry).To(HaveMetric(prometheus.LRPOperationErrors, fmt.Sprintf(`
+			# HELP %[1]s %[2]s
+			# TYPE %[1]s counter
+			%[1]s{error="conflict",operation="desire"} 1
+			`, prometheus.LRPOperationErrors, prometheus.LRPOperationErrorsHelp,
+		)))
+	})
+})
+
 func HaveMetric(name string, promText string) types.GomegaMatcher {
 	return WithTransform(func(registry prometheus_api.Gatherer) error {
 		return testutil.GatherAndCompare(registry, strings.NewReader(promText), name)
@@ -161,3 +294,35 @@ func HaveHistogram(name, help string, sum float64, count int) types.GomegaMatche
 		name, help, sum, count,
 	))
 }
+
+func HaveOperationHistogram(name, help, operation string, observations ...float64) types.GomegaMatcher {
+	return HaveMetric(name, histogramText(name, help, fmt.Sprintf(`operation=%q`, operation), observations...))
+}
+
+func histogramText(name, help, labels string, observations ...float64) string {
+	text := &strings.Builder{}
+	fmt.Fprintf(text, "# HELP %[1]s %[2]s\n# TYPE %[1]s histogram\n", name, help)
+
+	sum := 0.0
+	for _, o := range observations {
+		sum += o
+	}
+
+	for _, bucket := range prometheus_api.ExponentialBuckets(5, 2, 12) {
+		count := 0
+
+		for _, o := range observations {
+			if o <= bucket {
+				count++
+			}
+		}
+
+		fmt.Fprintf(text, "%s_bucket{%s,le=\"%g\"} %d\n", name, labels, bucket, count)
+	}
+
+	fmt.Fprintf(text, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, len(observations))
+	fmt.Fprintf(text, "%s_sum{%s} %g\n", name, labels, sum)
+	fmt.Fprintf(text, "%s_count{%s} %d\n", name, labels, len(observations))
+
+	return text.String()
+}
@@ -0,0 +1,93 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/eirini"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/clock"
+)
+
+const (
+	LabelOperation  = "operation"
+	LabelErrorClass = "error"
+
+	OperationDesire       = "desire"
+	OperationList         = "list"
+	OperationGet          = "get"
+	OperationGetInstances = "get_instances"
+	OperationUpdate       = "update"
+	OperationStop         = "stop"
+	OperationStopInstance = "stop_instance"
+	OperationDelete       = "delete"
+
+	ErrorClassNotFound       = "not_found"
+	ErrorClassInvalidRequest = "invalid_request"
+	ErrorClassConflict       = "conflict"
+	ErrorClassForbidden      = "forbidden"
+	ErrorClassTimeout        = "timeout"
+	ErrorClassInternal       = "internal"
+)
+
+// durationBucketsMs spans 5ms to roughly 10s, which covers everything from a
+// cached read to a slow statefulset creation.
+var durationBucketsMs = prometheus_api.ExponentialBuckets(5, 2, 12)
+
+// operationMetrics records how long each operation of a client takes and how
+// often it fails, by class of error.
+type operationMetrics struct {
+	durations *prometheus_api.HistogramVec
+	errors    *prometheus_api.CounterVec
+	clock     clock.PassiveClock
+}
+
+func newOperationMetrics(
+	registry prometheus_api.Registerer,
+	clck clock.PassiveClock,
+	durationsName, durationsHelp string,
+	errorsName, errorsHelp string,
+) (operationMetrics, error) {
+	durations, err := registerHistogramVec(registry, durationsName, durationsHelp, LabelOperation)
+	if err != nil {
+		return operationMetrics{}, err
+	}
+
+	errs, err := registerCounterVec(registry, errorsName, errorsHelp, LabelOperation, LabelErrorClass)
+	if err != nil {
+		return operationMetrics{}, err
+	}
+
+	return operationMetrics{
+		durations: durations,
+		errors:    errs,
+		clock:     clck,
+	}, nil
+}
+
+func (m operationMetrics) observe(operation string, start time.Time, err error) {
+	m.durations.WithLabelValues(operation).Observe(float64(m.clock.Since(start).Milliseconds()))
+
+	if err != nil {
+		m.errors.WithLabelValues(operation, errorClass(err)).Inc()
+	}
+}
+
+func errorClass(err error) string {
+	switch {
+	case errors.Is(err, eirini.ErrNotFound), apierrors.IsNotFound(err):
+		return ErrorClassNotFound
+	case errors.Is(err, eirini.ErrInvalidInstanceIndex), errors.Is(err, eirini.ErrInvalidTaskRequest), apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return ErrorClassInvalidRequest
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return ErrorClassConflict
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return ErrorClassForbidden
+	case errors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return ErrorClassTimeout
+	default:
+		return ErrorClassInternal
+	}
+}
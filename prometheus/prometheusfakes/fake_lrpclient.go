@@ -39,6 +39,58 @@ type FakeLRPClient struct {
 		result1 *api.LRP
 		result2 error
 	}
+	GetInstancesStub        func(context.Context, api.LRPIdentifier) ([]*api.Instance, error)
+	getInstancesMutex       sync.RWMutex
+	getInstancesArgsForCall []struct {
+		arg1 context.Context
+		arg2 api.LRPIdentifier
+	}
+	getInstancesReturns struct {
+		result1 []*api.Instance
+		result2 error
+	}
+	getInstancesReturnsOnCall map[int]struct {
+		result1 []*api.Instance
+		result2 error
+	}
+	ListStub        func(context.Context) ([]*api.LRP, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		arg1 context.Context
+	}
+	listReturns struct {
+		result1 []*api.LRP
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []*api.LRP
+		result2 error
+	}
+	StopStub        func(context.Context, api.LRPIdentifier) error
+	stopMutex       sync.RWMutex
+	stopArgsForCall []struct {
+		arg1 context.Context
+		arg2 api.LRPIdentifier
+	}
+	stopReturns struct {
+		result1 error
+	}
+	stopReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StopInstanceStub        func(context.Context, api.LRPIdentifier, uint) error
+	stopInstanceMutex       sync.RWMutex
+	stopInstanceArgsForCall []struct {
+		arg1 context.Context
+		arg2 api.LRPIdentifier
+		arg3 uint
+	}
+	stopInstanceReturns struct {
+		result1 error
+	}
+	stopInstanceReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateStub        func(context.Context, *api.LRP) error
 	updateMutex       sync.RWMutex
 	updateArgsForCall []struct {
@@ -184,6 +236,260 @@ func (fake *FakeLRPClient) GetReturnsOnCall(i int, result1 *api.LRP, result2 err
 	}{result1, result2}
 }
 
+func (fake *FakeLRPClient) GetInstances(arg1 context.Context, arg2 api.LRPIdentifier) ([]*api.Instance, error) {
+	fake.getInstancesMutex.Lock()
+	ret, specificReturn := fake.getInstancesReturnsOnCall[len(fake.getInstancesArgsForCall)]
+	fake.getInstancesArgsForCall = append(fake.getInstancesArgsForCall, struct {
+		arg1 context.Context
+		arg2 api.LRPIdentifier
+	}{arg1, arg2})
+	stub := fake.GetInstancesStub
+	fakeReturns := fake.getInstancesReturns
+	fake.recordInvocation("GetInstances", []interface{}{arg1, arg2})
+	fake.getInstancesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLRPClient) GetInstancesCallCount() int {
+	fake.getInstancesMutex.RLock()
+	defer fake.getInstancesMutex.RUnlock()
+	return len(fake.getInstancesArgsForCall)
+}
+
+func (fake *FakeLRPClient) GetInstancesCalls(stub func(context.Context, api.LRPIdentifier) ([]*api.Instance, error)) {
+	fake.getInstancesMutex.Lock()
+	defer fake.getInstancesMutex.Unlock()
+	fake.GetInstancesStub = stub
+}
+
+func (fake *FakeLRPClient) GetInstancesArgsForCall(i int) (context.Context, api.LRPIdentifier) {
+	fake.getInstancesMutex.RLock()
+	defer fake.getInstancesMutex.RUnlock()
+	argsForCall := fake.getInstancesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeLRPClient) GetInstancesReturns(result1 []*api.Instance, result2 error) {
+	fake.getInstancesMutex.Lock()
+	defer fake.getInstancesMutex.Unlock()
+	fake.GetInstancesStub = nil
+	fake.getInstancesReturns = struct {
+		result1 []*api.Instance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLRPClient) GetInstancesReturnsOnCall(i int, result1 []*api.Instance, result2 error) {
+	fake.getInstancesMutex.Lock()
+	defer fake.getInstancesMutex.Unlock()
+	fake.GetInstancesStub = nil
+	if fake.getInstancesReturnsOnCall == nil {
+		fake.getInstancesReturnsOnCall = make(map[int]struct {
+			result1 []*api.Instance
+			result2 error
+		})
+	}
+	fake.getInstancesReturnsOnCall[i] = struct {
+		result1 []*api.Instance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLRPClient) List(arg1 context.Context) ([]*api.LRP, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListStub
+	fakeReturns := fake.listReturns
+	fake.recordInvocation("List", []interface{}{arg1})
+	fake.listMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLRPClient) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeLRPClient) ListCalls(stub func(context.Context) ([]*api.LRP, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakeLRPClient) ListArgsForCall(i int) context.Context {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	argsForCall := fake.listArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLRPClient) ListReturns(result1 []*api.LRP, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []*api.LRP
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLRPClient) ListReturnsOnCall(i int, result1 []*api.LRP, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []*api.LRP
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []*api.LRP
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLRPClient) Stop(arg1 context.Context, arg2 api.LRPIdentifier) error {
+	fake.stopMutex.Lock()
+	ret, specificReturn := fake.stopReturnsOnCall[len(fake.stopArgsForCall)]
+	fake.stopArgsForCall = append(fake.stopArgsForCall, struct {
+		arg1 context.Context
+		arg2 api.LRPIdentifier
+	}{arg1, arg2})
+	stub := fake.StopStub
+	fakeReturns := fake.stopReturns
+	fake.recordInvocation("Stop", []interface{}{arg1, arg2})
+	fake.stopMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLRPClient) StopCallCount() int {
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
+	return len(fake.stopArgsForCall)
+}
+
+func (fake *FakeLRPClient) StopCalls(stub func(context.Context, api.LRPIdentifier) error) {
+	fake.stopMutex.Lock()
+	defer fake.stopMutex.Unlock()
+	fake.StopStub = stub
+}
+
+func (fake *FakeLRPClient) StopArgsForCall(i int) (context.Context, api.LRPIdentifier) {
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
+	argsForCall := fake.stopArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeLRPClient) StopReturns(result1 error) {
+	fake.stopMutex.Lock()
+	defer fake.stopMutex.Unlock()
+	fake.StopStub = nil
+	fake.stopReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLRPClient) StopReturnsOnCall(i int, result1 error) {
+	fake.stopMutex.Lock()
+	defer fake.stopMutex.Unlock()
+	fake.StopStub = nil
+	if fake.stopReturnsOnCall == nil {
+		fake.stopReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.stopReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLRPClient) StopInstance(arg1 context.Context, arg2 api.LRPIdentifier, arg3 uint) error {
+	fake.stopInstanceMutex.Lock()
+	ret, specificReturn := fake.stopInstanceReturnsOnCall[len(fake.stopInstanceArgsForCall)]
+	fake.stopInstanceArgsForCall = append(fake.stopInstanceArgsForCall, struct {
+		arg1 context.Context
+		arg2 api.LRPIdentifier
+		arg3 uint
+	}{arg1, arg2, arg3})
+	stub := fake.StopInstanceStub
+	fakeReturns := fake.stopInstanceReturns
+	fake.recordInvocation("StopInstance", []interface{}{arg1, arg2, arg3})
+	fake.stopInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLRPClient) StopInstanceCallCount() int {
+	fake.stopInstanceMutex.RLock()
+	defer fake.stopInstanceMutex.RUnlock()
+	return len(fake.stopInstanceArgsForCall)
+}
+
+func (fake *FakeLRPClient) StopInstanceCalls(stub func(context.Context, api.LRPIdentifier, uint) error) {
+	fake.stopInstanceMutex.Lock()
+	defer fake.stopInstanceMutex.Unlock()
+	fake.StopInstanceStub = stub
+}
+
+func (fake *FakeLRPClient) StopInstanceArgsForCall(i int) (context.Context, api.LRPIdentifier, uint) {
+	fake.stopInstanceMutex.RLock()
+	defer fake.stopInstanceMutex.RUnlock()
+	argsForCall := fake.stopInstanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeLRPClient) StopInstanceReturns(result1 error) {
+	fake.stopInstanceMutex.Lock()
+	defer fake.stopInstanceMutex.Unlock()
+	fake.StopInstanceStub = nil
+	fake.stopInstanceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLRPClient) StopInstanceReturnsOnCall(i int, result1 error) {
+	fake.stopInstanceMutex.Lock()
+	defer fake.stopInstanceMutex.Unlock()
+	fake.StopInstanceStub = nil
+	if fake.stopInstanceReturnsOnCall == nil {
+		fake.stopInstanceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.stopInstanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeLRPClient) Update(arg1 context.Context, arg2 *api.LRP) error {
 	fake.updateMutex.Lock()
 	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
@@ -253,6 +559,14 @@ func (fake *FakeLRPClient) Invocations() map[string][][]interface{} {
 	defer fake.desireMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.getInstancesMutex.RLock()
+	defer fake.getInstancesMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
+	fake.stopInstanceMutex.RLock()
+	defer fake.stopInstanceMutex.RUnlock()
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,84 @@
+package prometheus
+
+import (
+	"errors"
+
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+)
+
+func registerCounter(registry prometheus_api.Registerer, name, help string) (prometheus_api.Counter, error) {
+	c := prometheus_api.NewCounter(prometheus_api.CounterOpts{
+		Name: name,
+		Help: help,
+	})
+
+	err := registry.Register(c)
+	if err == nil {
+		return c, nil
+	}
+
+	var are prometheus_api.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		return are.ExistingCollector.(prometheus_api.Counter), nil //nolint:forcetypeassert
+	}
+
+	return nil, err
+}
+
+func registerHistogram(registry prometheus_api.Registerer, name, help string) (prometheus_api.Histogram, error) {
+	h := prometheus_api.NewHistogram(prometheus_api.HistogramOpts{
+		Name: name,
+		Help: help,
+	})
+
+	err := registry.Register(h)
+	if err == nil {
+		return h, nil
+	}
+
+	var are prometheus_api.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		return are.ExistingCollector.(prometheus_api.Histogram), nil //nolint:forcetypeassert
+	}
+
+	return nil, err
+}
+
+func registerCounterVec(registry prometheus_api.Registerer, name, help string, labels ...string) (*prometheus_api.CounterVec, error) {
+	c := prometheus_api.NewCounterVec(prometheus_api.CounterOpts{
+		Name: name,
+		Help: help,
+	}, labels)
+
+	err := registry.Register(c)
+	if err == nil {
+		return c, nil
+	}
+
+	var are prometheus_api.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		return are.ExistingCollector.(*prometheus_api.CounterVec), nil //nolint:forcetypeassert
+	}
+
+	return nil, err
+}
+
+func registerHistogramVec(registry prometheus_api.Registerer, name, help string, labels ...string) (*prometheus_api.HistogramVec, error) {
+	h := prometheus_api.NewHistogramVec(prometheus_api.HistogramOpts{
+		Name:    name,
+		Help:    help,
+		Buckets: durationBucketsMs,
+	}, labels)
+
+	err := registry.Register(h)
+	if err == nil {
+		return h, nil
+	}
+
+	var are prometheus_api.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		return are.ExistingCollector.(*prometheus_api.HistogramVec), nil //nolint:forcetypeassert
+	}
+
+	return nil, err
+}
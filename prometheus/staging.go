@@ -0,0 +1,81 @@
+package prometheus
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/eirini/models/cf"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	"k8s.io/utils/clock"
+)
+
+const (
+	StagingsStarted      = "eirini_stagings_started"
+	StagingsStartedHelp  = "The total number of started stagings"
+	StagingsFinished     = "eirini_stagings_finished"
+	StagingsFinishedHelp = "The total number of finished stagings, by whether their result was reported to the Cloud Controller"
+	StagingDurations     = "eirini_staging_durations"
+	StagingDurationsHelp = "The duration of stagings in milliseconds"
+)
+
+//counterfeiter:generate . StagingBifrost
+
+type StagingBifrost interface {
+	TransferStaging(ctx context.Context, stagingGUID string, request cf.StagingRequest) error
+	CompleteStaging(ctx context.Context, request cf.StagingCompletedRequest) error
+}
+
+// StagingBifrostDecorator counts started and finished stagings and records
+// how long they take.
+type StagingBifrostDecorator struct {
+	StagingBifrost
+	started   prometheus_api.Counter
+	finished  *prometheus_api.CounterVec
+	durations *prometheus_api.HistogramVec
+	clock     clock.PassiveClock
+}
+
+func NewStagingBifrostDecorator(
+	stagingBifrost StagingBifrost,
+	registry prometheus_api.Registerer,
+	clck clock.PassiveClock,
+) (*StagingBifrostDecorator, error) {
+	started, err := registerCounter(registry, StagingsStarted, StagingsStartedHelp)
+	if err != nil {
+		return nil, err
+	}
+
+	finished, err := registerCounterVec(registry, StagingsFinished, StagingsFinishedHelp, LabelResult)
+	if err != nil {
+		return nil, err
+	}
+
+	durations, err := registerHistogramVec(registry, StagingDurations, StagingDurationsHelp, LabelResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StagingBifrostDecorator{
+		StagingBifrost: stagingBifrost,
+		started:        started,
+		finished:       finished,
+		durations:      durations,
+		clock:          clck,
+	}, nil
+}
+
+func (d *StagingBifrostDecorator) TransferStaging(ctx context.Context, stagingGUID string, request cf.StagingRequest) error {
+	start := d.clock.Now()
+	d.started.Inc()
+
+	err := d.StagingBifrost.TransferStaging(ctx, stagingGUID, request)
+
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+
+	d.finished.WithLabelValues(result).Inc()
+	d.durations.WithLabelValues(result).Observe(float64(d.clock.Since(start).Milliseconds()))
+
+	return err
+}
@@ -0,0 +1,104 @@
+package prometheus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/eirini/prometheus"
+	"code.cloudfoundry.org/eirini/prometheus/prometheusfakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	clock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Staging Bifrost Prometheus Decorator", func() {
+	var (
+		stagingBifrost *prometheusfakes.FakeStagingBifrost
+		decorator      *prometheus.StagingBifrostDecorator
+		registry       metrics.RegistererGatherer
+		fakeClock      *clock.FakePassiveClock
+		t0             time.Time
+		transferErr    error
+	)
+
+	BeforeEach(func() {
+		stagingBifrost = new(prometheusfakes.FakeStagingBifrost)
+		registry = prometheus_api.NewRegistry()
+
+		t0 = time.Now()
+		fakeClock = clock.NewFakePassiveClock(t0)
+
+		stagingBifrost.TransferStagingStub = func(context.Context, string, cf.StagingRequest) error {
+			fakeClock.SetTime(t0.Add(300 * time.Millisecond))
+
+			return nil
+		}
+
+		var err error
+		decorator, err = prometheus.NewStagingBifrostDecorator(stagingBifrost, registry, fakeClock)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		transferErr = decorator.TransferStaging(context.Background(), "the-guid", cf.StagingRequest{AppGUID: "the-app"})
+	})
+
+	It("delegates to the staging bifrost", func() {
+		Expect(transferErr).NotTo(HaveOccurred())
+		Expect(stagingBifrost.TransferStagingCallCount()).To(Equal(1))
+		_, guid, request := stagingBifrost.TransferStagingArgsForCall(0)
+		Expect(guid).To(Equal("the-guid"))
+		Expect(request.AppGUID).To(Equal("the-app"))
+	})
+
+	It("counts the started staging", func() {
+		Expect(registry).To(HaveCounter(prometheus.StagingsStarted, prometheus.StagingsStartedHelp, 1))
+	})
+
+	It("counts the finished staging", func() {
+		Expect(registry).To(HaveMetric(prometheus.StagingsFinished, fmt.Sprintf(`
+			# HELP %[1]s %[2]s
+			# TYPE %[1]s counter
+			%[1]s{result="success"} 1
+			`, prometheus.StagingsFinished, prometheus.StagingsFinishedHelp,
+		)))
+	})
+
+	It("records the staging duration", func() {
+		Expect(registry).To(HaveMetric(prometheus.StagingDurations,
+			histogramText(prometheus.StagingDurations, prometheus.StagingDurationsHelp, `result="success"`, 300),
+		))
+	})
+
+	When("the staging fails", func() {
+		BeforeEach(func() {
+			stagingBifrost.TransferStagingStub = nil
+			stagingBifrost.TransferStagingReturns(errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			Expect(transferErr).To(MatchError("boom"))
+		})
+
+		It("counts the failed staging", func() {
+			Expect(registry).To(HaveMetric(prometheus.StagingsFinished, fmt.Sprintf(`
+				# HELP %[1]s %[2]s
+				# TYPE %[1]s counter
+				%[1]s{result="failure"} 1
+				`, prometheus.StagingsFinished, prometheus.StagingsFinishedHelp,
+			)))
+		})
+	})
+
+	When("a decorator is created with the same registry", func() {
+		It("reuses the existing metrics", func() {
+			_, err := prometheus.NewStagingBifrostDecorator(stagingBifrost, registry, fakeClock)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package prometheus
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	"code.cloudfoundry.org/lager"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	"k8s.io/utils/clock"
+)
+
+const (
+	TaskCreations              = "eirini_task_creations"
+	TaskCreationsHelp          = "The total number of created tasks"
+	TaskOperationDurations     = "eirini_task_operation_durations"
+	TaskOperationDurationsHelp = "The duration of task operations in milliseconds"
+	TaskOperationErrors        = "eirini_task_operation_errors"
+	TaskOperationErrorsHelp    = "The total number of failed task operations"
+)
+
+//counterfeiter:generate . TaskClient
+
+type TaskClient interface {
+	Desire(ctx context.Context, namespace string, task *api.Task, opts ...shared.Option) error
+	Get(ctx context.Context, guid string) (*api.Task, error)
+	List(ctx context.Context) ([]*api.Task, error)
+	Delete(ctx context.Context, guid string) (string, error)
+}
+
+type TaskClientDecorator struct {
+	TaskClient
+	logger     lager.Logger
+	creations  prometheus_api.Counter
+	operations operationMetrics
+	clock      clock.PassiveClock
+}
+
+func NewTaskClientDecorator(
+	logger lager.Logger,
+	taskClient TaskClient,
+	registry prometheus_api.Registerer,
+	clck clock.PassiveClock,
+) (*TaskClientDecorator, error) {
+	creations, err := registerCounter(registry, TaskCreations, TaskCreationsHelp)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := newOperationMetrics(registry, clck,
+		TaskOperationDurations, TaskOperationDurationsHelp,
+		TaskOperationErrors, TaskOperationErrorsHelp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaskClientDecorator{
+		TaskClient: taskClient,
+		logger:     logger,
+		creations:  creations,
+		operations: operations,
+		clock:      clck,
+	}, nil
+}
+
+func (d *TaskClientDecorator) Desire(ctx context.Context, namespace string, task *api.Task, opts ...shared.Option) error {
+	start := d.clock.Now()
+
+	err := d.TaskClient.Desire(ctx, namespace, task, opts...)
+	if err == nil {
+		d.creations.Inc()
+	}
+
+	d.operations.observe(OperationDesire, start, err)
+
+	return err
+}
+
+func (d *TaskClientDecorator) Get(ctx context.Context, guid string) (*api.Task, error) {
+	start := d.clock.Now()
+	task, err := d.TaskClient.Get(ctx, guid)
+	d.operations.observe(OperationGet, start, err)
+
+	return task, err
+}
+
+func (d *TaskClientDecorator) List(ctx context.Context) ([]*api.Task, error) {
+	start := d.clock.Now()
+	tasks, err := d.TaskClient.List(ctx)
+	d.operations.observe(OperationList, start, err)
+
+	return tasks, err
+}
+
+func (d *TaskClientDecorator) Delete(ctx context.Context, guid string) (string, error) {
+	start := d.clock.Now()
+	callbackURL, err := d.TaskClient.Delete(ctx, guid)
+	d.operations.observe(OperationDelete, start, err)
+
+	return callbackURL, err
+}
@@ -0,0 +1,144 @@
+package prometheus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	"code.cloudfoundry.org/eirini/prometheus"
+	"code.cloudfoundry.org/eirini/prometheus/prometheusfakes"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	prometheus_api "github.com/prometheus/client_golang/prometheus"
+	clock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Task Client Prometheus Decorator", func() {
+	var (
+		taskClient *prometheusfakes.FakeTaskClient
+		decorator  *prometheus.TaskClientDecorator
+		registry   metrics.RegistererGatherer
+		fakeClock  *clock.FakePassiveClock
+		t0         time.Time
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		taskClient = new(prometheusfakes.FakeTaskClient)
+		registry = prometheus_api.NewRegistry()
+
+		t0 = time.Now()
+		fakeClock = clock.NewFakePassiveClock(t0)
+
+		var err error
+		decorator, err = prometheus.NewTaskClientDecorator(tests.NewTestLogger("task-decorator"), taskClient, registry, fakeClock)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Desire", func() {
+		var (
+			task      *api.Task
+			desireErr error
+		)
+
+		BeforeEach(func() {
+			task = &api.Task{GUID: "the-guid"}
+			taskClient.DesireStub = func(context.Context, string, *api.Task, ...shared.Option) error {
+				fakeClock.SetTime(t0.Add(50 * time.Millisecond))
+
+				return nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			desireErr = decorator.Desire(ctx, "the-namespace", task)
+		})
+
+		It("delegates to the task client", func() {
+			Expect(desireErr).NotTo(HaveOccurred())
+			Expect(taskClient.DesireCallCount()).To(Equal(1))
+			_, actualNamespace, actualTask, _ := taskClient.DesireArgsForCall(0)
+			Expect(actualNamespace).To(Equal("the-namespace"))
+			Expect(actualTask).To(Equal(task))
+		})
+
+		It("increments the task creation counter", func() {
+			Expect(registry).To(HaveCounter(prometheus.TaskCreations, prometheus.TaskCreationsHelp, 1))
+		})
+
+		It("measures the duration of the desiring", func() {
+			Expect(registry).To(HaveOperationHistogram(prometheus.TaskOperationDurations, prometheus.TaskOperationDurationsHelp, prometheus.OperationDesire, 50))
+		})
+
+		When("desiring the task fails", func() {
+			BeforeEach(func() {
+				taskClient.DesireStub = nil
+				taskClient.DesireReturns(errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(desireErr).To(MatchError("boom"))
+			})
+
+			It("does not increment the task creation counter", func() {
+				Expect(registry).To(HaveCounter(prometheus.TaskCreations, prometheus.TaskCreationsHelp, 0))
+			})
+
+			It("counts the error", func() {
+				Expect(registry).To(HaveMetric(prometheus.TaskOperationErrors, fmt.Sprintf(`
+					# HELP %[1]s %[2]s
+					# TYPE %[1]s counter
+					%[1]s{error="internal",operation="desire"} 1
+					`, prometheus.TaskOperationErrors, prometheus.TaskOperationErrorsHelp,
+				)))
+			})
+		})
+	})
+
+	It("delegates Get and measures its duration", func() {
+		taskClient.GetReturns(&api.Task{GUID: "the-guid"}, nil)
+
+		task, err := decorator.Get(ctx, "the-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(task.GUID).To(Equal("the-guid"))
+		Expect(registry).To(HaveOperationHistogram(prometheus.TaskOperationDurations, prometheus.TaskOperationDurationsHelp, prometheus.OperationGet, 0))
+	})
+
+	It("delegates List and measures its duration", func() {
+		taskClient.ListReturns([]*api.Task{{GUID: "the-guid"}}, nil)
+
+		tasks, err := decorator.List(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(1))
+		Expect(registry).To(HaveOperationHistogram(prometheus.TaskOperationDurations, prometheus.TaskOperationDurationsHelp, prometheus.OperationList, 0))
+	})
+
+	It("delegates Delete and measures its duration", func() {
+		taskClient.DeleteReturns("the-callback", nil)
+
+		callbackURL, err := decorator.Delete(ctx, "the-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(callbackURL).To(Equal("the-callback"))
+		Expect(registry).To(HaveOperationHistogram(prometheus.TaskOperationDurations, prometheus.TaskOperationDurationsHelp, prometheus.OperationDelete, 0))
+	})
+
+	It("counts failed operations by error class", func() {
+		taskClient.GetReturns(nil, fmt.Errorf("failed to get task: %w", eirini.ErrNotFound))
+
+		_, err := decorator.Get(ctx, "the-guid")
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+		Expect(registry).To(HaveMetric(prometheus.TaskOperationErrors, fmt.Sprintf(`
+			# HELP %[1]s %[2]s
+			# TYPE %[1]s counter
+			%[1]s{error="not_found",operation="get"} 1
+			`, prometheus.TaskOperationErrors, prometheus.TaskOperationErrorsHelp,
+		)))
+	})
+})
@@ -5,34 +5,61 @@ import (
 
 	"github.com/containers/image/docker"
 	"github.com/containers/image/image"
+	"github.com/containers/image/manifest"
 	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
 
-func Fetch(dockerRef string, sysCtx types.SystemContext) (*v1.ImageConfig, error) {
+// Fetch returns the config and the manifest digest of an image. For
+// multi-arch images the image for the platform chosen in sysCtx is used, and
+// its digest is returned instead of the digest of the manifest list.
+func Fetch(ctx context.Context, dockerRef string, sysCtx types.SystemContext) (*v1.ImageConfig, digest.Digest, error) {
 	ref, err := docker.ParseReference(dockerRef)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse docker reference")
+		return nil, "", errors.Wrap(err, "failed to parse docker reference")
 	}
 
-	ctx := context.Background()
-
 	imgSrc, err := ref.NewImageSource(ctx, &sysCtx)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get image source")
+		return nil, "", errors.Wrap(err, "failed to get image source")
 	}
 	defer imgSrc.Close()
 
-	img, err := image.FromUnparsedImage(ctx, &sysCtx, image.UnparsedInstance(imgSrc, nil))
+	unparsedImg := image.UnparsedInstance(imgSrc, nil)
+
+	imgDigest, err := getDigest(ctx, sysCtx, unparsedImg)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get image")
+		return nil, "", err
+	}
+
+	img, err := image.FromUnparsedImage(ctx, &sysCtx, unparsedImg)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get image")
 	}
 
 	imgV1, err := img.OCIConfig(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get oci config")
+		return nil, "", errors.Wrap(err, "failed to get oci config")
+	}
+
+	return &imgV1.Config, imgDigest, nil
+}
+
+func getDigest(ctx context.Context, sysCtx types.SystemContext, unparsedImg types.UnparsedImage) (digest.Digest, error) {
+	manifestBlob, manifestType, err := unparsedImg.Manifest(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get image manifest")
 	}
 
-	return &imgV1.Config, nil
+	if manifest.MIMETypeIsMultiImage(manifestType) {
+		imgDigest, err := image.ChooseManifestInstanceFromManifestList(ctx, &sysCtx, unparsedImg)
+
+		return imgDigest, errors.Wrap(err, "failed to choose image from manifest list")
+	}
+
+	imgDigest, err := manifest.Digest(manifestBlob)
+
+	return imgDigest, errors.Wrap(err, "failed to compute manifest digest")
 }
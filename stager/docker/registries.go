@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/docker"
+	"github.com/containers/image/types"
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// Registries holds the TLS settings used to fetch image metadata from
+// particular registries, keyed by host[:port].
+type Registries struct {
+	certDir  string
+	insecure map[string]bool
+}
+
+// NewRegistries trusts the CA certificates in caFiles for their registries
+// and skips TLS verification for the insecure registries. The CA files are
+// linked rather than copied, so that rotated certificates are picked up.
+func NewRegistries(caFiles map[string]string, insecureRegistries []string) (*Registries, error) {
+	registries := &Registries{insecure: map[string]bool{}}
+
+	for _, host := range insecureRegistries {
+		registries.insecure[host] = true
+	}
+
+	if len(caFiles) == 0 {
+		return registries, nil
+	}
+
+	certDir, err := os.MkdirTemp("", "registry-certs-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create registry certificates dir")
+	}
+
+	for host, caFile := range caFiles {
+		if err := linkCAFile(certDir, host, caFile); err != nil {
+			return nil, errors.Wrapf(err, "failed to set up CA file for registry %q", host)
+		}
+	}
+
+	registries.certDir = certDir
+
+	return registries, nil
+}
+
+// Fetch is like the package level Fetch, applying the settings of the
+// registry of dockerRef first.
+func (r *Registries) Fetch(ctx context.Context, dockerRef string, sysCtx types.SystemContext) (*v1.ImageConfig, digest.Digest, error) {
+	registrySysCtx, err := r.SystemContext(dockerRef, sysCtx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return Fetch(ctx, dockerRef, registrySysCtx)
+}
+
+// SystemContext returns sysCtx with the settings of the registry of
+// dockerRef applied.
+func (r *Registries) SystemContext(dockerRef string, sysCtx types.SystemContext) (types.SystemContext, error) {
+	ref, err := docker.ParseReference(dockerRef)
+	if err != nil {
+		return types.SystemContext{}, errors.Wrap(err, "failed to parse docker reference")
+	}
+
+	sysCtx.DockerPerHostCertDirPath = r.certDir
+
+	if r.insecure[reference.Domain(ref.DockerReference())] {
+		sysCtx.DockerInsecureSkipTLSVerify = types.OptionalBoolTrue
+	}
+
+	return sysCtx, nil
+}
+
+func linkCAFile(certDir, host, caFile string) error {
+	absCAFile, err := filepath.Abs(caFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to resolve CA file path")
+	}
+
+	if _, err = os.Stat(absCAFile); err != nil {
+		return errors.Wrap(err, "failed to stat CA file")
+	}
+
+	hostDir := filepath.Join(certDir, host)
+	if err = os.Mkdir(hostDir, 0o700); err != nil {
+		return errors.Wrap(err, "failed to create host certificates dir")
+	}
+
+	return errors.Wrap(os.Symlink(absCAFile, filepath.Join(hostDir, "ca.crt")), "failed to link CA file")
+}
@@ -0,0 +1,113 @@
+package docker_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/eirini/stager/docker"
+	"github.com/containers/image/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registries", func() {
+	var (
+		caFiles            map[string]string
+		insecureRegistries []string
+		registries         *docker.Registries
+		err                error
+	)
+
+	BeforeEach(func() {
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+		Expect(os.WriteFile(caFile, []byte("the-ca"), 0o600)).To(Succeed())
+
+		caFiles = map[string]string{"private-registry.io:5000": caFile}
+		insecureRegistries = []string{"insecure-registry.io"}
+	})
+
+	JustBeforeEach(func() {
+		registries, err = docker.NewRegistries(caFiles, insecureRegistries)
+	})
+
+	It("succeeds", func() {
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("SystemContext", func() {
+		var (
+			dockerRef string
+			sysCtx    types.SystemContext
+		)
+
+		BeforeEach(func() {
+			dockerRef = "//private-registry.io:5000/user/repo"
+		})
+
+		JustBeforeEach(func() {
+			sysCtx, err = registries.SystemContext(dockerRef, types.SystemContext{
+				DockerAuthConfig: &types.DockerAuthConfig{Username: "user"},
+			})
+		})
+
+		It("keeps the given settings", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sysCtx.DockerAuthConfig).To(Equal(&types.DockerAuthConfig{Username: "user"}))
+		})
+
+		It("trusts the CA of the registry", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sysCtx.DockerPerHostCertDirPath).NotTo(BeEmpty())
+
+			ca, readErr := os.ReadFile(filepath.Join(sysCtx.DockerPerHostCertDirPath, "private-registry.io:5000", "ca.crt"))
+			Expect(readErr).NotTo(HaveOccurred())
+			Expect(string(ca)).To(Equal("the-ca"))
+		})
+
+		It("verifies the registry certificate", func() {
+			Expect(sysCtx.DockerInsecureSkipTLSVerify).To(Equal(types.OptionalBoolUndefined))
+		})
+
+		When("the registry is insecure", func() {
+			BeforeEach(func() {
+				dockerRef = "//insecure-registry.io/user/repo"
+			})
+
+			It("skips TLS verification", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sysCtx.DockerInsecureSkipTLSVerify).To(Equal(types.OptionalBoolTrue))
+			})
+		})
+
+		When("no CA files are configured", func() {
+			BeforeEach(func() {
+				caFiles = nil
+			})
+
+			It("uses the default certificate dirs", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sysCtx.DockerPerHostCertDirPath).To(BeEmpty())
+			})
+		})
+
+		When("the docker ref is invalid", func() {
+			BeforeEach(func() {
+				dockerRef = "this is invalid"
+			})
+
+			It("fails", func() {
+				Expect(err).To(MatchError(ContainSubstring("failed to parse docker reference")))
+			})
+		})
+	})
+
+	When("a CA file does not exist", func() {
+		BeforeEach(func() {
+			caFiles = map[string]string{"private-registry.io": "/does/not/exist"}
+		})
+
+		It("fails", func() {
+			Expect(err).To(MatchError(ContainSubstring(`failed to set up CA file for registry "private-registry.io"`)))
+		})
+	})
+})
@@ -29,6 +29,8 @@ var _ = Describe("Tasks Reporter [needs-logs-for: eirini-api, eirini-task-report
 			GUID:               taskGUID,
 			Namespace:          fixture.Namespace,
 			AppGUID:            tests.GenerateGUID(),
+			MemoryMB:           256,
+			DiskMB:             256,
 			CompletionCallback: fmt.Sprintf("%s/%s", fixture.Wiremock.Address(), taskGUID),
 			Lifecycle: cf.Lifecycle{
 				DockerLifecycle: &cf.DockerLifecycle{
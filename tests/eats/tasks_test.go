@@ -31,6 +31,8 @@ var _ = Describe("Tasks [needs-logs-for: eirini-api, eirini-task-reporter]", fun
 			GUID:               guid,
 			Namespace:          fixture.Namespace,
 			CompletionCallback: completionCallback,
+			MemoryMB:           256,
+			DiskMB:             256,
 			Lifecycle: cf.Lifecycle{
 				DockerLifecycle: &cf.DockerLifecycle{
 					Image: "eirini/dorini",
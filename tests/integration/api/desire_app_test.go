@@ -13,6 +13,7 @@ import (
 	"code.cloudfoundry.org/eirini/tests/integration"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -75,6 +76,27 @@ var _ = Describe("Desire App", func() {
 		})
 	})
 
+	When("the image is not allowed by the image policy", func() {
+		BeforeEach(func() {
+			apiConfig.ImagePolicy.BannedTags = []string{"latest"}
+		})
+
+		It("should return a 422 Unprocessable Entity HTTP code", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+		})
+
+		It("should not create the statefulset", func() {
+			Consistently(func() ([]appsv1.StatefulSet, error) {
+				statefulSets, err := fixture.Clientset.AppsV1().StatefulSets(fixture.Namespace).List(context.Background(), metav1.ListOptions{})
+				if err != nil {
+					return nil, err
+				}
+
+				return statefulSets.Items, nil
+			}, "2s").Should(BeEmpty())
+		})
+	})
+
 	When("no app namespace is explicitly requested", func() {
 		BeforeEach(func() {
 			lrp.Namespace = ""
@@ -43,10 +43,10 @@ var _ = Describe("Docker Staging", func() {
 				Expect(request.Error).To(BeNil())
 				stagingResult := bifrost.StagingResult{}
 				Expect(json.Unmarshal(*request.Result, &stagingResult)).To(Succeed())
-				Expect(stagingResult.ExecutionMetadata).To(Equal(`{"cmd":[],"ports":[{"Port":8888,"Protocol":"tcp"}]}`))
+				Expect(stagingResult.ExecutionMetadata).To(MatchJSON(`{"cmd":[],"entrypoint":["/notdora"],"ports":[{"Port":8888,"Protocol":"tcp"}],"user":"1001"}`))
 				Expect(stagingResult.LifecycleType).To(Equal("docker"))
-				Expect(stagingResult.LifecycleMetadata.DockerImage).To(Equal("eirini/custom-port"))
-				Expect(stagingResult.ProcessTypes).To(Equal(bifrost.ProcessTypes{Web: ""}))
+				Expect(stagingResult.LifecycleMetadata.DockerImage).To(HavePrefix("eirini/custom-port@sha256:"))
+				Expect(stagingResult.ProcessTypes).To(Equal(bifrost.ProcessTypes{Web: "/notdora"}))
 			},
 		)
 	})
@@ -67,7 +67,7 @@ var _ = Describe("Docker Staging", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(code).To(Equal(http.StatusAccepted))
 
-		Expect(capiServer.ReceivedRequests()).To(HaveLen(1))
+		Eventually(capiServer.ReceivedRequests).Should(HaveLen(1))
 	})
 
 	When("image lives in a private registry", func() {
@@ -83,8 +83,8 @@ var _ = Describe("Docker Staging", func() {
 					Expect(request.Error).To(BeNil())
 					stagingResult := bifrost.StagingResult{}
 					Expect(json.Unmarshal(*request.Result, &stagingResult)).To(Succeed())
-					Expect(stagingResult.ExecutionMetadata).To(Equal(`{"cmd":[],"ports":[{"Port":8888,"Protocol":"tcp"}]}`))
-					Expect(stagingResult.LifecycleMetadata.DockerImage).To(Equal("eiriniuser/notdora"))
+					Expect(stagingResult.ExecutionMetadata).To(MatchJSON(`{"cmd":[],"entrypoint":["/notdora"],"ports":[{"Port":8888,"Protocol":"tcp"}],"user":"1001"}`))
+					Expect(stagingResult.LifecycleMetadata.DockerImage).To(HavePrefix("eiriniuser/notdora@sha256:"))
 				},
 			)
 		})
@@ -103,12 +103,45 @@ var _ = Describe("Docker Staging", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(code).To(Equal(http.StatusAccepted))
 
-			Expect(capiServer.ReceivedRequests()).To(HaveLen(1))
+			Eventually(capiServer.ReceivedRequests).Should(HaveLen(1))
+		})
+	})
+
+	When("the image runs as root", func() {
+		BeforeEach(func() {
+			capiServer.RouteToHandler(
+				http.MethodPost,
+				"/staging/completed",
+				func(w http.ResponseWriter, req *http.Request) {
+					bytes, err := io.ReadAll(req.Body)
+					Expect(err).NotTo(HaveOccurred())
+					request := &cc_messages.StagingResponseForCC{}
+					Expect(json.Unmarshal(bytes, request)).To(Succeed())
+					Expect(request.Error).NotTo(BeNil())
+					Expect(request.Error.Id).To(Equal(cc_messages.STAGING_ERROR))
+					Expect(request.Error.Message).To(ContainSubstring("image runs as root"))
+				},
+			)
+		})
+
+		It("fails staging", func() {
+			code, err := desireStaging(httpClient, cf.StagingRequest{
+				Lifecycle: cf.StagingLifecycle{
+					DockerLifecycle: &cf.StagingDockerLifecycle{
+						Image: "eirini/busybox-root",
+					},
+				},
+				CompletionCallback: fmt.Sprintf("%s/staging/completed", capiServer.URL()),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(code).To(Equal(http.StatusAccepted))
+
+			Eventually(capiServer.ReceivedRequests).Should(HaveLen(1))
 		})
 	})
 
 	When("the callback uri is invalid", func() {
-		It("should return a 500 Internal Server Error", func() {
+		It("still accepts the staging, as it is staged in the background", func() {
 			code, err := desireStaging(httpClient, cf.StagingRequest{
 				Lifecycle: cf.StagingLifecycle{
 					DockerLifecycle: &cf.StagingDockerLifecycle{
@@ -118,7 +151,7 @@ var _ = Describe("Docker Staging", func() {
 				CompletionCallback: "http://definitely-does-not-exist.io/staging/completed",
 			})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(code).To(Equal(http.StatusInternalServerError))
+			Expect(code).To(Equal(http.StatusAccepted))
 		})
 	})
 
@@ -139,7 +172,7 @@ var _ = Describe("Docker Staging", func() {
 			)
 		})
 
-		It("should return a 500 Internal Server Error", func() {
+		It("reports the staging failure to the cloud controller", func() {
 			code, err := desireStaging(httpClient, cf.StagingRequest{
 				Lifecycle: cf.StagingLifecycle{
 					DockerLifecycle: &cf.StagingDockerLifecycle{
@@ -150,6 +183,8 @@ var _ = Describe("Docker Staging", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(code).To(Equal(http.StatusAccepted))
+
+			Eventually(capiServer.ReceivedRequests).Should(HaveLen(1))
 		})
 	})
 })
@@ -0,0 +1,47 @@
+package api_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	var metricsURL string
+
+	BeforeEach(func() {
+		apiConfig.PrometheusPort = fixture.NextAvailablePort()
+		metricsURL = fmt.Sprintf("http://localhost:%d/metrics", apiConfig.PrometheusPort)
+	})
+
+	JustBeforeEach(func() {
+		desireLRPWithGUID(tests.GenerateGUID(), fixture.Namespace)
+	})
+
+	getMetrics := func() string {
+		resp, err := http.Get(metricsURL) //nolint:noctx
+		Expect(err).NotTo(HaveOccurred())
+
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		return string(body)
+	}
+
+	It("serves the lrp operation metrics", func() {
+		Eventually(getMetrics).Should(ContainSubstring("eirini_lrp_creations 1"))
+		Expect(getMetrics()).To(ContainSubstring(`eirini_lrp_operation_durations_count{operation="desire"} 1`))
+	})
+
+	It("serves the http request metrics by route", func() {
+		Eventually(getMetrics).Should(ContainSubstring(`eirini_http_requests{code="202",method="PUT",route="/apps/:process_guid"} 1`))
+	})
+})
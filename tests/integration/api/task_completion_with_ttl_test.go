@@ -48,6 +48,8 @@ var _ = Describe("Tasks completion with respect to TTL", func() {
 			AppName:   "my_app",
 			SpaceName: "my_space",
 			Namespace: fixture.Namespace,
+			MemoryMB:  256,
+			DiskMB:    256,
 			Lifecycle: cf.Lifecycle{
 				DockerLifecycle: &cf.DockerLifecycle{
 					Image:   "eirini/busybox",
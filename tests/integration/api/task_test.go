@@ -41,6 +41,8 @@ var _ = Describe("Tasks", func() {
 			SpaceName:   "my_space",
 			Namespace:   fixture.Namespace,
 			Environment: []cf.EnvironmentVariable{{Name: "my-env", Value: "my-value"}},
+			MemoryMB:    256,
+			DiskMB:      256,
 			Lifecycle: cf.Lifecycle{
 				DockerLifecycle: &cf.DockerLifecycle{
 					Image: "eirini/dorini",
@@ -18,6 +18,9 @@ var (
 )
 
 var _ = SynchronizedBeforeSuite(func() []byte {
+	// eirini-controller cannot start without its custom resource definitions
+	integration.InstallCRDs(tests.GetKubeconfig())
+
 	eiriniBins = integration.NewEiriniBinaries()
 
 	data, err := json.Marshal(eiriniBins)
@@ -35,6 +38,7 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 var _ = SynchronizedAfterSuite(func() {
 	fixture.Destroy()
 }, func() {
+	integration.UninstallCRDs(tests.GetKubeconfig())
 	eiriniBins.TearDown()
 })
 
@@ -0,0 +1,114 @@
+package cmd_test
+
+import (
+	"context"
+	"os"
+
+	"code.cloudfoundry.org/eirini"
+	eiriniv1 "code.cloudfoundry.org/eirini/pkg/apis/eirini/v1"
+	"code.cloudfoundry.org/eirini/tests"
+	"code.cloudfoundry.org/eirini/tests/integration"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("EiriniController", func() {
+	var (
+		config         *eirini.ControllerConfig
+		configFilePath string
+		session        *gexec.Session
+	)
+
+	BeforeEach(func() {
+		config = integration.DefaultControllerConfig(fixture.Namespace)
+		config.ConfigPath = fixture.KubeConfigPath
+	})
+
+	JustBeforeEach(func() {
+		session, configFilePath = eiriniBins.EiriniController.Run(config)
+	})
+
+	AfterEach(func() {
+		if configFilePath != "" {
+			Expect(os.Remove(configFilePath)).To(Succeed())
+		}
+		if session != nil {
+			Eventually(session.Kill()).Should(gexec.Exit())
+		}
+	})
+
+	When("eirini controller is executed with a valid config", func() {
+		It("should be able to start properly", func() {
+			Consistently(session, "5s").ShouldNot(gexec.Exit())
+		})
+	})
+
+	When("an LRP resource is created", func() {
+		var (
+			client runtimeclient.Client
+			lrp    *eiriniv1.LRP
+			guid   string
+		)
+
+		BeforeEach(func() {
+			kubeConfig, err := clientcmd.BuildConfigFromFlags("", fixture.KubeConfigPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			scheme := runtime.NewScheme()
+			Expect(eiriniv1.AddToScheme(scheme)).To(Succeed())
+
+			client, err = runtimeclient.New(kubeConfig, runtimeclient.Options{Scheme: scheme})
+			Expect(err).NotTo(HaveOccurred())
+
+			guid = tests.GenerateGUID()
+			lrp = &eiriniv1.LRP{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-lrp",
+					Namespace: fixture.Namespace,
+				},
+				Spec: eiriniv1.LRPSpec{
+					GUID:      guid,
+					Version:   "version-1",
+					Image:     "eirini/dorini",
+					Instances: 1,
+					MemoryMB:  100,
+					DiskMB:    100,
+				},
+			}
+			Expect(client.Create(context.Background(), lrp)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			// the controller has to remove the cleanup finalizer before it is stopped
+			Expect(client.Delete(context.Background(), lrp)).To(Succeed())
+			Eventually(func() bool {
+				err := client.Get(context.Background(), runtimeclient.ObjectKeyFromObject(lrp), &eiriniv1.LRP{})
+
+				return k8serrors.IsNotFound(err)
+			}).Should(BeTrue())
+		})
+
+		It("creates the statefulset of the lrp", func() {
+			Eventually(func() *appsv1.StatefulSet {
+				return integration.GetStatefulSet(fixture.Clientset, fixture.Namespace, guid, "version-1")
+			}).ShouldNot(BeNil())
+		})
+	})
+
+	When("the config file doesn't exist", func() {
+		It("exits reporting missing config file", func() {
+			session = eiriniBins.EiriniController.Restart("/does/not/exist", session)
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(BeZero())
+			Expect(session.Err).To(gbytes.Say("Failed to read config file: failed to read file"))
+		})
+	})
+})
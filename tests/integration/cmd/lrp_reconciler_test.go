@@ -0,0 +1,73 @@
+package cmd_test
+
+import (
+	"fmt"
+	"os"
+
+	"code.cloudfoundry.org/eirini"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("LRPReconciler", func() {
+	var (
+		config         *eirini.LRPReconcilerConfig
+		configFilePath string
+		session        *gexec.Session
+	)
+
+	BeforeEach(func() {
+		config = &eirini.LRPReconcilerConfig{
+			CommonConfig: eirini.CommonConfig{
+				KubeConfig: eirini.KubeConfig{
+					ConfigPath: fixture.KubeConfigPath,
+				},
+				WorkloadsNamespace: fixture.Namespace,
+			},
+			LeaderElectionID:        fmt.Sprintf("test-lrp-reconciler-%d", GinkgoParallelProcess()),
+			LeaderElectionNamespace: fixture.Namespace,
+		}
+	})
+
+	JustBeforeEach(func() {
+		session, configFilePath = eiriniBins.LRPReconciler.Run(config)
+	})
+
+	AfterEach(func() {
+		if configFilePath != "" {
+			Expect(os.Remove(configFilePath)).To(Succeed())
+		}
+		if session != nil {
+			Eventually(session.Kill()).Should(gexec.Exit())
+		}
+	})
+
+	When("lrp reconciler is executed with a valid config", func() {
+		It("should be able to start properly", func() {
+			Consistently(session, "5s").ShouldNot(gexec.Exit())
+		})
+	})
+
+	When("the config file doesn't exist", func() {
+		It("exits reporting missing config file", func() {
+			session = eiriniBins.LRPReconciler.Restart("/does/not/exist", session)
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(BeZero())
+			Expect(session.Err).To(gbytes.Say("Failed to read config file: failed to read file"))
+		})
+	})
+
+	When("nonexistent kubeconfig path is provided", func() {
+		BeforeEach(func() {
+			config.ConfigPath = "foo"
+		})
+
+		It("fails", func() {
+			Eventually(session).Should(gexec.Exit())
+			Expect(session.ExitCode()).NotTo(BeZero())
+			Expect(session.Err).To(gbytes.Say("foo: no such file or directory"))
+		})
+	})
+})
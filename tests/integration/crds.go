@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/clientcmd"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InstallCRDs creates or updates the eirini custom resource definitions
+// generated in config/crd/bases and waits for them to be established.
+func InstallCRDs(kubeConfigPath string) {
+	client := newCRDClient(kubeConfigPath)
+
+	for _, manifest := range crdManifests() {
+		crd := readCRD(manifest)
+		createOrUpdateCRD(client, crd)
+
+		Eventually(func() bool {
+			return crdEstablished(client, crd.Name)
+		}).Should(BeTrue(), "CRD %s is not established", crd.Name)
+	}
+}
+
+// UninstallCRDs deletes the eirini custom resource definitions along with
+// all their custom resources.
+func UninstallCRDs(kubeConfigPath string) {
+	client := newCRDClient(kubeConfigPath)
+
+	for _, manifest := range crdManifests() {
+		err := client.Delete(context.Background(), readCRD(manifest))
+		if !k8serrors.IsNotFound(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+}
+
+func newCRDClient(kubeConfigPath string) runtimeclient.Client {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+	Expect(err).NotTo(HaveOccurred())
+
+	scheme := k8sruntime.NewScheme()
+	Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	client, err := runtimeclient.New(config, runtimeclient.Options{Scheme: scheme})
+	Expect(err).NotTo(HaveOccurred())
+
+	return client
+}
+
+func crdManifests() []string {
+	manifests, err := filepath.Glob(filepath.Join(crdsDir(), "*.yaml"))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(manifests).NotTo(BeEmpty())
+
+	return manifests
+}
+
+func crdsDir() string {
+	_, thisFile, _, ok := runtime.Caller(0)
+	Expect(ok).To(BeTrue())
+
+	return filepath.Join(filepath.Dir(thisFile), "..", "..", "config", "crd", "bases")
+}
+
+func readCRD(path string) *apiextensionsv1.CustomResourceDefinition {
+	file, err := os.Open(path)
+	Expect(err).NotTo(HaveOccurred())
+
+	defer file.Close()
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	Expect(yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(crd)).To(Succeed())
+
+	return crd
+}
+
+func createOrUpdateCRD(client runtimeclient.Client, crd *apiextensionsv1.CustomResourceDefinition) {
+	err := client.Create(context.Background(), crd)
+	if !k8serrors.IsAlreadyExists(err) {
+		Expect(err).NotTo(HaveOccurred())
+
+		return
+	}
+
+	existing := &apiextensionsv1.CustomResourceDefinition{}
+	Expect(client.Get(context.Background(), types.NamespacedName{Name: crd.Name}, existing)).To(Succeed())
+
+	existing.Spec = crd.Spec
+	Expect(client.Update(context.Background(), existing)).To(Succeed())
+}
+
+func crdEstablished(client runtimeclient.Client, name string) bool {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	Expect(client.Get(context.Background(), types.NamespacedName{Name: name}, crd)).To(Succeed())
+
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established {
+			return condition.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -1,17 +1,20 @@
 package docker_test
 
 import (
+	"context"
+
 	"code.cloudfoundry.org/eirini/stager/docker"
 	"code.cloudfoundry.org/eirini/tests"
 	"github.com/containers/image/types"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
 )
 
 var _ = Describe("Fetch Docker Image Metadata", func() {
 	Context("public image from DockerHub", func() {
 		It("should return the correct exposed ports", func() {
-			imgConfig, err := docker.Fetch("//docker.io/eirini/custom-port:latest", types.SystemContext{})
+			imgConfig, _, err := docker.Fetch(context.Background(), "//docker.io/eirini/custom-port:latest", types.SystemContext{})
 
 			Expect(err).To(BeNil())
 			Expect(imgConfig).ToNot(BeNil())
@@ -19,9 +22,33 @@ var _ = Describe("Fetch Docker Image Metadata", func() {
 			Expect(imgConfig.ExposedPorts).To(HaveKey("8888/tcp"))
 		})
 
+		It("should return the manifest digest", func() {
+			_, imgDigest, err := docker.Fetch(context.Background(), "//docker.io/eirini/custom-port:latest", types.SystemContext{})
+
+			Expect(err).To(BeNil())
+			Expect(imgDigest.Validate()).To(Succeed())
+		})
+
+		Context("multi-arch image", func() {
+			fetchDigest := func(arch string) digest.Digest {
+				_, imgDigest, err := docker.Fetch(context.Background(), "//docker.io/library/busybox:latest", types.SystemContext{
+					OSChoice:           "linux",
+					ArchitectureChoice: arch,
+				})
+				Expect(err).To(BeNil())
+				Expect(imgDigest.Validate()).To(Succeed())
+
+				return imgDigest
+			}
+
+			It("should return the digest of the image for the chosen platform", func() {
+				Expect(fetchDigest("amd64")).NotTo(Equal(fetchDigest("arm64")))
+			})
+		})
+
 		Context("when repo is invalid", func() {
 			It("should return an error", func() {
-				imgConfig, err := docker.Fetch("//docker.io/eirini/no_such_image:latest", types.SystemContext{})
+				imgConfig, _, err := docker.Fetch(context.Background(), "//docker.io/eirini/no_such_image:latest", types.SystemContext{})
 
 				Expect(err).To(MatchError(ContainSubstring("failed to get image source")))
 				Expect(imgConfig).To(BeNil())
@@ -30,7 +57,7 @@ var _ = Describe("Fetch Docker Image Metadata", func() {
 
 		Context("private image from DockerHub", func() {
 			It("should return the correct exposed ports", func() {
-				imgConfig, err := docker.Fetch("//docker.io/eiriniuser/notdora:custom-port", types.SystemContext{
+				imgConfig, _, err := docker.Fetch(context.Background(), "//docker.io/eiriniuser/notdora:custom-port", types.SystemContext{
 					DockerAuthConfig: &types.DockerAuthConfig{
 						Username: "eiriniuser",
 						Password: tests.GetEiriniDockerHubPassword(),
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"strconv"
 
+	"code.cloudfoundry.org/eirini"
 	"code.cloudfoundry.org/eirini/api"
 	"code.cloudfoundry.org/eirini/k8s"
 	"code.cloudfoundry.org/eirini/k8s/client"
+	"code.cloudfoundry.org/eirini/k8s/netpol"
 	"code.cloudfoundry.org/eirini/k8s/pdb"
+	"code.cloudfoundry.org/eirini/k8s/route"
 	"code.cloudfoundry.org/eirini/k8s/shared"
 	"code.cloudfoundry.org/eirini/k8s/stset"
 	"code.cloudfoundry.org/eirini/tests"
@@ -74,7 +77,26 @@ var _ = Describe("LRPClient", func() {
 			Expect(statefulset.Spec.Template.Spec.SecurityContext.RunAsNonRoot).To(PointTo(BeTrue()))
 			Expect(statefulset.Spec.Template.Spec.Containers[0].Command).To(Equal(lrp.Command))
 			Expect(statefulset.Spec.Template.Spec.Containers[0].Image).To(Equal(lrp.Image))
-			Expect(statefulset.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "FOO", Value: "BAR"}))
+			Expect(statefulset.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name: "FOO",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: stset.EnvSecretName(statefulset.Name)},
+						Key:                  "opi.FOO",
+					},
+				},
+			}))
+		})
+
+		It("stores the environment in a secret owned by the statefulset", func() {
+			statefulset := getStatefulSetForLRP(lrp)
+			secret, err := getSecret(fixture.Namespace, stset.EnvSecretName(statefulset.Name))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Data).To(HaveKeyWithValue("opi.FOO", []byte("BAR")))
+			Expect(secret.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Kind": Equal("StatefulSet"),
+				"Name": Equal(statefulset.Name),
+			})))
 		})
 
 		It("sets the latest migration index annotation", func() {
@@ -556,6 +578,12 @@ fi;`,
 				Expect(actualLRP.GUID).To(Equal(lrp.GUID))
 			})
 		})
+
+		It("resolves the environment from the env secret", func() {
+			actualLRP, err := lrpClient.Get(ctx, lrp.LRPIdentifier)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualLRP.Env).To(HaveKeyWithValue("FOO", "BAR"))
+		})
 	})
 
 	Describe("GetInstances", func() {
@@ -689,9 +717,13 @@ func createLrpClient(workloadsNamespace string, allowRunImageAsRoot bool) *k8s.L
 		"registry-secret",
 		false,
 		allowRunImageAsRoot,
+		nil,
+		nil,
+		eirini.SchedulingConfig{},
 		123,
 		k8s.CreateLivenessProbe,
 		k8s.CreateReadinessProbe,
+		k8s.CreateStartupProbe,
 	)
 
 	return k8s.NewLRPClient(
@@ -699,10 +731,13 @@ func createLrpClient(workloadsNamespace string, allowRunImageAsRoot bool) *k8s.L
 		client.NewSecret(fixture.Clientset),
 		client.NewStatefulSet(fixture.Clientset, workloadsNamespace),
 		client.NewPod(fixture.Clientset, workloadsNamespace),
-		pdb.NewUpdater(client.NewPodDisruptionBudget(fixture.Clientset)),
+		pdb.NewUpdater(client.NewPodDisruptionBudget(fixture.Clientset), ""),
+		route.NewUpdater(client.NewService(fixture.Clientset), client.NewIngress(fixture.Clientset), ""),
+		netpol.NewUpdater(logger, client.NewNetworkPolicy(fixture.Clientset)),
 		client.NewEvent(fixture.Clientset),
 		lrpToStatefulSetConverter,
 		stset.NewStatefulSetToLRPConverter(),
+		nil,
 	)
 }
 
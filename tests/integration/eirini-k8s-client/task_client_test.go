@@ -292,6 +292,8 @@ func createTaskClient(workloadsNamespace string) *k8s.TaskClient {
 		tests.GetApplicationServiceAccount(),
 		"registry-secret",
 		false,
+		nil,
+		nil,
 		123,
 	)
 
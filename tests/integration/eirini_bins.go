@@ -19,6 +19,7 @@ import (
 type EiriniBinaries struct {
 	API                      Binary `json:"api"`
 	EventsReporter           Binary `json:"events_reporter"`
+	LRPReconciler            Binary `json:"lrp_reconciler"`
 	TaskReporter             Binary `json:"task_reporter"`
 	EiriniController         Binary `json:"eirini_controller"`
 	InstanceIndexEnvInjector Binary `json:"instance_index_env_injector"`
@@ -37,6 +38,7 @@ func NewEiriniBinaries() EiriniBinaries {
 	bins.setBinsPath()
 	bins.API = NewBinary("code.cloudfoundry.org/eirini/cmd/api", bins.BinsPath, bins.CertsPath)
 	bins.EventsReporter = NewBinary("code.cloudfoundry.org/eirini/cmd/event-reporter", bins.BinsPath, bins.CertsPath)
+	bins.LRPReconciler = NewBinary("code.cloudfoundry.org/eirini/cmd/lrp-reconciler", bins.BinsPath, bins.CertsPath)
 	bins.TaskReporter = NewBinary("code.cloudfoundry.org/eirini/cmd/task-reporter", bins.BinsPath, bins.CertsPath)
 	bins.EiriniController = NewBinary("code.cloudfoundry.org/eirini/cmd/eirini-controller", bins.BinsPath, bins.CertsPath)
 	bins.InstanceIndexEnvInjector = NewBinary("code.cloudfoundry.org/eirini/cmd/instance-index-env-injector", bins.BinsPath, bins.CertsPath)
@@ -14,7 +14,9 @@ import (
 	"code.cloudfoundry.org/eirini/k8s"
 	"code.cloudfoundry.org/eirini/k8s/client"
 	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/k8s/netpol"
 	"code.cloudfoundry.org/eirini/k8s/pdb"
+	"code.cloudfoundry.org/eirini/k8s/route"
 	"code.cloudfoundry.org/eirini/k8s/stset"
 	"code.cloudfoundry.org/eirini/tests"
 	"code.cloudfoundry.org/eirini/tests/integration"
@@ -94,19 +96,26 @@ var _ = Describe("Events", func() {
 				"registry-secret",
 				false,
 				false,
+				nil,
+				nil,
+				eirini.SchedulingConfig{},
 				1,
 				k8s.CreateLivenessProbe,
 				k8s.CreateReadinessProbe,
+				k8s.CreateStartupProbe,
 			)
 			lrpClient = k8s.NewLRPClient(
 				logger,
 				client.NewSecret(fixture.Clientset),
 				client.NewStatefulSet(fixture.Clientset, fixture.Namespace),
 				client.NewPod(fixture.Clientset, fixture.Namespace),
-				pdb.NewUpdater(client.NewPodDisruptionBudget(fixture.Clientset)),
+				pdb.NewUpdater(client.NewPodDisruptionBudget(fixture.Clientset), ""),
+				route.NewUpdater(client.NewService(fixture.Clientset), client.NewIngress(fixture.Clientset), ""),
+				netpol.NewUpdater(logger, client.NewNetworkPolicy(fixture.Clientset)),
 				client.NewEvent(fixture.Clientset),
 				lrpToStatefulSetConverter,
 				stset.NewStatefulSetToLRPConverter(),
+				nil,
 			)
 		})
 
@@ -240,7 +249,7 @@ var _ = Describe("Events", func() {
 		var taskDesirer jobs.Desirer
 
 		BeforeEach(func() {
-			taskToJobConverter := jobs.NewTaskToJobConverter(tests.GetApplicationServiceAccount(), "", false, 1234)
+			taskToJobConverter := jobs.NewTaskToJobConverter(tests.GetApplicationServiceAccount(), "", false, nil, nil, 1234)
 			taskDesirer = jobs.NewDesirer(
 				logger,
 				taskToJobConverter,
@@ -251,9 +260,11 @@ var _ = Describe("Events", func() {
 
 		JustBeforeEach(func() {
 			task := api.Task{
-				Command: []string{"exit", "1"},
-				Image:   "eirini/busybox",
-				GUID:    tests.GenerateGUID(),
+				Command:  []string{"exit", "1"},
+				Image:    "eirini/busybox",
+				GUID:     tests.GenerateGUID(),
+				MemoryMB: 256,
+				DiskMB:   256,
 			}
 			Expect(taskDesirer.Desire(ctx, fixture.Namespace, &task)).To(Succeed())
 		})
@@ -0,0 +1,205 @@
+package integration_test
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/eirini/api"
+	"code.cloudfoundry.org/eirini/k8s/client"
+	"code.cloudfoundry.org/eirini/k8s/jobs"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/tests"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		informerCache *client.Cache
+		cancel        context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		informerCache = client.NewCache(fixture.Clientset, fixture.Namespace, 0)
+
+		var cacheCtx context.Context
+		cacheCtx, cancel = context.WithCancel(context.Background())
+		informerCache.Start(cacheCtx)
+		Expect(informerCache.WaitForSync(cacheCtx)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("reports that it has synced", func() {
+		Expect(informerCache.HasSynced()).To(BeTrue())
+	})
+
+	Describe("CachedStatefulSet", func() {
+		var (
+			statefulSetClient *client.CachedStatefulSet
+			guid              string
+		)
+
+		BeforeEach(func() {
+			statefulSetClient = client.NewCachedStatefulSet(client.NewStatefulSet(fixture.Clientset, fixture.Namespace), informerCache)
+			guid = tests.GenerateGUID()
+
+			createStatefulSet(fixture.Namespace, "one", map[string]string{
+				stset.LabelSourceType: "FOO",
+				stset.LabelGUID:       guid,
+				stset.LabelVersion:    "42",
+			})
+			createStatefulSet(fixture.Namespace, "two", map[string]string{
+				stset.LabelSourceType: "BAR",
+				stset.LabelGUID:       guid,
+				stset.LabelVersion:    "43",
+			})
+		})
+
+		It("lists statefulsets by source type", func() {
+			Eventually(func() []string {
+				statefulSets, err := statefulSetClient.GetBySourceType(ctx, "FOO")
+				Expect(err).NotTo(HaveOccurred())
+
+				return statefulSetNames(statefulSets)
+			}).Should(ConsistOf("one"))
+		})
+
+		It("lists statefulsets by lrp identifier", func() {
+			Eventually(func() []string {
+				statefulSets, err := statefulSetClient.GetByLRPIdentifier(ctx, api.LRPIdentifier{GUID: guid, Version: "43"})
+				Expect(err).NotTo(HaveOccurred())
+
+				return statefulSetNames(statefulSets)
+			}).Should(ConsistOf("two"))
+		})
+	})
+
+	Describe("CachedPod", func() {
+		var (
+			podClient *client.CachedPod
+			guid      string
+		)
+
+		BeforeEach(func() {
+			podClient = client.NewCachedPod(client.NewPod(fixture.Clientset, fixture.Namespace), informerCache)
+			guid = tests.GenerateGUID()
+
+			createLrpPods(fixture.Namespace, "one")
+			createTaskPods(fixture.Namespace, "two")
+			createPod(fixture.Namespace, "three", map[string]string{
+				stset.LabelGUID:    guid,
+				stset.LabelVersion: "42",
+			})
+		})
+
+		It("lists all eirini pods", func() {
+			Eventually(func() []string {
+				pods, err := podClient.GetAll(ctx)
+				Expect(err).NotTo(HaveOccurred())
+
+				return podNames(pods)
+			}).Should(ConsistOf("one", "two"))
+		})
+
+		It("lists pods by lrp identifier", func() {
+			Eventually(func() []string {
+				pods, err := podClient.GetByLRPIdentifier(ctx, api.LRPIdentifier{GUID: guid, Version: "42"})
+				Expect(err).NotTo(HaveOccurred())
+
+				return podNames(pods)
+			}).Should(ConsistOf("three"))
+		})
+	})
+
+	Describe("CachedJob", func() {
+		var (
+			jobClient *client.CachedJob
+			guid      string
+		)
+
+		BeforeEach(func() {
+			jobClient = client.NewCachedJob(client.NewJob(fixture.Clientset, fixture.Namespace), informerCache)
+			guid = tests.GenerateGUID()
+
+			createJob(fixture.Namespace, "running", map[string]string{
+				jobs.LabelSourceType: jobs.TaskSourceType,
+				jobs.LabelGUID:       guid,
+			})
+			createJob(fixture.Namespace, "completed", map[string]string{
+				jobs.LabelSourceType:    jobs.TaskSourceType,
+				jobs.LabelGUID:          tests.GenerateGUID(),
+				jobs.LabelTaskCompleted: jobs.TaskCompletedTrue,
+			})
+		})
+
+		It("lists task jobs", func() {
+			Eventually(func() []string {
+				allJobs, err := jobClient.List(ctx, true)
+				Expect(err).NotTo(HaveOccurred())
+
+				return jobNames(allJobs)
+			}).Should(ConsistOf("running", "completed"))
+		})
+
+		It("excludes completed jobs when asked to", func() {
+			Eventually(func() []string {
+				allJobs, err := jobClient.List(ctx, false)
+				Expect(err).NotTo(HaveOccurred())
+
+				return jobNames(allJobs)
+			}).Should(ConsistOf("running"))
+		})
+
+		It("gets jobs by guid", func() {
+			Eventually(func() []string {
+				allJobs, err := jobClient.GetByGUID(ctx, guid, false)
+				Expect(err).NotTo(HaveOccurred())
+
+				return jobNames(allJobs)
+			}).Should(ConsistOf("running"))
+		})
+	})
+
+	Describe("CachedEvent", func() {
+		var (
+			eventClient *client.CachedEvent
+			pod         corev1.Pod
+		)
+
+		BeforeEach(func() {
+			eventClient = client.NewCachedEvent(client.NewEvent(fixture.Clientset), informerCache)
+			pod = corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "the-pod",
+					Namespace: fixture.Namespace,
+					UID:       types.UID(tests.GenerateGUID()),
+				},
+			}
+
+			createEvent(fixture.Namespace, "the-event", corev1.ObjectReference{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+				UID:       pod.UID,
+			})
+			createEvent(fixture.Namespace, "another-event", corev1.ObjectReference{
+				Name:      "another-pod",
+				Namespace: fixture.Namespace,
+				UID:       types.UID(tests.GenerateGUID()),
+			})
+		})
+
+		It("lists the events belonging to a pod", func() {
+			Eventually(func() []string {
+				events, err := eventClient.GetByPod(ctx, pod)
+				Expect(err).NotTo(HaveOccurred())
+
+				return eventNames(events)
+			}).Should(ConsistOf("the-event"))
+		})
+	})
+})
@@ -0,0 +1,33 @@
+package integration_test
+
+import (
+	"code.cloudfoundry.org/eirini/k8s/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ConfigMaps", func() {
+	var configMapClient *client.ConfigMap
+
+	BeforeEach(func() {
+		configMapClient = client.NewConfigMap(fixture.Clientset)
+	})
+
+	Describe("Create", func() {
+		It("creates the config map in the namespace", func() {
+			created, err := configMapClient.Create(ctx, fixture.Namespace, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "the-config-",
+				},
+				Data: map[string]string{"foo": "bar"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			configMap, err := fixture.Clientset.CoreV1().ConfigMaps(fixture.Namespace).Get(ctx, created.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configMap.Data).To(HaveKeyWithValue("foo", "bar"))
+		})
+	})
+})
@@ -0,0 +1,128 @@
+package integration_test
+
+import (
+	"code.cloudfoundry.org/eirini/k8s/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Ingresses", func() {
+	var ingressClient *client.Ingress
+
+	BeforeEach(func() {
+		ingressClient = client.NewIngress(fixture.Clientset)
+	})
+
+	Describe("Get", func() {
+		BeforeEach(func() {
+			createIngress(fixture.Namespace, "foo")
+		})
+
+		It("retrieves an Ingress by namespace and name", func() {
+			ingress, err := ingressClient.Get(ctx, fixture.Namespace, "foo")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ingress.Name).To(Equal("foo"))
+			Expect(ingress.Namespace).To(Equal(fixture.Namespace))
+		})
+	})
+
+	Describe("Create", func() {
+		It("creates the ingress in the namespace", func() {
+			_, err := ingressClient.Create(ctx, fixture.Namespace, ingressSpec("foo"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ingressNames(listIngresses(fixture.Namespace))).To(ContainElement("foo"))
+		})
+	})
+
+	Describe("Update", func() {
+		var ingress *networkingv1.Ingress
+
+		BeforeEach(func() {
+			ingress = createIngress(fixture.Namespace, "foo")
+		})
+
+		It("updates the existing ingress", func() {
+			ingress.Spec.Rules[0].Host = "bar.example.com"
+			_, err := ingressClient.Update(ctx, fixture.Namespace, ingress)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated, err := fixture.Clientset.NetworkingV1().Ingresses(fixture.Namespace).Get(ctx, "foo", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Spec.Rules[0].Host).To(Equal("bar.example.com"))
+		})
+	})
+
+	Describe("Delete", func() {
+		BeforeEach(func() {
+			createIngress(fixture.Namespace, "foo")
+		})
+
+		It("deletes an Ingress", func() {
+			err := ingressClient.Delete(ctx, fixture.Namespace, "foo")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() []string {
+				return ingressNames(listIngresses(fixture.Namespace))
+			}).ShouldNot(ContainElement("foo"))
+		})
+	})
+})
+
+func ingressSpec(name string) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "foo.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: name,
+											Port: networkingv1.ServiceBackendPort{Number: 8080},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func createIngress(ns, name string) *networkingv1.Ingress {
+	ingress, err := fixture.Clientset.NetworkingV1().Ingresses(ns).Create(ctx, ingressSpec(name), metav1.CreateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	return ingress
+}
+
+func listIngresses(ns string) []networkingv1.Ingress {
+	ingresses, err := fixture.Clientset.NetworkingV1().Ingresses(ns).List(ctx, metav1.ListOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	return ingresses.Items
+}
+
+func ingressNames(ingresses []networkingv1.Ingress) []string {
+	names := make([]string, 0, len(ingresses))
+	for _, i := range ingresses {
+		names = append(names, i.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,109 @@
+package integration_test
+
+import (
+	"code.cloudfoundry.org/eirini/k8s/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("NetworkPolicies", func() {
+	var networkPolicyClient *client.NetworkPolicy
+
+	BeforeEach(func() {
+		networkPolicyClient = client.NewNetworkPolicy(fixture.Clientset)
+	})
+
+	Describe("Get", func() {
+		BeforeEach(func() {
+			createNetworkPolicy(fixture.Namespace, "foo")
+		})
+
+		It("retrieves a NetworkPolicy by namespace and name", func() {
+			networkPolicy, err := networkPolicyClient.Get(ctx, fixture.Namespace, "foo")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(networkPolicy.Name).To(Equal("foo"))
+			Expect(networkPolicy.Namespace).To(Equal(fixture.Namespace))
+		})
+	})
+
+	Describe("Create", func() {
+		It("creates the network policy in the namespace", func() {
+			_, err := networkPolicyClient.Create(ctx, fixture.Namespace, networkPolicySpec("foo"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(networkPolicyNames(listNetworkPolicies(fixture.Namespace))).To(ContainElement("foo"))
+		})
+	})
+
+	Describe("Update", func() {
+		var networkPolicy *networkingv1.NetworkPolicy
+
+		BeforeEach(func() {
+			networkPolicy = createNetworkPolicy(fixture.Namespace, "foo")
+		})
+
+		It("updates the existing network policy", func() {
+			networkPolicy.Spec.Egress[0].To[0].IPBlock.CIDR = "192.168.0.0/16"
+			_, err := networkPolicyClient.Update(ctx, fixture.Namespace, networkPolicy)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated, err := fixture.Clientset.NetworkingV1().NetworkPolicies(fixture.Namespace).Get(ctx, "foo", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Spec.Egress[0].To[0].IPBlock.CIDR).To(Equal("192.168.0.0/16"))
+		})
+	})
+
+	Describe("Delete", func() {
+		BeforeEach(func() {
+			createNetworkPolicy(fixture.Namespace, "foo")
+		})
+
+		It("deletes a NetworkPolicy", func() {
+			err := networkPolicyClient.Delete(ctx, fixture.Namespace, "foo")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() []string {
+				return networkPolicyNames(listNetworkPolicies(fixture.Namespace))
+			}).ShouldNot(ContainElement("foo"))
+		})
+	})
+})
+
+func networkPolicySpec(name string) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}}},
+			},
+		},
+	}
+}
+
+func createNetworkPolicy(ns, name string) *networkingv1.NetworkPolicy {
+	networkPolicy, err := fixture.Clientset.NetworkingV1().NetworkPolicies(ns).Create(ctx, networkPolicySpec(name), metav1.CreateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	return networkPolicy
+}
+
+func listNetworkPolicies(ns string) []networkingv1.NetworkPolicy {
+	networkPolicies, err := fixture.Clientset.NetworkingV1().NetworkPolicies(ns).List(ctx, metav1.ListOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	return networkPolicies.Items
+}
+
+func networkPolicyNames(networkPolicies []networkingv1.NetworkPolicy) []string {
+	names := make([]string, 0, len(networkPolicies))
+	for _, n := range networkPolicies {
+		names = append(names, n.Name)
+	}
+
+	return names
+}
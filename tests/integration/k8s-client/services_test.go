@@ -0,0 +1,106 @@
+package integration_test
+
+import (
+	"code.cloudfoundry.org/eirini/k8s/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Services", func() {
+	var serviceClient *client.Service
+
+	BeforeEach(func() {
+		serviceClient = client.NewService(fixture.Clientset)
+	})
+
+	Describe("Get", func() {
+		BeforeEach(func() {
+			createService(fixture.Namespace, "foo")
+		})
+
+		It("retrieves a Service by namespace and name", func() {
+			service, err := serviceClient.Get(ctx, fixture.Namespace, "foo")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.Name).To(Equal("foo"))
+			Expect(service.Namespace).To(Equal(fixture.Namespace))
+		})
+	})
+
+	Describe("Create", func() {
+		It("creates the service in the namespace", func() {
+			_, err := serviceClient.Create(ctx, fixture.Namespace, serviceSpec("foo"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(serviceNames(listServices(fixture.Namespace))).To(ContainElement("foo"))
+		})
+	})
+
+	Describe("Update", func() {
+		var service *corev1.Service
+
+		BeforeEach(func() {
+			service = createService(fixture.Namespace, "foo")
+		})
+
+		It("updates the existing service", func() {
+			service.Spec.Ports[0].Port = 9090
+			_, err := serviceClient.Update(ctx, fixture.Namespace, service)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated, err := fixture.Clientset.CoreV1().Services(fixture.Namespace).Get(ctx, "foo", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Spec.Ports[0].Port).To(Equal(int32(9090)))
+		})
+	})
+
+	Describe("Delete", func() {
+		BeforeEach(func() {
+			createService(fixture.Namespace, "foo")
+		})
+
+		It("deletes a Service", func() {
+			err := serviceClient.Delete(ctx, fixture.Namespace, "foo")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() []string {
+				return serviceNames(listServices(fixture.Namespace))
+			}).ShouldNot(ContainElement("foo"))
+		})
+	})
+})
+
+func serviceSpec(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Port: 8080}},
+		},
+	}
+}
+
+func createService(ns, name string) *corev1.Service {
+	service, err := fixture.Clientset.CoreV1().Services(ns).Create(ctx, serviceSpec(name), metav1.CreateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	return service
+}
+
+func listServices(ns string) []corev1.Service {
+	services, err := fixture.Clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	return services.Items
+}
+
+func serviceNames(services []corev1.Service) []string {
+	names := make([]string, 0, len(services))
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,103 @@
+package migration_test
+
+import (
+	"context"
+	"strconv"
+
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/migrations"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Move Env To Secret Migration", func() {
+	BeforeEach(func() {
+		originalRequest := `{"instances":1,"environment":{"FOO":"secret"}}`
+		stSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-env-stset",
+				Namespace: fixture.Namespace,
+				Labels: map[string]string{
+					stset.LabelSourceType: stset.AppSourceType,
+					stset.LabelGUID:       "env-stset-guid",
+					stset.LabelVersion:    "env-stset-version",
+				},
+				Annotations: map[string]string{
+					shared.AnnotationLatestMigration: strconv.Itoa(migrations.AdoptJobSecretSequenceID),
+					stset.AnnotationOriginalRequest:  originalRequest,
+				},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"app": "env",
+					},
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"app": "env",
+						},
+						Annotations: map[string]string{
+							stset.AnnotationOriginalRequest: originalRequest,
+						},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:  stset.ApplicationContainerName,
+							Image: "eirini/dorini",
+							Env:   []corev1.EnvVar{{Name: "FOO", Value: "secret"}},
+						}},
+					},
+				},
+			},
+		}
+
+		_, err := fixture.Clientset.AppsV1().StatefulSets(fixture.Namespace).Create(context.Background(), stSet, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("moves the environment values to a secret owned by the statefulset", func() {
+		stSet, err := fixture.Clientset.AppsV1().StatefulSets(fixture.Namespace).Get(context.Background(), "my-env-stset", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		secret, err := fixture.Clientset.CoreV1().Secrets(fixture.Namespace).Get(context.Background(), "my-env-stset-env", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(secret.Data).To(HaveKeyWithValue("opi.FOO", []byte("secret")))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].UID).To(Equal(stSet.UID))
+	})
+
+	It("refers to the secret from the container", func() {
+		stSet, err := fixture.Clientset.AppsV1().StatefulSets(fixture.Namespace).Get(context.Background(), "my-env-stset", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		env := stSet.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(HaveLen(1))
+		Expect(env[0].Value).To(BeEmpty())
+		Expect(env[0].ValueFrom.SecretKeyRef.Name).To(Equal("my-env-stset-env"))
+		Expect(env[0].ValueFrom.SecretKeyRef.Key).To(Equal("opi.FOO"))
+	})
+
+	It("redacts the original request", func() {
+		stSet, err := fixture.Clientset.AppsV1().StatefulSets(fixture.Namespace).Get(context.Background(), "my-env-stset", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(stSet.Annotations[stset.AnnotationOriginalRequest]).NotTo(ContainSubstring("secret"))
+		Expect(stSet.Spec.Template.Annotations[stset.AnnotationOriginalRequest]).NotTo(ContainSubstring("secret"))
+	})
+
+	It("bumps the latest migration annotation", func() {
+		stSet, err := fixture.Clientset.AppsV1().StatefulSets(fixture.Namespace).Get(context.Background(), "my-env-stset", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		version, err := strconv.Atoi(stSet.Annotations[shared.AnnotationLatestMigration])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(BeNumerically(">=", migrations.MoveEnvToSecretSequenceID))
+	})
+})
@@ -0,0 +1,91 @@
+package migration_test
+
+import (
+	"context"
+	"strconv"
+
+	"code.cloudfoundry.org/eirini/k8s/shared"
+	"code.cloudfoundry.org/eirini/k8s/stset"
+	"code.cloudfoundry.org/eirini/migrations"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("Rewrite PDB Migration", func() {
+	var selector *metav1.LabelSelector
+
+	BeforeEach(func() {
+		two := int32(2)
+		selector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				stset.LabelGUID:       "stset-guid",
+				stset.LabelVersion:    "stset-version",
+				stset.LabelSourceType: stset.AppSourceType,
+			},
+		}
+
+		stSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-stset",
+				Namespace: fixture.Namespace,
+				Labels:    selector.MatchLabels,
+				Annotations: map[string]string{
+					shared.AnnotationLatestMigration: strconv.Itoa(migrations.MoveEnvToSecretSequenceID),
+				},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: selector,
+				Replicas: &two,
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: selector.MatchLabels,
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:  "opi",
+							Image: "eirini/dorini",
+						}},
+					},
+				},
+			},
+		}
+
+		half := intstr.FromString("50%")
+		pdb := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-stset",
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector:     selector,
+				MinAvailable: &half,
+			},
+		}
+
+		_, err := fixture.Clientset.AppsV1().StatefulSets(fixture.Namespace).Create(context.Background(), stSet, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = fixture.Clientset.PolicyV1().PodDisruptionBudgets(fixture.Namespace).Create(context.Background(), pdb, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("keeps the pdb", func() {
+		pdb, err := fixture.Clientset.PolicyV1().PodDisruptionBudgets(fixture.Namespace).Get(context.Background(), "my-stset", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pdb.Spec.Selector).To(Equal(selector))
+		Expect(pdb.Spec.MinAvailable).To(PointTo(Equal(intstr.FromString("50%"))))
+	})
+
+	It("bumps the latest migration annotation", func() {
+		stSet, err := fixture.Clientset.AppsV1().StatefulSets(fixture.Namespace).Get(context.Background(), "my-stset", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		version, err := strconv.Atoi(stSet.Annotations[shared.AnnotationLatestMigration])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(BeNumerically(">=", migrations.RewritePDBSequenceID))
+	})
+})
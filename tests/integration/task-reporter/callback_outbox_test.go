@@ -0,0 +1,108 @@
+package task_reporter_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"code.cloudfoundry.org/eirini"
+	"code.cloudfoundry.org/eirini/k8s/client"
+	"code.cloudfoundry.org/eirini/k8s/outbox"
+	"code.cloudfoundry.org/eirini/models/cf"
+	"code.cloudfoundry.org/eirini/tests"
+	"code.cloudfoundry.org/eirini/tests/integration"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Callback outbox", func() {
+	var (
+		cloudControllerServer *ghttp.Server
+		callback              cf.TaskCompletedRequest
+		ctx                   context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+
+		cloudControllerServer, err = integration.CreateTestServer(
+			filepath.Join(eiriniBins.CertsPath, "tls.crt"),
+			filepath.Join(eiriniBins.CertsPath, "tls.key"),
+			filepath.Join(eiriniBins.CertsPath, "tls.ca"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		cloudControllerServer.HTTPTestServer.StartTLS()
+
+		config = &eirini.TaskReporterConfig{
+			KubeConfig: eirini.KubeConfig{
+				ConfigPath: fixture.KubeConfigPath,
+			},
+			WorkloadsNamespace:       fixture.Namespace,
+			CallbackOutboxRetryLimit: 2,
+			LeaderElectionID:         fmt.Sprintf("test-task-reporter-%d", GinkgoParallelProcess()),
+			LeaderElectionNamespace:  fixture.Namespace,
+		}
+
+		callback = cf.TaskCompletedRequest{
+			TaskGUID:      tests.GenerateGUID(),
+			Failed:        true,
+			FailureReason: "task was cancelled",
+		}
+	})
+
+	JustBeforeEach(func() {
+		callbackOutbox := outbox.NewOutbox(client.NewConfigMap(fixture.Clientset), fixture.Namespace)
+		Expect(callbackOutbox.Post(ctx, fmt.Sprintf("%s/the-callback", cloudControllerServer.URL()), callback)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		cloudControllerServer.Close()
+	})
+
+	When("the cloud controller accepts the callback", func() {
+		BeforeEach(func() {
+			cloudControllerServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/the-callback"),
+					ghttp.VerifyJSONRepresenting(callback),
+				),
+			)
+		})
+
+		It("delivers the callback", func() {
+			Eventually(cloudControllerServer.ReceivedRequests).Should(HaveLen(1))
+		})
+
+		It("removes the callback from the outbox", func() {
+			Eventually(getOutboxCallbacks).Should(BeEmpty())
+		})
+	})
+
+	When("the cloud controller keeps failing", func() {
+		BeforeEach(func() {
+			cloudControllerServer.RouteToHandler(http.MethodPost, "/the-callback", ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+		})
+
+		It("retries until the retry limit is reached", func() {
+			Eventually(cloudControllerServer.ReceivedRequests).Should(HaveLen(2))
+			Eventually(getOutboxCallbacks).Should(BeEmpty())
+			Consistently(cloudControllerServer.ReceivedRequests, "5s").Should(HaveLen(2))
+		})
+	})
+})
+
+func getOutboxCallbacks() ([]corev1.ConfigMap, error) {
+	configMaps, err := fixture.Clientset.CoreV1().ConfigMaps(fixture.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", outbox.LabelSourceType, outbox.SourceType),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return configMaps.Items, nil
+}
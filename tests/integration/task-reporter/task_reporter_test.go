@@ -58,7 +58,7 @@ var _ = Describe("TaskReporter", func() {
 			LeaderElectionNamespace:      fixture.Namespace,
 		}
 
-		taskToJobConverter := jobs.NewTaskToJobConverter("", "", false, 1234)
+		taskToJobConverter := jobs.NewTaskToJobConverter("", "", false, nil, nil, 1234)
 		taskDesirer = jobs.NewDesirer(
 			tests.NewTestLogger("test-task-desirer"),
 			taskToJobConverter,
@@ -157,7 +157,8 @@ var _ = Describe("TaskReporter", func() {
 					ghttp.VerifyJSONRepresenting(cf.TaskCompletedRequest{
 						TaskGUID:      task.GUID,
 						Failed:        true,
-						FailureReason: "Error",
+						FailureReason: "Exited with status 1",
+						ExitCode:      1,
 					}),
 				),
 			}
@@ -173,6 +174,28 @@ var _ = Describe("TaskReporter", func() {
 		})
 	})
 
+	When("the task declares a result file", func() {
+		BeforeEach(func() {
+			task.ResultFile = "/tmp/result.json"
+			task.Command = []string{"/bin/sh", "-c", `echo -n '{"rows":42}' > /tmp/result.json`}
+
+			handlers = []http.HandlerFunc{
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/the-callback"),
+					ghttp.VerifyJSONRepresenting(cf.TaskCompletedRequest{
+						TaskGUID: task.GUID,
+						Result:   `{"rows":42}`,
+					}),
+				),
+			}
+		})
+
+		It("sends the content of the result file to the cloud controller", func() {
+			Eventually(cloudControllerServer.ReceivedRequests).Should(HaveLen(1))
+			Consistently(cloudControllerServer.ReceivedRequests, "10s").Should(HaveLen(1))
+		})
+	})
+
 	When("the completion callback fails", func() {
 		BeforeEach(func() {
 			cloudControllerServer.SetAllowUnhandledRequests(true)
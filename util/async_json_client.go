@@ -0,0 +1,41 @@
+package util
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+)
+
+//counterfeiter:generate . JSONClient
+
+type JSONClient interface {
+	Post(ctx context.Context, url string, data interface{}) error
+}
+
+// AsyncJSONClient posts in the background and only logs failures. Callers
+// get no delivery guarantee, so it should only be used where losing a post
+// is acceptable.
+type AsyncJSONClient struct {
+	logger lager.Logger
+	client JSONClient
+}
+
+func NewAsyncJSONClient(logger lager.Logger, client JSONClient) *AsyncJSONClient {
+	return &AsyncJSONClient{
+		logger: logger,
+		client: client,
+	}
+}
+
+func (c *AsyncJSONClient) Post(_ context.Context, url string, data interface{}) error {
+	go func() {
+		// We need to pass context.Background() here as the caller context
+		// is usually a request context that is cancelled as soon as the
+		// HTTP response is returned.
+		if err := c.client.Post(context.Background(), url, data); err != nil {
+			c.logger.Error("async-post-failed", err, lager.Data{"url": url})
+		}
+	}()
+
+	return nil
+}
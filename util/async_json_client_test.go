@@ -0,0 +1,52 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/eirini/tests"
+	"code.cloudfoundry.org/eirini/util"
+	"code.cloudfoundry.org/eirini/util/utilfakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AsyncJSONClient", func() {
+	var (
+		jsonClient  *utilfakes.FakeJSONClient
+		asyncClient *util.AsyncJSONClient
+		err         error
+	)
+
+	BeforeEach(func() {
+		jsonClient = new(utilfakes.FakeJSONClient)
+		asyncClient = util.NewAsyncJSONClient(tests.NewTestLogger("async-json-client"), jsonClient)
+	})
+
+	JustBeforeEach(func() {
+		err = asyncClient.Post(ctx, "http://example.com", TestData{Value: "foo"})
+	})
+
+	It("succeeds", func() {
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("posts the data in the background", func() {
+		Eventually(jsonClient.PostCallCount).Should(Equal(1))
+		postCtx, url, data := jsonClient.PostArgsForCall(0)
+		Expect(postCtx).To(Equal(context.Background()))
+		Expect(url).To(Equal("http://example.com"))
+		Expect(data).To(Equal(TestData{Value: "foo"}))
+	})
+
+	When("posting fails", func() {
+		BeforeEach(func() {
+			jsonClient.PostReturns(errors.New("boom"))
+		})
+
+		It("does not return an error", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(jsonClient.PostCallCount).Should(Equal(1))
+		})
+	})
+})